@@ -71,6 +71,11 @@ func (d *AbstractDUT) Ports() map[string]*Port {
 	return d.Dims.Ports
 }
 
+// Capabilities returns the capabilities of the DUT.
+func (d *AbstractDUT) Capabilities() *opb.Capabilities {
+	return d.Dims.Capabilities
+}
+
 func (d *AbstractDUT) String() string {
 	return fmt.Sprintf("DUT%+v", *d)
 }
@@ -150,6 +155,11 @@ func (a *AbstractATE) Ports() map[string]*Port {
 	return a.Dims.Ports
 }
 
+// Capabilities returns the capabilities of the ATE.
+func (a *AbstractATE) Capabilities() *opb.Capabilities {
+	return a.Dims.Capabilities
+}
+
 func (a *AbstractATE) String() string {
 	return fmt.Sprintf("ATE%+v", *a)
 }
@@ -79,6 +79,25 @@ type Binding interface {
 	FetchReservation(ctx context.Context, id string) (*Reservation, error)
 }
 
+// Renewer is an optional extension of the Binding interface for
+// implementations whose reservations expire unless they are renewed.
+//
+// If the binding implements this interface, the framework sends periodic
+// heartbeats for as long as the tests are running, and tests may explicitly
+// extend the reservation by calling ondatra.ExtendReservation.
+type Renewer interface {
+
+	// Extend extends the current reservation, so that it is held for at least
+	// the specified duration from now. The framework has already checked that
+	// the duration is positive.
+	Extend(ctx context.Context, d time.Duration) error
+
+	// Heartbeat notifies the implementation that the current reservation is
+	// still in use. Implementations that track liveness of their users may
+	// release a reservation that stops receiving heartbeats.
+	Heartbeat(ctx context.Context) error
+}
+
 // Reservation holds the reserved DUTs and ATEs as an id map.
 type Reservation struct {
 	ID   string
@@ -93,6 +112,7 @@ type Device interface {
 	HardwareModel() string
 	SoftwareVersion() string
 	Ports() map[string]*Port
+	Capabilities() *opb.Capabilities
 }
 
 // Dims contains the dimensions of reserved DUT or ATE.
@@ -102,6 +122,9 @@ type Dims struct {
 	HardwareModel   string
 	SoftwareVersion string
 	Ports           map[string]*Port
+	// Capabilities of the device, if known to the binding. If nil, Ondatra
+	// trusts that the binding reserved a device with the required capabilities.
+	Capabilities *opb.Capabilities
 }
 
 func (d *Dims) String() string {
@@ -124,7 +147,9 @@ type DUT interface {
 	// is reset to its base config before the specified config is added.
 	// The following Go template functions are allowed in config:
 	// - {{ port "<portID>" }}: replaced with the physical port name
-	// - {{ secrets "<arg1>" "<arg2>" }}: left untouched, returned as-is
+	// - {{ secrets "<arg1>" "<arg2>" }}: left untouched, returned as-is,
+	// unless a secrets provider is set, in which case the secret is replaced
+	// with its value before the config is passed to the binding
 	PushConfig(ctx context.Context, config string, reset bool) error
 
 	// DialCLI creates a client connection to the DUT's CLI endpoint.
@@ -156,6 +181,34 @@ type DUT interface {
 	mustEmbedAbstractDUT()
 }
 
+// ConfigCheckpointer is an optional extension of the DUT interface for
+// implementations that natively support config checkpoints and rollback, and
+// config commits that revert unless they are confirmed, such as Junos
+// "commit confirmed" or EOS configure sessions.
+//
+// If the DUT does not implement this interface, the framework emulates these
+// operations: it saves the running-config of the DUT as text and restores it
+// with PushConfig, and it reverts an unconfirmed commit from the test process.
+type ConfigCheckpointer interface {
+
+	// CheckpointConfig saves the current config of the device and returns an
+	// ID with which to roll the device back to it.
+	CheckpointConfig(ctx context.Context) (string, error)
+
+	// RollbackConfig restores the config of the device to the checkpoint with
+	// the specified ID.
+	RollbackConfig(ctx context.Context, id string) error
+
+	// PushConfigWithCommitConfirm adds config to the device like PushConfig,
+	// but the device reverts the config after the timeout, unless the commit is
+	// confirmed first. It returns an ID with which to confirm the commit.
+	PushConfigWithCommitConfirm(ctx context.Context, config string, reset bool, timeout time.Duration) (string, error)
+
+	// ConfirmConfig confirms the commit with the specified ID, so that the
+	// device does not revert it.
+	ConfirmConfig(ctx context.Context, id string) error
+}
+
 // ATE is a reserved ATE.
 //
 // All implementations of this interface must embed AbstractATE.
@@ -190,6 +243,12 @@ type Port struct {
 	Speed     opb.Port_Speed
 	CardModel string
 	PMD       opb.Port_Pmd
+
+	// BreakoutGroup identifies the physical port of which the port is a
+	// breakout channel, typically by the name of the physical port. All the
+	// channels of a physical port have the same breakout group. It is empty if
+	// the port is not a breakout channel.
+	BreakoutGroup string
 }
 
 func (p *Port) String() string {
@@ -247,3 +306,12 @@ type ConsoleClient interface {
 	Close() error
 	mustEmbedAbstractConsoleClient()
 }
+
+// ConsoleBreaker is an optional extension of the ConsoleClient interface for
+// implementations that can send a break to the console, for example to
+// interrupt the boot of a device into its ROM monitor.
+type ConsoleBreaker interface {
+
+	// SendBreak sends a break to the console.
+	SendBreak(ctx context.Context) error
+}
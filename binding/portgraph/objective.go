@@ -0,0 +1,133 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portgraph
+
+import (
+	"golang.org/x/net/context"
+)
+
+// NodeCost returns the cost of assigning a ConcreteNode.
+type NodeCost func(*ConcreteNode) float64
+
+// PortCost returns the cost of assigning a ConcretePort.
+type PortCost func(*ConcretePort) float64
+
+// AssignmentCost returns the cost of an Assignment as a whole, for costs that
+// depend on several of its nodes or ports.
+type AssignmentCost func(*Assignment) float64
+
+// Objective is a cost of an Assignment for Optimize to minimize: the sum of the
+// costs of its ConcreteNodes, of its ConcretePorts and of the Assignment itself.
+// Costs should be non-negative, so that Optimize can skip assignments that
+// cannot be cheaper than the best assignment found so far.
+type Objective struct {
+	NodeCosts       []NodeCost
+	PortCosts       []PortCost
+	AssignmentCosts []AssignmentCost
+}
+
+// Cost returns the cost of the assignment. Unassigned nodes and ports, as in
+// the maximal assignment of a SolveErr, cost nothing.
+func (o *Objective) Cost(a *Assignment) float64 {
+	var cost float64
+	for _, n := range a.Node2Node {
+		if n != nil {
+			cost += o.nodeCost(n)
+		}
+	}
+	for _, p := range a.Port2Port {
+		if p != nil {
+			cost += o.portCost(p)
+		}
+	}
+	for _, c := range o.AssignmentCosts {
+		cost += c(a)
+	}
+	return cost
+}
+
+func (o *Objective) nodeCost(n *ConcreteNode) float64 {
+	var cost float64
+	for _, c := range o.NodeCosts {
+		cost += c(n)
+	}
+	return cost
+}
+
+func (o *Objective) portCost(p *ConcretePort) float64 {
+	var cost float64
+	for _, c := range o.PortCosts {
+		cost += c(p)
+	}
+	return cost
+}
+
+// NodeAttrCost returns a NodeCost that weighs a node by the value of one of its
+// attributes. A node costs the weight of its value, or nothing if its value has
+// no weight or it does not have the attribute.
+func NodeAttrCost(attr string, weights map[string]float64) NodeCost {
+	return func(n *ConcreteNode) float64 {
+		return attrWeight(n.Attrs, attr, weights)
+	}
+}
+
+// PortAttrCost returns a PortCost that weighs a port by the value of one of its
+// attributes, such as its speed to save scarce high speed ports. A port costs
+// the weight of its value, or nothing if its value has no weight or it does not
+// have the attribute.
+func PortAttrCost(attr string, weights map[string]float64) PortCost {
+	return func(p *ConcretePort) float64 {
+		return attrWeight(p.Attrs, attr, weights)
+	}
+}
+
+func attrWeight(attrs map[string]string, attr string, weights map[string]float64) float64 {
+	v, ok := attrs[attr]
+	if !ok {
+		return 0
+	}
+	return weights[v]
+}
+
+// NodeAttrSpread returns an AssignmentCost of weight for each distinct value of
+// a node attribute beyond the first among the assigned nodes, such as to keep
+// the nodes in one rack. Nodes without the attribute are not counted.
+func NodeAttrSpread(attr string, weight float64) AssignmentCost {
+	return func(a *Assignment) float64 {
+		vals := make(map[string]bool)
+		for _, n := range a.Node2Node {
+			if n == nil {
+				continue
+			}
+			if v, ok := n.Attrs[attr]; ok {
+				vals[v] = true
+			}
+		}
+		if len(vals) == 0 {
+			return 0
+		}
+		return weight * float64(len(vals)-1)
+	}
+}
+
+// Optimize returns the assignment from superGraph that satisfies abstractGraph
+// with the lowest cost under the objective. Optimize searches until it has
+// tried every assignment of nodes or the context is done, so the deadline of
+// the context is the time budget of the search; when it is done, Optimize
+// returns the best assignment found so far. For each assignment of nodes, the
+// ports are assigned greedily, trying cheaper ports first.
+func Optimize(ctx context.Context, abstractGraph *AbstractGraph, superGraph *ConcreteGraph, obj *Objective) (*Assignment, error) {
+	return solveGraph(ctx, abstractGraph, superGraph, obj)
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portgraph
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Initialize a super graph in two racks, with fast and slow ports.
+var (
+	rackNode1a = &ConcretePort{Desc: "rack1:a", Attrs: map[string]string{"speed": "400G"}}
+	rackNode1b = &ConcretePort{Desc: "rack1:b", Attrs: map[string]string{"speed": "100G"}}
+	rackNode1c = &ConcretePort{Desc: "rack1:c", Attrs: map[string]string{"speed": "100G"}}
+	rackNode2a = &ConcretePort{Desc: "rack2:a", Attrs: map[string]string{"speed": "400G"}}
+	rackNode2b = &ConcretePort{Desc: "rack2:b", Attrs: map[string]string{"speed": "100G"}}
+	rackNode3a = &ConcretePort{Desc: "rack3:a", Attrs: map[string]string{"speed": "400G"}}
+	rackNode3b = &ConcretePort{Desc: "rack3:b", Attrs: map[string]string{"speed": "400G"}}
+	rackNode4a = &ConcretePort{Desc: "rack4:a", Attrs: map[string]string{"speed": "400G"}}
+
+	rackNode1 = &ConcreteNode{Desc: "rack1", Ports: []*ConcretePort{rackNode1a, rackNode1b, rackNode1c}, Attrs: map[string]string{"rack": "r1", "warm": "false"}}
+	rackNode2 = &ConcreteNode{Desc: "rack2", Ports: []*ConcretePort{rackNode2a, rackNode2b}, Attrs: map[string]string{"rack": "r2", "warm": "false"}}
+	rackNode3 = &ConcreteNode{Desc: "rack3", Ports: []*ConcretePort{rackNode3a, rackNode3b}, Attrs: map[string]string{"rack": "r1", "warm": "false"}}
+	rackNode4 = &ConcreteNode{Desc: "rack4", Ports: []*ConcretePort{rackNode4a}, Attrs: map[string]string{"rack": "r1", "warm": "true"}}
+
+	rackGraph = &ConcreteGraph{
+		Desc:  "racks",
+		Nodes: []*ConcreteNode{rackNode1, rackNode2, rackNode3, rackNode4},
+		Edges: []*ConcreteEdge{
+			{Src: rackNode1a, Dst: rackNode2a},
+			{Src: rackNode1b, Dst: rackNode2b},
+			{Src: rackNode1c, Dst: rackNode3a},
+			{Src: rackNode3b, Dst: rackNode4a},
+		},
+	}
+
+	pairPort1 = &AbstractPort{Desc: "pair1:port1"}
+	pairPort2 = &AbstractPort{Desc: "pair2:port1"}
+	pairGraph = &AbstractGraph{
+		Desc: "two linked nodes",
+		Nodes: []*AbstractNode{
+			{Desc: "pair1", Ports: []*AbstractPort{pairPort1}},
+			{Desc: "pair2", Ports: []*AbstractPort{pairPort2}},
+		},
+		Edges: []*AbstractEdge{{Src: pairPort1, Dst: pairPort2}},
+	}
+)
+
+// assignedPorts returns the sorted descriptions of the assigned concrete ports.
+func assignedPorts(a *Assignment) string {
+	var descs []string
+	for _, p := range a.Port2Port {
+		descs = append(descs, p.Desc)
+	}
+	sort.Strings(descs)
+	return strings.Join(descs, ",")
+}
+
+func TestOptimize(t *testing.T) {
+	speedCost := PortAttrCost("speed", map[string]float64{"400G": 10, "100G": 1})
+	tests := []struct {
+		desc      string
+		obj       *Objective
+		wantPorts string
+		wantCost  float64
+	}{{
+		desc:      "slow ports",
+		obj:       &Objective{PortCosts: []PortCost{speedCost}},
+		wantPorts: "rack1:b,rack2:b",
+		wantCost:  2,
+	}, {
+		desc: "one rack",
+		obj: &Objective{
+			PortCosts:       []PortCost{speedCost},
+			AssignmentCosts: []AssignmentCost{NodeAttrSpread("rack", 100)},
+		},
+		wantPorts: "rack1:c,rack3:a",
+		wantCost:  11,
+	}, {
+		desc:      "warm nodes",
+		obj:       &Objective{NodeCosts: []NodeCost{NodeAttrCost("warm", map[string]float64{"false": 1})}},
+		wantPorts: "rack3:b,rack4:a",
+		wantCost:  1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			a, err := Optimize(context.Background(), pairGraph, rackGraph, tt.obj)
+			if err != nil {
+				t.Fatalf("Optimize() failed: %v", err)
+			}
+			if got := assignedPorts(a); got != tt.wantPorts {
+				t.Errorf("Optimize() got ports %q, want %q", got, tt.wantPorts)
+			}
+			if got := tt.obj.Cost(a); got != tt.wantCost {
+				t.Errorf("Cost() got %v, want %v", got, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestOptimizeNotSolvable(t *testing.T) {
+	obj := &Objective{NodeCosts: []NodeCost{NodeAttrCost("warm", map[string]float64{"false": 1})}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Optimize(ctx, pairGraph, rackGraph, obj); err == nil {
+		t.Error("Optimize() got nil error, want error due to cancel")
+	}
+	unsolvable := &AbstractGraph{
+		Desc:  "node with no match",
+		Nodes: []*AbstractNode{{Desc: "pair1", Constraints: map[string]NodeConstraint{"rack": Equal("r3")}}},
+	}
+	if _, err := Optimize(context.Background(), unsolvable, rackGraph, obj); err == nil {
+		t.Error("Optimize() got nil error, want error for unsolvable graph")
+	}
+}
+
+func TestOptimizeDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var evals int
+	// Reach the deadline while evaluating the first complete assignment.
+	waitForDeadline := func(*Assignment) float64 {
+		evals++
+		<-ctx.Done()
+		return 0
+	}
+	obj := &Objective{AssignmentCosts: []AssignmentCost{waitForDeadline}}
+	a, err := Optimize(ctx, pairGraph, rackGraph, obj)
+	if err != nil {
+		t.Fatalf("Optimize() failed: %v", err)
+	}
+	if got := len(a.Port2Port); got != 2 {
+		t.Errorf("Optimize() got %d assigned ports, want 2", got)
+	}
+	if evals != 1 {
+		t.Errorf("Optimize() evaluated %d assignments, want 1", evals)
+	}
+}
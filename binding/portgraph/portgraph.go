@@ -17,6 +17,7 @@ package portgraph
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 
@@ -216,11 +217,19 @@ type solver struct {
 	portConstraints         *orderedmap.OrderedMap[*AbstractPort, *orderedmap.OrderedMap[string, LeafConstraint]]
 	deferredNodeConstraints *orderedmap.OrderedMap[*AbstractNode, []deferredNodeConstraint]
 	deferredPortConstraints *orderedmap.OrderedMap[*AbstractPort, []deferredPortConstraint]
+
+	obj *Objective // The objective to minimize, or nil to return the first Assignment.
 }
 
 // Solve returns an assignment from superGraph that satisfies abstractGraph.
 // Solve accepts a context to handle termination if the solve takes too long.
 func Solve(ctx context.Context, abstractGraph *AbstractGraph, superGraph *ConcreteGraph) (*Assignment, error) {
+	return solveGraph(ctx, abstractGraph, superGraph, nil)
+}
+
+// solveGraph returns the first assignment from superGraph that satisfies
+// abstractGraph or, if obj is non-nil, the best assignment under obj.
+func solveGraph(ctx context.Context, abstractGraph *AbstractGraph, superGraph *ConcreteGraph, obj *Objective) (*Assignment, error) {
 	solveErr := &SolveErr{absGraphDesc: abstractGraph.Desc, conGraphDesc: superGraph.Desc}
 	if len(abstractGraph.Nodes) > len(superGraph.Nodes) {
 		return nil, solveErr
@@ -298,6 +307,7 @@ func Solve(ctx context.Context, abstractGraph *AbstractGraph, superGraph *Concre
 		deferredNodeConstraints: orderedmap.NewOrderedMap[*AbstractNode, []deferredNodeConstraint](),
 		portConstraints:         orderedmap.NewOrderedMap[*AbstractPort, *orderedmap.OrderedMap[string, LeafConstraint]](),
 		deferredPortConstraints: orderedmap.NewOrderedMap[*AbstractPort, []deferredPortConstraint](),
+		obj:                     obj,
 	}
 
 	a, ok := s.solve(ctx)
@@ -313,6 +323,7 @@ func Solve(ctx context.Context, abstractGraph *AbstractGraph, superGraph *Concre
 // 2. For each mapping, check the concrete nodes and satisfy the edges of the abstract graph.
 // 3. Assign concrete ports.
 // solve always returns the max assignment and true if the solve completed.
+// With an objective, solve tries every mapping and returns the one with the lowest cost.
 func (s *solver) solve(ctx context.Context) (*Assignment, bool) {
 	abs2ConNodes := make(map[*AbstractNode][]*ConcreteNode)
 	s.processConstraints()
@@ -333,10 +344,13 @@ func (s *solver) solve(ctx context.Context) (*Assignment, bool) {
 	defer stop()
 	// Iterate through each mapping.
 	// For each mapping, evaluate deferred constraints and attempt to match edges and assign ports.
+	var best *Assignment
+	bestCost := math.Inf(1)
 	for abs2ConNode := range abs2ConNodeChan {
 		select {
 		case <-ctx.Done():
-			return nil, false
+			// With an objective, the context is the time budget; return the best Assignment so far.
+			return best, best != nil
 		default:
 		}
 		if !s.matchDeferredNodes(abs2ConNode) {
@@ -346,6 +360,10 @@ func (s *solver) solve(ctx context.Context) (*Assignment, bool) {
 			s.maxAssign.assignment.Node2Node = abs2ConNode
 			s.maxAssign.numNodes = len(abs2ConNode)
 		}
+		if s.obj != nil && s.nodesCost(abs2ConNode) >= bestCost {
+			// The nodes alone cost at least as much as the best Assignment.
+			continue
+		}
 
 		// Since the edges can be satisfied, try to assign matching ports.
 		abs2ConPort := s.assignEdges(ctx, abs2ConNode)
@@ -353,9 +371,28 @@ func (s *solver) solve(ctx context.Context) (*Assignment, bool) {
 			continue
 		}
 
-		return &Assignment{abs2ConNode, abs2ConPort}, true
+		a := &Assignment{abs2ConNode, abs2ConPort}
+		if s.obj == nil {
+			return a, true
+		}
+		if cost := s.obj.Cost(a); cost < bestCost {
+			best, bestCost = a, cost
+			if cost <= 0 {
+				// No Assignment can cost less.
+				break
+			}
+		}
 	}
-	return nil, false
+	return best, best != nil
+}
+
+// nodesCost returns the cost of the ConcreteNodes of a mapping under the objective.
+func (s *solver) nodesCost(abs2ConNode map[*AbstractNode]*ConcreteNode) float64 {
+	var cost float64
+	for _, n := range abs2ConNode {
+		cost += s.obj.nodeCost(n)
+	}
+	return cost
 }
 
 // checkEdge validates there are enough Ports to fulfill the Edge between the given Nodes and
@@ -559,6 +596,10 @@ func (pa *portAssigner) assign(ctx context.Context) bool {
 			}
 		}
 		rewindState()
+		if ctx.Err() != nil {
+			// The context is done; don't try the remaining edges.
+			return false
+		}
 	}
 	// None of these Edges work with the current state.
 	return false
@@ -621,6 +662,21 @@ func (s *solver) assignEdges(ctx context.Context, abs2ConNode map[*AbstractNode]
 			}
 		}
 	}
+	if s.obj != nil && len(s.obj.PortCosts) > 0 {
+		// Try cheaper edges first.
+		edgeCost := func(e *ConcreteEdge) float64 {
+			cost := s.obj.portCost(e.Src)
+			if e.Dst != nil {
+				cost += s.obj.portCost(e.Dst)
+			}
+			return cost
+		}
+		for _, conEdges := range abs2ConEdgeCombos {
+			sort.SliceStable(conEdges, func(i, j int) bool {
+				return edgeCost(conEdges[i]) < edgeCost(conEdges[j])
+			})
+		}
+	}
 	var orderedEdges []*AbstractEdge
 	for e := range abs2ConEdgeCombos {
 		orderedEdges = append(orderedEdges, e)
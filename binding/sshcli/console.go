@@ -0,0 +1,311 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ondatra/binding"
+	"golang.org/x/crypto/ssh"
+)
+
+// DialSSHConsole connects to the console of a device through an SSH
+// terminal-server port, such as a port of a console server that is mapped
+// to the serial console of the device.
+func DialSSHConsole(ctx context.Context, addr string, cfg *ssh.ClientConfig) (binding.ConsoleClient, error) {
+	client, session, err := dialSession(ctx, addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newSSHConsole(client, session)
+	if err != nil {
+		session.Close()
+		client.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+type sshConsole struct {
+	*binding.AbstractConsoleClient
+	client  *ssh.Client
+	session *ssh.Session
+	stdin   io.WriteCloser
+	stdout  io.ReadCloser
+	stderr  io.ReadCloser
+}
+
+func newSSHConsole(client *ssh.Client, session *ssh.Session) (*sshConsole, error) {
+	if err := session.RequestPty("vt100", 0, 32767, ssh.TerminalModes{}); err != nil {
+		return nil, fmt.Errorf("error requesting pty: %w", err)
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := session.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Shell(); err != nil {
+		return nil, fmt.Errorf("error starting shell: %w", err)
+	}
+	return &sshConsole{
+		client:  client,
+		session: session,
+		stdin:   stdin,
+		stdout:  io.NopCloser(stdout),
+		stderr:  io.NopCloser(stderr),
+	}, nil
+}
+
+func (c *sshConsole) Stdin() io.WriteCloser {
+	return c.stdin
+}
+
+func (c *sshConsole) Stdout() io.ReadCloser {
+	return c.stdout
+}
+
+func (c *sshConsole) Stderr() io.ReadCloser {
+	return c.stderr
+}
+
+// breakLength is the length of a break sent over SSH, in milliseconds.
+const breakLength = 500
+
+// SendBreak sends a break request, as defined by RFC 4335.
+func (c *sshConsole) SendBreak(context.Context) error {
+	ok, err := c.session.SendRequest("break", true, ssh.Marshal(struct{ Length uint32 }{breakLength}))
+	if err != nil {
+		return fmt.Errorf("error sending break: %w", err)
+	}
+	if !ok {
+		return errors.New("SSH server does not support break")
+	}
+	return nil
+}
+
+func (c *sshConsole) Close() error {
+	if err := c.session.Close(); err != nil && err != io.EOF {
+		c.client.Close()
+		return err
+	}
+	return c.client.Close()
+}
+
+var (
+	_ binding.ConsoleBreaker = (*sshConsole)(nil)
+	_ binding.ConsoleBreaker = (*telnetConsole)(nil)
+)
+
+// Telnet commands and options, as defined by RFC 854 and RFC 857/858.
+const (
+	telnetSE   = 240
+	telnetBRK  = 243
+	telnetSB   = 250
+	telnetWILL = 251
+	telnetWONT = 252
+	telnetDO   = 253
+	telnetDONT = 254
+	telnetIAC  = 255
+
+	telnetOptEcho = 1
+	telnetOptSGA  = 3
+)
+
+// DialTelnetConsole connects to the console of a device through a telnet
+// terminal-server port. The console negotiates only that the server echoes
+// and suppresses go-ahead, which terminal servers commonly require, and
+// refuses all other telnet options.
+func DialTelnetConsole(ctx context.Context, addr string) (binding.ConsoleClient, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial telnet server %s: %w", addr, err)
+	}
+	c := &telnetConsole{conn: conn}
+	c.stdin = &telnetWriter{c: c}
+	c.stdout = &telnetReader{c: c, r: bufio.NewReader(conn)}
+	return c, nil
+}
+
+type telnetConsole struct {
+	*binding.AbstractConsoleClient
+	conn   net.Conn
+	stdin  *telnetWriter
+	stdout *telnetReader
+
+	mu sync.Mutex // serializes writes to conn
+}
+
+func (c *telnetConsole) Stdin() io.WriteCloser {
+	return c.stdin
+}
+
+func (c *telnetConsole) Stdout() io.ReadCloser {
+	return c.stdout
+}
+
+// Stderr returns an empty reader, because telnet has no separate error stream.
+func (c *telnetConsole) Stderr() io.ReadCloser {
+	return io.NopCloser(strings.NewReader(""))
+}
+
+// SendBreak sends the telnet BRK command.
+func (c *telnetConsole) SendBreak(context.Context) error {
+	return c.write([]byte{telnetIAC, telnetBRK})
+}
+
+func (c *telnetConsole) Close() error {
+	return c.conn.Close()
+}
+
+func (c *telnetConsole) write(b []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.conn.Write(b)
+	return err
+}
+
+// telnetWriter writes data to a telnet connection, escaping IAC bytes.
+type telnetWriter struct {
+	c *telnetConsole
+}
+
+func (w *telnetWriter) Write(b []byte) (int, error) {
+	escaped := make([]byte, 0, len(b))
+	for _, c := range b {
+		if c == telnetIAC {
+			escaped = append(escaped, telnetIAC)
+		}
+		escaped = append(escaped, c)
+	}
+	if err := w.c.write(escaped); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *telnetWriter) Close() error {
+	return w.c.Close()
+}
+
+// telnetReader reads data from a telnet connection, responding to and
+// removing the telnet commands in it.
+type telnetReader struct {
+	c *telnetConsole
+	r *bufio.Reader
+}
+
+func (r *telnetReader) Read(b []byte) (int, error) {
+	n := 0
+	for n < len(b) {
+		// Return the data read so far rather than block for more.
+		if n > 0 && r.r.Buffered() == 0 {
+			break
+		}
+		c, err := r.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if c != telnetIAC {
+			b[n] = c
+			n++
+			continue
+		}
+		data, err := r.command()
+		if err != nil {
+			return n, err
+		}
+		if data {
+			b[n] = telnetIAC
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *telnetReader) Close() error {
+	return r.c.Close()
+}
+
+// command handles the telnet command that follows an IAC byte. It returns
+// true if the command is an escaped IAC data byte.
+func (r *telnetReader) command() (bool, error) {
+	cmd, err := r.r.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	switch cmd {
+	case telnetIAC:
+		return true, nil
+	case telnetDO, telnetDONT, telnetWILL, telnetWONT:
+		opt, err := r.r.ReadByte()
+		if err != nil {
+			return false, err
+		}
+		if reply, ok := telnetReply(cmd, opt); ok {
+			return false, r.c.write([]byte{telnetIAC, reply, opt})
+		}
+	case telnetSB:
+		// Skip the subnegotiation up to and including IAC SE.
+		for prevIAC := false; ; {
+			c, err := r.r.ReadByte()
+			if err != nil {
+				return false, err
+			}
+			if prevIAC && c == telnetSE {
+				break
+			}
+			prevIAC = c == telnetIAC && !prevIAC
+		}
+	}
+	return false, nil
+}
+
+// telnetReply returns the reply to a telnet option negotiation, if any.
+func telnetReply(cmd, opt byte) (byte, bool) {
+	switch cmd {
+	case telnetWILL:
+		if opt == telnetOptEcho || opt == telnetOptSGA {
+			return telnetDO, true
+		}
+		return telnetDONT, true
+	case telnetDO:
+		if opt == telnetOptSGA {
+			return telnetWILL, true
+		}
+		return telnetWONT, true
+	}
+	// WONT and DONT need no reply, because the console never enables options
+	// other than those the server offers.
+	return 0, false
+}
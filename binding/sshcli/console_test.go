@@ -0,0 +1,163 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcli
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding"
+)
+
+// readUntil reads from r until the data read contains want.
+func readUntil(t *testing.T, r io.Reader, want string) string {
+	t.Helper()
+	var got []byte
+	b := make([]byte, 256)
+	for !bytes.Contains(got, []byte(want)) {
+		n, err := r.Read(b)
+		got = append(got, b[:n]...)
+		if err != nil {
+			t.Fatalf("Read() got error %v after reading %q, want %q", err, got, want)
+		}
+	}
+	return string(got)
+}
+
+func TestSSHConsole(t *testing.T) {
+	srv := startServer(t, "login: ", func(cmd string) string {
+		return "Password:"
+	})
+	c, err := DialSSHConsole(context.Background(), srv.Addr(), sshConfig(password))
+	if err != nil {
+		t.Fatalf("DialSSHConsole() got unexpected error: %v", err)
+	}
+	defer c.Close()
+	readUntil(t, c.Stdout(), "login: ")
+	if _, err := io.WriteString(c.Stdin(), "admin\n"); err != nil {
+		t.Fatalf("Write() got unexpected error: %v", err)
+	}
+	readUntil(t, c.Stdout(), "admin\r\nPassword:")
+	if err := c.(binding.ConsoleBreaker).SendBreak(context.Background()); err != nil {
+		t.Fatalf("SendBreak() got unexpected error: %v", err)
+	}
+	if got, want := srv.Breaks(), 1; got != want {
+		t.Errorf("SendBreak() sent %d breaks, want %d", got, want)
+	}
+	if diff := cmp.Diff([]string{"admin"}, srv.Commands()); diff != "" {
+		t.Errorf("console sent unexpected commands (-want +got):\n%s", diff)
+	}
+}
+
+func TestTelnetConsole(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer lis.Close()
+	connCh := make(chan net.Conn, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			close(connCh)
+			return
+		}
+		connCh <- conn
+	}()
+
+	c, err := DialTelnetConsole(context.Background(), lis.Addr().String())
+	if err != nil {
+		t.Fatalf("DialTelnetConsole() got unexpected error: %v", err)
+	}
+	defer c.Close()
+	srv, ok := <-connCh
+	if !ok {
+		t.Fatalf("Accept() failed")
+	}
+	defer srv.Close()
+	srv.SetDeadline(time.Now().Add(10 * time.Second))
+
+	// Negotiate options, interleaved with data that includes an escaped IAC
+	// and a subnegotiation.
+	serverOut := []byte{
+		telnetIAC, telnetWILL, telnetOptEcho,
+		'h', 'i',
+		telnetIAC, telnetWILL, telnetOptSGA,
+		telnetIAC, telnetDO, 24, // Terminal type.
+		telnetIAC, telnetSB, 24, 1, telnetIAC, telnetIAC, telnetIAC, telnetSE,
+		telnetIAC, telnetIAC,
+		'\r', '\n', '>', ' ',
+	}
+	if _, err := srv.Write(serverOut); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if got, want := readUntil(t, c.Stdout(), "> "), "hi\xff\r\n> "; got != want {
+		t.Errorf("Stdout() got %q, want %q", got, want)
+	}
+
+	if _, err := c.Stdin().Write([]byte("ls\xff\n")); err != nil {
+		t.Fatalf("Write() got unexpected error: %v", err)
+	}
+	if err := c.(binding.ConsoleBreaker).SendBreak(context.Background()); err != nil {
+		t.Fatalf("SendBreak() got unexpected error: %v", err)
+	}
+	wantIn := []byte{
+		telnetIAC, telnetDO, telnetOptEcho,
+		telnetIAC, telnetDO, telnetOptSGA,
+		telnetIAC, telnetWONT, 24,
+		'l', 's', telnetIAC, telnetIAC, '\n',
+		telnetIAC, telnetBRK,
+	}
+	gotIn := make([]byte, len(wantIn))
+	if _, err := io.ReadFull(srv, gotIn); err != nil {
+		t.Fatalf("ReadFull() failed: %v", err)
+	}
+	if diff := cmp.Diff(wantIn, gotIn); diff != "" {
+		t.Errorf("console sent unexpected bytes (-want +got):\n%s", diff)
+	}
+
+	stderr, err := io.ReadAll(c.Stderr())
+	if err != nil || len(stderr) > 0 {
+		t.Errorf("Stderr() got %q, %v, want empty", stderr, err)
+	}
+}
+
+func TestTelnetReply(t *testing.T) {
+	tests := []struct {
+		cmd, opt  byte
+		wantReply byte
+		wantOK    bool
+	}{
+		{telnetWILL, telnetOptEcho, telnetDO, true},
+		{telnetWILL, telnetOptSGA, telnetDO, true},
+		{telnetWILL, 24, telnetDONT, true},
+		{telnetDO, telnetOptSGA, telnetWILL, true},
+		{telnetDO, telnetOptEcho, telnetWONT, true},
+		{telnetWONT, telnetOptEcho, 0, false},
+		{telnetDONT, telnetOptSGA, 0, false},
+	}
+	for _, test := range tests {
+		gotReply, gotOK := telnetReply(test.cmd, test.opt)
+		if gotReply != test.wantReply || gotOK != test.wantOK {
+			t.Errorf("telnetReply(%d, %d) got (%d, %v), want (%d, %v)", test.cmd, test.opt, gotReply, gotOK, test.wantReply, test.wantOK)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sshcli provides implementations of the binding CLIClient and
+// ConsoleClient interfaces over SSH and telnet, for use by any binding.
+//
+// The CLI client holds a persistent interactive session to the device, so
+// that CLI state such as the config mode carries over from one command to the
+// next. It detects the end of the output of a command by the vendor's prompt,
+// disables pagination on login, and reports the lines of output that match
+// the vendor's error patterns as the error of the command.
+package sshcli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/binding"
+	"golang.org/x/crypto/ssh"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+const defaultCommandTimeout = time.Minute
+
+// Config is the config of a CLI client.
+type Config struct {
+	// Addr is the address of the SSH server in the form "host:port". Required.
+	Addr string
+	// SSH is the config of the SSH client. Required.
+	SSH *ssh.ClientConfig
+	// Vendor is the vendor of the device, which determines the default prompt,
+	// the commands that disable pagination, and the error patterns.
+	Vendor opb.Device_Vendor
+	// Prompt, if non-nil, matches the last line of output when the CLI awaits
+	// a command, in place of the vendor's prompt.
+	Prompt *regexp.Regexp
+	// Errors, if non-nil, match the lines of output that report a failed
+	// command, in place of the vendor's error patterns.
+	Errors []*regexp.Regexp
+	// CommandTimeout is the timeout of commands run with a context that has no
+	// deadline. Defaults to one minute.
+	CommandTimeout time.Duration
+}
+
+// Dial logs in to the CLI of a device and disables pagination.
+func Dial(ctx context.Context, cfg *Config) (*CLI, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("no SSH address specified")
+	}
+	if cfg.SSH == nil {
+		return nil, errors.New("no SSH client config specified")
+	}
+	vc := cliFor(cfg.Vendor)
+	c := &CLI{
+		cfg:        cfg,
+		prompt:     vc.prompt,
+		errors:     vc.errors,
+		pagination: vc.pagination,
+		timeout:    defaultCommandTimeout,
+	}
+	if cfg.Prompt != nil {
+		c.prompt = cfg.Prompt
+	}
+	if cfg.Errors != nil {
+		c.errors = cfg.Errors
+	}
+	if cfg.CommandTimeout > 0 {
+		c.timeout = cfg.CommandTimeout
+	}
+	if err := c.login(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CLI is a persistent, prompt-aware CLI session to a device.
+// If the session is lost, for example because a command times out, the next
+// command logs in to a new session.
+type CLI struct {
+	*binding.AbstractCLIClient
+	cfg        *Config
+	prompt     *regexp.Regexp
+	errors     []*regexp.Regexp
+	pagination []string
+	timeout    time.Duration
+
+	mu sync.Mutex // guards sh
+	sh *shell
+}
+
+// RunCommand runs a command and returns its output, without the echo of the
+// command and the prompt that follows it. A command of multiple lines is run
+// one line at a time, and the outputs of the lines are concatenated. The error
+// of the result is the first line of output that matches an error pattern.
+func (c *CLI) RunCommand(ctx context.Context, cmd string) (binding.CommandResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sh == nil {
+		if err := c.loginLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	var out strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(cmd, "\n"), "\n") {
+		lineOut, err := c.runLine(ctx, line)
+		if err != nil {
+			c.closeLocked()
+			return nil, fmt.Errorf("error running command %q on %s: %w", line, c.cfg.Addr, err)
+		}
+		out.WriteString(lineOut)
+	}
+	res := &cmdResult{output: out.String()}
+	for _, line := range strings.Split(res.output, "\n") {
+		if c.isError(line) {
+			res.error = strings.TrimSpace(line)
+			break
+		}
+	}
+	return res, nil
+}
+
+// Close logs out of the CLI session.
+func (c *CLI) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+func (c *CLI) login(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.loginLocked(ctx)
+}
+
+func (c *CLI) loginLocked(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	sh, err := openShell(ctx, c.cfg.Addr, c.cfg.SSH)
+	if err != nil {
+		return err
+	}
+	c.sh = sh
+	if _, err := sh.readUntil(ctx, c.atPrompt); err != nil {
+		c.closeLocked()
+		return fmt.Errorf("error awaiting CLI prompt on %s: %w", c.cfg.Addr, err)
+	}
+	for _, cmd := range c.pagination {
+		if _, err := c.runLine(ctx, cmd); err != nil {
+			c.closeLocked()
+			return fmt.Errorf("error disabling pagination on %s: %w", c.cfg.Addr, err)
+		}
+	}
+	return nil
+}
+
+func (c *CLI) closeLocked() error {
+	if c.sh == nil {
+		return nil
+	}
+	err := c.sh.close()
+	c.sh = nil
+	return err
+}
+
+// runLine sends one line to the CLI and returns the output up to the next
+// prompt, without the echo of the line.
+func (c *CLI) runLine(ctx context.Context, line string) (string, error) {
+	if _, err := io.WriteString(c.sh.stdin, line+"\n"); err != nil {
+		return "", err
+	}
+	raw, err := c.sh.readUntil(ctx, func(s string) bool {
+		// The echo of the line must be complete before a prompt can follow.
+		return strings.Contains(s, "\n") && c.atPrompt(s)
+	})
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(normalizeNewlines(raw), "\n")
+	lines = lines[:len(lines)-1] // Drop the prompt.
+	if len(lines) > 0 && strings.HasSuffix(strings.TrimSpace(lines[0]), strings.TrimSpace(line)) {
+		lines = lines[1:] // Drop the echo.
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// atPrompt reports whether the last line of the output is a prompt.
+func (c *CLI) atPrompt(s string) bool {
+	s = normalizeNewlines(s)
+	return c.prompt.MatchString(s[strings.LastIndex(s, "\n")+1:])
+}
+
+func (c *CLI) isError(line string) bool {
+	for _, re := range c.errors {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeNewlines(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "")
+}
+
+type cmdResult struct {
+	*binding.AbstractCommandResult
+	output, error string
+}
+
+func (r *cmdResult) Output() string {
+	return r.output
+}
+
+func (r *cmdResult) Error() string {
+	return r.error
+}
+
+// shell is an interactive SSH session, the output of which is read into a
+// buffer in the background.
+type shell struct {
+	client  *ssh.Client
+	session *ssh.Session
+	stdin   io.WriteCloser
+
+	mu      sync.Mutex // guards buf and readErr
+	buf     bytes.Buffer
+	readErr error
+	update  chan struct{} // receives when the buffer is updated
+	done    chan struct{} // closed when reading stops
+}
+
+// openShell dials an SSH server and starts an interactive shell with a
+// terminal wide enough that the output of commands does not wrap.
+func openShell(ctx context.Context, addr string, cfg *ssh.ClientConfig) (*shell, error) {
+	client, session, err := dialSession(ctx, addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	sh := &shell{
+		client:  client,
+		session: session,
+		update:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	if err := sh.start(); err != nil {
+		sh.close()
+		return nil, err
+	}
+	return sh, nil
+}
+
+func (sh *shell) start() error {
+	modes := ssh.TerminalModes{ssh.ECHO: 1}
+	if err := sh.session.RequestPty("vt100", 0, 32767, modes); err != nil {
+		return fmt.Errorf("error requesting pty: %w", err)
+	}
+	stdin, err := sh.session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := sh.session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	sh.stdin = stdin
+	if err := sh.session.Shell(); err != nil {
+		return fmt.Errorf("error starting shell: %w", err)
+	}
+	go sh.read(stdout)
+	return nil
+}
+
+func (sh *shell) read(r io.Reader) {
+	defer close(sh.done)
+	b := make([]byte, 4096)
+	for {
+		n, err := r.Read(b)
+		sh.mu.Lock()
+		sh.buf.Write(b[:n])
+		if err != nil {
+			sh.readErr = err
+		}
+		sh.mu.Unlock()
+		select {
+		case sh.update <- struct{}{}:
+		default:
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readUntil waits until the unread output matches, then consumes and returns
+// it. It returns the unread output and an error if the context is done or the
+// session ends before the output matches.
+func (sh *shell) readUntil(ctx context.Context, match func(string) bool) (string, error) {
+	for {
+		sh.mu.Lock()
+		s, readErr := sh.buf.String(), sh.readErr
+		matched := match(s)
+		if matched {
+			sh.buf.Reset()
+		}
+		sh.mu.Unlock()
+		if matched {
+			return s, nil
+		}
+		if readErr != nil {
+			return s, fmt.Errorf("session ended: %w; output: %q", readErr, s)
+		}
+		select {
+		case <-sh.update:
+		case <-sh.done:
+		case <-ctx.Done():
+			return s, fmt.Errorf("%w; output: %q", ctx.Err(), s)
+		}
+	}
+}
+
+func (sh *shell) close() error {
+	if err := sh.session.Close(); err != nil && err != io.EOF {
+		log.Warningf("error closing SSH session: %v", err)
+	}
+	return sh.client.Close()
+}
+
+// dialSession dials an SSH server and opens a session on it.
+func dialSession(ctx context.Context, addr string, cfg *ssh.ClientConfig) (*ssh.Client, *ssh.Session, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not dial SSH server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("could not dial SSH server %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Time{})
+	client := ssh.NewClient(sshConn, chans, reqs)
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("could not create SSH session: %w", err)
+	}
+	return client, session, nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/internal/fakessh"
+	"golang.org/x/crypto/ssh"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+const (
+	user     = "admin"
+	password = "hunter2"
+)
+
+func sshConfig(pass string) *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.Password(pass)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+}
+
+func startServer(t *testing.T, prompt string, handler func(string) string) *fakessh.Server {
+	t.Helper()
+	srv, err := fakessh.New(&fakessh.Config{
+		User:     user,
+		Password: password,
+		Banner:   "Welcome to the lab\r\nLast login: yesterday\r\n",
+		Prompt:   prompt,
+		Handler:  handler,
+	})
+	if err != nil {
+		t.Fatalf("fakessh.New() failed: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+func TestRunCommand(t *testing.T) {
+	srv := startServer(t, "switch1#", func(cmd string) string {
+		switch cmd {
+		case "show version":
+			return "Arista vEOS\nSoftware image version: 4.30.1F"
+		case "show bogus":
+			return "% Invalid input (at token 1: 'bogus')"
+		}
+		return ""
+	})
+	cli, err := Dial(context.Background(), &Config{
+		Addr:   srv.Addr(),
+		SSH:    sshConfig(password),
+		Vendor: opb.Device_ARISTA,
+	})
+	if err != nil {
+		t.Fatalf("Dial() got unexpected error: %v", err)
+	}
+	defer cli.Close()
+
+	tests := []struct {
+		desc, cmd, wantOutput, wantError string
+	}{{
+		desc:       "output",
+		cmd:        "show version",
+		wantOutput: "Arista vEOS\nSoftware image version: 4.30.1F\n",
+	}, {
+		desc: "no output",
+		cmd:  "configure",
+	}, {
+		desc:       "error",
+		cmd:        "show bogus",
+		wantOutput: "% Invalid input (at token 1: 'bogus')\n",
+		wantError:  "% Invalid input (at token 1: 'bogus')",
+	}, {
+		desc:       "multiple lines",
+		cmd:        "show version\nshow bogus\n",
+		wantOutput: "Arista vEOS\nSoftware image version: 4.30.1F\n% Invalid input (at token 1: 'bogus')\n",
+		wantError:  "% Invalid input (at token 1: 'bogus')",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			res, err := cli.RunCommand(context.Background(), test.cmd)
+			if err != nil {
+				t.Fatalf("RunCommand(%q) got unexpected error: %v", test.cmd, err)
+			}
+			if got := res.Output(); got != test.wantOutput {
+				t.Errorf("RunCommand(%q) got output %q, want %q", test.cmd, got, test.wantOutput)
+			}
+			if got := res.Error(); got != test.wantError {
+				t.Errorf("RunCommand(%q) got error %q, want %q", test.cmd, got, test.wantError)
+			}
+		})
+	}
+	wantCmds := []string{
+		"terminal length 0",
+		"terminal width 32767",
+		"show version",
+		"configure",
+		"show bogus",
+		"show version",
+		"show bogus",
+	}
+	if diff := cmp.Diff(wantCmds, srv.Commands()); diff != "" {
+		t.Errorf("RunCommand() sent unexpected commands (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunCommandTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	srv := startServer(t, "user@router> ", func(cmd string) string {
+		if cmd == "show slow" {
+			<-block
+		}
+		return "ok"
+	})
+	cli, err := Dial(context.Background(), &Config{
+		Addr:           srv.Addr(),
+		SSH:            sshConfig(password),
+		Vendor:         opb.Device_JUNIPER,
+		CommandTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Dial() got unexpected error: %v", err)
+	}
+	defer cli.Close()
+	if _, err := cli.RunCommand(context.Background(), "show slow"); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("RunCommand() got error %v, want deadline exceeded", err)
+	}
+	// The next command logs in to a new session.
+	res, err := cli.RunCommand(context.Background(), "show fast")
+	if err != nil {
+		t.Fatalf("RunCommand() after timeout got unexpected error: %v", err)
+	}
+	if got, want := res.Output(), "ok\n"; got != want {
+		t.Errorf("RunCommand() after timeout got output %q, want %q", got, want)
+	}
+}
+
+func TestDialErrors(t *testing.T) {
+	srv := startServer(t, "router#", nil)
+	tests := []struct {
+		desc    string
+		cfg     *Config
+		wantErr string
+	}{{
+		desc:    "no address",
+		cfg:     &Config{SSH: sshConfig(password)},
+		wantErr: "no SSH address",
+	}, {
+		desc:    "no SSH config",
+		cfg:     &Config{Addr: srv.Addr()},
+		wantErr: "no SSH client config",
+	}, {
+		desc:    "wrong password",
+		cfg:     &Config{Addr: srv.Addr(), SSH: sshConfig("wrong")},
+		wantErr: "could not dial",
+	}, {
+		desc: "no prompt",
+		cfg: &Config{
+			Addr:           srv.Addr(),
+			SSH:            sshConfig(password),
+			Vendor:         opb.Device_JUNIPER,
+			CommandTimeout: 100 * time.Millisecond,
+		},
+		wantErr: "error awaiting CLI prompt",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := Dial(context.Background(), test.cfg)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Dial() got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestVendorPrompts(t *testing.T) {
+	tests := []struct {
+		vendor  opb.Device_Vendor
+		prompts []string
+		others  []string
+	}{{
+		vendor:  opb.Device_ARISTA,
+		prompts: []string{"switch1>", "switch1#", "switch1(config-if-Et1)#", "switch1# "},
+		others:  []string{"", "show version", "Arista vEOS"},
+	}, {
+		vendor:  opb.Device_CISCO,
+		prompts: []string{"RP/0/RP0/CPU0:router#", "RP/0/RP0/CPU0:router(config)#", "router>"},
+		others:  []string{"", "Cisco IOS XR Software"},
+	}, {
+		vendor:  opb.Device_JUNIPER,
+		prompts: []string{"user@router> ", "user@router# ", "{master:0} user@router>"},
+		others:  []string{"", "Hostname: router", "[edit]"},
+	}, {
+		vendor:  opb.Device_NOKIA,
+		prompts: []string{"A:router# ", "*A:router>config>router# ", "A:admin@router# ", "B:admin@router$ "},
+		others:  []string{"", "[/]", "TiMOS-B-23.10.R1"},
+	}, {
+		vendor:  opb.Device_OPENCONFIG,
+		prompts: []string{"host$ ", "root@host:~# "},
+		others:  []string{"", "some output"},
+	}}
+	for _, test := range tests {
+		t.Run(test.vendor.String(), func(t *testing.T) {
+			prompt := cliFor(test.vendor).prompt
+			for _, p := range test.prompts {
+				if !prompt.MatchString(p) {
+					t.Errorf("prompt %v does not match %q", prompt, p)
+				}
+			}
+			for _, o := range test.others {
+				if prompt.MatchString(o) {
+					t.Errorf("prompt %v matches %q", prompt, o)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcli
+
+import (
+	"regexp"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// vendorCLI is the CLI behavior of a vendor.
+type vendorCLI struct {
+	// prompt matches the last line of output when the CLI awaits a command.
+	prompt *regexp.Regexp
+	// pagination lists the commands that disable pagination of output.
+	pagination []string
+	// errors match the lines of output that report a failed command.
+	errors []*regexp.Regexp
+}
+
+var (
+	// defaultCLI is the CLI behavior of vendors without an entry in vendorCLIs.
+	defaultCLI = &vendorCLI{
+		prompt: regexp.MustCompile(`[>#$%]\s*$`),
+	}
+
+	vendorCLIs = map[opb.Device_Vendor]*vendorCLI{
+		// Matches "switch>", "switch#" and "switch(config-if-Et1)#".
+		opb.Device_ARISTA: {
+			prompt:     regexp.MustCompile(`^[\w.\-@:/]+(\([\w.\-/ ]+\))?[>#]\s*$`),
+			pagination: []string{"terminal length 0", "terminal width 32767"},
+			errors:     []*regexp.Regexp{regexp.MustCompile(`^% .*`)},
+		},
+		// Matches "router#" and "RP/0/RP0/CPU0:router(config)#".
+		opb.Device_CISCO: {
+			prompt:     regexp.MustCompile(`^[\w.\-@:/]+(\([\w.\-/ ]+\))?[>#]\s*$`),
+			pagination: []string{"terminal length 0", "terminal width 511"},
+			errors: []*regexp.Regexp{
+				regexp.MustCompile(`^% .*`),
+				regexp.MustCompile(`^\s*\^$`),
+			},
+		},
+		// Matches "user@router>" and "user@router#".
+		opb.Device_JUNIPER: {
+			prompt:     regexp.MustCompile(`^(\{\w+(:\w+)?\}\s*)?[\w.\-]+@[\w.\-]+[>#%]\s*$`),
+			pagination: []string{"set cli screen-length 0", "set cli screen-width 0"},
+			errors: []*regexp.Regexp{
+				regexp.MustCompile(`^error: .*`),
+				regexp.MustCompile(`^syntax error.*`),
+				regexp.MustCompile(`^unknown command\..*`),
+			},
+		},
+		// Matches the classic CLI prompt "A:router#" and the MD-CLI prompt
+		// "A:admin@router#", with an optional leading "*" for unsaved changes.
+		opb.Device_NOKIA: {
+			prompt:     regexp.MustCompile(`^\*?(\(\w+\))?[AB]:[\w.\-@]+(>[\w.\-]+)*[#$]\s*$`),
+			pagination: []string{"environment more false"},
+			errors: []*regexp.Regexp{
+				regexp.MustCompile(`^(MINOR|MAJOR|CRITICAL): .*`),
+				regexp.MustCompile(`^Error: .*`),
+			},
+		},
+	}
+)
+
+// cliFor returns the CLI behavior of the specified vendor.
+func cliFor(v opb.Device_Vendor) *vendorCLI {
+	if c, ok := vendorCLIs[v]; ok {
+		return c
+	}
+	return defaultCLI
+}
@@ -16,12 +16,15 @@
 package cli
 
 import (
+	"errors"
 	"testing"
 
 	"golang.org/x/net/context"
 
 	"github.com/openconfig/ondatra/binding"
 	"github.com/openconfig/ondatra/internal/events"
+	"github.com/openconfig/ondatra/internal/rawapis"
+	"github.com/openconfig/ondatra/internal/trace"
 )
 
 // New constructs a new instance of the CLI API.
@@ -42,7 +45,7 @@ type CLI struct {
 func (c *CLI) Run(t testing.TB, cmd string) string {
 	t.Helper()
 	t = events.ActionStarted(t, "Running CLI command on %s", c.dut)
-	res, err := c.run(context.Background(), cmd)
+	res, err := c.run(t, cmd)
 	if err != nil {
 		t.Fatalf("Run(t, %q) on %s: %v", cmd, c.dut, err)
 	}
@@ -59,15 +62,24 @@ func (c *CLI) Run(t testing.TB, cmd string) string {
 func (c *CLI) RunResult(t testing.TB, cmd string) binding.CommandResult {
 	t.Helper()
 	t = events.ActionStarted(t, "Running CLI command on %s", c.dut)
-	res, err := c.run(context.Background(), cmd)
+	res, err := c.run(t, cmd)
 	if err != nil {
 		t.Fatalf("RunResult(t, %q) on %s: %v", cmd, c.dut, err)
 	}
 	return res
 }
 
-func (c *CLI) run(ctx context.Context, cmd string) (binding.CommandResult, error) {
-	cli, err := c.dut.DialCLI(ctx)
+func (c *CLI) run(t testing.TB, cmd string) (res binding.CommandResult, rerr error) {
+	span := trace.Start(t, "cli.Run", "device", c.dut.Name(), "command", cmd)
+	defer func() {
+		if rerr == nil && res != nil && res.Error() != "" {
+			span.End(errors.New(res.Error()))
+		} else {
+			span.End(rerr)
+		}
+	}()
+	ctx := context.Background()
+	cli, err := rawapis.NewCLI(ctx, c.dut)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,218 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openconfig/ondatra/cli/textfsm"
+	"github.com/openconfig/ondatra/internal/events"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// Parser parses the output of a CLI command into a Go value.
+type Parser interface {
+	// Command returns the command to run on the device in place of the
+	// specified command, for example with a suffix that requests JSON output.
+	Command(cmd string) string
+	// Parse parses the output of the command into the value pointed to by out.
+	Parse(output string, out any) error
+}
+
+// JSONParser returns a parser for vendors that support JSON output: it
+// appends the suffix to the command and unmarshals the output as JSON.
+func JSONParser(suffix string) Parser {
+	return &jsonParser{suffix: suffix}
+}
+
+type jsonParser struct {
+	suffix string
+}
+
+func (p *jsonParser) Command(cmd string) string {
+	return cmd + p.suffix
+}
+
+func (p *jsonParser) Parse(output string, out any) error {
+	return json.Unmarshal([]byte(output), out)
+}
+
+// TemplateParser returns a parser that parses the output of the command
+// with a TextFSM template. The records of the template are unmarshalled into
+// the value pointed to by out as JSON objects, with keys that are the names of
+// the template values, so out may point to a slice of structs or to a single
+// struct, if the template emits exactly one record. The value of a List value
+// is a JSON array of strings, and the value of any other value is a string.
+func TemplateParser(template string) (Parser, error) {
+	tmpl, err := textfsm.Parse(template)
+	if err != nil {
+		return nil, err
+	}
+	return &templateParser{tmpl: tmpl}, nil
+}
+
+type templateParser struct {
+	tmpl *textfsm.Template
+}
+
+func (p *templateParser) Command(cmd string) string {
+	return cmd
+}
+
+func (p *templateParser) Parse(output string, out any) error {
+	recs, err := p.tmpl.ParseText(output)
+	if err != nil {
+		return err
+	}
+	var v any = recs
+	if rv := reflect.ValueOf(out); rv.Kind() == reflect.Pointer && rv.Elem().Kind() == reflect.Struct {
+		if len(recs) != 1 {
+			return fmt.Errorf("template emitted %d records, cannot parse into a single %T", len(recs), rv.Elem().Interface())
+		}
+		v = recs[0]
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+type registration struct {
+	vendor opb.Device_Vendor
+	cmdRE  *regexp.Regexp
+	parser Parser
+}
+
+var (
+	mu            sync.RWMutex
+	registrations []*registration
+)
+
+// RegisterParser registers a parser for the commands of a vendor that match
+// the regular expression, which is anchored to match the entire command.
+// A parser registered later takes precedence over one registered earlier, so
+// that teams may override the built-in parsers.
+func RegisterParser(vendor opb.Device_Vendor, cmdRegex string, p Parser) error {
+	re, err := regexp.Compile("^(?:" + cmdRegex + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid command regexp: %w", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	registrations = append(registrations, &registration{vendor: vendor, cmdRE: re, parser: p})
+	return nil
+}
+
+// RegisterTemplate registers a TextFSM template for the commands of a
+// vendor that match the regular expression, as with [RegisterParser].
+func RegisterTemplate(vendor opb.Device_Vendor, cmdRegex, template string) error {
+	p, err := TemplateParser(template)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return RegisterParser(vendor, cmdRegex, p)
+}
+
+// ParserFor returns the parser registered for the command of a vendor.
+func ParserFor(vendor opb.Device_Vendor, cmd string) (Parser, error) {
+	cmd = strings.Join(strings.Fields(cmd), " ")
+	mu.RLock()
+	defer mu.RUnlock()
+	for i := len(registrations) - 1; i >= 0; i-- {
+		if r := registrations[i]; r.vendor == vendor && r.cmdRE.MatchString(cmd) {
+			return r.parser, nil
+		}
+	}
+	return nil, fmt.Errorf("no parser registered for %v command %q", vendor, cmd)
+}
+
+//go:embed templates/*.textfsm
+var templates embed.FS
+
+// builtinTemplates maps the names of the built-in template files to the
+// vendor and the command regexp of each template.
+var builtinTemplates = map[string]struct {
+	vendor   opb.Device_Vendor
+	cmdRegex string
+}{
+	"cisco_iosxr_show_version.textfsm": {
+		vendor:   opb.Device_CISCO,
+		cmdRegex: `sh(ow?)? ver(s(i(on?)?)?)?`,
+	},
+	"cisco_iosxr_show_ipv4_interface_brief.textfsm": {
+		vendor:   opb.Device_CISCO,
+		cmdRegex: `sh(ow?)? ipv4 int(e(r(f(a(ce?)?)?)?)?)? br(i(ef?)?)?`,
+	},
+	"nokia_sros_show_port.textfsm": {
+		vendor:   opb.Device_NOKIA,
+		cmdRegex: `show port`,
+	},
+}
+
+func init() {
+	// Arista EOS and Junos can render the output of most commands as JSON.
+	for vendor, suffix := range map[opb.Device_Vendor]string{
+		opb.Device_ARISTA:  " | json",
+		opb.Device_JUNIPER: " | display json",
+	} {
+		if err := RegisterParser(vendor, `.*`, JSONParser(suffix)); err != nil {
+			panic(err)
+		}
+	}
+	for name, bt := range builtinTemplates {
+		b, err := templates.ReadFile("templates/" + name)
+		if err != nil {
+			panic(err)
+		}
+		if err := RegisterTemplate(bt.vendor, bt.cmdRegex, string(b)); err != nil {
+			panic(fmt.Sprintf("invalid built-in template %s: %v", name, err))
+		}
+	}
+}
+
+// RunParsed runs the specified CLI command on the DUT and parses its output
+// into the value pointed to by out, with the parser registered for the vendor
+// of the DUT and the command. Like [CLI.Run], RunParsed fails fatally if the
+// command reports an error, and it also fails fatally if the output cannot be
+// parsed. The command that runs may differ from the specified command, for
+// example with a suffix that requests JSON output.
+func (c *CLI) RunParsed(t testing.TB, cmd string, out any) {
+	t.Helper()
+	t = events.ActionStarted(t, "Running parsed CLI command on %s", c.dut)
+	p, err := ParserFor(c.dut.Vendor(), cmd)
+	if err != nil {
+		t.Fatalf("RunParsed(t, %q) on %s: %v", cmd, c.dut, err)
+	}
+	runCmd := p.Command(cmd)
+	res, err := c.run(t, runCmd)
+	if err != nil {
+		t.Fatalf("RunParsed(t, %q) on %s: %v", cmd, c.dut, err)
+	}
+	if res.Error() != "" {
+		t.Fatalf("RunParsed(t, %q) on %s: %v", cmd, c.dut, res.Error())
+	}
+	if err := p.Parse(res.Output(), out); err != nil {
+		t.Fatalf("RunParsed(t, %q) on %s: error parsing output of %q: %v", cmd, c.dut, runCmd, err)
+	}
+}
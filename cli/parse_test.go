@@ -0,0 +1,241 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/fakebind"
+	"github.com/openconfig/testt"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the built-in templates")
+
+// TestBuiltinTemplates parses testdata/<template>.txt with each built-in
+// template and compares the records to testdata/<template>.json.
+func TestBuiltinTemplates(t *testing.T) {
+	for name := range builtinTemplates {
+		t.Run(name, func(t *testing.T) {
+			base := filepath.Join("testdata", strings.TrimSuffix(name, ".textfsm"))
+			input, err := os.ReadFile(base + ".txt")
+			if err != nil {
+				t.Fatalf("ReadFile() failed: %v", err)
+			}
+			tmpl, err := templates.ReadFile("templates/" + name)
+			if err != nil {
+				t.Fatalf("ReadFile() failed: %v", err)
+			}
+			p, err := TemplateParser(string(tmpl))
+			if err != nil {
+				t.Fatalf("TemplateParser() got unexpected error: %v", err)
+			}
+			var got []map[string]any
+			if err := p.Parse(string(input), &got); err != nil {
+				t.Fatalf("Parse() got unexpected error: %v", err)
+			}
+			gotJSON, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatalf("MarshalIndent() failed: %v", err)
+			}
+			gotJSON = append(gotJSON, '\n')
+			if *update {
+				if err := os.WriteFile(base+".json", gotJSON, 0644); err != nil {
+					t.Fatalf("WriteFile() failed: %v", err)
+				}
+				return
+			}
+			wantJSON, err := os.ReadFile(base + ".json")
+			if err != nil {
+				t.Fatalf("ReadFile() failed: %v", err)
+			}
+			if diff := cmp.Diff(string(wantJSON), string(gotJSON)); diff != "" {
+				t.Errorf("Parse() got unexpected records (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParserFor(t *testing.T) {
+	tests := []struct {
+		desc    string
+		vendor  opb.Device_Vendor
+		cmd     string
+		wantCmd string
+		wantErr string
+	}{{
+		desc:    "arista json",
+		vendor:  opb.Device_ARISTA,
+		cmd:     "show interfaces status",
+		wantCmd: "show interfaces status | json",
+	}, {
+		desc:    "juniper json",
+		vendor:  opb.Device_JUNIPER,
+		cmd:     "show version",
+		wantCmd: "show version | display json",
+	}, {
+		desc:    "cisco abbreviated",
+		vendor:  opb.Device_CISCO,
+		cmd:     "sh  ver",
+		wantCmd: "sh  ver",
+	}, {
+		desc:    "no parser",
+		vendor:  opb.Device_CISCO,
+		cmd:     "show running-config",
+		wantErr: "no parser registered",
+	}, {
+		desc:    "partial match",
+		vendor:  opb.Device_CISCO,
+		cmd:     "show version brief",
+		wantErr: "no parser registered",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			p, err := ParserFor(test.vendor, test.cmd)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("ParserFor() got error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParserFor() got unexpected error: %v", err)
+			}
+			if got := p.Command(test.cmd); got != test.wantCmd {
+				t.Errorf("Command() got %q, want %q", got, test.wantCmd)
+			}
+		})
+	}
+}
+
+type fakeCommandRecorder struct {
+	*binding.AbstractCLIClient
+	cmds   []string
+	output string
+}
+
+func (f *fakeCommandRecorder) RunCommand(_ context.Context, cmd string) (binding.CommandResult, error) {
+	f.cmds = append(f.cmds, cmd)
+	return &fakeCommandResult{output: f.output}, nil
+}
+
+func TestRunParsed(t *testing.T) {
+	client := &fakeCommandRecorder{}
+	dut := &fakebind.DUT{
+		AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{Name: "fakeDUT", Vendor: opb.Device_OPENCONFIG}},
+		DialCLIFn: func(context.Context) (binding.CLIClient, error) {
+			return client, nil
+		},
+	}
+	const template = `
+Value Required NAME (\S+)
+Value ADDRESS (\S+)
+Value List VLANS (\d+)
+
+Start
+  ^interface ${NAME} -> Continue
+  ^\s+address ${ADDRESS}
+  ^\s+vlan ${VLANS}
+  ^! -> Record
+`
+	if err := RegisterTemplate(opb.Device_OPENCONFIG, `show interfaces?( \S+)?`, template); err != nil {
+		t.Fatalf("RegisterTemplate() got unexpected error: %v", err)
+	}
+	type intf struct {
+		Name    string
+		Address string
+		VLANs   []string
+	}
+
+	t.Run("slice", func(t *testing.T) {
+		client.output = "interface eth0\n  address 192.0.2.1\n  vlan 10\n  vlan 20\n!\ninterface eth1\n!\n"
+		var got []intf
+		New(dut).RunParsed(t, "show interfaces", &got)
+		want := []intf{
+			{Name: "eth0", Address: "192.0.2.1", VLANs: []string{"10", "20"}},
+			{Name: "eth1", VLANs: []string{}},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("RunParsed() got unexpected output (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		client.output = "interface eth0\n  address 192.0.2.1\n!\n"
+		var got intf
+		New(dut).RunParsed(t, "show interface eth0", &got)
+		if want := (intf{Name: "eth0", Address: "192.0.2.1", VLANs: []string{}}); !cmp.Equal(want, got) {
+			t.Errorf("RunParsed() got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("struct with multiple records", func(t *testing.T) {
+		client.output = "interface eth0\n!\ninterface eth1\n!\n"
+		var got intf
+		gotErr := testt.ExpectFatal(t, func(t testing.TB) {
+			New(dut).RunParsed(t, "show interfaces", &got)
+		})
+		if want := "emitted 2 records"; !strings.Contains(gotErr, want) {
+			t.Errorf("RunParsed() got error %q, want %q", gotErr, want)
+		}
+	})
+
+	t.Run("no parser", func(t *testing.T) {
+		gotErr := testt.ExpectFatal(t, func(t testing.TB) {
+			New(dut).RunParsed(t, "show version", &struct{}{})
+		})
+		if want := "no parser registered"; !strings.Contains(gotErr, want) {
+			t.Errorf("RunParsed() got error %q, want %q", gotErr, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		if err := RegisterParser(opb.Device_OPENCONFIG, `show version`, JSONParser(" | json")); err != nil {
+			t.Fatalf("RegisterParser() got unexpected error: %v", err)
+		}
+		client.cmds = nil
+		client.output = `{"version": "1.2.3", "uptime": 3600}`
+		var got struct {
+			Version string
+			Uptime  int
+		}
+		New(dut).RunParsed(t, "show version", &got)
+		if got.Version != "1.2.3" || got.Uptime != 3600 {
+			t.Errorf("RunParsed() got %+v, want version 1.2.3 and uptime 3600", got)
+		}
+		if want := []string{"show version | json"}; !cmp.Equal(client.cmds, want) {
+			t.Errorf("RunParsed() ran commands %q, want %q", client.cmds, want)
+		}
+	})
+}
+
+func TestRegisterErrors(t *testing.T) {
+	if err := RegisterParser(opb.Device_CISCO, `(`, JSONParser("")); err == nil {
+		t.Errorf("RegisterParser() with invalid regexp got no error")
+	}
+	if err := RegisterTemplate(opb.Device_CISCO, `show foo`, "Start\n"); err == nil {
+		t.Errorf("RegisterTemplate() with invalid template got no error")
+	}
+}
@@ -0,0 +1,452 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package textfsm parses semi-structured text, such as the output of CLI
+// commands, with TextFSM templates.
+//
+// The package supports the template syntax of the Python TextFSM library:
+// Value definitions with the Filldown, Fillup, Key, List and Required options;
+// states of rules; the Next and Continue line actions; the Record, NoRecord,
+// Clear and Clearall record actions; state transitions; and the Error action.
+// Regular expressions use the RE2 syntax of the Go regexp package, so
+// templates that rely on Python-only regular expression features, such as
+// lookarounds, are not supported.
+package textfsm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Reserved state names.
+const (
+	startState = "Start"
+	endState   = "End"
+	eofState   = "EOF"
+)
+
+// Template is a parsed TextFSM template.
+type Template struct {
+	values []*valueDef
+	states map[string][]*rule
+	hasEOF bool
+}
+
+type valueDef struct {
+	name     string
+	regex    string
+	filldown bool
+	fillup   bool
+	list     bool
+	required bool
+}
+
+type lineAction int
+
+const (
+	lineNext lineAction = iota
+	lineContinue
+	lineError
+)
+
+type recordAction int
+
+const (
+	recordNone recordAction = iota
+	recordRecord
+	recordClear
+	recordClearAll
+)
+
+type rule struct {
+	line     int
+	re       *regexp.Regexp
+	lineOp   lineAction
+	recordOp recordAction
+	newState string
+	errMsg   string
+}
+
+var (
+	valueRE = regexp.MustCompile(`^Value\s+(?:([\w,]+)\s+)?(\w+)\s+(\(.*\))\s*$`)
+	stateRE = regexp.MustCompile(`^(\w+)\s*$`)
+	varRE   = regexp.MustCompile(`\$\$|\$\{(\w+)\}|\$(\w+)`)
+)
+
+// Parse parses a TextFSM template.
+func Parse(template string) (*Template, error) {
+	tmpl := &Template{states: make(map[string][]*rule)}
+	valueNames := make(map[string]bool)
+	var state string
+	inValues := true
+	sc := bufio.NewScanner(strings.NewReader(template))
+	for lineNum := 1; sc.Scan(); lineNum++ {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if inValues {
+			if trimmed == "" {
+				if len(tmpl.values) > 0 {
+					inValues = false
+				}
+				continue
+			}
+			v, err := parseValue(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			if valueNames[v.name] {
+				return nil, fmt.Errorf("line %d: duplicate value %q", lineNum, v.name)
+			}
+			valueNames[v.name] = true
+			tmpl.values = append(tmpl.values, v)
+			continue
+		}
+		switch {
+		case trimmed == "":
+			state = ""
+		case state == "":
+			m := stateRE.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("line %d: invalid state name %q", lineNum, trimmed)
+			}
+			state = m[1]
+			if _, ok := tmpl.states[state]; ok {
+				return nil, fmt.Errorf("line %d: duplicate state %q", lineNum, state)
+			}
+			if state == endState {
+				return nil, fmt.Errorf("line %d: state %q is reserved", lineNum, endState)
+			}
+			tmpl.states[state] = nil
+			if state == eofState {
+				tmpl.hasEOF = true
+			}
+		default:
+			r, err := tmpl.parseRule(trimmed, lineNum, valueNames)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			tmpl.states[state] = append(tmpl.states[state], r)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(tmpl.values) == 0 {
+		return nil, errors.New("template defines no values")
+	}
+	if _, ok := tmpl.states[startState]; !ok {
+		return nil, fmt.Errorf("template has no %q state", startState)
+	}
+	for name, rules := range tmpl.states {
+		for _, r := range rules {
+			if r.newState == "" || r.newState == endState {
+				continue
+			}
+			if _, ok := tmpl.states[r.newState]; !ok {
+				return nil, fmt.Errorf("line %d: rule of state %q transitions to undefined state %q", r.line, name, r.newState)
+			}
+		}
+	}
+	return tmpl, nil
+}
+
+func parseValue(line string) (*valueDef, error) {
+	m := valueRE.FindStringSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("invalid value definition %q", line)
+	}
+	v := &valueDef{name: m[2], regex: m[3]}
+	if _, err := regexp.Compile(v.regex); err != nil {
+		return nil, fmt.Errorf("invalid regexp of value %q: %w", v.name, err)
+	}
+	if m[1] == "" {
+		return v, nil
+	}
+	for _, opt := range strings.Split(m[1], ",") {
+		switch opt {
+		case "Filldown":
+			v.filldown = true
+		case "Fillup":
+			v.fillup = true
+		case "List":
+			v.list = true
+		case "Required":
+			v.required = true
+		case "Key":
+		default:
+			return nil, fmt.Errorf("unknown option %q of value %q", opt, v.name)
+		}
+	}
+	return v, nil
+}
+
+func (tmpl *Template) parseRule(line string, lineNum int, valueNames map[string]bool) (*rule, error) {
+	if !strings.HasPrefix(line, "^") {
+		return nil, fmt.Errorf("rule %q does not start with '^'", line)
+	}
+	pattern, action := line, ""
+	if i := strings.LastIndex(line, " -> "); i >= 0 {
+		pattern, action = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+len(" -> "):])
+	}
+	var substErr error
+	expanded := varRE.ReplaceAllStringFunc(pattern, func(s string) string {
+		if s == "$$" {
+			return "$"
+		}
+		name := strings.Trim(s, "${}")
+		if !valueNames[name] {
+			substErr = fmt.Errorf("rule %q references undefined value %q", line, name)
+			return s
+		}
+		for _, v := range tmpl.values {
+			if v.name == name {
+				return fmt.Sprintf("(?P<%s>%s)", name, v.regex[1:len(v.regex)-1])
+			}
+		}
+		return s
+	})
+	if substErr != nil {
+		return nil, substErr
+	}
+	re, err := regexp.Compile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regexp of rule %q: %w", line, err)
+	}
+	r := &rule{line: lineNum, re: re}
+	if err := r.parseAction(action); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+var (
+	lineActions = map[string]lineAction{
+		"Next":     lineNext,
+		"Continue": lineContinue,
+	}
+	recordActions = map[string]recordAction{
+		"NoRecord": recordNone,
+		"Record":   recordRecord,
+		"Clear":    recordClear,
+		"Clearall": recordClearAll,
+	}
+)
+
+// parseAction parses an action of the form "LineOp.RecordOp NewState", where
+// each part is optional, or "Error [message]".
+func (r *rule) parseAction(action string) error {
+	if action == "" {
+		return nil
+	}
+	if rest, ok := strings.CutPrefix(action, "Error"); ok && (rest == "" || rest[0] == ' ') {
+		r.lineOp = lineError
+		r.errMsg = strings.Trim(strings.TrimSpace(rest), `"`)
+		return nil
+	}
+	fields := strings.Fields(action)
+	if len(fields) > 2 {
+		return fmt.Errorf("invalid action %q", action)
+	}
+	ops := fields[0]
+	lineOp, recordOp, _ := strings.Cut(ops, ".")
+	lop, isLineOp := lineActions[lineOp]
+	rop, isRecordOp := recordActions[lineOp]
+	switch {
+	case isLineOp && recordOp != "":
+		if rop, isRecordOp = recordActions[recordOp]; !isRecordOp {
+			return fmt.Errorf("invalid record action %q in action %q", recordOp, action)
+		}
+		r.lineOp, r.recordOp = lop, rop
+	case isLineOp:
+		r.lineOp = lop
+	case isRecordOp && recordOp == "":
+		r.recordOp = rop
+	case len(fields) == 1 && stateRE.MatchString(ops):
+		r.newState = ops
+		return nil
+	default:
+		return fmt.Errorf("invalid action %q", action)
+	}
+	if len(fields) == 2 {
+		if !stateRE.MatchString(fields[1]) {
+			return fmt.Errorf("invalid state %q in action %q", fields[1], action)
+		}
+		if r.lineOp == lineContinue {
+			return fmt.Errorf("action %q may not combine Continue with a state transition", action)
+		}
+		r.newState = fields[1]
+	}
+	return nil
+}
+
+// Record is a record of parsed values, keyed by value name. The value of a
+// List value is a []string, and the value of any other value is a string.
+type Record map[string]any
+
+// ParseText parses text with the template and returns the records that the
+// template emits.
+func (tmpl *Template) ParseText(text string) ([]Record, error) {
+	p := &parser{tmpl: tmpl, cur: make([]any, len(tmpl.values))}
+	p.clearAll()
+	state := startState
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Buffer(nil, 1<<20)
+lines:
+	for lineNum := 1; sc.Scan(); lineNum++ {
+		line := strings.TrimRight(sc.Text(), "\r")
+		for _, r := range tmpl.states[state] {
+			m := r.re.FindStringSubmatchIndex(line)
+			if m == nil {
+				continue
+			}
+			if r.lineOp == lineError {
+				msg := r.errMsg
+				if msg == "" {
+					msg = "state Error raised"
+				}
+				return nil, fmt.Errorf("%s; rule at template line %d matched input line %d: %q", msg, r.line, lineNum, line)
+			}
+			p.assign(r.re, line, m)
+			switch r.recordOp {
+			case recordRecord:
+				p.record()
+			case recordClear:
+				p.clear()
+			case recordClearAll:
+				p.clearAll()
+			}
+			if r.newState != "" {
+				state = r.newState
+			}
+			if state == endState {
+				break lines
+			}
+			if r.lineOp == lineNext {
+				break
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if state != endState && !tmpl.hasEOF {
+		p.record()
+	}
+	return p.records, nil
+}
+
+type parser struct {
+	tmpl    *Template
+	cur     []any
+	records []Record
+}
+
+func (p *parser) assign(re *regexp.Regexp, line string, m []int) {
+	for i, name := range re.SubexpNames() {
+		if name == "" || m[2*i] < 0 {
+			continue
+		}
+		val := line[m[2*i]:m[2*i+1]]
+		for j, v := range p.tmpl.values {
+			if v.name != name {
+				continue
+			}
+			if v.list {
+				p.cur[j] = append(p.cur[j].([]string), val)
+			} else {
+				p.cur[j] = val
+			}
+			if v.fillup {
+				p.fillUp(j, val)
+			}
+		}
+	}
+}
+
+// fillUp sets the value in previous records in which it is empty, up to the
+// most recent record in which it is not.
+func (p *parser) fillUp(j int, val string) {
+	name := p.tmpl.values[j].name
+	for i := len(p.records) - 1; i >= 0; i-- {
+		if p.records[i][name] != "" {
+			return
+		}
+		p.records[i][name] = val
+	}
+}
+
+func (p *parser) record() {
+	empty := true
+	for j, v := range p.tmpl.values {
+		if isEmpty(p.cur[j]) {
+			if v.required {
+				p.clear()
+				return
+			}
+		} else {
+			empty = false
+		}
+	}
+	if empty {
+		return
+	}
+	rec := make(Record, len(p.tmpl.values))
+	for j, v := range p.tmpl.values {
+		if v.list {
+			rec[v.name] = append([]string{}, p.cur[j].([]string)...)
+		} else {
+			rec[v.name] = p.cur[j]
+		}
+	}
+	p.records = append(p.records, rec)
+	p.clear()
+}
+
+func isEmpty(val any) bool {
+	switch val := val.(type) {
+	case string:
+		return val == ""
+	case []string:
+		return len(val) == 0
+	}
+	return true
+}
+
+// clear clears the values that are not Filldown.
+func (p *parser) clear() {
+	for j, v := range p.tmpl.values {
+		if !v.filldown {
+			p.cur[j] = zero(v)
+		}
+	}
+}
+
+func (p *parser) clearAll() {
+	for j, v := range p.tmpl.values {
+		p.cur[j] = zero(v)
+	}
+}
+
+func zero(v *valueDef) any {
+	if v.list {
+		return []string{}
+	}
+	return ""
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textfsm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseText(t *testing.T) {
+	tests := []struct {
+		desc     string
+		template string
+		text     string
+		want     []Record
+	}{{
+		desc: "record at EOF",
+		template: `Value VERSION (\S+)
+Value MODEL (\S+)
+
+Start
+  ^Version: ${VERSION}
+  ^Model: ${MODEL}
+`,
+		text: "Version: 1.2\nModel: X1\n",
+		want: []Record{{"VERSION": "1.2", "MODEL": "X1"}},
+	}, {
+		desc: "explicit EOF state suppresses record",
+		template: `Value VERSION (\S+)
+
+Start
+  ^Version: ${VERSION}
+
+EOF
+`,
+		text: "Version: 1.2\n",
+	}, {
+		desc: "filldown and required",
+		template: `Value Filldown SLOT (\d+)
+Value Required PORT (\S+)
+Value STATE (up|down)
+
+Start
+  ^Slot ${SLOT}
+  ^\s+${PORT} ${STATE} -> Record
+`,
+		text: "Slot 1\n  p1 up\n  p2 down\nSlot 2\n  p1 up\n",
+		want: []Record{
+			{"SLOT": "1", "PORT": "p1", "STATE": "up"},
+			{"SLOT": "1", "PORT": "p2", "STATE": "down"},
+			{"SLOT": "2", "PORT": "p1", "STATE": "up"},
+		},
+	}, {
+		desc: "fillup",
+		template: `Value NAME (\S+)
+Value Fillup GROUP (\S+)
+
+Start
+  ^name ${NAME} -> Record
+  ^group ${GROUP}
+`,
+		text: "name a\nname b\ngroup g1\nname c\ngroup g2\nname d\n",
+		want: []Record{
+			{"NAME": "a", "GROUP": "g1"},
+			{"NAME": "b", "GROUP": "g1"},
+			{"NAME": "c", "GROUP": "g1"},
+			{"NAME": "d", "GROUP": "g2"},
+		},
+	}, {
+		desc: "list and continue",
+		template: `Value Required NAME (\S+)
+Value List MEMBERS (\S+)
+
+Start
+  ^group ${NAME} -> Continue
+  ^group \S+ members ${MEMBERS} -> Continue
+  ^group \S+ members \S+ ${MEMBERS}
+  ^end -> Record
+`,
+		text: "group g1 members m1 m2\nend\ngroup g2\nend\n",
+		want: []Record{
+			{"NAME": "g1", "MEMBERS": []string{"m1", "m2"}},
+			{"NAME": "g2", "MEMBERS": []string{}},
+		},
+	}, {
+		desc: "states and clear",
+		template: `Value Filldown HOST (\S+)
+Value NAME (\S+)
+
+Start
+  ^host ${HOST}
+  ^begin -> Table
+
+Table
+  ^row ${NAME} -> Record
+  ^reset -> Clearall
+  ^done -> Start
+`,
+		text: "host h1\nrow ignored-before-begin\nbegin\nrow r1\nreset\nrow r2\ndone\nrow r3\n",
+		want: []Record{
+			{"HOST": "h1", "NAME": "r1"},
+			{"HOST": "", "NAME": "r2"},
+		},
+	}, {
+		desc: "end state",
+		template: `Value NAME (\S+)
+
+Start
+  ^name ${NAME} -> Record
+  ^stop -> End
+`,
+		text: "name a\nstop\nname b\n",
+		want: []Record{{"NAME": "a"}},
+	}, {
+		desc: "record with state transition and escaped dollar",
+		template: `Value NAME (\S+)
+
+Start
+  ^name ${NAME}$$ -> Record Names
+
+Names
+  ^name ${NAME}$$ -> Next.Record
+`,
+		text: "name a\nname b\nname c d\n",
+		want: []Record{{"NAME": "a"}, {"NAME": "b"}},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			tmpl, err := Parse(test.template)
+			if err != nil {
+				t.Fatalf("Parse() got unexpected error: %v", err)
+			}
+			got, err := tmpl.ParseText(test.text)
+			if err != nil {
+				t.Fatalf("ParseText() got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ParseText() got unexpected records (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseTextError(t *testing.T) {
+	tmpl, err := Parse(`Value NAME (\S+)
+
+Start
+  ^name ${NAME}
+  ^% -> Error "invalid input"
+`)
+	if err != nil {
+		t.Fatalf("Parse() got unexpected error: %v", err)
+	}
+	_, err = tmpl.ParseText("name a\n% bad command\n")
+	if want := `invalid input; rule at template line 5 matched input line 2: "% bad command"`; err == nil || err.Error() != want {
+		t.Errorf("ParseText() got error %v, want %q", err, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		desc     string
+		template string
+		wantErr  string
+	}{{
+		desc:     "no values",
+		template: "Start\n  ^foo\n",
+		wantErr:  "invalid value definition",
+	}, {
+		desc:     "unknown option",
+		template: "Value Bogus NAME (\\S+)\n\nStart\n",
+		wantErr:  `unknown option "Bogus"`,
+	}, {
+		desc:     "duplicate value",
+		template: "Value NAME (\\S+)\nValue NAME (\\S+)\n\nStart\n",
+		wantErr:  `duplicate value "NAME"`,
+	}, {
+		desc:     "no start state",
+		template: "Value NAME (\\S+)\n\nOther\n  ^${NAME}\n",
+		wantErr:  `no "Start" state`,
+	}, {
+		desc:     "undefined value",
+		template: "Value NAME (\\S+)\n\nStart\n  ^${OTHER}\n",
+		wantErr:  `undefined value "OTHER"`,
+	}, {
+		desc:     "undefined state",
+		template: "Value NAME (\\S+)\n\nStart\n  ^${NAME} -> Other\n",
+		wantErr:  `undefined state "Other"`,
+	}, {
+		desc:     "continue with transition",
+		template: "Value NAME (\\S+)\n\nStart\n  ^${NAME} -> Continue Start\n",
+		wantErr:  "may not combine Continue",
+	}, {
+		desc:     "invalid record action",
+		template: "Value NAME (\\S+)\n\nStart\n  ^${NAME} -> Next.Bogus\n",
+		wantErr:  `invalid record action "Bogus"`,
+	}, {
+		desc:     "rule without caret",
+		template: "Value NAME (\\S+)\n\nStart\n  ${NAME}\n",
+		wantErr:  "does not start with '^'",
+	}, {
+		desc:     "reserved state",
+		template: "Value NAME (\\S+)\n\nStart\n  ^${NAME}\n\nEnd\n",
+		wantErr:  `state "End" is reserved`,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := Parse(test.template)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Parse() got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/internal/events"
+	"github.com/openconfig/ondatra/internal/rawapis"
+	"github.com/openconfig/ondatra/internal/trace"
+	"github.com/openconfig/ondatra/secrets"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// runningConfigCmds are the CLI commands that show the running-config of each
+// vendor, with which checkpoints are emulated for DUTs that do not implement
+// binding.ConfigCheckpointer.
+var runningConfigCmds = map[opb.Device_Vendor]string{
+	opb.Device_ARISTA:  "show running-config",
+	opb.Device_CISCO:   "show running-config",
+	opb.Device_JUNIPER: "show configuration",
+	opb.Device_NOKIA:   "admin show configuration",
+}
+
+// runningConfigHeaders match the lines that each vendor prints before the
+// running-config, which are not config and must not be pushed back on rollback.
+var runningConfigHeaders = map[opb.Device_Vendor][]*regexp.Regexp{
+	opb.Device_ARISTA: {
+		regexp.MustCompile(`^! Command: `),
+	},
+	opb.Device_CISCO: {
+		// IOS XR prints the time at which the command ran.
+		regexp.MustCompile(`^\w{3} \w{3} +\d+ \d+:\d+:\d+(\.\d+)? \w+$`),
+		regexp.MustCompile(`^Building configuration`),
+		regexp.MustCompile(`^Current configuration : \d+ bytes`),
+		regexp.MustCompile(`^!! (IOS XR Configuration|Last configuration change)`),
+	},
+	opb.Device_JUNIPER: {
+		regexp.MustCompile(`^## Last (commit|changed): `),
+	},
+	opb.Device_NOKIA: {
+		// SR OS prints a banner of the software version and generation time.
+		regexp.MustCompile(`^# (TiMOS-|All rights reserved|Built on |Configuration format version |Generated |Last modified )`),
+	},
+}
+
+// stripRunningConfigHeader removes the header lines, and any blank lines among
+// them, from the start of the running-config of a vendor.
+func stripRunningConfigHeader(vendor opb.Device_Vendor, text string) string {
+	headers := runningConfigHeaders[vendor]
+	isHeader := func(line string) bool {
+		line = strings.TrimRight(line, "\r\n")
+		if strings.TrimSpace(line) == "" {
+			return true
+		}
+		for _, re := range headers {
+			if re.MatchString(line) {
+				return true
+			}
+		}
+		return false
+	}
+	lines := strings.SplitAfter(text, "\n")
+	i := 0
+	for i < len(lines) && isHeader(lines[i]) {
+		i++
+	}
+	return strings.Join(lines[i:], "")
+}
+
+// Checkpoint is a saved config of a DUT, to which the DUT can be rolled back.
+type Checkpoint struct {
+	dutName string
+	// id is the ID of a checkpoint taken by a binding.ConfigCheckpointer.
+	id string
+	// text is the running-config, without its header, of a DUT that is not a
+	// binding.ConfigCheckpointer.
+	text string
+	// Time is the time at which the checkpoint was taken.
+	Time time.Time
+}
+
+// TakeCheckpoint saves the current config of the DUT.
+// Tests must not call this directly; call dut.Config().Checkpoint instead.
+func TakeCheckpoint(t testing.TB, dut binding.DUT) *Checkpoint {
+	t.Helper()
+	t = events.ActionStarted(t, "Checkpointing config of %s", dut)
+	span := trace.Start(t, "config.Checkpoint", "device", dut.Name())
+	cp, err := takeCheckpoint(context.Background(), dut)
+	span.End(err)
+	if err != nil {
+		t.Fatalf("Checkpoint(t) on %s: %v", dut, err)
+	}
+	return cp
+}
+
+func takeCheckpoint(ctx context.Context, dut binding.DUT) (*Checkpoint, error) {
+	cp := &Checkpoint{dutName: dut.Name(), Time: time.Now()}
+	if cc, ok := dut.(binding.ConfigCheckpointer); ok {
+		id, err := cc.CheckpointConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cp.id = id
+		return cp, nil
+	}
+	cmd, ok := runningConfigCmds[dut.Vendor()]
+	if !ok {
+		return nil, fmt.Errorf("checkpoints are not supported for vendor %v", dut.Vendor())
+	}
+	cli, err := rawapis.NewCLI(ctx, dut)
+	if err != nil {
+		return nil, err
+	}
+	res, err := cli.RunCommand(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("error running %q: %w", cmd, err)
+	}
+	if res.Error() != "" {
+		return nil, fmt.Errorf("error running %q: %s", cmd, res.Error())
+	}
+	cp.text = stripRunningConfigHeader(dut.Vendor(), res.Output())
+	return cp, nil
+}
+
+// Rollback restores the config of the DUT to the checkpoint.
+// Tests must not call this directly; call dut.Config().Rollback instead.
+func Rollback(t testing.TB, dut binding.DUT, cp *Checkpoint) {
+	t.Helper()
+	t = events.ActionStarted(t, "Rolling back config of %s", dut)
+	span := trace.Start(t, "config.Rollback", "device", dut.Name())
+	err := rollback(context.Background(), dut, cp)
+	span.End(err)
+	if err != nil {
+		t.Fatal(secrets.Redact(fmt.Sprintf("Rollback(t) on %s: %v", dut, err)))
+	}
+}
+
+func rollback(ctx context.Context, dut binding.DUT, cp *Checkpoint) error {
+	if cp.dutName != dut.Name() {
+		return fmt.Errorf("checkpoint was taken of DUT %q", cp.dutName)
+	}
+	if cc, ok := dut.(binding.ConfigCheckpointer); ok {
+		return cc.RollbackConfig(ctx, cp.id)
+	}
+	return dut.PushConfig(ctx, cp.text, true)
+}
+
+// PendingCommit is a config commit that reverts unless it is confirmed.
+type PendingCommit struct {
+	dut binding.DUT
+	// id is the ID of a commit by a binding.ConfigCheckpointer.
+	id string
+
+	// The fields below emulate commit confirm for DUTs that are not a
+	// binding.ConfigCheckpointer.
+	mu        sync.Mutex
+	timer     *time.Timer
+	cp        *Checkpoint
+	confirmed bool
+	reverted  bool
+}
+
+// PushWithCommitConfirm resets the device to its base config and appends the
+// specified config, like Push, but the device reverts to its previous config
+// after the timeout, unless [PendingCommit.Confirm] is called first.
+//
+// If the binding does not support commit confirm natively, the revert is
+// emulated by the test process, which rolls back to a checkpoint of the
+// config that it takes before the push. In that case, the config is also
+// reverted when the test ends, if it has not been confirmed by then. Unlike a
+// native commit confirm, the emulated revert does not survive the test
+// process: if the process dies before the timeout, the device keeps the
+// unconfirmed config.
+func (c *VendorConfig) PushWithCommitConfirm(t testing.TB, timeout time.Duration) *PendingCommit {
+	t.Helper()
+	t = events.ActionStarted(t, "Pushing config with commit confirm to %s", c.dut)
+	span := trace.Start(t, "config.PushWithCommitConfirm", "device", c.dut.Name())
+	pc, err := c.pushWithCommitConfirm(context.Background(), timeout)
+	span.End(err)
+	if err != nil {
+		t.Fatal(secrets.Redact(fmt.Sprintf("PushWithCommitConfirm(t, %v) on %s: %v", timeout, c.dut, err)))
+	}
+	if pc.timer != nil {
+		t.Cleanup(func() {
+			if err := pc.revertNow(); err != nil {
+				log.Error(secrets.Redact(fmt.Sprintf("Failed to revert unconfirmed config of %s: %v", c.dut, err)))
+			}
+		})
+	}
+	return pc
+}
+
+func (c *VendorConfig) pushWithCommitConfirm(ctx context.Context, timeout time.Duration) (*PendingCommit, error) {
+	if timeout <= 0 {
+		return nil, fmt.Errorf("commit confirm timeout must be positive, got %v", timeout)
+	}
+	config, err := c.text(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pc := &PendingCommit{dut: c.dut}
+	if cc, ok := c.dut.(binding.ConfigCheckpointer); ok {
+		id, err := cc.PushConfigWithCommitConfirm(ctx, config, true, timeout)
+		if err != nil {
+			return nil, err
+		}
+		pc.id = id
+		return pc, nil
+	}
+	cp, err := takeCheckpoint(ctx, c.dut)
+	if err != nil {
+		return nil, fmt.Errorf("error checkpointing config before push: %w", err)
+	}
+	pc.cp = cp
+	if err := c.dut.PushConfig(ctx, config, true); err != nil {
+		if rerr := rollback(ctx, c.dut, cp); rerr != nil {
+			log.Error(secrets.Redact(fmt.Sprintf("Failed to roll back config of %s after failed push: %v", c.dut, rerr)))
+		}
+		return nil, err
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.timer = time.AfterFunc(timeout, func() {
+		if err := pc.revertNow(); err != nil {
+			log.Error(secrets.Redact(fmt.Sprintf("Failed to revert unconfirmed config of %s: %v", c.dut, err)))
+		}
+	})
+	return pc, nil
+}
+
+// Confirm confirms the commit, so that the device does not revert it.
+// It fails fatally if the commit has already been reverted.
+func (pc *PendingCommit) Confirm(t testing.TB) {
+	t.Helper()
+	t = events.ActionStarted(t, "Confirming config commit on %s", pc.dut)
+	span := trace.Start(t, "config.Confirm", "device", pc.dut.Name())
+	err := pc.confirm(context.Background())
+	span.End(err)
+	if err != nil {
+		t.Fatal(secrets.Redact(fmt.Sprintf("Confirm(t) on %s: %v", pc.dut, err)))
+	}
+}
+
+func (pc *PendingCommit) confirm(ctx context.Context) error {
+	if pc.timer == nil {
+		return pc.dut.(binding.ConfigCheckpointer).ConfirmConfig(ctx, pc.id)
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.reverted {
+		return errors.New("commit was already reverted")
+	}
+	pc.timer.Stop()
+	pc.confirmed = true
+	return nil
+}
+
+// revertNow rolls back an emulated commit, unless it is confirmed or was
+// already reverted.
+func (pc *PendingCommit) revertNow() error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.confirmed || pc.reverted {
+		return nil
+	}
+	pc.timer.Stop()
+	pc.reverted = true
+	return rollback(context.Background(), pc.dut, pc.cp)
+}
@@ -0,0 +1,261 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/fakebind"
+	"github.com/openconfig/testt"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+type fakeCLI struct {
+	*binding.AbstractCLIClient
+	output string
+	cmds   []string
+}
+
+func (c *fakeCLI) RunCommand(_ context.Context, cmd string) (binding.CommandResult, error) {
+	c.cmds = append(c.cmds, cmd)
+	return &fakeResult{output: c.output}, nil
+}
+
+type fakeResult struct {
+	*binding.AbstractCommandResult
+	output string
+}
+
+func (r *fakeResult) Output() string { return r.output }
+func (r *fakeResult) Error() string  { return "" }
+
+// pushRecorder records the configs pushed to a DUT.
+type pushRecorder struct {
+	mu     sync.Mutex
+	pushes []string
+}
+
+func (r *pushRecorder) push(_ context.Context, config string, reset bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !reset {
+		config = "append: " + config
+	}
+	r.pushes = append(r.pushes, config)
+	return nil
+}
+
+func (r *pushRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.pushes...)
+}
+
+func emulatedDUT(vendor opb.Device_Vendor) (*fakebind.DUT, *fakeCLI, *pushRecorder) {
+	cli := &fakeCLI{output: "running config"}
+	rec := &pushRecorder{}
+	return &fakebind.DUT{
+		AbstractDUT:  &binding.AbstractDUT{Dims: &binding.Dims{Name: "dut", Vendor: vendor}},
+		PushConfigFn: rec.push,
+		DialCLIFn: func(context.Context) (binding.CLIClient, error) {
+			return cli, nil
+		},
+	}, cli, rec
+}
+
+func TestCheckpointEmulated(t *testing.T) {
+	dut, cli, rec := emulatedDUT(opb.Device_JUNIPER)
+	cp := TakeCheckpoint(t, dut)
+	if want := []string{"show configuration"}; !cmp.Equal(cli.cmds, want) {
+		t.Errorf("TakeCheckpoint() ran commands %v, want %v", cli.cmds, want)
+	}
+	Rollback(t, dut, cp)
+	if want := []string{"running config"}; !cmp.Equal(rec.get(), want) {
+		t.Errorf("Rollback() pushed %v, want %v", rec.get(), want)
+	}
+}
+
+func TestStripRunningConfigHeader(t *testing.T) {
+	tests := []struct {
+		desc   string
+		vendor opb.Device_Vendor
+		text   string
+		want   string
+	}{{
+		desc:   "Cisco IOS",
+		vendor: opb.Device_CISCO,
+		text:   "Building configuration...\n\nCurrent configuration : 1234 bytes\n!\nhostname dut\n",
+		want:   "!\nhostname dut\n",
+	}, {
+		desc:   "Cisco IOS XR",
+		vendor: opb.Device_CISCO,
+		text:   "Thu Oct 18 11:00:00.123 UTC\r\nBuilding configuration...\r\n!! IOS XR Configuration 7.9.1\r\n!! Last configuration change at Thu Oct 18 10:00:00 2026 by admin\r\n!\r\nhostname dut\r\n",
+		want:   "!\r\nhostname dut\r\n",
+	}, {
+		desc:   "Juniper",
+		vendor: opb.Device_JUNIPER,
+		text:   "## Last commit: 2026-10-18 11:00:00 UTC by admin\nsystem {\n    host-name dut;\n}\n",
+		want:   "system {\n    host-name dut;\n}\n",
+	}, {
+		desc:   "Nokia",
+		vendor: opb.Device_NOKIA,
+		text:   "# TiMOS-B-23.10.R1 both/x86_64 Nokia 7750 SR Copyright (c) 2000-2023 Nokia.\n# All rights reserved. All use subject to applicable license agreements.\n# Built on Thu Oct 26 20:12:19 UTC 2023 by builder in /builds/c/2310B/R1/panos/main/sros\n# Configuration format version 23.10 revision 0\n\n# Generated 2026-10-18T11:00:00.0+00:00 by admin from 10.0.0.1\n# Last modified 2026-10-18T10:00:00.0+00:00 by admin\n\nconfigure {\n    system {\n        name \"dut\"\n    }\n}\n",
+		want:   "configure {\n    system {\n        name \"dut\"\n    }\n}\n",
+	}, {
+		desc:   "Arista",
+		vendor: opb.Device_ARISTA,
+		text:   "! Command: show running-config\n! device: dut (cEOSLab, EOS-4.30)\nhostname dut\n",
+		want:   "! device: dut (cEOSLab, EOS-4.30)\nhostname dut\n",
+	}, {
+		desc:   "header-like line in config",
+		vendor: opb.Device_CISCO,
+		text:   "hostname dut\nBuilding configuration\n",
+		want:   "hostname dut\nBuilding configuration\n",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := stripRunningConfigHeader(test.vendor, test.text); got != test.want {
+				t.Errorf("stripRunningConfigHeader() got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckpointNative(t *testing.T) {
+	var gotRollback string
+	dut := &fakebind.CheckpointingDUT{
+		DUT: &fakebind.DUT{AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{Name: "dut"}}},
+		CheckpointConfigFn: func(context.Context) (string, error) {
+			return "cp1", nil
+		},
+		RollbackConfigFn: func(_ context.Context, id string) error {
+			gotRollback = id
+			return nil
+		},
+	}
+	Rollback(t, dut, TakeCheckpoint(t, dut))
+	if want := "cp1"; gotRollback != want {
+		t.Errorf("Rollback() rolled back to %q, want %q", gotRollback, want)
+	}
+}
+
+func TestCheckpointErrors(t *testing.T) {
+	t.Run("unsupported vendor", func(t *testing.T) {
+		dut, _, _ := emulatedDUT(opb.Device_DELL)
+		gotErr := testt.ExpectFatal(t, func(t testing.TB) {
+			TakeCheckpoint(t, dut)
+		})
+		if want := "not supported for vendor"; !strings.Contains(gotErr, want) {
+			t.Errorf("TakeCheckpoint() got error %q, want %q", gotErr, want)
+		}
+	})
+
+	t.Run("other DUT", func(t *testing.T) {
+		dut, _, _ := emulatedDUT(opb.Device_ARISTA)
+		cp := TakeCheckpoint(t, dut)
+		other, _, _ := emulatedDUT(opb.Device_ARISTA)
+		other.AbstractDUT.Dims.Name = "other"
+		gotErr := testt.ExpectFatal(t, func(t testing.TB) {
+			Rollback(t, other, cp)
+		})
+		if want := `checkpoint was taken of DUT "dut"`; !strings.Contains(gotErr, want) {
+			t.Errorf("Rollback() got error %q, want %q", gotErr, want)
+		}
+	})
+}
+
+func TestPushWithCommitConfirmEmulated(t *testing.T) {
+	t.Run("confirmed", func(t *testing.T) {
+		dut, _, rec := emulatedDUT(opb.Device_ARISTA)
+		pc := NewVendorConfig(dut).WithText("new config").PushWithCommitConfirm(t, time.Hour)
+		pc.Confirm(t)
+		if err := pc.revertNow(); err != nil {
+			t.Fatalf("revertNow() got unexpected error: %v", err)
+		}
+		if want := []string{"new config"}; !cmp.Equal(rec.get(), want) {
+			t.Errorf("PushWithCommitConfirm() pushed %v, want %v", rec.get(), want)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		dut, _, rec := emulatedDUT(opb.Device_ARISTA)
+		pc := NewVendorConfig(dut).WithText("new config").PushWithCommitConfirm(t, time.Millisecond)
+		want := []string{"new config", "running config"}
+		for start := time.Now(); len(rec.get()) < len(want) && time.Since(start) < 10*time.Second; {
+			time.Sleep(time.Millisecond)
+		}
+		if !cmp.Equal(rec.get(), want) {
+			t.Errorf("PushWithCommitConfirm() pushed %v, want %v", rec.get(), want)
+		}
+		gotErr := testt.ExpectFatal(t, func(t testing.TB) {
+			pc.Confirm(t)
+		})
+		if want := "already reverted"; !strings.Contains(gotErr, want) {
+			t.Errorf("Confirm() got error %q, want %q", gotErr, want)
+		}
+	})
+
+	t.Run("revert on cleanup", func(t *testing.T) {
+		dut, _, rec := emulatedDUT(opb.Device_ARISTA)
+		t.Run("push", func(t *testing.T) {
+			NewVendorConfig(dut).WithText("new config").PushWithCommitConfirm(t, time.Hour)
+		})
+		if want := []string{"new config", "running config"}; !cmp.Equal(rec.get(), want) {
+			t.Errorf("PushWithCommitConfirm() pushed %v, want %v", rec.get(), want)
+		}
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		dut, _, _ := emulatedDUT(opb.Device_ARISTA)
+		gotErr := testt.ExpectFatal(t, func(t testing.TB) {
+			NewVendorConfig(dut).WithText("new config").PushWithCommitConfirm(t, 0)
+		})
+		if want := "must be positive"; !strings.Contains(gotErr, want) {
+			t.Errorf("PushWithCommitConfirm() got error %q, want %q", gotErr, want)
+		}
+	})
+}
+
+func TestPushWithCommitConfirmNative(t *testing.T) {
+	var gotConfig, gotConfirm string
+	var gotTimeout time.Duration
+	dut := &fakebind.CheckpointingDUT{
+		DUT: &fakebind.DUT{AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{Name: "dut"}}},
+		PushConfigWithCommitConfirmFn: func(_ context.Context, config string, reset bool, timeout time.Duration) (string, error) {
+			gotConfig, gotTimeout = config, timeout
+			return "commit1", nil
+		},
+		ConfirmConfigFn: func(_ context.Context, id string) error {
+			gotConfirm = id
+			return nil
+		},
+	}
+	pc := NewVendorConfig(dut).WithText("new config").PushWithCommitConfirm(t, time.Minute)
+	if gotConfig != "new config" || gotTimeout != time.Minute {
+		t.Errorf("PushWithCommitConfirm() pushed %q with timeout %v, want %q with timeout %v", gotConfig, gotTimeout, "new config", time.Minute)
+	}
+	pc.Confirm(t)
+	if want := "commit1"; gotConfirm != want {
+		t.Errorf("Confirm() confirmed %q, want %q", gotConfirm, want)
+	}
+}
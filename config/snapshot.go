@@ -0,0 +1,243 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmi/value"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// leaf is a config leaf of an OpenConfig tree.
+type leaf struct {
+	path *gpb.Path
+	val  *gpb.TypedValue
+}
+
+// leaves flattens an OpenConfig tree into a map from path string to leaf.
+// Paths are translated to their config paths, i.e. any "state" container
+// that directly holds a leaf is replaced with "config".
+func leaves(s ygot.GoStruct) (map[string]*leaf, error) {
+	m := make(map[string]*leaf)
+	if s == nil {
+		return m, nil
+	}
+	ns, err := ygot.TogNMINotifications(s, 0, ygot.GNMINotificationsConfig{UsePathElem: true})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling config to notifications: %w", err)
+	}
+	for _, n := range ns {
+		for _, u := range n.GetUpdate() {
+			p, err := util.JoinPaths(n.GetPrefix(), u.GetPath())
+			if err != nil {
+				return nil, err
+			}
+			if isKeyLeaf(p) {
+				continue // Duplicate of the key leaf in the config container.
+			}
+			p = configPath(p)
+			ps, err := ygot.PathToString(p)
+			if err != nil {
+				return nil, err
+			}
+			m[ps] = &leaf{path: p, val: u.GetVal()}
+		}
+	}
+	return m, nil
+}
+
+// isKeyLeaf reports whether the path is a list key leaf directly under its
+// list entry, rather than in the entry's config or state container.
+func isKeyLeaf(p *gpb.Path) bool {
+	elems := p.GetElem()
+	if len(elems) < 2 {
+		return false
+	}
+	_, ok := elems[len(elems)-2].GetKey()[elems[len(elems)-1].GetName()]
+	return ok
+}
+
+func configPath(p *gpb.Path) *gpb.Path {
+	p = proto.Clone(p).(*gpb.Path)
+	if elems := p.GetElem(); len(elems) >= 2 && elems[len(elems)-2].GetName() == "state" {
+		elems[len(elems)-2].Name = "config"
+	}
+	return p
+}
+
+// DiffSetRequest returns a minimal SetRequest that changes the config of a
+// device from the "from" OpenConfig tree to the "to" OpenConfig tree.
+// Changed and added leaves are updated, and removed leaves are deleted. If a
+// list entry is removed entirely, the whole entry is deleted with a single
+// path rather than leaf by leaf. Every path has the "openconfig" origin.
+func DiffSetRequest(from, to ygot.GoStruct) (*gpb.SetRequest, error) {
+	fromLeaves, err := leaves(from)
+	if err != nil {
+		return nil, err
+	}
+	toLeaves, err := leaves(to)
+	if err != nil {
+		return nil, err
+	}
+	req := &gpb.SetRequest{}
+	for _, ps := range sortedKeys(toLeaves) {
+		tl := toLeaves[ps]
+		if fl, ok := fromLeaves[ps]; ok && proto.Equal(fl.val, tl.val) {
+			continue
+		}
+		req.Update = append(req.Update, &gpb.Update{Path: withOrigin(tl.path), Val: tl.val})
+	}
+	deleted := make(map[string]bool)
+	for _, ps := range sortedKeys(fromLeaves) {
+		if _, ok := toLeaves[ps]; ok {
+			continue
+		}
+		p := removedEntry(fromLeaves[ps].path, toLeaves)
+		dps, err := ygot.PathToString(p)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted[dps] {
+			deleted[dps] = true
+			req.Delete = append(req.Delete, withOrigin(p))
+		}
+	}
+	return req, nil
+}
+
+// withOrigin returns a copy of the path with the "openconfig" origin, which
+// devices that serve several schemas require to resolve the path.
+func withOrigin(p *gpb.Path) *gpb.Path {
+	p = proto.Clone(p).(*gpb.Path)
+	p.Origin = "openconfig"
+	return p
+}
+
+// removedEntry returns the path of the outermost list entry that contains the
+// specified leaf and that no longer has any leaves in the "to" tree, or the
+// leaf path itself if there is no such entry.
+func removedEntry(p *gpb.Path, toLeaves map[string]*leaf) *gpb.Path {
+	for i, elem := range p.GetElem() {
+		if len(elem.GetKey()) == 0 {
+			continue
+		}
+		entry := &gpb.Path{Elem: p.GetElem()[:i+1]}
+		eps, err := ygot.PathToString(entry)
+		if err != nil {
+			continue
+		}
+		if !hasPrefix(toLeaves, eps+"/") {
+			return entry
+		}
+	}
+	return p
+}
+
+func hasPrefix(m map[string]*leaf, prefix string) bool {
+	for ps := range m {
+		if strings.HasPrefix(ps, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffText returns a human-readable, line-oriented diff between two
+// OpenConfig trees, or the empty string if they are equal. Leaves only in "a"
+// are prefixed by "-", leaves only in "b" by "+", and changed leaves appear
+// with both.
+func DiffText(a, b ygot.GoStruct) (string, error) {
+	aLeaves, err := leaves(a)
+	if err != nil {
+		return "", err
+	}
+	bLeaves, err := leaves(b)
+	if err != nil {
+		return "", err
+	}
+	all := make(map[string]*leaf)
+	for ps, l := range aLeaves {
+		all[ps] = l
+	}
+	for ps, l := range bLeaves {
+		all[ps] = l
+	}
+	var sb strings.Builder
+	for _, ps := range sortedKeys(all) {
+		al, aOK := aLeaves[ps]
+		bl, bOK := bLeaves[ps]
+		if aOK && bOK && proto.Equal(al.val, bl.val) {
+			continue
+		}
+		if aOK {
+			fmt.Fprintf(&sb, "- %s: %s\n", ps, formatVal(al.val))
+		}
+		if bOK {
+			fmt.Fprintf(&sb, "+ %s: %s\n", ps, formatVal(bl.val))
+		}
+	}
+	return sb.String(), nil
+}
+
+// DiffLines returns a human-readable, line-oriented diff between two texts,
+// such as vendor running-configs, or the empty string if they are equal.
+// Lines only in "a" are prefixed by "-" and lines only in "b" by "+".
+func DiffLines(a, b string) string {
+	count := make(map[string]int)
+	for _, l := range strings.Split(a, "\n") {
+		count[l]--
+	}
+	for _, l := range strings.Split(b, "\n") {
+		count[l]++
+	}
+	var sb strings.Builder
+	for _, l := range strings.Split(a, "\n") {
+		if count[l] < 0 {
+			count[l]++
+			fmt.Fprintf(&sb, "- %s\n", l)
+		}
+	}
+	for _, l := range strings.Split(b, "\n") {
+		if count[l] > 0 {
+			count[l]--
+			fmt.Fprintf(&sb, "+ %s\n", l)
+		}
+	}
+	return sb.String()
+}
+
+func formatVal(tv *gpb.TypedValue) string {
+	v, err := value.ToScalar(tv)
+	if err != nil {
+		return tv.String()
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func sortedKeys(m map[string]*leaf) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func newRoot(one string, keys ...string) *exampleoc.Root {
+	root := &exampleoc.Root{}
+	if one != "" {
+		root.GetOrCreateParent().GetOrCreateChild().One = ygot.String(one)
+	}
+	for _, k := range keys {
+		root.GetOrCreateModel().GetOrCreateSingleKey(k).Value = ygot.Int64(1)
+	}
+	return root
+}
+
+func TestDiffSetRequest(t *testing.T) {
+	tests := []struct {
+		desc        string
+		from, to    ygot.GoStruct
+		wantUpdates []string
+		wantDeletes []string
+	}{{
+		desc: "equal",
+		from: newRoot("foo", "k1"),
+		to:   newRoot("foo", "k1"),
+	}, {
+		desc:        "changed leaf",
+		from:        newRoot("foo"),
+		to:          newRoot("bar"),
+		wantUpdates: []string{"/parent/child/config/one"},
+	}, {
+		desc:        "removed leaf",
+		from:        newRoot("foo", "k1"),
+		to:          newRoot("", "k1"),
+		wantDeletes: []string{"/parent/child/config/one"},
+	}, {
+		desc:        "added list entry",
+		from:        newRoot("foo"),
+		to:          newRoot("foo", "k1"),
+		wantUpdates: []string{"/model/a/single-key[key=k1]/config/key", "/model/a/single-key[key=k1]/config/value"},
+	}, {
+		desc:        "removed list entry",
+		from:        newRoot("foo", "k1", "k2"),
+		to:          newRoot("foo", "k2"),
+		wantDeletes: []string{"/model/a/single-key[key=k1]"},
+	}, {
+		desc:        "from nil",
+		to:          newRoot("foo"),
+		wantUpdates: []string{"/parent/child/config/one"},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			req, err := DiffSetRequest(test.from, test.to)
+			if err != nil {
+				t.Fatalf("DiffSetRequest() got error: %v", err)
+			}
+			var gotUpdates, gotDeletes []string
+			for _, u := range req.GetUpdate() {
+				if got, want := u.GetPath().GetOrigin(), "openconfig"; got != want {
+					t.Errorf("DiffSetRequest() got update origin %q, want %q", got, want)
+				}
+				ps, err := ygot.PathToString(u.GetPath())
+				if err != nil {
+					t.Fatalf("PathToString() got error: %v", err)
+				}
+				gotUpdates = append(gotUpdates, ps)
+			}
+			for _, p := range req.GetDelete() {
+				if got, want := p.GetOrigin(), "openconfig"; got != want {
+					t.Errorf("DiffSetRequest() got delete origin %q, want %q", got, want)
+				}
+				ps, err := ygot.PathToString(p)
+				if err != nil {
+					t.Fatalf("PathToString() got error: %v", err)
+				}
+				gotDeletes = append(gotDeletes, ps)
+			}
+			if diff := cmp.Diff(test.wantUpdates, gotUpdates); diff != "" {
+				t.Errorf("DiffSetRequest() got unexpected updates (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantDeletes, gotDeletes); diff != "" {
+				t.Errorf("DiffSetRequest() got unexpected deletes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffText(t *testing.T) {
+	got, err := DiffText(newRoot("foo", "k1"), newRoot("bar"))
+	if err != nil {
+		t.Fatalf("DiffText() got error: %v", err)
+	}
+	want := "- /model/a/single-key[key=k1]/config/key: k1\n" +
+		"- /model/a/single-key[key=k1]/config/value: 1\n" +
+		"- /parent/child/config/one: foo\n" +
+		"+ /parent/child/config/one: bar\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DiffText() got unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := DiffLines("hostname r1\nip routing", "hostname r2\nip routing")
+	want := "- hostname r1\n+ hostname r2\n"
+	if got != want {
+		t.Errorf("DiffLines() got %q, want %q", got, want)
+	}
+	if got := DiffLines("a\nb", "a\nb"); got != "" {
+		t.Errorf("DiffLines() of equal text got %q, want empty", got)
+	}
+}
@@ -56,6 +56,24 @@
 // to `port1`, where `port1` is the ID of a port in the testbed file. The syntax
 // may also contain instances of `{{ var "<key>" }}`, which will be replaced at
 // runtime by calling `WithVarValue` or `WithVarMap` on the config object.
+// Instances of `{{ secrets "<key>" }}` are replaced at push time with the
+// secret with that key from the provider set by the --secrets flag; see the
+// secrets package. If no provider is set, they are left for the binding.
+//
+// Configs can also loop over the ports of the device, or of a port group in
+// the testbed, allocate addresses from IPv4 pools, and include other files:
+//
+//	{{ include "path/to/common.config" }}
+//	{{ range $i, $p := portsOfGroup "lag1" }}
+//	interface {{ port $p }}
+//	 description to {{ linkPeer $p }}
+//	 ip address {{ ipv4 "links" $i }}
+//	{{ end }}
+//
+// where the "links" pool is set with `WithIPv4Pool("links", "192.0.2.1/30")`.
+// The `ports` function returns the IDs of all the ports of the device, and
+// `model` and `version` return its hardware model and software version. To
+// inspect the rendered config without pushing it, call [VendorConfig.Render].
 //
 // # Push vs Append
 //
@@ -65,12 +83,43 @@
 // reset the device config to be hermetic; it suffices to just start the test
 // case with a call to Push. If a test wants only to append config and skip
 // the reset behavior, it should call [VendorConfig.Append] instead.
+//
+// # Checkpoints and Commit Confirm
+//
+// A push of bad config can leave a device unreachable. To guard against that,
+// a test can push config that the device reverts after a timeout, unless the
+// test confirms it first, for example after checking that the device is
+// still reachable:
+//
+//	commit := dut.Config().New().WithAristaText(text).PushWithCommitConfirm(t, 5*time.Minute)
+//	gnmi.Get(t, dut, gnmi.OC().System().Hostname().State())
+//	commit.Confirm(t)
+//
+// A test can also checkpoint the native config of a device and later roll it
+// back to the checkpoint:
+//
+//	cp := dut.Config().Checkpoint(t)
+//	defer dut.Config().Rollback(t, cp)
+//
+// Bindings may support these operations natively; otherwise they are emulated
+// by restoring the running-config of the device with a push.
+//
+// # Snapshots
+//
+// This package also provides the diffing functions behind the config snapshot
+// API, which lets a test put the device config back the way it found it:
+//
+//	dut.Config().RestoreOnCleanup(t, dut.Config().Snapshot(t))
 package config
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
+	"net/netip"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 	"text/template"
@@ -80,6 +129,8 @@ import (
 	"github.com/openconfig/ondatra/binding"
 	"github.com/openconfig/ondatra/internal/events"
 	"github.com/openconfig/ondatra/internal/testbed"
+	"github.com/openconfig/ondatra/internal/trace"
+	"github.com/openconfig/ondatra/secrets"
 
 	opb "github.com/openconfig/ondatra/proto"
 )
@@ -94,6 +145,7 @@ func NewVendorConfig(dut binding.DUT) *VendorConfig {
 		dut:       dut,
 		perVendor: make(map[opb.Device_Vendor]configProvider),
 		vars:      make(map[string]string),
+		ipv4Pools: make(map[string]netip.Prefix),
 	}
 }
 
@@ -103,6 +155,8 @@ type VendorConfig struct {
 	allVendor configProvider
 	perVendor map[opb.Device_Vendor]configProvider
 	vars      map[string]string
+	ipv4Pools map[string]netip.Prefix
+	err       error
 }
 
 func (c *VendorConfig) String() string {
@@ -174,12 +228,41 @@ func (c *VendorConfig) WithVarMap(m map[string]string) *VendorConfig {
 	return c
 }
 
+// WithIPv4Pool sets the first address of the pool used to replace each
+// occurrence of {{ ipv4 "name" idx }} in the pushed config. The idx-th address
+// of the pool is the first address advanced by idx times the size of its
+// prefix, so "192.0.2.1/30" allocates 192.0.2.1/30, 192.0.2.5/30, and so on.
+func (c *VendorConfig) WithIPv4Pool(name, cidr string) *VendorConfig {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil || !prefix.Addr().Is4() {
+		c.err = fmt.Errorf("invalid IPv4 CIDR %q for pool %q", cidr, name)
+		return c
+	}
+	c.ipv4Pools[name] = prefix
+	return c
+}
+
+// Render returns the config that Push would push to the device, without
+// pushing it, so that tests can inspect or golden-test the rendered config.
+// Secrets are left untouched in the rendered config.
+func (c *VendorConfig) Render(t testing.TB) string {
+	t.Helper()
+	text, err := c.render(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Render(t) on %s: %v", c.dut, err)
+	}
+	return text
+}
+
 // Push resets the device to its base config and appends the specified config.
 func (c *VendorConfig) Push(t testing.TB) {
 	t.Helper()
 	t = events.ActionStarted(t, "Pushing config to %s", c.dut)
-	if err := c.pushConfig(context.Background(), true); err != nil {
-		t.Fatalf("Push(t) on %s: %v", c.dut, err)
+	span := trace.Start(t, "config.Push", "device", c.dut.Name())
+	err := c.pushConfig(context.Background(), true)
+	span.End(err)
+	if err != nil {
+		t.Fatal(secrets.Redact(fmt.Sprintf("Push(t) on %s: %v", c.dut, err)))
 	}
 }
 
@@ -187,14 +270,34 @@ func (c *VendorConfig) Push(t testing.TB) {
 func (c *VendorConfig) Append(t testing.TB) {
 	t.Helper()
 	t = events.ActionStarted(t, "Appending config to %s", c.dut)
-	if err := c.pushConfig(context.Background(), false); err != nil {
-		t.Fatalf("Append(t) on %s: %v", c.dut, err)
+	span := trace.Start(t, "config.Append", "device", c.dut.Name())
+	err := c.pushConfig(context.Background(), false)
+	span.End(err)
+	if err != nil {
+		t.Fatal(secrets.Redact(fmt.Sprintf("Append(t) on %s: %v", c.dut, err)))
 	}
 }
 
 func (c *VendorConfig) pushConfig(ctx context.Context, reset bool) error {
+	config, err := c.text(ctx)
+	if err != nil {
+		return err
+	}
+	return c.dut.PushConfig(ctx, config, reset)
+}
+
+// text returns the interpolated config text for the vendor of the DUT, with
+// the values of its secrets.
+func (c *VendorConfig) text(ctx context.Context) (string, error) {
+	return c.render(ctx, true)
+}
+
+func (c *VendorConfig) render(ctx context.Context, resolveSecrets bool) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
 	if c.allVendor != nil && len(c.perVendor) > 0 {
-		return errors.New("cannot specify both all-vendor and per-vendor config")
+		return "", errors.New("cannot specify both all-vendor and per-vendor config")
 	}
 	var prov configProvider
 	if c.allVendor != nil {
@@ -202,25 +305,39 @@ func (c *VendorConfig) pushConfig(ctx context.Context, reset bool) error {
 	} else if pv, ok := c.perVendor[c.dut.Vendor()]; ok {
 		prov = pv
 	} else {
-		return fmt.Errorf("no config specified for device %v", c.dut)
+		return "", fmt.Errorf("no config specified for device %v", c.dut)
 	}
 	text, err := prov.get()
 	if err != nil {
-		return fmt.Errorf("error getting config from provider %v: %w", prov, err)
-	}
-	config, err := c.interpolate(text)
-	if err != nil {
-		return err
+		return "", fmt.Errorf("error getting config from provider %v: %w", prov, err)
 	}
-	return c.dut.PushConfig(ctx, config, reset)
+	return c.interpolate(ctx, text, resolveSecrets)
 }
 
-// interpolateConfig substitutes templated variables in device config text.
+// maxIncludeDepth is the maximum depth of nested includes in config.
+const maxIncludeDepth = 10
+
+// interpolate substitutes templated variables in device config text.
 // The following Go template functions are allowed in config:
 // - {{ port "<portID>" }}: replaced with the physical port name
-// - {{ secrets "<arg1>" "<arg2>" }}: left untouched, returned as-is
+// - {{ ports }}: returns the sorted IDs of all the ports of the DUT
+// - {{ portsOfGroup "<group>" }}: returns the sorted IDs of the ports of the
+// DUT in the group in the testbed
+// - {{ linkPeer "<portID>" }}: replaced with "<device>:<port>", the names of
+// the device and port at the other end of the link from the port
+// - {{ ipv4 "<pool>" <idx> }}: replaced with the idx-th CIDR of the pool
+// - {{ model }}: replaced with the hardware model of the DUT
+// - {{ version }}: replaced with the software version of the DUT
+// - {{ include "<path>" }}: replaced with the interpolated config in the file
+// - {{ secrets "<arg1>" "<arg2>" }}: replaced with the secret with key
+// "<arg1>/<arg2>" from the secrets provider, if resolveSecrets is true and a
+// provider is set; otherwise left untouched, returned as-is
 // - {{ var "<key>" }}: returns the value for the key in the vars map
-func (c *VendorConfig) interpolate(config string) (string, error) {
+func (c *VendorConfig) interpolate(ctx context.Context, config string, resolveSecrets bool) (string, error) {
+	return c.execute(ctx, c.dut.Name(), config, 0, resolveSecrets && secrets.HasProvider())
+}
+
+func (c *VendorConfig) execute(ctx context.Context, name, config string, depth int, resolveSecrets bool) (string, error) {
 	funcMap := map[string]any{
 		"port": func(portID string) (string, error) {
 			port, err := testbed.Port(c.dut, portID)
@@ -229,13 +346,52 @@ func (c *VendorConfig) interpolate(config string) (string, error) {
 			}
 			return port.Name, nil
 		},
-		// "secrets" function should be a noop
-		"secrets": func(secrets ...string) string {
+		"ports": func() []string {
+			var ids []string
+			for id := range c.dut.Ports() {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			return ids
+		},
+		"portsOfGroup": func(group string) ([]string, error) {
+			return testbed.PortsOfGroup(c.dut, group)
+		},
+		"linkPeer": func(portID string) (string, error) {
+			dev, port, err := testbed.LinkPeer(c.dut, portID)
+			if err != nil {
+				return "", err
+			}
+			return dev.Name() + ":" + port.Name, nil
+		},
+		"ipv4": func(pool string, idx int) (string, error) {
+			return c.ipv4(pool, idx)
+		},
+		"model": func() string {
+			return c.dut.HardwareModel()
+		},
+		"version": func() string {
+			return c.dut.SoftwareVersion()
+		},
+		"include": func(path string) (string, error) {
+			if depth >= maxIncludeDepth {
+				return "", fmt.Errorf("includes nested more than %d deep", maxIncludeDepth)
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			return c.execute(ctx, path, string(b), depth+1, resolveSecrets)
+		},
+		"secrets": func(keys ...string) (string, error) {
+			if resolveSecrets {
+				return secrets.Get(ctx, strings.Join(keys, "/"))
+			}
 			var args []string
-			for _, s := range secrets {
-				args = append(args, fmt.Sprintf("%q", s))
+			for _, k := range keys {
+				args = append(args, fmt.Sprintf("%q", k))
 			}
-			return fmt.Sprintf("{{ secrets %s }}", strings.Join(args, " "))
+			return fmt.Sprintf("{{ secrets %s }}", strings.Join(args, " ")), nil
 		},
 		"var": func(key string) (string, error) {
 			v, ok := c.vars[key]
@@ -245,7 +401,7 @@ func (c *VendorConfig) interpolate(config string) (string, error) {
 			return v, nil
 		},
 	}
-	template, err := template.New(c.dut.Name()).Funcs(funcMap).Parse(config)
+	template, err := template.New(name).Funcs(funcMap).Parse(config)
 	if err != nil {
 		return "", fmt.Errorf("invalid template in config %q: %w", config, err)
 	}
@@ -256,6 +412,25 @@ func (c *VendorConfig) interpolate(config string) (string, error) {
 	return b.String(), nil
 }
 
+// ipv4 returns the idx-th CIDR of the IPv4 pool, like netutil.GenCIDRs.
+func (c *VendorConfig) ipv4(pool string, idx int) (string, error) {
+	prefix, ok := c.ipv4Pools[pool]
+	if !ok {
+		return "", fmt.Errorf("no IPv4 pool %q", pool)
+	}
+	if idx < 0 {
+		return "", fmt.Errorf("negative index %d into IPv4 pool %q", idx, pool)
+	}
+	start := prefix.Addr().As4()
+	addr := uint64(binary.BigEndian.Uint32(start[:])) + uint64(idx)<<(32-prefix.Bits())
+	if addr > math.MaxUint32 {
+		return "", fmt.Errorf("index %d exceeds the IPv4 address space of pool %q", idx, pool)
+	}
+	var next [4]byte
+	binary.BigEndian.PutUint32(next[:], uint32(addr))
+	return netip.PrefixFrom(netip.AddrFrom4(next), prefix.Bits()).String(), nil
+}
+
 type configProvider interface {
 	get() (string, error)
 }
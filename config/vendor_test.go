@@ -15,6 +15,9 @@
 package config
 
 import (
+	"errors"
+	"flag"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -24,6 +27,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/openconfig/ondatra/binding"
 	"github.com/openconfig/ondatra/fakebind"
+	"github.com/openconfig/ondatra/internal/testbed"
+	"github.com/openconfig/ondatra/secrets"
 	"github.com/openconfig/testt"
 
 	opb "github.com/openconfig/ondatra/proto"
@@ -166,3 +171,153 @@ func TestPush(t *testing.T) {
 		}
 	})
 }
+
+type mapProvider map[string]string
+
+func (p mapProvider) Secret(_ context.Context, key string) (string, error) {
+	if v, ok := p[key]; ok {
+		return v, nil
+	}
+	return "", errors.New("no such secret")
+}
+
+func TestPushSecrets(t *testing.T) {
+	var gotConfig string
+	dut := &fakebind.DUT{
+		AbstractDUT: &binding.AbstractDUT{&binding.Dims{Name: "dut1", Vendor: opb.Device_ARISTA}},
+		PushConfigFn: func(_ context.Context, config string, _ bool) error {
+			gotConfig = config
+			return nil
+		},
+	}
+	secrets.SetProvider(mapProvider{"dut1/snmp": "c0mmunity"})
+	t.Cleanup(func() { secrets.SetProvider(nil) })
+
+	cfg := NewVendorConfig(dut).WithAristaText(`snmp-server community {{ secrets "dut1" "snmp" }} ro`)
+	cfg.Push(t)
+	if want := "snmp-server community c0mmunity ro"; gotConfig != want {
+		t.Errorf("Push(t) got config %q, want %q", gotConfig, want)
+	}
+	if got, want := cfg.Render(t), `snmp-server community {{ secrets "dut1" "snmp" }} ro`; got != want {
+		t.Errorf("Render(t) got config %q, want %q", got, want)
+	}
+	if got, want := secrets.Redact(gotConfig), "snmp-server community <redacted> ro"; got != want {
+		t.Errorf("Redact() got %q, want %q", got, want)
+	}
+
+	gotErr := testt.ExpectFatal(t, func(t testing.TB) {
+		NewVendorConfig(dut).WithAristaText(`{{ secrets "dut1" "missing" }}`).Push(t)
+	})
+	if want := "no such secret"; !strings.Contains(gotErr, want) {
+		t.Errorf("Push(t) got error %q, want %q", gotErr, want)
+	}
+}
+
+var update = flag.Bool("update", false, "update the golden file of the rendered config")
+
+func TestRender(t *testing.T) {
+	dut := &fakebind.DUT{
+		AbstractDUT: &binding.AbstractDUT{&binding.Dims{
+			Name:            "dut1",
+			Vendor:          opb.Device_ARISTA,
+			HardwareModel:   "7280R3",
+			SoftwareVersion: "4.30.1F",
+			Ports: map[string]*binding.Port{
+				"port1": {Name: "Ethernet1"},
+				"port2": {Name: "Ethernet2"},
+				"port3": {Name: "Ethernet3"},
+			},
+		}},
+	}
+	ate := &binding.AbstractATE{&binding.Dims{
+		Name: "ate1",
+		Ports: map[string]*binding.Port{
+			"port1": {Name: "1/1"},
+			"port2": {Name: "1/2"},
+		},
+	}}
+	fakebind.Setup().WithReservation(&binding.Reservation{
+		DUTs: map[string]binding.DUT{"dut": dut},
+		ATEs: map[string]binding.ATE{"ate": ate},
+	})
+	testbed.SetTestbedForTesting(&opb.Testbed{
+		Duts: []*opb.Device{{
+			Id: "dut",
+			Ports: []*opb.Port{
+				{Id: "port1", Group: "lag1"},
+				{Id: "port2", Group: "lag1"},
+				{Id: "port3"},
+			},
+		}},
+		Ates: []*opb.Device{{
+			Id:    "ate",
+			Ports: []*opb.Port{{Id: "port1"}, {Id: "port2"}},
+		}},
+		Links: []*opb.Link{
+			{A: "dut:port1", B: "ate:port1"},
+			{A: "ate:port2", B: "dut:port2"},
+		},
+	})
+
+	t.Run("golden", func(t *testing.T) {
+		got := NewVendorConfig(dut).
+			WithAristaFile(filepath.Join("testdata", "render.txt")).
+			WithVarValue("hostname", "dut1").
+			WithIPv4Pool("links", "192.0.2.1/30").
+			Render(t)
+		golden := filepath.Join("testdata", "render.golden")
+		if *update {
+			if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+				t.Fatalf("WriteFile() failed: %v", err)
+			}
+			return
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("ReadFile() failed: %v", err)
+		}
+		if diff := cmp.Diff(string(want), got); diff != "" {
+			t.Errorf("Render(t) got unexpected config (-want +got):\n%s", diff)
+		}
+	})
+
+	tests := []struct {
+		desc      string
+		config    *VendorConfig
+		wantFatal string
+	}{{
+		desc:      "unknown group",
+		config:    NewVendorConfig(dut).WithText(`{{ portsOfGroup "lag2" }}`),
+		wantFatal: "no ports in group",
+	}, {
+		desc:      "unlinked port",
+		config:    NewVendorConfig(dut).WithText(`{{ linkPeer "port3" }}`),
+		wantFatal: "not linked",
+	}, {
+		desc:      "unknown pool",
+		config:    NewVendorConfig(dut).WithText(`{{ ipv4 "links" 0 }}`),
+		wantFatal: "no IPv4 pool",
+	}, {
+		desc:      "invalid pool",
+		config:    NewVendorConfig(dut).WithText(`{{ ipv4 "links" 0 }}`).WithIPv4Pool("links", "2001:db8::/64"),
+		wantFatal: "invalid IPv4 CIDR",
+	}, {
+		desc:      "pool exhausted",
+		config:    NewVendorConfig(dut).WithText(`{{ ipv4 "links" 2 }}`).WithIPv4Pool("links", "255.255.255.0/25"),
+		wantFatal: "exceeds the IPv4 address space",
+	}, {
+		desc:      "recursive include",
+		config:    NewVendorConfig(dut).WithText(`{{ include "testdata/render_recursive.txt" }}`),
+		wantFatal: "nested more than",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := testt.ExpectFatal(t, func(t testing.TB) {
+				tt.config.Render(t)
+			})
+			if !strings.Contains(got, tt.wantFatal) {
+				t.Errorf("Render(t) failed with message %q, want %q", got, tt.wantFatal)
+			}
+		})
+	}
+}
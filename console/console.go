@@ -34,6 +34,15 @@
 //	stopFn := dut.Console().StartCapture(t, stdoutFile, stderrFile)
 //	doStuffToTriggerConsoleOutput()
 //	stopFn(t)
+//
+// To interact with the serial console, for example to log in to a device
+// after it reboots, start a [Session] and script it in the style of expect:
+//
+//	s := dut.Console().Session(t)
+//	s.Expect(t, `login: $`, 5*time.Minute)
+//	s.Send(t, "admin\n")
+//	s.Expect(t, `Password: $`, 10*time.Second)
+//	s.Send(t, password+"\n")
 package console
 
 import (
@@ -48,6 +57,7 @@ import (
 	log "github.com/golang/glog"
 	"github.com/openconfig/ondatra/binding"
 	"github.com/openconfig/ondatra/internal/events"
+	"github.com/openconfig/ondatra/internal/trace"
 )
 
 // New constructs a new instance of the Console API.
@@ -67,10 +77,13 @@ type Console struct {
 func (c *Console) StartCapture(t testing.TB, outw, errw io.Writer) StopCaptureFunc {
 	t.Helper()
 	t = events.ActionStarted(t, "Starting console capture on %s", c.dut)
+	span := trace.Start(t, "console.Capture", "device", c.dut.Name())
 	cap, err := c.startCapture(context.Background(), outw, errw)
 	if err != nil {
+		span.End(err)
 		t.Fatalf("StartCapture(t, out, err) on %s: %v", c.dut, err)
 	}
+	cap.span = span
 	t.Cleanup(func() {
 		if err := cap.stop(); err != nil {
 			log.Errorf("StopCapture(t) on %s: %v", c.dut, err)
@@ -102,6 +115,7 @@ func (c *Console) startCapture(ctx context.Context, outw, errw io.Writer) (*capt
 
 type capturer struct {
 	client  binding.ConsoleClient
+	span    *trace.Span
 	wg      sync.WaitGroup
 	stopped atomic.Bool
 }
@@ -137,6 +151,7 @@ func (c *capturer) stop() error {
 	if c.stopped.Swap(true) {
 		return nil
 	}
+	defer c.span.End(nil)
 	if err := c.client.Close(); err != nil {
 		return fmt.Errorf("failed to close the ConsoleClient: %w", err)
 	}
@@ -0,0 +1,248 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/internal/events"
+	"github.com/openconfig/ondatra/internal/trace"
+	"github.com/openconfig/ondatra/secrets"
+)
+
+// Session starts an interactive session on the console, in the style of the
+// expect tool. It registers a cleanup function to close the session, which
+// logs the transcript of the session if the test has failed.
+//
+// For example, to interrupt the boot of a device and wait for its ROM monitor:
+//
+//	s := dut.Console().Session(t)
+//	s.SendBreak(t)
+//	s.Expect(t, `rommon \d+ >`, time.Minute)
+//	s.Send(t, "boot\n")
+func (c *Console) Session(t testing.TB) *Session {
+	t.Helper()
+	t = events.ActionStarted(t, "Starting console session on %s", c.dut)
+	span := trace.Start(t, "console.Session", "device", c.dut.Name())
+	s, err := c.startSession(context.Background())
+	if err != nil {
+		span.End(err)
+		t.Fatalf("Session(t) on %s: %v", c.dut, err)
+	}
+	s.span = span
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("Transcript of console session on %s:\n%s", c.dut, s.Transcript())
+		}
+		if err := s.close(); err != nil {
+			log.Errorf("Close(t) on console session of %s: %v", c.dut, err)
+		}
+	})
+	return s
+}
+
+func (c *Console) startSession(ctx context.Context) (*Session, error) {
+	client, err := c.dut.DialConsole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{
+		dut:    c.dut,
+		client: client,
+		update: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	s.wg.Add(2)
+	go s.read(client.Stdout())
+	go s.read(client.Stderr())
+	go func() {
+		s.wg.Wait()
+		close(s.done)
+	}()
+	return s, nil
+}
+
+// Session is an interactive session on the console of a DUT.
+// The stdout and stderr of the console are merged, as on a terminal.
+type Session struct {
+	dut    binding.DUT
+	client binding.ConsoleClient
+	span   *trace.Span
+	wg     sync.WaitGroup
+	update chan struct{} // receives when the output is updated
+	done   chan struct{} // closed when reading stops
+
+	mu         sync.Mutex // guards the fields below
+	unmatched  []byte
+	transcript bytes.Buffer
+	readErr    error
+	closed     bool
+}
+
+// Expect waits up to the timeout for the console output that has not yet
+// been matched by a previous call to match the regular expression. It
+// consumes the output up to the end of the match and returns the text of the
+// match followed by the text of its subexpressions, as regexp.FindSubmatch.
+// It fails the test if the timeout expires before the output matches.
+func (s *Session) Expect(t testing.TB, regex string, timeout time.Duration) []string {
+	t.Helper()
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		t.Fatal(secrets.Redact(fmt.Sprintf("Expect(t, %q, %v) on %s: %v", regex, timeout, s.dut, err)))
+	}
+	match, err := s.expect(re, timeout)
+	if err != nil {
+		t.Fatal(secrets.Redact(fmt.Sprintf("Expect(t, %q, %v) on %s: %v", regex, timeout, s.dut, err)))
+	}
+	return match
+}
+
+func (s *Session) expect(re *regexp.Regexp, timeout time.Duration) ([]string, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		loc := re.FindSubmatchIndex(s.unmatched)
+		var match []string
+		if loc != nil {
+			match = submatches(s.unmatched, loc)
+			s.unmatched = s.unmatched[loc[1]:]
+		}
+		unmatched, readErr := string(s.unmatched), s.readErr
+		s.mu.Unlock()
+		if match != nil {
+			return match, nil
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("console output ended with error %v; unmatched output: %q", readErr, unmatched)
+		}
+		select {
+		case <-s.update:
+		case <-s.done:
+			s.mu.Lock()
+			if s.readErr == nil {
+				s.readErr = io.EOF
+			}
+			s.mu.Unlock()
+		case <-timer.C:
+			return nil, fmt.Errorf("timed out after %v; unmatched output: %q", timeout, unmatched)
+		}
+	}
+}
+
+func submatches(b []byte, loc []int) []string {
+	match := make([]string, len(loc)/2)
+	for i := range match {
+		if start := loc[2*i]; start >= 0 {
+			match[i] = string(b[start:loc[2*i+1]])
+		}
+	}
+	return match
+}
+
+// Send writes the text to the console.
+func (s *Session) Send(t testing.TB, text string) {
+	t.Helper()
+	// Record the text first, so that it precedes any output that it causes.
+	s.mu.Lock()
+	s.transcript.WriteString(text)
+	s.mu.Unlock()
+	if _, err := io.WriteString(s.client.Stdin(), text); err != nil {
+		t.Fatal(secrets.Redact(fmt.Sprintf("Send(t, %q) on %s: %v", text, s.dut, err)))
+	}
+}
+
+// SendBreak sends a break to the console. It fails the test if the binding
+// does not support breaks.
+func (s *Session) SendBreak(t testing.TB) {
+	t.Helper()
+	if err := s.sendBreak(context.Background()); err != nil {
+		t.Fatalf("SendBreak(t) on %s: %v", s.dut, err)
+	}
+}
+
+func (s *Session) sendBreak(ctx context.Context) error {
+	b, ok := s.client.(binding.ConsoleBreaker)
+	if !ok {
+		return fmt.Errorf("console client %T does not support breaks", s.client)
+	}
+	return b.SendBreak(ctx)
+}
+
+// Transcript returns the transcript of the session so far: all of the
+// output of the console, including the output matched by Expect, interleaved
+// with the text sent by Send as it would appear on a terminal without echo.
+func (s *Session) Transcript() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transcript.String()
+}
+
+// Close closes the session.
+// Tests need only call Close to close the session before the test ends.
+func (s *Session) Close(t testing.TB) {
+	t.Helper()
+	if err := s.close(); err != nil {
+		t.Fatalf("Close(t) on console session of %s: %v", s.dut, err)
+	}
+}
+
+func (s *Session) close() error {
+	s.mu.Lock()
+	closed := s.closed
+	s.closed = true
+	s.mu.Unlock()
+	if closed {
+		return nil
+	}
+	defer s.span.End(nil)
+	if err := s.client.Close(); err != nil {
+		return fmt.Errorf("failed to close the ConsoleClient: %w", err)
+	}
+	<-s.done
+	return nil
+}
+
+func (s *Session) read(r io.Reader) {
+	defer s.wg.Done()
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		s.mu.Lock()
+		s.unmatched = append(s.unmatched, buf[:n]...)
+		s.transcript.Write(buf[:n])
+		if err != nil && err != io.EOF && s.readErr == nil && !s.closed {
+			s.readErr = err
+		}
+		s.mu.Unlock()
+		select {
+		case s.update <- struct{}{}:
+		default:
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package console
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/fakebind"
+)
+
+func TestSession(t *testing.T) {
+	var err error
+	fakeClient, err = fakebind.NewScriptedConsoleClient(
+		&fakebind.ConsoleStep{Output: "Booting...\r\n"},
+		&fakebind.ConsoleStep{Break: true, Output: "rommon 1 > "},
+		&fakebind.ConsoleStep{Expect: `boot\n`, Output: "Proceed with reload? [confirm]"},
+		&fakebind.ConsoleStep{Expect: `y`, Output: "\r\nfakeDUT login: "},
+		&fakebind.ConsoleStep{Expect: `admin\n`, Output: "Welcome, admin (uid 0)\r\nfakeDUT# "},
+	)
+	if err != nil {
+		t.Fatalf("NewScriptedConsoleClient() got unexpected error: %v", err)
+	}
+	s := New(dut).Session(t)
+	s.Expect(t, `Booting`, time.Second)
+	s.SendBreak(t)
+	s.Expect(t, `rommon \d+ > $`, time.Second)
+	s.Send(t, "boot\n")
+	s.Expect(t, `\[confirm\]`, time.Second)
+	s.Send(t, "y")
+	s.Expect(t, `login: $`, time.Second)
+	s.Send(t, "admin\n")
+	got := s.Expect(t, `Welcome, (\w+) \(uid (\d+)\)`, time.Second)
+	if want := []string{"Welcome, admin (uid 0)", "admin", "0"}; !cmp.Equal(got, want) {
+		t.Errorf("Expect() got %q, want %q", got, want)
+	}
+	s.Expect(t, `# $`, time.Second)
+	if err := fakeClient.Wait(); err != nil {
+		t.Errorf("console script got unexpected error: %v", err)
+	}
+
+	wantTranscript := "Booting...\r\n" +
+		"rommon 1 > boot\n" +
+		"Proceed with reload? [confirm]y" +
+		"\r\nfakeDUT login: admin\n" +
+		"Welcome, admin (uid 0)\r\nfakeDUT# "
+	if got := s.Transcript(); got != wantTranscript {
+		t.Errorf("Transcript() got %q, want %q", got, wantTranscript)
+	}
+	s.Close(t)
+}
+
+func TestExpectErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		close   bool
+		wantErr string
+	}{{
+		desc:    "timeout",
+		wantErr: `timed out after 50ms; unmatched output: "no match here"`,
+	}, {
+		desc:    "closed",
+		close:   true,
+		wantErr: `console output ended with error EOF; unmatched output: "no match here"`,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var err error
+			fakeClient, err = fakebind.NewScriptedConsoleClient(&fakebind.ConsoleStep{Output: "no match here"})
+			if err != nil {
+				t.Fatalf("NewScriptedConsoleClient() got unexpected error: %v", err)
+			}
+			s, err := New(dut).startSession(context.Background())
+			if err != nil {
+				t.Fatalf("startSession() got unexpected error: %v", err)
+			}
+			defer s.close()
+			if err := fakeClient.Wait(); err != nil {
+				t.Fatalf("console script got unexpected error: %v", err)
+			}
+			if test.close {
+				fakeClient.OutWriter.Close()
+				fakeClient.ErrWriter.Close()
+			}
+			_, gotErr := s.expect(regexp.MustCompile("login:"), 50*time.Millisecond)
+			if gotErr == nil || gotErr.Error() != test.wantErr {
+				t.Errorf("expect() got error %v, want %q", gotErr, test.wantErr)
+			}
+		})
+	}
+}
+
+type unbreakableClient struct {
+	*binding.AbstractConsoleClient
+}
+
+func (*unbreakableClient) Stdout() io.ReadCloser {
+	return io.NopCloser(strings.NewReader(""))
+}
+
+func (*unbreakableClient) Stderr() io.ReadCloser {
+	return io.NopCloser(strings.NewReader(""))
+}
+
+func (*unbreakableClient) Close() error {
+	return nil
+}
+
+func TestSendBreakPlainFake(t *testing.T) {
+	fakeClient = fakebind.NewConsoleClient()
+	s := New(dut).Session(t)
+	s.SendBreak(t)
+	s.SendBreak(t)
+	if got, want := len(fakeClient.Breaks), 2; got != want {
+		t.Errorf("SendBreak(t) queued %d breaks, want %d", got, want)
+	}
+	s.Close(t)
+}
+
+func TestSendBreakUnsupported(t *testing.T) {
+	dut := &fakebind.DUT{
+		AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{Name: "fakeDUT"}},
+		DialConsoleFn: func(context.Context) (binding.ConsoleClient, error) {
+			return &unbreakableClient{}, nil
+		},
+	}
+	s, err := New(dut).startSession(context.Background())
+	if err != nil {
+		t.Fatalf("startSession() got unexpected error: %v", err)
+	}
+	if err := s.sendBreak(context.Background()); err == nil || !strings.Contains(err.Error(), "does not support breaks") {
+		t.Errorf("sendBreak() got error %v, want unsupported", err)
+	}
+	if err := s.close(); err != nil {
+		t.Errorf("close() got unexpected error: %v", err)
+	}
+}
@@ -174,10 +174,20 @@ const (
 	Speed5Gb = Speed(opb.Port_S_5GB)
 	// Speed10Gb is a port speed of 10Gbps.
 	Speed10Gb = Speed(opb.Port_S_10GB)
+	// Speed25Gb is a port speed of 25Gbps.
+	Speed25Gb = Speed(opb.Port_S_25GB)
+	// Speed40Gb is a port speed of 40Gbps.
+	Speed40Gb = Speed(opb.Port_S_40GB)
+	// Speed50Gb is a port speed of 50Gbps.
+	Speed50Gb = Speed(opb.Port_S_50GB)
 	// Speed100Gb is a port speed of 100Gbps.
 	Speed100Gb = Speed(opb.Port_S_100GB)
+	// Speed200Gb is a port speed of 200Gbps.
+	Speed200Gb = Speed(opb.Port_S_200GB)
 	// Speed400Gb is a port speed of 400Gbps.
 	Speed400Gb = Speed(opb.Port_S_400GB)
+	// Speed800Gb is a port speed of 800Gbps.
+	Speed800Gb = Speed(opb.Port_S_800GB)
 )
 
 // Speed returns the port speed.
@@ -250,6 +260,30 @@ const (
 	PMD400GBASELR8 = PMD(opb.Port_PMD_400GBASE_LR8)
 	// PMD400GBASEDR4 is the PMD 400GBASE-DR4.
 	PMD400GBASEDR4 = PMD(opb.Port_PMD_400GBASE_DR4)
+	// PMD100GBASEDR is the PMD 100GBASE-DR.
+	PMD100GBASEDR = PMD(opb.Port_PMD_100GBASE_DR)
+	// PMD400GBASEZRPLUS is the PMD 400GBASE-ZR+.
+	PMD400GBASEZRPLUS = PMD(opb.Port_PMD_400GBASE_ZR_PLUS)
+	// PMD800GBASEDR8 is the PMD 800GBASE-DR8.
+	PMD800GBASEDR8 = PMD(opb.Port_PMD_800GBASE_DR8)
+	// PMD800GBASESR8 is the PMD 800GBASE-SR8.
+	PMD800GBASESR8 = PMD(opb.Port_PMD_800GBASE_SR8)
+	// PMD800GBASECR8 is the PMD 800GBASE-CR8.
+	PMD800GBASECR8 = PMD(opb.Port_PMD_800GBASE_CR8)
+	// PMD800GBASE2XFR4 is the PMD 800GBASE-2XFR4.
+	PMD800GBASE2XFR4 = PMD(opb.Port_PMD_800GBASE_2XFR4)
+	// PMD800GBASEZR is the PMD 800GBASE-ZR.
+	PMD800GBASEZR = PMD(opb.Port_PMD_800GBASE_ZR)
+	// PMD4X25GBASESR is the breakout PMD 4X25GBASE-SR.
+	PMD4X25GBASESR = PMD(opb.Port_PMD_4X25GBASE_SR)
+	// PMD4X100GBASEDR is the breakout PMD 4X100GBASE-DR.
+	PMD4X100GBASEDR = PMD(opb.Port_PMD_4X100GBASE_DR)
+	// PMD4X100GBASEFR is the breakout PMD 4X100GBASE-FR.
+	PMD4X100GBASEFR = PMD(opb.Port_PMD_4X100GBASE_FR)
+	// PMD8X100GBASEDR is the breakout PMD 8X100GBASE-DR.
+	PMD8X100GBASEDR = PMD(opb.Port_PMD_8X100GBASE_DR)
+	// PMD2X400GBASEDR4 is the breakout PMD 2X400GBASE-DR4.
+	PMD2X400GBASEDR4 = PMD(opb.Port_PMD_2X400GBASE_DR4)
 )
 
 func (pmd PMD) String() string {
@@ -260,3 +294,11 @@ func (pmd PMD) String() string {
 func (p *Port) PMD() PMD {
 	return PMD(p.res.PMD)
 }
+
+// BreakoutGroup returns the breakout group of the port, which identifies the
+// physical port of which the port is a breakout channel. All the channels of a
+// physical port have the same breakout group. It returns the empty string if
+// the port is not a breakout channel.
+func (p *Port) BreakoutGroup() string {
+	return p.res.BreakoutGroup
+}
@@ -15,10 +15,20 @@
 package ondatra
 
 import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
 	"github.com/openconfig/ondatra/binding"
 	"github.com/openconfig/ondatra/cli"
 	"github.com/openconfig/ondatra/config"
 	"github.com/openconfig/ondatra/console"
+	"github.com/openconfig/ondatra/gnmi"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ondatra/gnmi/oc/ocpath"
+	"github.com/openconfig/ondatra/internal/events"
+	"github.com/openconfig/ondatra/internal/rawapis"
 	"github.com/openconfig/ondatra/raw"
 )
 
@@ -30,12 +40,14 @@ type DUTDevice struct {
 // Config returns a handle to the DUT configuration API.
 func (d *DUTDevice) Config() *Config {
 	return &Config{
+		dev: d.Device,
 		dut: d.res.(binding.DUT),
 	}
 }
 
 // Config is the DUT configuration API.
 type Config struct {
+	dev *Device
 	dut binding.DUT
 }
 
@@ -44,6 +56,123 @@ func (c *Config) New() *config.VendorConfig {
 	return config.NewVendorConfig(c.dut)
 }
 
+// ConfigSnapshot is a snapshot of the configuration of a DUT.
+type ConfigSnapshot struct {
+	// OC is the OpenConfig config tree of the DUT.
+	OC *oc.Root
+	// CLI is the vendor running-config of the DUT, if it was captured.
+	CLI string
+	// Time is the time at which the snapshot was taken.
+	Time time.Time
+}
+
+// Snapshot captures the full OpenConfig config tree of the DUT via gNMI.
+func (c *Config) Snapshot(t testing.TB) *ConfigSnapshot {
+	t.Helper()
+	t = events.ActionStarted(t, "Taking config snapshot of %s", c.dut)
+	return &ConfigSnapshot{OC: c.fetchOC(t), Time: time.Now()}
+}
+
+// SnapshotWithCLI captures the full OpenConfig config tree of the DUT via
+// gNMI and the output of the specified CLI command, which is typically the
+// vendor command that shows the running-config.
+func (c *Config) SnapshotWithCLI(t testing.TB, cmd string) *ConfigSnapshot {
+	t.Helper()
+	t = events.ActionStarted(t, "Taking config snapshot of %s", c.dut)
+	snap := &ConfigSnapshot{OC: c.fetchOC(t), Time: time.Now()}
+	ctx := context.Background()
+	cliClient, err := rawapis.NewCLI(ctx, c.dut)
+	if err != nil {
+		t.Fatalf("SnapshotWithCLI(t, %q) on %s: %v", cmd, c.dut, err)
+	}
+	res, err := cliClient.RunCommand(ctx, cmd)
+	if err != nil {
+		t.Fatalf("SnapshotWithCLI(t, %q) on %s: %v", cmd, c.dut, err)
+	}
+	if res.Error() != "" {
+		t.Fatalf("SnapshotWithCLI(t, %q) on %s: command failed: %s", cmd, c.dut, res.Error())
+	}
+	snap.CLI = res.Output()
+	return snap
+}
+
+// fetchOC fetches the OpenConfig config tree of the DUT. It fails fatally if
+// the DUT returns no config, rather than treating it as an empty tree, which
+// Restore would push by deleting every leaf.
+func (c *Config) fetchOC(t testing.TB) *oc.Root {
+	t.Helper()
+	root, ok := gnmi.Lookup(t, c.dev, ocpath.Root().Config()).Val()
+	if !ok {
+		t.Fatalf("Fetching the OpenConfig config of %s: no config returned", c.dut)
+	}
+	return root
+}
+
+// Restore restores the OpenConfig config tree of the DUT to the specified
+// snapshot, with a single gNMI Set of only the leaves that differ from the
+// current config. The vendor running-config of the snapshot, if any, is not
+// pushed to the device; it is only used for reporting by Diff.
+func (c *Config) Restore(t testing.TB, snap *ConfigSnapshot) {
+	t.Helper()
+	t = events.ActionStarted(t, "Restoring config snapshot to %s", c.dut)
+	req, err := config.DiffSetRequest(c.fetchOC(t), snap.OC)
+	if err != nil {
+		t.Fatalf("Restore(t) on %s: %v", c.dut, err)
+	}
+	if len(req.GetUpdate()) == 0 && len(req.GetDelete()) == 0 {
+		return
+	}
+	ctx := context.Background()
+	gnmiClient, err := rawapis.FetchGNMI(ctx, c.dut)
+	if err != nil {
+		t.Fatalf("Restore(t) on %s: %v", c.dut, err)
+	}
+	if _, err := gnmiClient.Set(ctx, req); err != nil {
+		t.Fatalf("Restore(t) on %s: %v", c.dut, err)
+	}
+}
+
+// RestoreOnCleanup registers a cleanup function with the test that restores
+// the specified snapshot, so that the test leaves the DUT as it found it:
+//
+//	dut.Config().RestoreOnCleanup(t, dut.Config().Snapshot(t))
+func (c *Config) RestoreOnCleanup(t testing.TB, snap *ConfigSnapshot) {
+	t.Helper()
+	t.Cleanup(func() {
+		c.Restore(t, snap)
+	})
+}
+
+// Diff returns a human-readable diff between two snapshots, or the empty
+// string if they are equal. OpenConfig leaves and vendor running-config
+// lines only in snapshot "a" are prefixed by "-" and those only in snapshot
+// "b" are prefixed by "+".
+func (c *Config) Diff(t testing.TB, a, b *ConfigSnapshot) string {
+	t.Helper()
+	diff, err := config.DiffText(a.OC, b.OC)
+	if err != nil {
+		t.Fatalf("Diff(t) on %s: %v", c.dut, err)
+	}
+	if a.CLI != "" || b.CLI != "" {
+		diff += config.DiffLines(a.CLI, b.CLI)
+	}
+	return diff
+}
+
+// Checkpoint saves the config of the DUT, so that the test can later restore
+// it with Rollback. Unlike Snapshot, which captures the OpenConfig config
+// tree, a checkpoint captures the full native config of the DUT.
+func (c *Config) Checkpoint(t testing.TB) *config.Checkpoint {
+	t.Helper()
+	return config.TakeCheckpoint(t, c.dut)
+}
+
+// Rollback restores the config of the DUT to the specified checkpoint.
+func (c *Config) Rollback(t testing.TB, cp *config.Checkpoint) {
+	t.Helper()
+	config.Rollback(t, c.dut, cp)
+}
+
 // CLI returns a handle to the DUT CLI API.
 func (d *DUTDevice) CLI() *cli.CLI {
 	return cli.New(d.res.(binding.DUT))
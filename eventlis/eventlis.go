@@ -31,6 +31,15 @@
 //		handleAfterTestsEvent(e)
 //	})
 //
+// Code can register a callback to be invoked whenever the framework fails to
+// renew the reservation, either by a periodic heartbeat or by an explicit call
+// to ondatra.ExtendReservation, by calling
+// [EventListener.AddRenewalFailedCallback]:
+//
+//	ondatra.EventListener().AddRenewalFailedCallback(func (e *eventlis.RenewalFailedEvent) {
+//		handleRenewalFailedEvent(e)
+//	})
+//
 // These calls can be made from the test itself, from the binding, or from a
 // helper library that the test imports.
 package eventlis
@@ -46,7 +55,11 @@ type EventListener struct{}
 // BeforeTestsEvent occurs after the reservation is complete and before tests
 // start executing.
 type BeforeTestsEvent struct {
+	// Reservation is the reservation of the first testbed.
 	Reservation *binding.Reservation
+	// Reservations are the reservations of all the testbeds, in the order of
+	// their bindings. There is more than one when tests run in parallel.
+	Reservations []*binding.Reservation
 }
 
 // AfterTestsEvent occurs after the tests are finished executing and before
@@ -55,11 +68,16 @@ type AfterTestsEvent struct {
 	ExitCode *int
 }
 
+// RenewalFailedEvent occurs when the framework fails to renew the reservation.
+type RenewalFailedEvent struct {
+	Err error
+}
+
 // AddBeforeTestsCallback adds a callback to run after the reservation is
 // complete and before tests start executing.
 func (el *EventListener) AddBeforeTestsCallback(cb func(*BeforeTestsEvent) error) {
-	events.AddBeforeTests(func(res *binding.Reservation) error {
-		return cb(&BeforeTestsEvent{Reservation: res})
+	events.AddBeforeTests(func(rs []*binding.Reservation) error {
+		return cb(&BeforeTestsEvent{Reservation: rs[0], Reservations: rs})
 	})
 }
 
@@ -70,3 +88,11 @@ func (el *EventListener) AddAfterTestsCallback(cb func(*AfterTestsEvent) error)
 		return cb(&AfterTestsEvent{ExitCode: exitCode})
 	})
 }
+
+// AddRenewalFailedCallback adds a callback to run when the framework fails to
+// renew the reservation.
+func (el *EventListener) AddRenewalFailedCallback(cb func(*RenewalFailedEvent) error) {
+	events.AddRenewalFailed(func(err error) error {
+		return cb(&RenewalFailedEvent{Err: err})
+	})
+}
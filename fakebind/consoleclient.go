@@ -15,11 +15,20 @@
 package fakebind
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"regexp"
+	"sync"
+
+	"golang.org/x/net/context"
 
 	"github.com/openconfig/ondatra/binding"
 )
 
+// maxPendingBreaks is the number of breaks that the Breaks channel buffers.
+const maxPendingBreaks = 16
+
 // ConsoleClient is a fake implementation of ConsoleClient for use in testing.
 type ConsoleClient struct {
 	*binding.AbstractConsoleClient
@@ -29,9 +38,19 @@ type ConsoleClient struct {
 	InReader  *io.PipeReader
 	OutWriter *io.PipeWriter
 	ErrWriter *io.PipeWriter
-	cErr      error
+	// Breaks receives a value for each break sent to the console. It buffers
+	// breaks, so tests need not receive from it unless they check for breaks.
+	Breaks chan struct{}
+	cErr   error
+
+	closeOnce  sync.Once
+	closed     chan struct{}
+	scriptDone chan struct{}
+	scriptErr  error
 }
 
+var _ binding.ConsoleBreaker = (*ConsoleClient)(nil)
+
 // NewConsoleClient returns a new ConsoleClient.
 // Tests should access the stub side of the pipes via exported fields.
 func NewConsoleClient() *ConsoleClient {
@@ -42,6 +61,8 @@ func NewConsoleClient() *ConsoleClient {
 		InReader:  inReader,
 		OutWriter: outWriter,
 		ErrWriter: errWriter,
+		Breaks:    make(chan struct{}, maxPendingBreaks),
+		closed:    make(chan struct{}),
 		stdin:     inWriter,
 		stdout:    outReader,
 		stderr:    errReader,
@@ -53,6 +74,7 @@ func (f *ConsoleClient) Close() error {
 	f.InReader.Close()
 	f.OutWriter.Close()
 	f.ErrWriter.Close()
+	f.closeOnce.Do(func() { close(f.closed) })
 	return f.cErr
 }
 
@@ -70,3 +92,106 @@ func (f *ConsoleClient) Stdout() io.ReadCloser {
 func (f *ConsoleClient) Stderr() io.ReadCloser {
 	return f.stderr
 }
+
+// SendBreak sends a value on the Breaks channel without blocking. It fails if
+// the client is closed or the channel is full of breaks that were never
+// received.
+func (f *ConsoleClient) SendBreak(ctx context.Context) error {
+	select {
+	case <-f.closed:
+		return errors.New("console client is closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	select {
+	case f.Breaks <- struct{}{}:
+		return nil
+	default:
+		return fmt.Errorf("%d breaks sent to the console were never received", maxPendingBreaks)
+	}
+}
+
+// ConsoleStep is a step of a console script.
+type ConsoleStep struct {
+	// Expect is a regular expression that the input to the console must match
+	// before the step writes its output. If empty, the step does not wait for
+	// input.
+	Expect string
+	// Break, if true, makes the step wait for a break before it writes its
+	// output. Break and Expect are mutually exclusive.
+	Break bool
+	// Output is written to the stdout of the console.
+	Output string
+}
+
+// NewScriptedConsoleClient returns a new ConsoleClient that plays the
+// specified steps on the stub side of its pipes, in order. The input that a
+// step matches is consumed, so a later step only matches subsequent input.
+// Tests can call Wait to await the end of the script.
+func NewScriptedConsoleClient(steps ...*ConsoleStep) (*ConsoleClient, error) {
+	res := make([]*regexp.Regexp, len(steps))
+	for i, step := range steps {
+		if step.Expect == "" {
+			continue
+		}
+		if step.Break {
+			return nil, fmt.Errorf("console step %d both expects %q and a break", i, step.Expect)
+		}
+		re, err := regexp.Compile(step.Expect)
+		if err != nil {
+			return nil, fmt.Errorf("console step %d has invalid regexp: %w", i, err)
+		}
+		res[i] = re
+	}
+	f := NewConsoleClient()
+	f.scriptDone = make(chan struct{})
+	go func() {
+		defer close(f.scriptDone)
+		f.scriptErr = f.play(steps, res)
+	}()
+	return f, nil
+}
+
+// Wait waits until the script of a scripted ConsoleClient ends and returns
+// the error that ended it, if any.
+func (f *ConsoleClient) Wait() error {
+	if f.scriptDone == nil {
+		return errors.New("console client is not scripted")
+	}
+	<-f.scriptDone
+	return f.scriptErr
+}
+
+func (f *ConsoleClient) play(steps []*ConsoleStep, res []*regexp.Regexp) error {
+	var in []byte
+	buf := make([]byte, 4096)
+	for i, step := range steps {
+		switch {
+		case step.Break:
+			select {
+			case <-f.Breaks:
+			case <-f.closed:
+				return fmt.Errorf("console closed while step %d awaited a break", i)
+			}
+		case res[i] != nil:
+			for {
+				if loc := res[i].FindIndex(in); loc != nil {
+					in = in[loc[1]:]
+					break
+				}
+				n, err := f.InReader.Read(buf)
+				if err != nil {
+					return fmt.Errorf("console step %d awaiting %q got input %q and error: %w", i, step.Expect, in, err)
+				}
+				in = append(in, buf[:n]...)
+			}
+		}
+		if step.Output != "" {
+			if _, err := io.WriteString(f.OutWriter, step.Output); err != nil {
+				return fmt.Errorf("console step %d failed to write output: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
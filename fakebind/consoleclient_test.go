@@ -15,9 +15,13 @@
 package fakebind
 
 import (
+	"io"
 	"math/rand"
+	"strings"
 	"sync"
 	"testing"
+
+	"golang.org/x/net/context"
 )
 
 func TestFake(t *testing.T) {
@@ -57,6 +61,55 @@ func TestFake(t *testing.T) {
 	}
 }
 
+func TestScriptedConsoleClient(t *testing.T) {
+	f, err := NewScriptedConsoleClient(
+		&ConsoleStep{Output: "login: "},
+		&ConsoleStep{Expect: `^\w+\n`, Output: "Password: "},
+		&ConsoleStep{Break: true, Output: "rommon > "},
+	)
+	if err != nil {
+		t.Fatalf("NewScriptedConsoleClient() got unexpected error: %v", err)
+	}
+	go func() {
+		f.Stdin().Write([]byte("admin\n"))
+		f.SendBreak(context.Background())
+	}()
+	out, err := io.ReadAll(io.LimitReader(f.Stdout(), int64(len("login: Password: rommon > "))))
+	if err != nil {
+		t.Fatalf("ReadAll() got unexpected error: %v", err)
+	}
+	if got, want := string(out), "login: Password: rommon > "; got != want {
+		t.Errorf("Stdout got %q, want %q", got, want)
+	}
+	if err := f.Wait(); err != nil {
+		t.Errorf("Wait() got unexpected error: %v", err)
+	}
+}
+
+func TestScriptedConsoleClientErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		step    *ConsoleStep
+		wantErr string
+	}{{
+		desc:    "expect and break",
+		step:    &ConsoleStep{Expect: "a", Break: true},
+		wantErr: "both expects",
+	}, {
+		desc:    "invalid regexp",
+		step:    &ConsoleStep{Expect: "("},
+		wantErr: "invalid regexp",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := NewScriptedConsoleClient(test.step)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("NewScriptedConsoleClient() got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
 var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
 func randSeq(n int) string {
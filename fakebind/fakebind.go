@@ -83,6 +83,42 @@ func (b *Binding) FetchReservation(ctx context.Context, id string) (*binding.Res
 	return b.FetchReservationFn(ctx, id)
 }
 
+var _ binding.Renewer = (*RenewableBinding)(nil)
+
+// RenewableBinding is a fake binding.Binding implementation that also
+// implements binding.Renewer.
+type RenewableBinding struct {
+	*Binding
+	ExtendFn    func(context.Context, time.Duration) error
+	HeartbeatFn func(context.Context) error
+}
+
+// SetupRenewable initializes Ondatra with a new fake renewable binding,
+// initializes the testbed to an unreserved state, and returns the fake binding
+// for further stubbing.
+func SetupRenewable() *RenewableBinding {
+	bind := &RenewableBinding{Binding: new(Binding)}
+	testbed.SetBinding(bind)
+	bind.WithReservation(nil)
+	return bind
+}
+
+// Extend delegates to b.ExtendFn.
+func (b *RenewableBinding) Extend(ctx context.Context, d time.Duration) error {
+	if b.ExtendFn == nil {
+		log.Fatal("fakebind Extend called but ExtendFn not set")
+	}
+	return b.ExtendFn(ctx, d)
+}
+
+// Heartbeat delegates to b.HeartbeatFn.
+func (b *RenewableBinding) Heartbeat(ctx context.Context) error {
+	if b.HeartbeatFn == nil {
+		log.Fatal("fakebind Heartbeat called but HeartbeatFn not set")
+	}
+	return b.HeartbeatFn(ctx)
+}
+
 var _ binding.DUT = (*DUT)(nil)
 
 // DUT is a fake implementation of binding.DUT comprised of stubs.
@@ -195,3 +231,47 @@ func (a *ATE) DialOTG(ctx context.Context, opts ...grpc.DialOption) (gosnappi.Go
 	}
 	return a.DialOTGFn(ctx, opts...)
 }
+
+var _ binding.ConfigCheckpointer = (*CheckpointingDUT)(nil)
+
+// CheckpointingDUT is a fake binding.DUT implementation that also implements
+// binding.ConfigCheckpointer.
+type CheckpointingDUT struct {
+	*DUT
+	CheckpointConfigFn            func(context.Context) (string, error)
+	RollbackConfigFn              func(context.Context, string) error
+	PushConfigWithCommitConfirmFn func(context.Context, string, bool, time.Duration) (string, error)
+	ConfirmConfigFn               func(context.Context, string) error
+}
+
+// CheckpointConfig delegates to d.CheckpointConfigFn.
+func (d *CheckpointingDUT) CheckpointConfig(ctx context.Context) (string, error) {
+	if d.CheckpointConfigFn == nil {
+		log.Fatal("fakebind CheckpointConfig called but CheckpointConfigFn not set")
+	}
+	return d.CheckpointConfigFn(ctx)
+}
+
+// RollbackConfig delegates to d.RollbackConfigFn.
+func (d *CheckpointingDUT) RollbackConfig(ctx context.Context, id string) error {
+	if d.RollbackConfigFn == nil {
+		log.Fatal("fakebind RollbackConfig called but RollbackConfigFn not set")
+	}
+	return d.RollbackConfigFn(ctx, id)
+}
+
+// PushConfigWithCommitConfirm delegates to d.PushConfigWithCommitConfirmFn.
+func (d *CheckpointingDUT) PushConfigWithCommitConfirm(ctx context.Context, config string, reset bool, timeout time.Duration) (string, error) {
+	if d.PushConfigWithCommitConfirmFn == nil {
+		log.Fatal("fakebind PushConfigWithCommitConfirm called but PushConfigWithCommitConfirmFn not set")
+	}
+	return d.PushConfigWithCommitConfirmFn(ctx, config, reset, timeout)
+}
+
+// ConfirmConfig delegates to d.ConfirmConfigFn.
+func (d *CheckpointingDUT) ConfirmConfig(ctx context.Context, id string) error {
+	if d.ConfirmConfigFn == nil {
+		log.Fatal("fakebind ConfirmConfig called but ConfirmConfigFn not set")
+	}
+	return d.ConfirmConfigFn(ctx, id)
+}
@@ -0,0 +1,214 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expect
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+	"golang.org/x/exp/constraints"
+)
+
+// maxSamples is the number of recent samples in the report of a failed
+// Eventually expectation.
+const maxSamples = 5
+
+// checker evaluates an expectation over the samples of a query. The samples
+// are observed by a single goroutine, and the report is only requested after
+// the last sample is observed.
+type checker[T any] interface {
+	// observe observes a sample and reports whether the outcome of the
+	// expectation is already decided, so no more samples are needed.
+	observe(v *ygnmi.Value[T]) bool
+	// report returns why the expectation failed, or the empty string if it
+	// held over the window.
+	report(window time.Duration) string
+}
+
+// eventually holds if any sample satisfies the predicate.
+type eventually[T any] struct {
+	pred   func(*ygnmi.Value[T]) bool
+	ok     bool
+	recent []*ygnmi.Value[T]
+}
+
+func (c *eventually[T]) observe(v *ygnmi.Value[T]) bool {
+	if c.pred(v) {
+		c.ok = true
+		return true
+	}
+	c.recent = append(c.recent, v)
+	if len(c.recent) > maxSamples {
+		c.recent = c.recent[1:]
+	}
+	return false
+}
+
+func (c *eventually[T]) report(window time.Duration) string {
+	switch {
+	case c.ok:
+		return ""
+	case len(c.recent) == 0:
+		return fmt.Sprintf("no samples received within %v", window)
+	}
+	return fmt.Sprintf("no sample satisfied the predicate within %v; last samples:\n%s", window, formatSamples(c.recent))
+}
+
+// always holds if every sample satisfies the predicate and at least one
+// sample is received. If want is false, it instead holds if no sample
+// satisfies the predicate, which does not require any samples.
+type always[T any] struct {
+	pred func(*ygnmi.Value[T]) bool
+	want bool
+	n    int
+	bad  *ygnmi.Value[T]
+}
+
+func (c *always[T]) observe(v *ygnmi.Value[T]) bool {
+	c.n++
+	if c.pred(v) != c.want {
+		c.bad = v
+		return true
+	}
+	return false
+}
+
+func (c *always[T]) report(window time.Duration) string {
+	switch {
+	case c.bad != nil && c.want:
+		return fmt.Sprintf("sample %d did not satisfy the predicate:\n%s", c.n, formatSamples([]*ygnmi.Value[T]{c.bad}))
+	case c.bad != nil:
+		return fmt.Sprintf("sample %d satisfied the predicate:\n%s", c.n, formatSamples([]*ygnmi.Value[T]{c.bad}))
+	case c.n == 0 && c.want:
+		return fmt.Sprintf("no samples received within %v", window)
+	}
+	return ""
+}
+
+// changes holds if the value at each path changes at most max times.
+type changes[T any] struct {
+	max int
+	// samples holds, for each path, the first sample and every sample that
+	// changed the value.
+	samples map[string][]*ygnmi.Value[T]
+	failed  string
+}
+
+func (c *changes[T]) observe(v *ygnmi.Value[T]) bool {
+	path := samplePath(v)
+	prev := c.samples[path]
+	if len(prev) > 0 {
+		last := prev[len(prev)-1]
+		lastVal, lastOK := last.Val()
+		val, ok := v.Val()
+		if lastOK == ok && reflect.DeepEqual(lastVal, val) {
+			return false
+		}
+	}
+	c.samples[path] = append(prev, v)
+	if len(c.samples[path])-1 > c.max {
+		c.failed = path
+		return true
+	}
+	return false
+}
+
+func (c *changes[T]) report(time.Duration) string {
+	if c.failed == "" {
+		return ""
+	}
+	samples := c.samples[c.failed]
+	return fmt.Sprintf("value at %s changed %d times, want at most %d:\n%s", c.failed, len(samples)-1, c.max, formatSamples(samples))
+}
+
+// number is a numeric telemetry value, such as a counter.
+type number interface {
+	constraints.Integer | constraints.Float
+}
+
+// rate holds if the rate of increase of the value at each path, between the
+// first and last present samples, is within the range.
+type rate[T number] struct {
+	lo, hi      float64
+	first, last map[string]*ygnmi.Value[T]
+}
+
+func (c *rate[T]) observe(v *ygnmi.Value[T]) bool {
+	if !v.IsPresent() {
+		return false
+	}
+	path := samplePath(v)
+	if _, ok := c.first[path]; !ok {
+		c.first[path] = v
+	}
+	c.last[path] = v
+	return false
+}
+
+func (c *rate[T]) report(window time.Duration) string {
+	if len(c.first) == 0 {
+		return fmt.Sprintf("no samples received within %v", window)
+	}
+	var paths []string
+	for path := range c.first {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var fails []string
+	for _, path := range paths {
+		first, last := c.first[path], c.last[path]
+		samples := formatSamples([]*ygnmi.Value[T]{first, last})
+		secs := last.Timestamp.Sub(first.Timestamp).Seconds()
+		if secs <= 0 {
+			fails = append(fails, fmt.Sprintf("value at %s has no two samples with different timestamps:\n%s", path, samples))
+			continue
+		}
+		firstVal, _ := first.Val()
+		lastVal, _ := last.Val()
+		r := (float64(lastVal) - float64(firstVal)) / secs
+		if r < c.lo || r > c.hi {
+			fails = append(fails, fmt.Sprintf("value at %s changed at %.6g/s, want between %g/s and %g/s:\n%s", path, r, c.lo, c.hi, samples))
+		}
+	}
+	return strings.Join(fails, "\n")
+}
+
+// samplePath returns the path of the sample as a string.
+func samplePath[T any](v *ygnmi.Value[T]) string {
+	path, err := ygot.PathToString(v.Path)
+	if err != nil {
+		return v.Path.String()
+	}
+	return path
+}
+
+// formatSamples formats samples one per line, with their path, timestamp,
+// and value.
+func formatSamples[T any](vs []*ygnmi.Value[T]) string {
+	var lines []string
+	for _, v := range vs {
+		val := "(not present)"
+		if got, ok := v.Val(); ok {
+			val = fmt.Sprintf("%+v", got)
+		}
+		lines = append(lines, fmt.Sprintf("  %s @ %s: %s", samplePath(v), v.Timestamp.Format(time.RFC3339Nano), val))
+	}
+	return strings.Join(lines, "\n")
+}
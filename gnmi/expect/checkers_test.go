@@ -0,0 +1,191 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expect
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func sample[T any](t *testing.T, path string, sec int, val T) *ygnmi.Value[T] {
+	t.Helper()
+	p, err := ygot.StringToStructuredPath(path)
+	if err != nil {
+		t.Fatalf("StringToStructuredPath(%q) failed: %v", path, err)
+	}
+	v := &ygnmi.Value[T]{Path: p, Timestamp: start.Add(time.Duration(sec) * time.Second)}
+	return v.SetVal(val)
+}
+
+func absent[T any](t *testing.T, path string, sec int) *ygnmi.Value[T] {
+	t.Helper()
+	v := sample(t, path, sec, *new(T))
+	return &ygnmi.Value[T]{Path: v.Path, Timestamp: v.Timestamp}
+}
+
+func TestCheckers(t *testing.T) {
+	const (
+		path  = "/interfaces/interface[name=eth0]/state/counters/in-pkts"
+		path2 = "/interfaces/interface[name=eth1]/state/counters/in-pkts"
+	)
+	isOne := func(v *ygnmi.Value[uint64]) bool {
+		val, ok := v.Val()
+		return ok && val == 1
+	}
+	newRate := func(lo, hi float64) checker[uint64] {
+		return &rate[uint64]{lo: lo, hi: hi, first: map[string]*ygnmi.Value[uint64]{}, last: map[string]*ygnmi.Value[uint64]{}}
+	}
+	newChanges := func(k int) checker[uint64] {
+		return &changes[uint64]{max: k, samples: map[string][]*ygnmi.Value[uint64]{}}
+	}
+
+	tests := []struct {
+		desc        string
+		checker     checker[uint64]
+		samples     []*ygnmi.Value[uint64]
+		wantDecided bool
+		// wantReport are substrings of the report; nil means it passed.
+		wantReport []string
+	}{{
+		desc:        "eventually met",
+		checker:     &eventually[uint64]{pred: isOne},
+		samples:     []*ygnmi.Value[uint64]{sample(t, path, 0, uint64(0)), sample(t, path, 1, uint64(1))},
+		wantDecided: true,
+	}, {
+		desc:       "eventually not met",
+		checker:    &eventually[uint64]{pred: isOne},
+		samples:    []*ygnmi.Value[uint64]{sample(t, path, 0, uint64(0)), absent[uint64](t, path, 1)},
+		wantReport: []string{"no sample satisfied the predicate within 1m0s", path + " @ 2024-01-01T00:00:00Z: 0", path + " @ 2024-01-01T00:00:01Z: (not present)"},
+	}, {
+		desc:       "eventually no samples",
+		checker:    &eventually[uint64]{pred: isOne},
+		wantReport: []string{"no samples received within 1m0s"},
+	}, {
+		desc:    "always met",
+		checker: &always[uint64]{pred: isOne, want: true},
+		samples: []*ygnmi.Value[uint64]{sample(t, path, 0, uint64(1)), sample(t, path2, 1, uint64(1))},
+	}, {
+		desc:        "always violated",
+		checker:     &always[uint64]{pred: isOne, want: true},
+		samples:     []*ygnmi.Value[uint64]{sample(t, path, 0, uint64(1)), sample(t, path, 1, uint64(2))},
+		wantDecided: true,
+		wantReport:  []string{"sample 2 did not satisfy the predicate", path + " @ 2024-01-01T00:00:01Z: 2"},
+	}, {
+		desc:       "always no samples",
+		checker:    &always[uint64]{pred: isOne, want: true},
+		wantReport: []string{"no samples received"},
+	}, {
+		desc:    "never met",
+		checker: &always[uint64]{pred: isOne, want: false},
+	}, {
+		desc:        "never violated",
+		checker:     &always[uint64]{pred: isOne, want: false},
+		samples:     []*ygnmi.Value[uint64]{sample(t, path, 0, uint64(0)), sample(t, path, 5, uint64(1))},
+		wantDecided: true,
+		wantReport:  []string{"sample 2 satisfied the predicate", path + " @ 2024-01-01T00:00:05Z: 1"},
+	}, {
+		desc:    "changes at most met",
+		checker: newChanges(1),
+		samples: []*ygnmi.Value[uint64]{
+			sample(t, path, 0, uint64(0)),
+			sample(t, path, 1, uint64(0)),
+			sample(t, path, 2, uint64(1)),
+			sample(t, path2, 3, uint64(5)),
+			sample(t, path2, 4, uint64(6)),
+		},
+	}, {
+		desc:    "changes at most exceeded",
+		checker: newChanges(1),
+		samples: []*ygnmi.Value[uint64]{
+			sample(t, path, 0, uint64(0)),
+			sample(t, path, 1, uint64(1)),
+			absent[uint64](t, path, 2),
+		},
+		wantDecided: true,
+		wantReport: []string{
+			"value at " + path + " changed 2 times, want at most 1",
+			path + " @ 2024-01-01T00:00:00Z: 0",
+			path + " @ 2024-01-01T00:00:01Z: 1",
+			path + " @ 2024-01-01T00:00:02Z: (not present)",
+		},
+	}, {
+		desc:    "rate between met",
+		checker: newRate(90, 110),
+		samples: []*ygnmi.Value[uint64]{
+			sample(t, path, 0, uint64(1000)),
+			absent[uint64](t, path, 5),
+			sample(t, path, 10, uint64(2000)),
+		},
+	}, {
+		desc:    "rate too low on one path",
+		checker: newRate(90, 110),
+		samples: []*ygnmi.Value[uint64]{
+			sample(t, path, 0, uint64(1000)),
+			sample(t, path, 10, uint64(2000)),
+			sample(t, path2, 0, uint64(1000)),
+			sample(t, path2, 10, uint64(1500)),
+		},
+		wantReport: []string{
+			"value at " + path2 + " changed at 50/s, want between 90/s and 110/s",
+			path2 + " @ 2024-01-01T00:00:00Z: 1000",
+			path2 + " @ 2024-01-01T00:00:10Z: 1500",
+		},
+	}, {
+		desc:       "rate counter reset",
+		checker:    newRate(90, 110),
+		samples:    []*ygnmi.Value[uint64]{sample(t, path, 0, uint64(1000)), sample(t, path, 10, uint64(0))},
+		wantReport: []string{"changed at -100/s"},
+	}, {
+		desc:       "rate single sample",
+		checker:    newRate(90, 110),
+		samples:    []*ygnmi.Value[uint64]{sample(t, path, 0, uint64(1000))},
+		wantReport: []string{"has no two samples with different timestamps"},
+	}, {
+		desc:       "rate no samples",
+		checker:    newRate(90, 110),
+		wantReport: []string{"no samples received within 1m0s"},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var decided bool
+			for _, s := range test.samples {
+				if decided = test.checker.observe(s); decided {
+					break
+				}
+			}
+			if decided != test.wantDecided {
+				t.Errorf("observe() got decided %v, want %v", decided, test.wantDecided)
+			}
+			report := test.checker.report(time.Minute)
+			if test.wantReport == nil {
+				if report != "" {
+					t.Errorf("report() got %q, want no failure", report)
+				}
+				return
+			}
+			for _, want := range test.wantReport {
+				if !strings.Contains(report, want) {
+					t.Errorf("report() got %q, want it to contain %q", report, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package expect provides temporal expectations over gNMI telemetry, such as
+// that a value eventually satisfies a predicate, always satisfies it, or
+// changes at a rate within a range.
+//
+// An expectation is built from a ygnmi.SingletonQuery or WildcardQuery and
+// given a time window, and expectations are checked together with [Check],
+// which evaluates them concurrently over a single Subscribe stream and
+// reports every failed expectation with the samples that caused the failure.
+//
+//	expect.Check(t, dut,
+//		expect.Eventually(operStatusQ, isUp).Within(time.Minute),
+//		expect.Always(operStatusQ, isUp).For(30*time.Second),
+//		expect.NeverExceeds(inErrorsQ, 0).For(30*time.Second),
+//		expect.ChangesAtMost(lastChangeQ, 1).For(30*time.Second),
+//		expect.RateBetween(inPktsQ, 900, 1100).For(30*time.Second),
+//	)
+//
+// For a wildcard query, the predicate of an expectation is evaluated on the
+// samples of every matching path; ChangesAtMost and RateBetween consider the
+// values at each path separately.
+package expect
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ondatra/gnmi"
+	"github.com/openconfig/ondatra/internal/trace"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"golang.org/x/exp/constraints"
+)
+
+// Expectation is a temporal expectation about the samples of a telemetry
+// query over a time window.
+type Expectation struct {
+	kind   string
+	method string
+	query  any
+	window time.Duration
+	// err is set if the expectation cannot be checked.
+	err error
+	// start starts watching the query over the window and returns a func that
+	// waits for the outcome and returns why the expectation failed, if it did.
+	start func(t testing.TB, dev gnmi.DeviceOrOpts, window time.Duration) func(testing.TB) string
+}
+
+// For sets the time window over which the expectation must hold.
+func (e *Expectation) For(window time.Duration) *Expectation {
+	e.window = window
+	return e
+}
+
+// Within sets the time window within which the expectation must be met.
+// It is the same as For, but reads better for Eventually.
+func (e *Expectation) Within(window time.Duration) *Expectation {
+	e.window = window
+	return e
+}
+
+// String returns a description of the expectation.
+func (e *Expectation) String() string {
+	return fmt.Sprintf("%s(%v).%s(%v)", e.kind, e.query, e.method, e.window)
+}
+
+// Eventually expects some sample of the query to satisfy the predicate within
+// the window. It is met as soon as one sample does.
+func Eventually[T any](q ygnmi.AnyQuery[T], pred func(*ygnmi.Value[T]) bool) *Expectation {
+	return newExpectation("Eventually", "Within", q, func() checker[T] {
+		return &eventually[T]{pred: pred}
+	})
+}
+
+// Always expects every sample of the query over the window to satisfy the
+// predicate. It fails if no sample is received.
+func Always[T any](q ygnmi.AnyQuery[T], pred func(*ygnmi.Value[T]) bool) *Expectation {
+	return newExpectation("Always", "For", q, func() checker[T] {
+		return &always[T]{pred: pred, want: true}
+	})
+}
+
+// Never expects no sample of the query over the window to satisfy the
+// predicate.
+func Never[T any](q ygnmi.AnyQuery[T], pred func(*ygnmi.Value[T]) bool) *Expectation {
+	return newExpectation("Never", "For", q, func() checker[T] {
+		return &always[T]{pred: pred, want: false}
+	})
+}
+
+// NeverExceeds expects no present sample of the query over the window to be
+// greater than the max.
+func NeverExceeds[T constraints.Ordered](q ygnmi.AnyQuery[T], max T) *Expectation {
+	e := Never(q, func(v *ygnmi.Value[T]) bool {
+		val, ok := v.Val()
+		return ok && val > max
+	})
+	e.kind = fmt.Sprintf("NeverExceeds[%v]", max)
+	return e
+}
+
+// ChangesAtMost expects the value at each path of the query to change at
+// most k times over the window. A change between present and not present
+// counts as a change.
+func ChangesAtMost[T any](q ygnmi.AnyQuery[T], k int) *Expectation {
+	e := newExpectation("ChangesAtMost", "For", q, func() checker[T] {
+		return &changes[T]{max: k, samples: make(map[string][]*ygnmi.Value[T])}
+	})
+	e.kind = fmt.Sprintf("ChangesAtMost[%d]", k)
+	return e
+}
+
+// RateBetween expects the value at each path of the query, typically a
+// counter, to increase at a rate per second between lo and hi inclusive over
+// the window. The rate is computed from the device timestamps of the first
+// and last present samples at the path.
+func RateBetween[T constraints.Integer | constraints.Float](q ygnmi.AnyQuery[T], lo, hi float64) *Expectation {
+	e := newExpectation("RateBetween", "For", q, func() checker[T] {
+		return &rate[T]{
+			lo:    lo,
+			hi:    hi,
+			first: make(map[string]*ygnmi.Value[T]),
+			last:  make(map[string]*ygnmi.Value[T]),
+		}
+	})
+	e.kind = fmt.Sprintf("RateBetween[%g,%g]", lo, hi)
+	return e
+}
+
+func newExpectation[T any](kind, method string, q ygnmi.AnyQuery[T], newChecker func() checker[T]) *Expectation {
+	e := &Expectation{kind: kind, method: method, query: q}
+	var watch func(t testing.TB, dev gnmi.DeviceOrOpts, window time.Duration, pred func(*ygnmi.Value[T]) bool) *gnmi.Watcher[T]
+	switch q := q.(type) {
+	case ygnmi.SingletonQuery[T]:
+		watch = func(t testing.TB, dev gnmi.DeviceOrOpts, window time.Duration, pred func(*ygnmi.Value[T]) bool) *gnmi.Watcher[T] {
+			t.Helper()
+			return gnmi.Watch(t, dev, q, window, pred)
+		}
+	case ygnmi.WildcardQuery[T]:
+		watch = func(t testing.TB, dev gnmi.DeviceOrOpts, window time.Duration, pred func(*ygnmi.Value[T]) bool) *gnmi.Watcher[T] {
+			t.Helper()
+			return gnmi.WatchAll(t, dev, q, window, pred)
+		}
+	default:
+		e.err = fmt.Errorf("query %v is neither a singleton nor a wildcard query", q)
+		return e
+	}
+	e.start = func(t testing.TB, dev gnmi.DeviceOrOpts, window time.Duration) func(testing.TB) string {
+		t.Helper()
+		c := newChecker()
+		w := watch(t, dev, window, c.observe)
+		return func(t testing.TB) string {
+			t.Helper()
+			w.Await(t)
+			return c.report(window)
+		}
+	}
+	return e
+}
+
+// Check checks the expectations concurrently, over a single Subscribe stream
+// to the device, and waits until the outcome of every expectation is known:
+// until its window elapses or a sample decides it earlier. It fails fatally
+// with a report of every failed expectation and its offending samples.
+func Check(t testing.TB, dev gnmi.DeviceOrOpts, exps ...*Expectation) {
+	t.Helper()
+	if len(exps) == 0 {
+		return
+	}
+	for _, e := range exps {
+		if e.err != nil {
+			t.Fatalf("Check(t) on %s: %v", dev, e.err)
+		}
+		if e.window <= 0 {
+			t.Fatalf("Check(t) on %s: expectation %v has no time window; set one with For or Within", dev, e)
+		}
+	}
+	span := trace.Start(t, "expect.Check", "expectations", fmt.Sprint(len(exps)))
+	client, err := dev.GNMIOpts().GNMIClient(context.Background())
+	if err != nil {
+		span.End(err)
+		t.Fatalf("Check(t) on %s: %v", dev, err)
+	}
+	opts := *dev.GNMIOpts()
+	opts.WithClient(newFanout(client, len(exps)))
+	var awaits []func(testing.TB) string
+	for _, e := range exps {
+		awaits = append(awaits, e.start(t, &opts, e.window))
+	}
+	var fails []string
+	for i, await := range awaits {
+		if report := await(t); report != "" {
+			fails = append(fails, fmt.Sprintf("%v: %s", exps[i], report))
+		}
+	}
+	if len(fails) > 0 {
+		err := fmt.Errorf("%d of %d expectations failed:\n%s", len(fails), len(exps), strings.Join(fails, "\n"))
+		span.End(err)
+		t.Fatalf("Check(t) on %s: %v", dev, err)
+	}
+	span.End(nil)
+}
@@ -0,0 +1,310 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expect
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// fanout is a gNMI client that merges the Subscribe RPCs of a fixed number of
+// subscribers into a single Subscribe stream of the underlying client and
+// dispatches to each subscriber only the notifications under its own paths.
+// The stream is opened when every subscriber has sent its subscription
+// request and closed when every subscriber is done.
+type fanout struct {
+	gpb.GNMIClient
+	n int
+
+	mu      sync.Mutex
+	subs    []*subStream
+	active  int
+	started bool
+	cancel  context.CancelFunc
+}
+
+func newFanout(client gpb.GNMIClient, n int) *fanout {
+	return &fanout{GNMIClient: client, n: n, active: n}
+}
+
+// Subscribe returns a stream that receives the notifications of the shared
+// Subscribe stream that match the subscriptions sent on it.
+func (f *fanout) Subscribe(ctx context.Context, _ ...grpc.CallOption) (gpb.GNMI_SubscribeClient, error) {
+	s := &subStream{f: f, ctx: ctx, ready: make(chan struct{}, 1)}
+	go func() {
+		<-ctx.Done()
+		f.release()
+	}()
+	return s, nil
+}
+
+// register adds the subscriber with the subscription list and opens the
+// shared stream once all subscribers are registered.
+func (f *fanout) register(s *subStream, sl *gpb.SubscriptionList) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.started {
+		return fmt.Errorf("all %d subscribers of the shared stream are already registered", f.n)
+	}
+	s.list = sl
+	f.subs = append(f.subs, s)
+	if len(f.subs) < f.n {
+		return nil
+	}
+	f.started = true
+	// The stream outlives the context of any one subscriber, so only the
+	// outgoing metadata of the first subscriber is carried over.
+	ctx, cancel := context.WithCancel(context.Background())
+	if md, ok := metadata.FromOutgoingContext(f.subs[0].ctx); ok {
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	f.cancel = cancel
+	if f.active == 0 {
+		cancel()
+	}
+	stream, err := f.GNMIClient.Subscribe(ctx)
+	if err == nil {
+		err = stream.Send(&gpb.SubscribeRequest{
+			Request: &gpb.SubscribeRequest_Subscribe{Subscribe: mergeLists(f.subs)},
+		})
+	}
+	if err != nil {
+		err = fmt.Errorf("error opening the shared Subscribe stream: %w", err)
+		for _, sub := range f.subs {
+			sub.push(nil, err)
+		}
+		return err
+	}
+	go f.dispatch(stream)
+	return nil
+}
+
+// release marks a subscriber as done and closes the shared stream once all
+// subscribers are done.
+func (f *fanout) release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active--
+	if f.active == 0 && f.cancel != nil {
+		f.cancel()
+	}
+}
+
+// dispatch receives from the shared stream until it fails and delivers each
+// response to the subscribers that it matches.
+func (f *fanout) dispatch(stream gpb.GNMI_SubscribeClient) {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			for _, s := range f.subs {
+				s.push(nil, err)
+			}
+			return
+		}
+		for _, s := range f.subs {
+			if r := filterResponse(resp, s.list); r != nil {
+				s.push(r, nil)
+			}
+		}
+	}
+}
+
+// mergeLists returns a STREAM subscription list with the subscriptions of all
+// the subscribers, without duplicates.
+func mergeLists(subs []*subStream) *gpb.SubscriptionList {
+	merged := proto.Clone(subs[0].list).(*gpb.SubscriptionList)
+	merged.Mode = gpb.SubscriptionList_STREAM
+	merged.Subscription = nil
+	for _, s := range subs {
+	subLoop:
+		for _, sub := range s.list.GetSubscription() {
+			for _, m := range merged.Subscription {
+				if proto.Equal(m, sub) {
+					continue subLoop
+				}
+			}
+			merged.Subscription = append(merged.Subscription, sub)
+		}
+	}
+	return merged
+}
+
+// filterResponse returns the part of the response that matches the
+// subscription list, or nil if no part of it matches. Sync responses match
+// every list.
+func filterResponse(resp *gpb.SubscribeResponse, sl *gpb.SubscriptionList) *gpb.SubscribeResponse {
+	n := resp.GetUpdate()
+	if n == nil {
+		return resp
+	}
+	matches := func(p *gpb.Path) bool {
+		full := &gpb.Path{
+			Origin: n.GetPrefix().GetOrigin(),
+			Elem:   append(append([]*gpb.PathElem{}, n.GetPrefix().GetElem()...), p.GetElem()...),
+		}
+		if full.Origin == "" {
+			full.Origin = p.GetOrigin()
+		}
+		for _, sub := range sl.GetSubscription() {
+			pattern := &gpb.Path{
+				Origin: sl.GetPrefix().GetOrigin(),
+				Elem:   append(append([]*gpb.PathElem{}, sl.GetPrefix().GetElem()...), sub.GetPath().GetElem()...),
+			}
+			if pattern.Origin == "" {
+				pattern.Origin = sub.GetPath().GetOrigin()
+			}
+			if pathMatches(pattern, full) {
+				return true
+			}
+		}
+		return false
+	}
+	filtered := &gpb.Notification{
+		Timestamp: n.GetTimestamp(),
+		Prefix:    n.GetPrefix(),
+		Atomic:    n.GetAtomic(),
+	}
+	for _, u := range n.GetUpdate() {
+		if matches(u.GetPath()) {
+			filtered.Update = append(filtered.Update, u)
+		}
+	}
+	for _, d := range n.GetDelete() {
+		if matches(d) {
+			filtered.Delete = append(filtered.Delete, d)
+		}
+	}
+	if len(filtered.Update) == 0 && len(filtered.Delete) == 0 {
+		return nil
+	}
+	return &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: filtered}}
+}
+
+// pathMatches reports whether the path is at or below the subscription
+// pattern, which may contain "*" and "..." wildcards.
+func pathMatches(pattern, path *gpb.Path) bool {
+	if normOrigin(pattern.GetOrigin()) != normOrigin(path.GetOrigin()) {
+		return false
+	}
+	elems := path.GetElem()
+	for i, pe := range pattern.GetElem() {
+		if pe.GetName() == "..." {
+			return true
+		}
+		if i >= len(elems) {
+			return false
+		}
+		if pe.GetName() != "*" && pe.GetName() != elems[i].GetName() {
+			return false
+		}
+		for k, v := range pe.GetKey() {
+			if v != "*" && elems[i].GetKey()[k] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func normOrigin(origin string) string {
+	if origin == "" {
+		return "openconfig"
+	}
+	return origin
+}
+
+// subStream is the Subscribe stream of one subscriber of a fanout.
+type subStream struct {
+	grpc.ClientStream
+	f     *fanout
+	ctx   context.Context
+	list  *gpb.SubscriptionList
+	ready chan struct{}
+
+	mu    sync.Mutex
+	queue []*gpb.SubscribeResponse
+	err   error
+	sent  bool
+}
+
+// push queues a response or, if err is non-nil, fails the stream after the
+// queued responses are received.
+func (s *subStream) push(resp *gpb.SubscribeResponse, err error) {
+	s.mu.Lock()
+	if err != nil {
+		s.err = err
+	} else {
+		s.queue = append(s.queue, resp)
+	}
+	s.mu.Unlock()
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subStream) Send(req *gpb.SubscribeRequest) error {
+	s.mu.Lock()
+	sent := s.sent
+	s.sent = true
+	s.mu.Unlock()
+	if sent {
+		return errors.New("only one subscription request may be sent on a shared Subscribe stream")
+	}
+	sl := req.GetSubscribe()
+	if sl == nil || sl.GetMode() != gpb.SubscriptionList_STREAM {
+		return fmt.Errorf("only STREAM subscriptions may share a Subscribe stream, got %v", req)
+	}
+	return s.f.register(s, sl)
+}
+
+func (s *subStream) Recv() (*gpb.SubscribeResponse, error) {
+	for {
+		s.mu.Lock()
+		if len(s.queue) > 0 {
+			resp := s.queue[0]
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+			return resp, nil
+		}
+		err := s.err
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case <-s.ctx.Done():
+			return nil, s.ctx.Err()
+		case <-s.ready:
+		}
+	}
+}
+
+func (s *subStream) CloseSend() error {
+	return nil
+}
+
+func (s *subStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expect
+
+import (
+	"io"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// fakeGNMI is a gNMI client with a single Subscribe stream that records the
+// request and replies with fixed responses.
+type fakeGNMI struct {
+	gpb.GNMIClient
+	subscribes int
+	stream     *fakeStream
+}
+
+func (f *fakeGNMI) Subscribe(context.Context, ...grpc.CallOption) (gpb.GNMI_SubscribeClient, error) {
+	f.subscribes++
+	return f.stream, nil
+}
+
+type fakeStream struct {
+	gpb.GNMI_SubscribeClient
+	req   *gpb.SubscribeRequest
+	resps []*gpb.SubscribeResponse
+}
+
+func (s *fakeStream) Send(req *gpb.SubscribeRequest) error {
+	s.req = req
+	return nil
+}
+
+func (s *fakeStream) Recv() (*gpb.SubscribeResponse, error) {
+	if len(s.resps) == 0 {
+		return nil, io.EOF
+	}
+	resp := s.resps[0]
+	s.resps = s.resps[1:]
+	return resp, nil
+}
+
+func mustPath(t *testing.T, s string) *gpb.Path {
+	t.Helper()
+	p, err := ygot.StringToStructuredPath(s)
+	if err != nil {
+		t.Fatalf("StringToStructuredPath(%q) failed: %v", s, err)
+	}
+	return p
+}
+
+func subscribeReq(paths ...*gpb.Path) *gpb.SubscribeRequest {
+	var subs []*gpb.Subscription
+	for _, p := range paths {
+		subs = append(subs, &gpb.Subscription{Path: p})
+	}
+	return &gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Subscribe{Subscribe: &gpb.SubscriptionList{
+		Prefix:       &gpb.Path{Target: "dut"},
+		Subscription: subs,
+		Mode:         gpb.SubscriptionList_STREAM,
+	}}}
+}
+
+func updateResp(t *testing.T, paths ...string) *gpb.SubscribeResponse {
+	t.Helper()
+	n := &gpb.Notification{Timestamp: 1, Prefix: &gpb.Path{Target: "dut", Origin: "openconfig"}}
+	for _, p := range paths {
+		n.Update = append(n.Update, &gpb.Update{Path: mustPath(t, p), Val: &gpb.TypedValue{Value: &gpb.TypedValue_UintVal{UintVal: 1}}})
+	}
+	return &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: n}}
+}
+
+func TestFanout(t *testing.T) {
+	const (
+		eth0Pkts = "/interfaces/interface[name=eth0]/state/counters/in-pkts"
+		eth1Pkts = "/interfaces/interface[name=eth1]/state/counters/in-pkts"
+		hostname = "/system/state/hostname"
+	)
+	syncResp := &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}}
+	stream := &fakeStream{resps: []*gpb.SubscribeResponse{
+		updateResp(t, eth0Pkts, eth1Pkts),
+		syncResp,
+		updateResp(t, hostname),
+	}}
+	client := &fakeGNMI{stream: stream}
+	f := newFanout(client, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reqs := []*gpb.SubscribeRequest{
+		subscribeReq(mustPath(t, "/interfaces/interface[name=*]/state/counters/in-pkts")),
+		subscribeReq(mustPath(t, "/system"), mustPath(t, "/interfaces/interface[name=eth1]")),
+	}
+	var subs []gpb.GNMI_SubscribeClient
+	for _, req := range reqs {
+		sub, err := f.Subscribe(ctx)
+		if err != nil {
+			t.Fatalf("Subscribe() got unexpected error: %v", err)
+		}
+		if err := sub.Send(req); err != nil {
+			t.Fatalf("Send() got unexpected error: %v", err)
+		}
+		subs = append(subs, sub)
+	}
+	if client.subscribes != 1 {
+		t.Errorf("Subscribe() on underlying client called %d times, want 1", client.subscribes)
+	}
+	wantReq := subscribeReq(
+		mustPath(t, "/interfaces/interface[name=*]/state/counters/in-pkts"),
+		mustPath(t, "/system"),
+		mustPath(t, "/interfaces/interface[name=eth1]"),
+	)
+	if diff := cmp.Diff(wantReq, stream.req, protocmp.Transform()); diff != "" {
+		t.Errorf("shared Subscribe request got diff (-want +got):\n%s", diff)
+	}
+
+	wantResps := [][]*gpb.SubscribeResponse{
+		{updateResp(t, eth0Pkts, eth1Pkts), syncResp},
+		{updateResp(t, eth1Pkts), syncResp, updateResp(t, hostname)},
+	}
+	for i, sub := range subs {
+		var got []*gpb.SubscribeResponse
+		for {
+			resp, err := sub.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Recv() on subscriber %d got unexpected error: %v", i, err)
+			}
+			got = append(got, resp)
+		}
+		if diff := cmp.Diff(wantResps[i], got, protocmp.Transform()); diff != "" {
+			t.Errorf("Recv() on subscriber %d got diff (-want +got):\n%s", i, diff)
+		}
+	}
+
+	sub, err := f.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe() got unexpected error: %v", err)
+	}
+	if err := sub.Send(reqs[0]); err == nil {
+		t.Errorf("Send() after all subscribers registered got no error, want error")
+	}
+}
+
+func TestPathMatches(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{pattern: "/interfaces", path: "/interfaces/interface[name=eth0]/state/mtu", want: true},
+		{pattern: "/interfaces/interface[name=*]/state", path: "/interfaces/interface[name=eth0]/state/mtu", want: true},
+		{pattern: "/interfaces/interface[name=eth1]", path: "/interfaces/interface[name=eth0]/state/mtu", want: false},
+		{pattern: "/interfaces/*/state", path: "/interfaces/interface[name=eth0]/state/mtu", want: true},
+		{pattern: "/interfaces/.../mtu", path: "/interfaces/interface[name=eth0]/state/mtu", want: true},
+		{pattern: "/interfaces/interface/state/mtu", path: "/interfaces/interface[name=eth0]/state", want: false},
+		{pattern: "/system", path: "/interfaces", want: false},
+	}
+	for _, test := range tests {
+		if got := pathMatches(mustPath(t, test.pattern), mustPath(t, test.path)); got != test.want {
+			t.Errorf("pathMatches(%q, %q) got %v, want %v", test.pattern, test.path, got, test.want)
+		}
+	}
+}
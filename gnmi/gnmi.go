@@ -84,6 +84,15 @@
 //		t.Fatalf("DUT did not reach target state: got %v", val)
 //	}
 //
+// Example 4: To set config and wait for the state of the device to reflect
+// it, use [SetAndVerify], which awaits the state path that mirrors each config
+// path in the batch and reports the leaves that did not converge.
+//
+//	batch := new(gnmi.SetBatch)
+//	gnmi.BatchReplace(batch, gnmi.OC().Interface(dp.Name()).Config(), intf)
+//	gnmi.BatchUpdate(batch, gnmi.OC().System().Hostname().Config(), "dut1")
+//	gnmi.SetAndVerify(t, dut, batch, time.Minute)
+//
 // # IxNetwork Telemetry
 //
 // When using Ondatra's IxNetwork (aka "ATE") API, a test can gather statistics and
@@ -163,6 +172,7 @@ package gnmi
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -170,6 +180,7 @@ import (
 
 	"github.com/openconfig/ondatra/gnmi/oc/ocpath"
 	"github.com/openconfig/ondatra/gnmi/otg/otgpath"
+	"github.com/openconfig/ondatra/internal/trace"
 	"github.com/openconfig/ygnmi/ygnmi"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -241,6 +252,15 @@ func (o *Opts) WithYGNMIOpts(opts ...ygnmi.Option) *Opts {
 // GNMIOpts implements the DeviceOrOpts interface.
 func (o *Opts) GNMIOpts() *Opts { return o }
 
+// GNMIClient returns the custom gNMI client set by WithClient, if any, or else
+// the gNMI client of the device.
+func (o *Opts) GNMIClient(ctx context.Context) (gpb.GNMIClient, error) {
+	if o.client != nil {
+		return o.client, nil
+	}
+	return o.initClientFn(ctx)
+}
+
 // DeviceOrOpts is an interface that is ondatra.Device or a gnmi.Opts
 type DeviceOrOpts interface {
 	GNMIOpts() *Opts
@@ -250,7 +270,9 @@ type DeviceOrOpts interface {
 func Lookup[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.SingletonQuery[T]) *ygnmi.Value[T] {
 	t.Helper()
 	c := newClient(t, dev, "Lookup")
+	span := startSpan(t, dev, "Lookup", q)
 	v, err := ygnmi.Lookup(createContext(dev), c, q, createGetOpts[T](dev, q)...)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("Lookup(t) on %s at %v: %v", dev, q, err)
 	}
@@ -271,7 +293,9 @@ func LookupConfig[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.ConfigQuery[T])
 func Get[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.SingletonQuery[T]) T {
 	t.Helper()
 	c := newClient(t, dev, "Get")
+	span := startSpan(t, dev, "Get", q)
 	v, err := ygnmi.Get(createContext(dev), c, q, createGetOpts[T](dev, q)...)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("Get(t) on %s at %v: %v", dev, q, err)
 	}
@@ -288,6 +312,7 @@ type Watcher[T any] struct {
 	cancelFn func()
 	dev      DeviceOrOpts
 	query    ygnmi.AnyQuery[T]
+	span     *trace.Span
 }
 
 func isContextErr(err error) bool {
@@ -310,10 +335,13 @@ func (w *Watcher[T]) Await(t testing.TB) (*ygnmi.Value[T], bool) {
 	v, err := w.watcher.Await()
 	if err != nil {
 		if isContextErr(err) {
+			w.span.End(nil)
 			return v, false
 		}
+		w.span.End(err)
 		t.Fatalf("Await(t) on %s at %v: %v", w.dev, w.query, err)
 	}
+	w.span.End(nil)
 	return v, true
 }
 
@@ -330,6 +358,7 @@ func (w *Watcher[T]) Cancel() {
 func Watch[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.SingletonQuery[T], timeout time.Duration, pred func(*ygnmi.Value[T]) bool) *Watcher[T] {
 	t.Helper()
 	c := newClient(t, dev, "Watch")
+	span := startSpan(t, dev, "Watch", q)
 	ctx, cancel := context.WithTimeout(createContext(dev), timeout)
 	w := ygnmi.Watch(ctx, c, q, func(v *ygnmi.Value[T]) error {
 		if ok := pred(v); ok {
@@ -342,6 +371,7 @@ func Watch[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.SingletonQuery[T], tim
 		cancelFn: cancel,
 		dev:      dev,
 		query:    q,
+		span:     span,
 	}
 }
 
@@ -355,7 +385,9 @@ func Await[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.SingletonQuery[T], tim
 	ctx, cancel := context.WithTimeout(createContext(dev), timeout)
 	defer cancel()
 
+	span := startSpan(t, dev, "Await", q)
 	v, err := ygnmi.Await(ctx, c, q, val, dev.GNMIOpts().opts...)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("Await(t) on %s at %v: %v", dev, q, err)
 	}
@@ -372,6 +404,7 @@ type Collector[T any] struct {
 	cancelFn  func()
 	dev       DeviceOrOpts
 	query     ygnmi.AnyQuery[T]
+	span      *trace.Span
 }
 
 // Await waits for the collection to finish and returns all received values.
@@ -380,12 +413,11 @@ type Collector[T any] struct {
 func (c *Collector[T]) Await(t testing.TB) []*ygnmi.Value[T] {
 	t.Helper()
 	vals, err := c.collector.Await()
-	if err != nil {
-		if isContextErr(err) {
-			return vals
-		}
+	if err != nil && !isContextErr(err) {
+		c.span.End(err)
 		t.Fatalf("Await(t) on %s at %v: %v", c.dev, c.query, err)
 	}
+	c.span.End(nil)
 	return vals
 }
 
@@ -394,12 +426,14 @@ func (c *Collector[T]) Await(t testing.TB) []*ygnmi.Value[T] {
 func Collect[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.SingletonQuery[T], timeout time.Duration) *Collector[T] {
 	t.Helper()
 	c := newClient(t, dev, "Collect")
+	span := startSpan(t, dev, "Collect", q)
 	ctx, cancel := context.WithTimeout(createContext(dev), timeout)
 	collect := &Collector[T]{
 		collector: ygnmi.Collect(ctx, c, q, dev.GNMIOpts().opts...),
 		cancelFn:  cancel,
 		dev:       dev,
 		query:     q,
+		span:      span,
 	}
 
 	return collect
@@ -410,7 +444,9 @@ func Collect[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.SingletonQuery[T], t
 func LookupAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T]) []*ygnmi.Value[T] {
 	t.Helper()
 	c := newClient(t, dev, "LookupAll")
+	span := startSpan(t, dev, "LookupAll", q)
 	v, err := ygnmi.LookupAll(createContext(dev), c, q, createGetOpts[T](dev, q)...)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("LookupAll(t) on %s at %v: %v", dev, q, err)
 	}
@@ -423,7 +459,9 @@ func LookupAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T])
 func GetAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T]) []T {
 	t.Helper()
 	c := newClient(t, dev, "GetAll")
+	span := startSpan(t, dev, "GetAll", q)
 	v, err := ygnmi.GetAll(createContext(dev), c, q, createGetOpts[T](dev, q)...)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("GetAll(t) on %s at %v: %v", dev, q, err)
 	}
@@ -438,6 +476,7 @@ func GetAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T]) []T
 func WatchAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T], timeout time.Duration, pred func(*ygnmi.Value[T]) bool) *Watcher[T] {
 	t.Helper()
 	c := newClient(t, dev, "WatchAll")
+	span := startSpan(t, dev, "WatchAll", q)
 	ctx, cancel := context.WithTimeout(createContext(dev), timeout)
 	w := ygnmi.WatchAll(ctx, c, q, func(v *ygnmi.Value[T]) error {
 		if ok := pred(v); ok {
@@ -450,6 +489,7 @@ func WatchAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T], t
 		cancelFn: cancel,
 		dev:      dev,
 		query:    q,
+		span:     span,
 	}
 }
 
@@ -458,12 +498,14 @@ func WatchAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T], t
 func CollectAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T], timeout time.Duration) *Collector[T] {
 	t.Helper()
 	c := newClient(t, dev, "CollectAll")
+	span := startSpan(t, dev, "CollectAll", q)
 	ctx, cancel := context.WithTimeout(createContext(dev), timeout)
 	collect := &Collector[T]{
 		collector: ygnmi.CollectAll(ctx, c, q, dev.GNMIOpts().opts...),
 		cancelFn:  cancel,
 		dev:       dev,
 		query:     q,
+		span:      span,
 	}
 
 	return collect
@@ -473,7 +515,9 @@ func CollectAll[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.WildcardQuery[T],
 func Update[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.ConfigQuery[T], val T) *ygnmi.Result {
 	t.Helper()
 	c := newClient(t, dev, "Update")
+	span := startSpan(t, dev, "Update", q)
 	res, err := ygnmi.Update(createContext(dev), c, q, val)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("Update(t) on %s %v: %v", dev, q, err)
 	}
@@ -484,7 +528,9 @@ func Update[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.ConfigQuery[T], val T
 func Replace[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.ConfigQuery[T], val T) *ygnmi.Result {
 	t.Helper()
 	c := newClient(t, dev, "Replace")
+	span := startSpan(t, dev, "Replace", q)
 	res, err := ygnmi.Replace(createContext(dev), c, q, val)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("Replace(t) on %s at %v: %v", dev, q, err)
 	}
@@ -495,7 +541,9 @@ func Replace[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.ConfigQuery[T], val
 func Delete[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.ConfigQuery[T]) *ygnmi.Result {
 	t.Helper()
 	c := newClient(t, dev, "Delete")
+	span := startSpan(t, dev, "Delete", q)
 	res, err := ygnmi.Delete(createContext(dev), c, q)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("Delete(t) on %s at %v: %v", dev, q, err)
 	}
@@ -503,16 +551,20 @@ func Delete[T any](t testing.TB, dev DeviceOrOpts, q ygnmi.ConfigQuery[T]) *ygnm
 }
 
 // SetBatch allows multiple Set operations (Replace, Update, Delete) to be applied as part of a single Set transaction.
-// Use BatchUpdate, BatchReplace, BatchDelete to add operations, and then call the Set method to send the SetRequest.
+// Use BatchUpdate, BatchReplace, BatchDelete to add operations, and then call the Set method to send the SetRequest,
+// or the SetAndVerify method to also wait for the state of the device to converge to the config.
 type SetBatch struct {
-	sb ygnmi.SetBatch
+	sb        ygnmi.SetBatch
+	verifiers []*verifier
 }
 
 // Set performs the gnmi.Set request with all queued operations.
 func (sb *SetBatch) Set(t testing.TB, dev DeviceOrOpts) *ygnmi.Result {
 	t.Helper()
 	c := newClient(t, dev, "Set")
+	span := trace.Start(t, "gnmi.Set", "device", dev.GNMIOpts().id)
 	res, err := sb.sb.Set(createContext(dev), c)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("Set(t) on %s: %v", dev, err)
 	}
@@ -522,11 +574,13 @@ func (sb *SetBatch) Set(t testing.TB, dev DeviceOrOpts) *ygnmi.Result {
 // BatchUpdate stores an update operation in the SetBatch.
 func BatchUpdate[T any](sb *SetBatch, q ygnmi.ConfigQuery[T], val T) {
 	ygnmi.BatchUpdate(&sb.sb, q, val)
+	addVerifier(sb, q, val, false)
 }
 
 // BatchReplace stores an replace operation in the SetBatch.
 func BatchReplace[T any](sb *SetBatch, q ygnmi.ConfigQuery[T], val T) {
 	ygnmi.BatchReplace(&sb.sb, q, val)
+	addVerifier(sb, q, val, false)
 }
 
 // BatchUnionReplace stores a union_replace operation in the SetBatch.
@@ -534,6 +588,7 @@ func BatchReplace[T any](sb *SetBatch, q ygnmi.ConfigQuery[T], val T) {
 // https://github.com/openconfig/reference/blob/master/rpc/gnmi/gnmi-union_replace.md
 func BatchUnionReplace[T any](sb *SetBatch, q ygnmi.ConfigQuery[T], val T) {
 	ygnmi.BatchUnionReplace(&sb.sb, q, val)
+	addVerifier(sb, q, val, false)
 }
 
 // BatchUnionReplaceCLI stores a CLI union_replace operation in the SetBatch.
@@ -551,6 +606,7 @@ func BatchUnionReplaceCLI(sb *SetBatch, nos, ascii string) {
 // BatchDelete stores a delete operation in the SetBatch.
 func BatchDelete[T any](sb *SetBatch, q ygnmi.ConfigQuery[T]) {
 	ygnmi.BatchDelete(&sb.sb, q)
+	addVerifier(sb, q, *new(T), true)
 }
 
 func createContext(d DeviceOrOpts) context.Context {
@@ -569,15 +625,19 @@ func createGetOpts[T any](d DeviceOrOpts, q ygnmi.AnyQuery[T]) []ygnmi.Option {
 	return opts
 }
 
+// startSpan starts a trace span of a gNMI operation on a query.
+func startSpan[T any](t testing.TB, dev DeviceOrOpts, method string, q ygnmi.AnyQuery[T]) *trace.Span {
+	if !trace.Enabled() {
+		return nil
+	}
+	return trace.Start(t, "gnmi."+method, "device", dev.GNMIOpts().id, "query", fmt.Sprint(q))
+}
+
 func newClient(t testing.TB, dev DeviceOrOpts, method string) *ygnmi.Client {
 	t.Helper()
-	gnmiC := dev.GNMIOpts().client
-	if gnmiC == nil {
-		var err error
-		gnmiC, err = dev.GNMIOpts().initClientFn(context.Background())
-		if err != nil {
-			t.Fatalf("Failed to get client: %v", err)
-		}
+	gnmiC, err := dev.GNMIOpts().GNMIClient(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get client: %v", err)
 	}
 
 	c, err := ygnmi.NewClient(gnmiC, ygnmi.WithTarget(dev.GNMIOpts().id))
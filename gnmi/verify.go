@@ -0,0 +1,214 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gnmi/value"
+	"github.com/openconfig/ondatra/internal/trace"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// verifier waits for the state of a device to converge to one operation of a
+// SetBatch.
+type verifier struct {
+	// err is set if the state of the operation cannot be verified.
+	err error
+	// start starts watching the state and returns a func that waits for the
+	// state to converge and returns the leaves that did not converge.
+	start func(t testing.TB, dev DeviceOrOpts, timeout time.Duration) func(t testing.TB) []string
+}
+
+// addVerifier adds a verifier of the state of the config query to the batch.
+// If del is true, the verifier waits for the state to be deleted; otherwise
+// it waits for the state to have every leaf that is set in the value.
+func addVerifier[T any](sb *SetBatch, q ygnmi.ConfigQuery[T], val T, del bool) {
+	sq, err := stateQuery(q)
+	if err != nil {
+		sb.verifiers = append(sb.verifiers, &verifier{err: err})
+		return
+	}
+	path := fmt.Sprint(sq)
+	sb.verifiers = append(sb.verifiers, &verifier{
+		start: func(t testing.TB, dev DeviceOrOpts, timeout time.Duration) func(testing.TB) []string {
+			t.Helper()
+			var mu sync.Mutex
+			var diffs []string
+			w := Watch(t, dev, sq, timeout, func(v *ygnmi.Value[T]) bool {
+				d := stateDiffs(path, val, v, del)
+				mu.Lock()
+				defer mu.Unlock()
+				diffs = d
+				return len(d) == 0
+			})
+			return func(t testing.TB) []string {
+				t.Helper()
+				if _, ok := w.Await(t); ok {
+					return nil
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if diffs == nil {
+					// No value was received before the timeout.
+					return stateDiffs(path, val, nil, del)
+				}
+				return diffs
+			}
+		},
+	})
+}
+
+// stateQuery returns the state query that mirrors the config query, by
+// calling the State method of the generated path struct of the query.
+func stateQuery[T any](q ygnmi.ConfigQuery[T]) (ygnmi.SingletonQuery[T], error) {
+	m := reflect.ValueOf(q.PathStruct()).MethodByName("State")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil, fmt.Errorf("no state path mirrors config path %v", q)
+	}
+	sq, ok := m.Call(nil)[0].Interface().(ygnmi.SingletonQuery[T])
+	if !ok {
+		return nil, fmt.Errorf("state path of config path %v is not a singleton of type %T", q, *new(T))
+	}
+	return sq, nil
+}
+
+// stateDiffs returns a description of each leaf of the state at the path
+// that does not match the wanted value. If del is true, the state matches
+// if it is absent; otherwise, it matches if every leaf set in the wanted
+// value has the same value in the state, so leaves that are only in the state
+// are ignored.
+func stateDiffs[T any](path string, want T, got *ygnmi.Value[T], del bool) []string {
+	var gotVal T
+	var present bool
+	if got != nil {
+		gotVal, present = got.Val()
+	}
+	if del {
+		if present {
+			return []string{fmt.Sprintf("%s: got %v, want deleted", path, gotVal)}
+		}
+		return nil
+	}
+	wantGS, ok := any(want).(ygot.GoStruct)
+	if !ok {
+		switch {
+		case !present:
+			return []string{fmt.Sprintf("%s: got no value, want %v", path, want)}
+		case !reflect.DeepEqual(gotVal, want):
+			return []string{fmt.Sprintf("%s: got %v, want %v", path, gotVal, want)}
+		}
+		return nil
+	}
+	wantLeaves, err := leaves(wantGS)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: cannot render wanted value: %v", path, err)}
+	}
+	gotLeaves := map[string]*gpb.TypedValue{}
+	if present {
+		if gotLeaves, err = leaves(any(gotVal).(ygot.GoStruct)); err != nil {
+			return []string{fmt.Sprintf("%s: cannot render state value: %v", path, err)}
+		}
+	}
+	var diffs []string
+	for leaf, wantTV := range wantLeaves {
+		gotTV, ok := gotLeaves[leaf]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("%s/%s: got no value, want %s", path, leaf, typedValueString(wantTV)))
+		case !proto.Equal(gotTV, wantTV):
+			diffs = append(diffs, fmt.Sprintf("%s/%s: got %s, want %s", path, leaf, typedValueString(gotTV), typedValueString(wantTV)))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+// leaves returns the values of the leaves set in a GoStruct, keyed by their
+// paths relative to the GoStruct.
+func leaves(gs ygot.GoStruct) (map[string]*gpb.TypedValue, error) {
+	notifs, err := ygot.TogNMINotifications(gs, 0, ygot.GNMINotificationsConfig{UsePathElem: true})
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]*gpb.TypedValue)
+	for _, n := range notifs {
+		for _, u := range n.GetUpdate() {
+			p, err := ygot.PathToString(&gpb.Path{Elem: append(n.GetPrefix().GetElem(), u.GetPath().GetElem()...)})
+			if err != nil {
+				return nil, err
+			}
+			m[strings.TrimPrefix(p, "/")] = u.GetVal()
+		}
+	}
+	return m, nil
+}
+
+func typedValueString(tv *gpb.TypedValue) string {
+	if v, err := value.ToScalar(tv); err == nil {
+		return fmt.Sprint(v)
+	}
+	return tv.String()
+}
+
+// SetAndVerify performs the gnmi.Set request with all queued operations and
+// then waits until the state of the device converges to the config: until
+// the state path that mirrors each replaced or updated config path has every
+// leaf set in the config value, and the state path that mirrors each deleted
+// config path is absent. It fails fatally with the leaves that did not
+// converge within the timeout. A CLI union_replace operation is not verified.
+func (sb *SetBatch) SetAndVerify(t testing.TB, dev DeviceOrOpts, timeout time.Duration) *ygnmi.Result {
+	t.Helper()
+	for _, v := range sb.verifiers {
+		if v.err != nil {
+			t.Fatalf("SetAndVerify(t) on %s: %v", dev, v.err)
+		}
+	}
+	res := sb.Set(t, dev)
+	span := trace.Start(t, "gnmi.Verify", "device", dev.GNMIOpts().id)
+	var awaits []func(testing.TB) []string
+	for _, v := range sb.verifiers {
+		awaits = append(awaits, v.start(t, dev, timeout))
+	}
+	var diffs []string
+	for _, await := range awaits {
+		diffs = append(diffs, await(t)...)
+	}
+	if len(diffs) > 0 {
+		err := fmt.Errorf("state did not converge to config within %v:\n%s", timeout, strings.Join(diffs, "\n"))
+		span.End(err)
+		t.Fatalf("SetAndVerify(t) on %s: %v", dev, err)
+	}
+	span.End(nil)
+	return res
+}
+
+// SetAndVerify performs the operations of the batch in a single gnmi.Set
+// request and waits for the state of the device to converge to the config;
+// see [SetBatch.SetAndVerify].
+func SetAndVerify(t testing.TB, dev DeviceOrOpts, batch *SetBatch, timeout time.Duration) *ygnmi.Result {
+	t.Helper()
+	return batch.SetAndVerify(t, dev, timeout)
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// testIntf is a minimal GoStruct with compressed config and state paths.
+type testIntf struct {
+	Name        *string `path:"config/name|name" shadow-path:"state/name|name"`
+	Description *string `path:"config/description" shadow-path:"state/description"`
+	Mtu         *uint16 `path:"config/mtu" shadow-path:"state/mtu"`
+	Counter     *uint64 `path:"state/counter"`
+}
+
+func (*testIntf) IsYANGGoStruct() {}
+
+func TestStateDiffs(t *testing.T) {
+	const path = "/interfaces/interface[name=eth0]"
+	intf := func(desc string, mtu uint16) *testIntf {
+		return &testIntf{Name: ygot.String("eth0"), Description: ygot.String(desc), Mtu: ygot.Uint16(mtu)}
+	}
+	withCounter := intf("uplink", 9000)
+	withCounter.Counter = ygot.Uint64(42)
+
+	t.Run("leaf", func(t *testing.T) {
+		tests := []struct {
+			desc string
+			got  *ygnmi.Value[string]
+			del  bool
+			want []string
+		}{{
+			desc: "converged",
+			got:  (&ygnmi.Value[string]{}).SetVal("uplink"),
+		}, {
+			desc: "different",
+			got:  (&ygnmi.Value[string]{}).SetVal("downlink"),
+			want: []string{path + ": got downlink, want uplink"},
+		}, {
+			desc: "absent",
+			got:  &ygnmi.Value[string]{},
+			want: []string{path + ": got no value, want uplink"},
+		}, {
+			desc: "no value received",
+			want: []string{path + ": got no value, want uplink"},
+		}, {
+			desc: "deleted",
+			got:  &ygnmi.Value[string]{},
+			del:  true,
+		}, {
+			desc: "not deleted",
+			got:  (&ygnmi.Value[string]{}).SetVal("uplink"),
+			del:  true,
+			want: []string{path + ": got uplink, want deleted"},
+		}}
+		for _, test := range tests {
+			t.Run(test.desc, func(t *testing.T) {
+				got := stateDiffs(path, "uplink", test.got, test.del)
+				if diff := cmp.Diff(test.want, got); diff != "" {
+					t.Errorf("stateDiffs() got unexpected diffs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("container", func(t *testing.T) {
+		tests := []struct {
+			desc string
+			got  *ygnmi.Value[*testIntf]
+			want []string
+		}{{
+			desc: "converged with state-only leaves",
+			got:  (&ygnmi.Value[*testIntf]{}).SetVal(withCounter),
+		}, {
+			desc: "different leaves",
+			got:  (&ygnmi.Value[*testIntf]{}).SetVal(intf("downlink", 1500)),
+			want: []string{
+				path + "/config/description: got downlink, want uplink",
+				path + "/config/mtu: got 1500, want 9000",
+			},
+		}, {
+			desc: "missing leaf",
+			got:  (&ygnmi.Value[*testIntf]{}).SetVal(&testIntf{Name: ygot.String("eth0"), Mtu: ygot.Uint16(9000)}),
+			want: []string{path + "/config/description: got no value, want uplink"},
+		}}
+		for _, test := range tests {
+			t.Run(test.desc, func(t *testing.T) {
+				got := stateDiffs(path, intf("uplink", 9000), test.got, false)
+				if diff := cmp.Diff(test.want, got); diff != "" {
+					t.Errorf("stateDiffs() got unexpected diffs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+}
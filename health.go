@@ -0,0 +1,164 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ondatra
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/internal/health"
+	"github.com/openconfig/ondatra/internal/testbed"
+)
+
+// HealthChecks specifies the health checks to run on every DUT before each
+// top-level test, so that a DUT left unreachable by one test is detected, and
+// optionally recovered, before the next test uses it.
+//
+// The checks of a top-level test run when the test starts, on the DUTs of the
+// first testbed, and again on the DUTs of the testbed of its own when the test
+// calls Parallel. The outcome of each check is attached to the test in the XML
+// report as a property named "health:<dut>:<probe>", and a test whose DUTs
+// remain unhealthy fails immediately.
+type HealthChecks struct {
+	// GNMI probes the gNMI endpoint with a Capabilities request.
+	GNMI bool
+	// GNOI probes the gNOI endpoint with a System Time request.
+	GNOI bool
+	// CLI probes the CLI endpoint by running CLICommand.
+	CLI bool
+	// CLICommand is the command run by the CLI probe.
+	// If empty, the CLI probe only checks that the CLI can be dialed.
+	CLICommand string
+	// ResetConfig restores the base config of a DUT that fails a probe, by
+	// calling PushConfig with reset=true, and then probes the DUT again.
+	ResetConfig bool
+	// Timeout is the time allowed for each probe. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// Option is an option to RunTests.
+type Option func(*runOpts)
+
+type runOpts struct {
+	healthCfg *health.Config
+	shards    int
+}
+
+// WithHealthChecks runs the specified health checks on every DUT before each
+// top-level test.
+func WithHealthChecks(hc HealthChecks) Option {
+	return func(o *runOpts) {
+		o.healthCfg = &health.Config{
+			GNMI:        hc.GNMI,
+			GNOI:        hc.GNOI,
+			CLI:         hc.CLI,
+			CLICommand:  hc.CLICommand,
+			ResetConfig: hc.ResetConfig,
+			Timeout:     hc.Timeout,
+		}
+	}
+}
+
+var healthState struct {
+	mu  sync.Mutex
+	cfg *health.Config
+	// checked holds the top-level tests checked on each reservation, keyed by
+	// test name and reservation ID.
+	checked map[string]bool
+}
+
+func setHealthChecks(cfg *health.Config) {
+	healthState.mu.Lock()
+	defer healthState.mu.Unlock()
+	healthState.cfg = cfg
+	healthState.checked = make(map[string]bool)
+}
+
+// checkHealthOnStart wraps every top-level test run by m, so that its health
+// checks run when the test starts, even if it never fetches a device.
+// testing.M does not export its tests, so if they cannot be found, the checks
+// of a test only run the first time it fetches a device.
+func checkHealthOnStart(m *testing.M) {
+	v := reflect.ValueOf(m).Elem().FieldByName("tests")
+	if !v.IsValid() || v.Type() != reflect.TypeOf([]testing.InternalTest(nil)) {
+		log.Infof("Cannot find the tests of %T; health checks run when a test first fetches a device", m)
+		return
+	}
+	tests := *(*[]testing.InternalTest)(unsafe.Pointer(v.UnsafeAddr()))
+	for i := range tests {
+		f := tests[i].F
+		tests[i].F = func(t *testing.T) {
+			if res, err := testbed.ReservationFor(t.Name()); err == nil {
+				checkHealth(t, res)
+			}
+			f(t)
+		}
+	}
+}
+
+// checkHealth runs the health checks for the top-level test of t on the DUTs
+// of the reservation, unless they have already run for that test on that
+// reservation.
+func checkHealth(t testing.TB, res *binding.Reservation) {
+	t.Helper()
+	test := topLevelTest(t)
+	key := test + "@" + res.ID
+	// Only hold the lock to claim the checks, so that probing a hung DUT does
+	// not block the tests running in parallel.
+	healthState.mu.Lock()
+	cfg := healthState.cfg
+	if cfg == nil || healthState.checked[key] {
+		healthState.mu.Unlock()
+		return
+	}
+	healthState.checked[key] = true
+	healthState.mu.Unlock()
+
+	results := health.CheckAll(context.Background(), res.DUTs, cfg)
+	var ids []string
+	for id := range results {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var unhealthy []string
+	for _, id := range ids {
+		r := results[id]
+		for probe, err := range r.Probes {
+			status := "ok"
+			if err != nil {
+				status = err.Error()
+			}
+			Report().AddRawProperty(test, "health:"+id+":"+probe, status)
+		}
+		if r.Recovered {
+			Report().AddRawProperty(test, "health:"+id+":recovered", "true")
+		}
+		if err := r.Err(); err != nil {
+			unhealthy = append(unhealthy, id+": "+err.Error())
+		}
+	}
+	if len(unhealthy) > 0 {
+		t.Fatalf("DUT health checks failed before %s:\n%s", test, strings.Join(unhealthy, "\n"))
+	}
+}
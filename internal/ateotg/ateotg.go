@@ -0,0 +1,192 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ateotg translates ATE topologies and flows, as configured with the
+// IxNetwork API, to Open Traffic Generator configs.
+//
+// Only the subset of the IxNetwork API that has a direct OTG equivalent is
+// translated: interfaces on ports with Ethernet, VLAN, IPv4 and IPv6 config,
+// and flows between interfaces with Ethernet, VLAN, IPv4, IPv6, MPLS, TCP and
+// UDP headers. Any other config results in an error rather than a config that
+// silently behaves differently than it would on IxNetwork.
+package ateotg
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// defaultRatePct is the frame rate of flows without a frame rate, which is
+// the IxNetwork default of 10% of the line rate.
+const defaultRatePct = 10
+
+// EthName returns the name of the OTG Ethernet config of an interface.
+func EthName(name string) string {
+	return name + ".eth"
+}
+
+// IPv4Name returns the name of the OTG IPv4 config of an interface.
+func IPv4Name(name string) string {
+	return name + ".ipv4"
+}
+
+// IPv6Name returns the name of the OTG IPv6 config of an interface.
+func IPv6Name(name string) string {
+	return name + ".ipv6"
+}
+
+// Config translates ATE interfaces, LAGs and flows to an OTG config.
+// The portIDs map the names of the reserved ports, by which the interfaces
+// refer to their ports, to the testbed IDs of the ports, which are the names
+// of the ports in the OTG config.
+func Config(intfs []*opb.InterfaceConfig, lags []*opb.Lag, flows []*opb.Flow, portIDs map[string]string) (gosnappi.Config, error) {
+	if len(lags) > 0 {
+		return nil, unsupported("LAGs")
+	}
+	cfg := gosnappi.NewConfig()
+	name2Intf := make(map[string]*intf)
+	addedPorts := make(map[string]bool)
+	for i, ipb := range intfs {
+		name := ipb.GetName()
+		if _, ok := name2Intf[name]; ok {
+			return nil, fmt.Errorf("duplicate interface %q", name)
+		}
+		portID, err := intfPort(ipb, portIDs)
+		if err != nil {
+			return nil, err
+		}
+		if !addedPorts[portID] {
+			cfg.Ports().Add().SetName(portID)
+			addedPorts[portID] = true
+		}
+		in := &intf{pb: ipb, mac: intfMAC(i)}
+		if err := addDevice(cfg, in, portID); err != nil {
+			return nil, fmt.Errorf("interface %q: %w", name, err)
+		}
+		name2Intf[name] = in
+	}
+	for _, f := range flows {
+		if err := addFlow(cfg, f, name2Intf); err != nil {
+			return nil, fmt.Errorf("flow %q: %w", f.GetName(), err)
+		}
+	}
+	return cfg, nil
+}
+
+// intf is an ATE interface and the MAC address generated for it.
+type intf struct {
+	pb  *opb.InterfaceConfig
+	mac string
+}
+
+func intfPort(pb *opb.InterfaceConfig, portIDs map[string]string) (string, error) {
+	switch l := pb.GetLink().(type) {
+	case *opb.InterfaceConfig_Port:
+		id, ok := portIDs[l.Port]
+		if !ok {
+			return "", fmt.Errorf("interface %q is on unknown port %q", pb.GetName(), l.Port)
+		}
+		return id, nil
+	case *opb.InterfaceConfig_Lag:
+		return "", unsupported("interfaces on LAGs")
+	default:
+		return "", fmt.Errorf("interface %q has no port", pb.GetName())
+	}
+}
+
+// intfMAC returns the MAC address of the i-th interface. IxNetwork assigns
+// interface MAC addresses itself, so the translation generates unique,
+// locally administered addresses in their place.
+func intfMAC(i int) string {
+	return fmt.Sprintf("02:00:00:00:%02x:%02x", (i+1)>>8&0xff, (i+1)&0xff)
+}
+
+func addDevice(cfg gosnappi.Config, in *intf, portID string) error {
+	pb := in.pb
+	switch {
+	case pb.GetIpv4LoopbackCidr() != "" || pb.GetIpv6LoopbackCidr() != "":
+		return unsupported("loopback addresses")
+	case pb.GetIsis() != nil:
+		return unsupported("IS-IS")
+	case pb.GetBgp() != nil:
+		return unsupported("BGP")
+	case len(pb.GetRsvps()) > 0:
+		return unsupported("RSVP")
+	case pb.GetDhcpv6Client() != nil || pb.GetDhcpv6Server() != nil:
+		return unsupported("DHCPv6")
+	case len(pb.GetNetworks()) > 0:
+		return unsupported("networks")
+	case pb.GetEnableLacp():
+		return unsupported("LACP")
+	case pb.GetEthernet().GetMacsec() != nil:
+		return unsupported("MACsec")
+	}
+	name := pb.GetName()
+	eth := cfg.Devices().Add().SetName(name).Ethernets().Add().
+		SetName(EthName(name)).
+		SetMac(in.mac)
+	eth.Connection().SetPortName(portID)
+	if mtu := pb.GetEthernet().GetMtu(); mtu != 0 {
+		eth.SetMtu(int32(mtu))
+	}
+	if vid := pb.GetEthernet().GetVlanId(); vid != 0 {
+		eth.Vlans().Add().SetName(name + ".vlan").SetId(int32(vid))
+	}
+	if ipc := pb.GetIpv4(); ipc != nil {
+		ip, prefix, err := parseCIDR(ipc.GetAddressCidr())
+		if err != nil {
+			return err
+		}
+		if ipc.GetDefaultGateway() == "" {
+			return fmt.Errorf("IPv4 config has no default gateway, which OTG requires")
+		}
+		eth.Ipv4Addresses().Add().
+			SetName(IPv4Name(name)).
+			SetAddress(ip).
+			SetPrefix(prefix).
+			SetGateway(ipc.GetDefaultGateway())
+	}
+	if ipc := pb.GetIpv6(); ipc != nil {
+		ip, prefix, err := parseCIDR(ipc.GetAddressCidr())
+		if err != nil {
+			return err
+		}
+		if ipc.GetDefaultGateway() == "" {
+			return fmt.Errorf("IPv6 config has no default gateway, which OTG requires")
+		}
+		eth.Ipv6Addresses().Add().
+			SetName(IPv6Name(name)).
+			SetAddress(ip).
+			SetPrefix(prefix).
+			SetGateway(ipc.GetDefaultGateway())
+	}
+	return nil
+}
+
+func parseCIDR(cidr string) (string, int32, error) {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid address %q: %w", cidr, err)
+	}
+	prefix, _ := ipNet.Mask.Size()
+	return ip.String(), int32(prefix), nil
+}
+
+func unsupported(feature string) error {
+	return fmt.Errorf("%s not supported by the OTG translation", feature)
+}
@@ -0,0 +1,248 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ateotg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+var portIDs = map[string]string{"1/1": "port1", "1/2": "port2"}
+
+func intfs() []*opb.InterfaceConfig {
+	return []*opb.InterfaceConfig{{
+		Name:     "intf1",
+		Link:     &opb.InterfaceConfig_Port{Port: "1/1"},
+		Ethernet: &opb.EthernetConfig{Mtu: 1500, VlanId: 10},
+		Ipv4:     &opb.IpConfig{AddressCidr: "192.0.2.2/30", DefaultGateway: "192.0.2.1"},
+	}, {
+		Name:     "intf2",
+		Link:     &opb.InterfaceConfig_Port{Port: "1/2"},
+		Ethernet: &opb.EthernetConfig{Mtu: 1500},
+		Ipv4:     &opb.IpConfig{AddressCidr: "192.0.2.6/30", DefaultGateway: "192.0.2.5"},
+		Ipv6:     &opb.IpConfig{AddressCidr: "2001:db8::2/126", DefaultGateway: "2001:db8::1"},
+	}}
+}
+
+func TestConfig(t *testing.T) {
+	flow := &opb.Flow{
+		Name:         "flow1",
+		SrcEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf1"}},
+		DstEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf2"}},
+		Headers: []*opb.Header{
+			{Type: &opb.Header_Eth{Eth: &opb.EthernetHeader{VlanId: 10}}},
+			{Type: &opb.Header_Ipv4{Ipv4: &opb.Ipv4Header{
+				Dscp:    46,
+				Ttl:     proto.Uint32(32),
+				DstAddr: &opb.AddressRange{Min: "198.51.100.0", Max: "198.51.100.255", Step: "0.0.0.1", Count: 256},
+			}}},
+			{Type: &opb.Header_Udp{Udp: &opb.UdpHeader{
+				SrcPort: &opb.UIntRange{Min: 5000, Max: 5000, Count: 1},
+			}}},
+		},
+		FrameRate:    &opb.FrameRate{Type: &opb.FrameRate_Fps{Fps: 1000}},
+		FrameSize:    &opb.FrameSize{Type: &opb.FrameSize_Fixed{Fixed: 512}},
+		Transmission: &opb.Transmission{Pattern: opb.Transmission_FIXED_FRAME_COUNT, FrameCount: 10000},
+	}
+
+	want := gosnappi.NewConfig()
+	want.Ports().Add().SetName("port1")
+	want.Ports().Add().SetName("port2")
+	eth1 := want.Devices().Add().SetName("intf1").Ethernets().Add().
+		SetName("intf1.eth").
+		SetMac("02:00:00:00:00:01").
+		SetMtu(1500)
+	eth1.Connection().SetPortName("port1")
+	eth1.Vlans().Add().SetName("intf1.vlan").SetId(10)
+	eth1.Ipv4Addresses().Add().
+		SetName("intf1.ipv4").
+		SetAddress("192.0.2.2").
+		SetPrefix(30).
+		SetGateway("192.0.2.1")
+	eth2 := want.Devices().Add().SetName("intf2").Ethernets().Add().
+		SetName("intf2.eth").
+		SetMac("02:00:00:00:00:02").
+		SetMtu(1500)
+	eth2.Connection().SetPortName("port2")
+	eth2.Ipv4Addresses().Add().
+		SetName("intf2.ipv4").
+		SetAddress("192.0.2.6").
+		SetPrefix(30).
+		SetGateway("192.0.2.5")
+	eth2.Ipv6Addresses().Add().
+		SetName("intf2.ipv6").
+		SetAddress("2001:db8::2").
+		SetPrefix(126).
+		SetGateway("2001:db8::1")
+	wantFlow := want.Flows().Add().SetName("flow1")
+	wantFlow.TxRx().Device().
+		SetTxNames([]string{"intf1.ipv4"}).
+		SetRxNames([]string{"intf2.ipv4"})
+	wantFlow.Metrics().SetEnable(true)
+	wantFlow.Rate().SetPps(1000)
+	wantFlow.Size().SetFixed(512)
+	wantFlow.Duration().FixedPackets().SetPackets(10000)
+	wantEth := wantFlow.Packet().Add().Ethernet()
+	wantEth.Src().SetValue("02:00:00:00:00:01")
+	wantFlow.Packet().Add().Vlan().Id().SetValue(10)
+	wantIP := wantFlow.Packet().Add().Ipv4()
+	wantIP.Src().SetValue("192.0.2.2")
+	wantIP.Dst().Increment().SetStart("198.51.100.0").SetStep("0.0.0.1").SetCount(256)
+	wantIP.Priority().Dscp().Phb().SetValue(46)
+	wantIP.Priority().Dscp().Ecn().SetValue(0)
+	wantIP.TimeToLive().SetValue(32)
+	wantFlow.Packet().Add().Udp().SrcPort().SetValue(5000)
+
+	got, err := Config(intfs(), nil, []*opb.Flow{flow}, portIDs)
+	if err != nil {
+		t.Fatalf("Config() got unexpected error: %v", err)
+	}
+	gotPB, err := got.ToProto()
+	if err != nil {
+		t.Fatalf("ToProto() of got config failed: %v", err)
+	}
+	wantPB, err := want.ToProto()
+	if err != nil {
+		t.Fatalf("ToProto() of want config failed: %v", err)
+	}
+	if diff := cmp.Diff(wantPB, gotPB, protocmp.Transform()); diff != "" {
+		t.Errorf("Config() got unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfigIPv6Flow(t *testing.T) {
+	flow := &opb.Flow{
+		Name:         "flow1",
+		SrcEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf2"}},
+		DstEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf2"}},
+		Headers:      []*opb.Header{{Type: &opb.Header_Ipv6{Ipv6: &opb.Ipv6Header{}}}},
+	}
+	cfg, err := Config(intfs(), nil, []*opb.Flow{flow}, portIDs)
+	if err != nil {
+		t.Fatalf("Config() got unexpected error: %v", err)
+	}
+	f := cfg.Flows().Items()[0]
+	if got, want := f.TxRx().Device().TxNames(), []string{"intf2.ipv6"}; !cmp.Equal(got, want) {
+		t.Errorf("Config() got flow tx names %v, want %v", got, want)
+	}
+	if got, want := f.Rate().Percentage(), float32(defaultRatePct); got != want {
+		t.Errorf("Config() got flow rate %v%%, want %v%%", got, want)
+	}
+	if got, want := f.Packet().Items()[0].Ipv6().Src().Value(), "2001:db8::2"; got != want {
+		t.Errorf("Config() got IPv6 source %q, want %q", got, want)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		intfs   []*opb.InterfaceConfig
+		lags    []*opb.Lag
+		flow    *opb.Flow
+		wantErr string
+	}{{
+		desc:    "LAG",
+		lags:    []*opb.Lag{{Name: "lag1"}},
+		wantErr: "LAGs not supported",
+	}, {
+		desc:    "unknown port",
+		intfs:   []*opb.InterfaceConfig{{Name: "intf1", Link: &opb.InterfaceConfig_Port{Port: "9/9"}}},
+		wantErr: "unknown port",
+	}, {
+		desc: "BGP",
+		intfs: []*opb.InterfaceConfig{{
+			Name: "intf1",
+			Link: &opb.InterfaceConfig_Port{Port: "1/1"},
+			Bgp:  &opb.BgpConfig{},
+		}},
+		wantErr: "BGP not supported",
+	}, {
+		desc: "no gateway",
+		intfs: []*opb.InterfaceConfig{{
+			Name: "intf1",
+			Link: &opb.InterfaceConfig_Port{Port: "1/1"},
+			Ipv4: &opb.IpConfig{AddressCidr: "192.0.2.2/30"},
+		}},
+		wantErr: "no default gateway",
+	}, {
+		desc: "network endpoint",
+		flow: &opb.Flow{
+			Name:         "flow1",
+			SrcEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf1", Generated: &opb.Flow_Endpoint_NetworkName{NetworkName: "net1"}}},
+			DstEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf2"}},
+		},
+		wantErr: "network and RSVP endpoints not supported",
+	}, {
+		desc: "no IPv6 config",
+		flow: &opb.Flow{
+			Name:         "flow1",
+			SrcEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf1"}},
+			DstEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf2"}},
+			Headers:      []*opb.Header{{Type: &opb.Header_Ipv6{Ipv6: &opb.Ipv6Header{}}}},
+		},
+		wantErr: "has no IPv6 config",
+	}, {
+		desc: "egress tracking",
+		flow: &opb.Flow{
+			Name:           "flow1",
+			SrcEndpoints:   []*opb.Flow_Endpoint{{InterfaceName: "intf1"}},
+			DstEndpoints:   []*opb.Flow_Endpoint{{InterfaceName: "intf2"}},
+			EgressTracking: &opb.EgressTracking{Enabled: true},
+		},
+		wantErr: "egress tracking not supported",
+	}, {
+		desc: "random range",
+		flow: &opb.Flow{
+			Name:         "flow1",
+			SrcEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf1"}},
+			DstEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf2"}},
+			Headers: []*opb.Header{{Type: &opb.Header_Tcp{Tcp: &opb.TcpHeader{
+				DstPort: &opb.UIntRange{Min: 1, Max: 100, Count: 10, Random: true},
+			}}}},
+		},
+		wantErr: "random integer ranges not supported",
+	}, {
+		desc: "unsupported header",
+		flow: &opb.Flow{
+			Name:         "flow1",
+			SrcEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf1"}},
+			DstEndpoints: []*opb.Flow_Endpoint{{InterfaceName: "intf2"}},
+			Headers:      []*opb.Header{{Type: &opb.Header_Gre{Gre: &opb.GreHeader{}}}},
+		},
+		wantErr: "not supported",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if test.intfs == nil {
+				test.intfs = intfs()
+			}
+			var flows []*opb.Flow
+			if test.flow != nil {
+				flows = append(flows, test.flow)
+			}
+			_, err := Config(test.intfs, test.lags, flows, portIDs)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Config() got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ateotg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+func addFlow(cfg gosnappi.Config, f *opb.Flow, intfs map[string]*intf) error {
+	switch {
+	case f.GetEgressTracking().GetEnabled():
+		return unsupported("egress tracking")
+	case f.GetIngressTrackingFilters() != nil:
+		return unsupported("ingress tracking")
+	case f.GetConvergenceTracking():
+		return unsupported("convergence tracking")
+	}
+	srcs, err := endpointIntfs(f.GetSrcEndpoints(), intfs)
+	if err != nil {
+		return err
+	}
+	dsts, err := endpointIntfs(f.GetDstEndpoints(), intfs)
+	if err != nil {
+		return err
+	}
+	epName, err := endpointNamer(f.GetHeaders(), append(srcs, dsts...))
+	if err != nil {
+		return err
+	}
+	flow := cfg.Flows().Add().SetName(f.GetName())
+	flow.TxRx().Device().
+		SetTxNames(mapNames(srcs, epName)).
+		SetRxNames(mapNames(dsts, epName))
+	flow.Metrics().SetEnable(true)
+	if err := setRate(flow.Rate(), f.GetFrameRate()); err != nil {
+		return err
+	}
+	if err := setSize(flow.Size(), f.GetFrameSize()); err != nil {
+		return err
+	}
+	if err := setDuration(flow.Duration(), f.GetTransmission()); err != nil {
+		return err
+	}
+	inferred := inferHeaders(f.GetHeaders(), srcs, dsts)
+	for _, h := range f.GetHeaders() {
+		if err := addHeader(flow.Packet(), h, inferred); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func endpointIntfs(eps []*opb.Flow_Endpoint, intfs map[string]*intf) ([]*intf, error) {
+	if len(eps) == 0 {
+		return nil, fmt.Errorf("flow has no endpoints")
+	}
+	var res []*intf
+	for _, ep := range eps {
+		if ep.GetGenerated() != nil {
+			return nil, unsupported("network and RSVP endpoints")
+		}
+		i, ok := intfs[ep.GetInterfaceName()]
+		if !ok {
+			return nil, fmt.Errorf("endpoint is unknown interface %q", ep.GetInterfaceName())
+		}
+		res = append(res, i)
+	}
+	return res, nil
+}
+
+// endpointNamer returns a func that maps an interface to the name of the OTG
+// config from which the flow is sent or received. That is the config of the
+// outermost IP header of the flow or, if it has none, the Ethernet config.
+func endpointNamer(hdrs []*opb.Header, eps []*intf) (func(*intf) string, error) {
+	for _, h := range hdrs {
+		switch h.GetType().(type) {
+		case *opb.Header_Ipv4:
+			for _, ep := range eps {
+				if ep.pb.GetIpv4() == nil {
+					return nil, fmt.Errorf("IPv4 flow endpoint %q has no IPv4 config", ep.pb.GetName())
+				}
+			}
+			return func(i *intf) string { return IPv4Name(i.pb.GetName()) }, nil
+		case *opb.Header_Ipv6:
+			for _, ep := range eps {
+				if ep.pb.GetIpv6() == nil {
+					return nil, fmt.Errorf("IPv6 flow endpoint %q has no IPv6 config", ep.pb.GetName())
+				}
+			}
+			return func(i *intf) string { return IPv6Name(i.pb.GetName()) }, nil
+		}
+	}
+	return func(i *intf) string { return EthName(i.pb.GetName()) }, nil
+}
+
+func mapNames(intfs []*intf, name func(*intf) string) []string {
+	var names []string
+	for _, i := range intfs {
+		names = append(names, name(i))
+	}
+	return names
+}
+
+func setRate(rate gosnappi.FlowRate, fr *opb.FrameRate) error {
+	switch v := fr.GetType().(type) {
+	case nil:
+		rate.SetPercentage(defaultRatePct)
+	case *opb.FrameRate_Percent:
+		rate.SetPercentage(float32(v.Percent))
+	case *opb.FrameRate_Bps:
+		rate.SetBps(int64(v.Bps))
+	case *opb.FrameRate_Fps:
+		rate.SetPps(int64(v.Fps))
+	default:
+		return fmt.Errorf("unknown frame rate type %T", v)
+	}
+	return nil
+}
+
+func setSize(size gosnappi.FlowSize, fs *opb.FrameSize) error {
+	switch v := fs.GetType().(type) {
+	case nil:
+	case *opb.FrameSize_Fixed:
+		size.SetFixed(int32(v.Fixed))
+	case *opb.FrameSize_Random_:
+		size.Random().SetMin(int32(v.Random.GetMin())).SetMax(int32(v.Random.GetMax()))
+	case *opb.FrameSize_ImixPreset_, *opb.FrameSize_ImixCustom_:
+		return unsupported("IMIX frame sizes")
+	default:
+		return fmt.Errorf("unknown frame size type %T", v)
+	}
+	return nil
+}
+
+func setDuration(dur gosnappi.FlowDuration, tx *opb.Transmission) error {
+	gap := int32(tx.GetMinGapBytes())
+	switch tx.GetPattern() {
+	case opb.Transmission_PATTERN_UNSPECIFIED, opb.Transmission_CONTINUOUS:
+		c := dur.Continuous()
+		if gap != 0 {
+			c.SetGap(gap)
+		}
+	case opb.Transmission_FIXED_FRAME_COUNT:
+		fp := dur.FixedPackets().SetPackets(int32(tx.GetFrameCount()))
+		if gap != 0 {
+			fp.SetGap(gap)
+		}
+	case opb.Transmission_FIXED_DURATION:
+		fs := dur.FixedSeconds().SetSeconds(float32(tx.GetDurationSecs()))
+		if gap != 0 {
+			fs.SetGap(gap)
+		}
+	case opb.Transmission_BURST:
+		b := dur.Burst().SetPackets(int32(tx.GetPacketsPerBurst()))
+		if gap != 0 {
+			b.SetGap(gap)
+		}
+		switch v := tx.GetInterburstGap().(type) {
+		case *opb.Transmission_Nanoseconds:
+			b.InterBurstGap().SetNanoseconds(float64(v.Nanoseconds))
+		case *opb.Transmission_Bytes:
+			b.InterBurstGap().SetBytes(float64(v.Bytes))
+		}
+	default:
+		return fmt.Errorf("unknown transmission pattern %v", tx.GetPattern())
+	}
+	return nil
+}
+
+// inferred holds the outermost Ethernet and IP headers of a flow and the
+// addresses inferred for them from the flow endpoints, which the headers use
+// if they do not set addresses of their own.
+type inferred struct {
+	eth, ipv4, ipv6  *opb.Header
+	mac              string
+	ipv4Src, ipv4Dst string
+	ipv6Src, ipv6Dst string
+}
+
+func inferHeaders(hdrs []*opb.Header, srcs, dsts []*intf) *inferred {
+	inf := &inferred{
+		mac:     srcs[0].mac,
+		ipv4Src: intfAddr(srcs[0].pb.GetIpv4()),
+		ipv4Dst: intfAddr(dsts[0].pb.GetIpv4()),
+		ipv6Src: intfAddr(srcs[0].pb.GetIpv6()),
+		ipv6Dst: intfAddr(dsts[0].pb.GetIpv6()),
+	}
+	for _, h := range hdrs {
+		switch {
+		case h.GetEth() != nil && inf.eth == nil:
+			inf.eth = h
+		case h.GetIpv4() != nil && inf.ipv4 == nil:
+			inf.ipv4 = h
+		case h.GetIpv6() != nil && inf.ipv6 == nil:
+			inf.ipv6 = h
+		}
+	}
+	return inf
+}
+
+func intfAddr(ipc *opb.IpConfig) string {
+	addr, _, _ := strings.Cut(ipc.GetAddressCidr(), "/")
+	return addr
+}
+
+func addHeader(pkt gosnappi.FlowFlowHeaderIter, h *opb.Header, inf *inferred) error {
+	switch v := h.GetType().(type) {
+	case *opb.Header_Eth:
+		return addEthernet(pkt, v.Eth, h == inf.eth, inf)
+	case *opb.Header_Ipv4:
+		return addIPv4(pkt, v.Ipv4, h == inf.ipv4, inf)
+	case *opb.Header_Ipv6:
+		return addIPv6(pkt, v.Ipv6, h == inf.ipv6, inf)
+	case *opb.Header_Mpls:
+		return addMPLS(pkt, v.Mpls)
+	case *opb.Header_Tcp:
+		return addTCP(pkt, v.Tcp)
+	case *opb.Header_Udp:
+		return addUDP(pkt, v.Udp)
+	default:
+		return unsupported(fmt.Sprintf("header type %T", v))
+	}
+}
+
+func addEthernet(pkt gosnappi.FlowFlowHeaderIter, h *opb.EthernetHeader, outer bool, inf *inferred) error {
+	switch {
+	case h.GetBadCrc():
+		return unsupported("bad CRCs")
+	case h.GetProtocolId() != 0:
+		return unsupported("Ethernet protocol IDs")
+	}
+	eth := pkt.Add().Ethernet()
+	src := h.GetSrcAddr()
+	if outer {
+		src = inferAddr(src, inf.mac)
+	}
+	if err := setAddrPattern(eth.Src, src); err != nil {
+		return fmt.Errorf("Ethernet source address: %w", err)
+	}
+	if err := setAddrPattern(eth.Dst, h.GetDstAddr()); err != nil {
+		return fmt.Errorf("Ethernet destination address: %w", err)
+	}
+	if et := h.GetEtherType(); et != 0 {
+		eth.EtherType().SetValue(int32(et))
+	}
+	if vid := h.GetVlanId(); vid != 0 {
+		pkt.Add().Vlan().Id().SetValue(int32(vid))
+	}
+	return nil
+}
+
+func addIPv4(pkt gosnappi.FlowFlowHeaderIter, h *opb.Ipv4Header, outer bool, inf *inferred) error {
+	if h.GetChecksum() != 0 {
+		return unsupported("IPv4 checksums")
+	}
+	ip := pkt.Add().Ipv4()
+	src, dst := h.GetSrcAddr(), h.GetDstAddr()
+	if outer {
+		src = inferAddr(src, inf.ipv4Src)
+		dst = inferAddr(dst, inf.ipv4Dst)
+	}
+	if err := setAddrPattern(ip.Src, src); err != nil {
+		return fmt.Errorf("IPv4 source address: %w", err)
+	}
+	if err := setAddrPattern(ip.Dst, dst); err != nil {
+		return fmt.Errorf("IPv4 destination address: %w", err)
+	}
+	if h.GetDscp() != 0 || h.GetEcn() != 0 {
+		dscp := ip.Priority().Dscp()
+		dscp.Phb().SetValue(int32(h.GetDscp()))
+		dscp.Ecn().SetValue(int32(h.GetEcn()))
+	}
+	if id := h.GetIdentification(); id != 0 {
+		ip.Identification().SetValue(int32(id))
+	}
+	if h.GetDontFragment() {
+		ip.DontFragment().SetValue(1)
+	}
+	if h.GetMoreFragments() {
+		ip.MoreFragments().SetValue(1)
+	}
+	if off := h.GetFragmentOffset(); off != 0 {
+		ip.FragmentOffset().SetValue(int32(off))
+	}
+	if h.Ttl != nil {
+		ip.TimeToLive().SetValue(int32(h.GetTtl()))
+	}
+	if h.Protocol != nil {
+		ip.Protocol().SetValue(int32(h.GetProtocol()))
+	}
+	return nil
+}
+
+func addIPv6(pkt gosnappi.FlowFlowHeaderIter, h *opb.Ipv6Header, outer bool, inf *inferred) error {
+	ip := pkt.Add().Ipv6()
+	src, dst := h.GetSrcAddr(), h.GetDstAddr()
+	if outer {
+		src = inferAddr(src, inf.ipv6Src)
+		dst = inferAddr(dst, inf.ipv6Dst)
+	}
+	if err := setAddrPattern(ip.Src, src); err != nil {
+		return fmt.Errorf("IPv6 source address: %w", err)
+	}
+	if err := setAddrPattern(ip.Dst, dst); err != nil {
+		return fmt.Errorf("IPv6 destination address: %w", err)
+	}
+	if h.GetDscp() != 0 || h.GetEcn() != 0 {
+		ip.TrafficClass().SetValue(int32(h.GetDscp()<<2 | h.GetEcn()))
+	}
+	if h.HopLimit != nil {
+		ip.HopLimit().SetValue(int32(h.GetHopLimit()))
+	}
+	if err := setUIntPattern(ip.FlowLabel, h.GetFlowLabel()); err != nil {
+		return fmt.Errorf("IPv6 flow label: %w", err)
+	}
+	return nil
+}
+
+func addMPLS(pkt gosnappi.FlowFlowHeaderIter, h *opb.MplsHeader) error {
+	mpls := pkt.Add().Mpls()
+	if err := setUIntPattern(mpls.Label, h.GetLabel()); err != nil {
+		return fmt.Errorf("MPLS label: %w", err)
+	}
+	if exp := h.GetExp(); exp != 0 {
+		mpls.TrafficClass().SetValue(int32(exp))
+	}
+	if ttl := h.GetTtl(); ttl != 0 {
+		mpls.TimeToLive().SetValue(int32(ttl))
+	}
+	return nil
+}
+
+func addTCP(pkt gosnappi.FlowFlowHeaderIter, h *opb.TcpHeader) error {
+	tcp := pkt.Add().Tcp()
+	if err := setUIntPattern(tcp.SrcPort, h.GetSrcPort()); err != nil {
+		return fmt.Errorf("TCP source port: %w", err)
+	}
+	if err := setUIntPattern(tcp.DstPort, h.GetDstPort()); err != nil {
+		return fmt.Errorf("TCP destination port: %w", err)
+	}
+	if seq := h.GetSeq(); seq != 0 {
+		tcp.SeqNum().SetValue(int64(seq))
+	}
+	return nil
+}
+
+func addUDP(pkt gosnappi.FlowFlowHeaderIter, h *opb.UdpHeader) error {
+	udp := pkt.Add().Udp()
+	if err := setUIntPattern(udp.SrcPort, h.GetSrcPort()); err != nil {
+		return fmt.Errorf("UDP source port: %w", err)
+	}
+	if err := setUIntPattern(udp.DstPort, h.GetDstPort()); err != nil {
+		return fmt.Errorf("UDP destination port: %w", err)
+	}
+	return nil
+}
+
+// inferAddr returns the address range of a header, or if it is unset, a
+// range of the single inferred address, if there is one.
+func inferAddr(r *opb.AddressRange, addr string) *opb.AddressRange {
+	if r == nil && addr != "" {
+		return &opb.AddressRange{Min: addr, Max: addr, Count: 1}
+	}
+	return r
+}
+
+type addrCounter[C any] interface {
+	SetStart(string) C
+	SetStep(string) C
+	SetCount(int32) C
+}
+
+type addrPattern[P any, C addrCounter[C]] interface {
+	SetValue(string) P
+	Increment() C
+}
+
+// setAddrPattern sets the header address pattern returned by the getter to an
+// address range. A nil range leaves the pattern unset, so it has the OTG
+// default.
+func setAddrPattern[P addrPattern[P, C], C addrCounter[C]](get func() P, r *opb.AddressRange) error {
+	switch {
+	case r == nil:
+		return nil
+	case r.GetRandom():
+		return unsupported("random address ranges")
+	case r.GetCount() <= 1 || r.GetMin() == r.GetMax():
+		get().SetValue(r.GetMin())
+	case r.GetStep() == "":
+		return unsupported("address ranges without a step")
+	default:
+		get().Increment().SetStart(r.GetMin()).SetStep(r.GetStep()).SetCount(int32(r.GetCount()))
+	}
+	return nil
+}
+
+type uintCounter[C any] interface {
+	SetStart(int32) C
+	SetStep(int32) C
+	SetCount(int32) C
+}
+
+type uintPattern[P any, C uintCounter[C]] interface {
+	SetValue(int32) P
+	Increment() C
+}
+
+// setUIntPattern sets the header integer pattern returned by the getter to an
+// integer range. A nil range leaves the pattern unset, so it has the OTG
+// default.
+func setUIntPattern[P uintPattern[P, C], C uintCounter[C]](get func() P, r *opb.UIntRange) error {
+	switch {
+	case r == nil:
+		return nil
+	case r.GetRandom():
+		return unsupported("random integer ranges")
+	case r.GetCount() <= 1 || r.GetMin() == r.GetMax():
+		get().SetValue(int32(r.GetMin()))
+	case r.GetStep() == 0:
+		return unsupported("integer ranges without a step")
+	default:
+		get().Increment().SetStart(int32(r.GetMin())).SetStep(int32(r.GetStep())).SetCount(int32(r.GetCount()))
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/secrets"
 )
 
 var (
@@ -94,14 +95,14 @@ func Banner(t TLogger, lines ...string) {
 	for _, ln := range lines {
 		b.WriteString(indent + ln + "\n")
 	}
-	t.Log(fmt.Sprintf(format, b.String()))
+	t.Log(secrets.Redact(fmt.Sprintf(format, b.String())))
 }
 
 // Action displays the specified action message.
 // If t is not nil, the message is associated with the current test case.
 func Action(t TLogger, action string) {
 	t.Helper()
-	t.Log(fmt.Sprintf("\n*** %s...\n\n", action))
+	t.Log(secrets.Redact(fmt.Sprintf("\n*** %s...\n\n", action)))
 }
 
 // StartReader starts a stdin reader.
@@ -34,19 +34,21 @@ import (
 var (
 	reservePause bool
 	running      atomic.Bool
-	beforeTests  []func(*binding.Reservation) error
+	beforeTests  []func([]*binding.Reservation) error
 	afterTests   []func(*int) error
+	renewFailed  []func(error) error
 
 	// To be stubbed out by tests.
 	startReaderFn   = display.StartReader
 	readerStartedFn = display.ReaderStarted
 	menuFn          = display.Menu
 	readLineFn      = display.ReadLine
-	reservationFn   = testbed.Reservation
+	reservationsFn  = testbed.Reservations
 )
 
 // AddBeforeTests adds a callback to run before the tests start executing.
-func AddBeforeTests(cb func(*binding.Reservation) error) {
+// The callback is passed the reservations of all the testbeds.
+func AddBeforeTests(cb func([]*binding.Reservation) error) {
 	checkNotRunning()
 	beforeTests = append(beforeTests, cb)
 }
@@ -57,11 +59,17 @@ func AddAfterTests(cb func(*int) error) {
 	afterTests = append(afterTests, cb)
 }
 
-func runBeforeTestsCallbacks(res *binding.Reservation) []error {
+// AddRenewalFailed adds a callback to run when renewing the reservation fails.
+func AddRenewalFailed(cb func(error) error) {
+	checkNotRunning()
+	renewFailed = append(renewFailed, cb)
+}
+
+func runBeforeTestsCallbacks(rs []*binding.Reservation) []error {
 	checkRunning()
 	var errs []error
 	for _, cb := range beforeTests {
-		if err := cb(res); err != nil {
+		if err := cb(rs); err != nil {
 			errs = append(errs, callbackErr(err, cb))
 		}
 	}
@@ -117,45 +125,52 @@ func TestStarted(debugMode bool) {
 	display.Action(display.MainT, "Reserving the testbed")
 }
 
-// ReservationDone notifies that the reservation is complete and that the tests
-// are about to begin execution and runs all the BeforeTestsCallbacks.
+// ReservationDone notifies that the reservations of all the testbeds are
+// complete and that the tests are about to begin execution and runs all the
+// BeforeTestsCallbacks.
 func ReservationDone() error {
-	res, err := reservationFn()
+	rs, err := reservationsFn()
 	if err != nil {
-		return fmt.Errorf("failed to fetch the testbed reservation: %v", err)
+		return fmt.Errorf("failed to fetch the testbed reservations: %v", err)
 	}
 
-	lines := []string{
-		"Testbed Reservation Complete",
-		fmt.Sprintf("ID: %s\n", res.ID),
-	}
+	lines := []string{"Testbed Reservation Complete"}
 	addLine := func(format string, args ...any) {
 		lines = append(lines, fmt.Sprintf(format, args...))
 	}
 	addAssign := func(id, name string) {
 		addLine("  %-17s %s", id+":", name)
 	}
-	for id, d := range res.DUTs {
-		addAssign(id, d.Name())
-		for pid, p := range d.Ports() {
-			addAssign(pid, p.Name)
+	for i, res := range rs {
+		if len(rs) > 1 {
+			addLine("Testbed %d", i)
 		}
-	}
-	for id, a := range res.ATEs {
-		addAssign(id, a.Name())
-		for pid, p := range a.Ports() {
-			addAssign(pid, p.Name)
+		addLine("ID: %s\n", res.ID)
+		for id, d := range res.DUTs {
+			addAssign(id, d.Name())
+			for pid, p := range d.Ports() {
+				addAssign(pid, p.Name)
+			}
+		}
+		for id, a := range res.ATEs {
+			addAssign(id, a.Name())
+			for pid, p := range a.Ports() {
+				addAssign(pid, p.Name)
+			}
 		}
 	}
 
 	if reservePause {
-		lines = append(lines,
-			"",
-			"To reuse this reservation for another test execution, run",
-			fmt.Sprintf("  go test <TEST_NAME> --reserve=%s", res.ID),
-			"",
-			"Press CTRL-C to release the reservation or ENTER to run the test cases.",
-		)
+		lines = append(lines, "")
+		// A reservation can only be reused by ID with a single testbed.
+		if len(rs) == 1 {
+			lines = append(lines,
+				"To reuse this reservation for another test execution, run",
+				fmt.Sprintf("  go test <TEST_NAME> --reserve=%s", rs[0].ID),
+				"",
+			)
+		}
+		lines = append(lines, "Press CTRL-C to release the reservations or ENTER to run the test cases.")
 	}
 
 	display.Banner(display.MainT, lines...)
@@ -163,7 +178,7 @@ func ReservationDone() error {
 		readLineFn()
 	}
 
-	if errs := runBeforeTestsCallbacks(res); len(errs) > 0 {
+	if errs := runBeforeTestsCallbacks(rs); len(errs) > 0 {
 		return fmt.Errorf("errors running BeforeTestsCallbacks: %v", errs)
 	}
 	return nil
@@ -180,6 +195,17 @@ func TestsDone(exitCode *int) (rerr error) {
 	return nil
 }
 
+// RenewalFailed notifies that renewing the reservation failed with the
+// specified error and runs all the RenewalFailedCallbacks.
+func RenewalFailed(renewErr error) {
+	log.Warningf("Error renewing the testbed reservation: %v", renewErr)
+	for _, cb := range renewFailed {
+		if err := cb(renewErr); err != nil {
+			log.Errorf("%v", callbackErr(err, cb))
+		}
+	}
+}
+
 // ActionStarted notifies that the specified action has started.
 // Used to restrict the library to calling t.Helper and t.Log only.
 func ActionStarted(t testing.TB, format string, dev binding.Device) testing.TB {
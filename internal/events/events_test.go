@@ -15,6 +15,8 @@
 package events
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/openconfig/ondatra/binding"
@@ -45,8 +47,8 @@ func init() {
 	readLineFn = func() string {
 		return ""
 	}
-	reservationFn = func() (*binding.Reservation, error) {
-		return &binding.Reservation{}, nil
+	reservationsFn = func() ([]*binding.Reservation, error) {
+		return []*binding.Reservation{{ID: "res0"}, {ID: "res1"}}, nil
 	}
 }
 
@@ -96,3 +98,45 @@ func TestDebugModeJustReserve(t *testing.T) {
 		t.Fatalf("Breakpoint got error %v", err)
 	}
 }
+
+func TestReservationDone(t *testing.T) {
+	resetStubs()
+	TestStarted(false)
+	defer TestsDone(exitCode)
+	var got []string
+	beforeTests = []func([]*binding.Reservation) error{func(rs []*binding.Reservation) error {
+		for _, r := range rs {
+			got = append(got, r.ID)
+		}
+		return nil
+	}}
+	defer func() { beforeTests = nil }()
+
+	if err := ReservationDone(); err != nil {
+		t.Fatalf("ReservationDone() got error %v", err)
+	}
+	if want := []string{"res0", "res1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReservationDone() passed reservations %v to callback, want %v", got, want)
+	}
+}
+
+func TestRenewalFailed(t *testing.T) {
+	wantErr := errors.New("renewal error")
+	var gotErrs []error
+	cb := func(err error) error {
+		gotErrs = append(gotErrs, err)
+		return errors.New("callback error")
+	}
+	renewFailed = []func(error) error{cb, cb}
+	defer func() { renewFailed = nil }()
+
+	RenewalFailed(wantErr)
+	if len(gotErrs) != 2 {
+		t.Fatalf("RenewalFailed() ran %d callbacks, want 2", len(gotErrs))
+	}
+	for _, gotErr := range gotErrs {
+		if gotErr != wantErr {
+			t.Errorf("RenewalFailed() passed error %v to callback, want %v", gotErr, wantErr)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakessh provides a fake SSH server of a device CLI, for tests of
+// SSH clients.
+//
+// The server accepts a single username and password. An interactive shell
+// writes the banner and the prompt, echoes each line it reads like a
+// terminal, and writes the output of the line as a command followed by the
+// prompt. A non-interactive session writes the output of its command.
+package fakessh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Config is the config of a fake SSH server.
+type Config struct {
+	// User and Password are the credentials that the server accepts.
+	User, Password string
+	// Banner is written at the start of an interactive shell.
+	Banner string
+	// Prompt is written after the banner and after the output of each command.
+	Prompt string
+	// Handler returns the output of a command. It may block, for example to
+	// simulate a command that times out. If nil, commands have no output.
+	Handler func(cmd string) string
+}
+
+// Server is a fake SSH server.
+type Server struct {
+	cfg    *Config
+	sshCfg *ssh.ServerConfig
+	lis    net.Listener
+
+	mu     sync.Mutex // guards cmds and breaks
+	cmds   []string
+	breaks int
+}
+
+// New starts a fake SSH server on a local port.
+func New(cfg *Config) (*Server, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, err
+	}
+	sshCfg := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == cfg.User && string(pass) == cfg.Password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("invalid credentials for user %q", c.User())
+		},
+	}
+	sshCfg.AddHostKey(signer)
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{cfg: cfg, sshCfg: sshCfg, lis: lis}
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the address of the server.
+func (s *Server) Addr() string {
+	return s.lis.Addr().String()
+}
+
+// Commands returns the commands that the server has received, in order.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.cmds...)
+}
+
+// Breaks returns the number of breaks that the server has received.
+func (s *Server) Breaks() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.breaks
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	return s.lis.Close()
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.lis.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, s.sshCfg)
+	if err != nil {
+		return
+	}
+	defer sshConn.Close()
+	go ssh.DiscardRequests(reqs)
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			nc.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, chReqs, err := nc.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleSession(ch, chReqs)
+	}
+}
+
+func (s *Server) handleSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range reqs {
+		switch req.Type {
+		case "pty-req", "env", "window-change":
+			req.Reply(true, nil)
+		case "shell":
+			req.Reply(true, nil)
+			go s.handleShellRequests(reqs)
+			s.shell(ch)
+			return
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				req.Reply(false, nil)
+				return
+			}
+			req.Reply(true, nil)
+			go ssh.DiscardRequests(reqs)
+			io.WriteString(ch, s.run(payload.Command))
+			ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+			return
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+// handleShellRequests accepts breaks and rejects other requests to a shell.
+func (s *Server) handleShellRequests(reqs <-chan *ssh.Request) {
+	for req := range reqs {
+		if req.Type != "break" {
+			req.Reply(false, nil)
+			continue
+		}
+		s.mu.Lock()
+		s.breaks++
+		s.mu.Unlock()
+		req.Reply(true, nil)
+	}
+}
+
+func (s *Server) shell(ch ssh.Channel) {
+	io.WriteString(ch, s.cfg.Banner+s.cfg.Prompt)
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		if _, err := ch.Read(b); err != nil {
+			return
+		}
+		switch b[0] {
+		case '\r':
+		case '\n':
+			cmd := string(line)
+			line = nil
+			out := s.run(cmd)
+			if out != "" && !strings.HasSuffix(out, "\n") {
+				out += "\n"
+			}
+			out = strings.ReplaceAll(out, "\n", "\r\n")
+			if _, err := io.WriteString(ch, cmd+"\r\n"+out+s.cfg.Prompt); err != nil {
+				return
+			}
+		default:
+			line = append(line, b[0])
+		}
+	}
+}
+
+func (s *Server) run(cmd string) string {
+	s.mu.Lock()
+	s.cmds = append(s.cmds, cmd)
+	s.mu.Unlock()
+	if s.cfg.Handler == nil {
+		return ""
+	}
+	return s.cfg.Handler(cmd)
+}
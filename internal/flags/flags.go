@@ -17,33 +17,51 @@ package flags
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"flag"
+
+	"github.com/openconfig/ondatra/internal/trace"
+	"github.com/openconfig/ondatra/secrets"
 )
 
 var (
-	testbed = flag.String("testbed", "", "Path to the Ondatra testbed file")
+	testbed = flag.String("testbed", "", "Path to the Ondatra testbed file, "+
+		"or a comma-separated list of paths to reserve several testbeds and run tests on them in parallel")
 	runTime = flag.Duration("run_time", 0, "Timeout of the test run, excluding the wait time for the testbed to be ready. "+
 		"A zero value means here is no time limit. Must be a non-negative value.")
 	waitTime = flag.Duration("wait_time", 0, "Maximum amount of time the test should wait until the testbed is ready. "+
 		"A zero value lets the binding implementation choose an appropriate wait time. Must be a non-negative value.")
 	reserve = flag.String("reserve", "", "Reservation id or a mapping of device and port IDs to names of the form "+
 		"'dut=mydevice,dut:port1=Ethernet1/1,ate=myixia,ate:port2=2/3'")
-	xml   = flag.String("xml", "", "File path to write JUnit XML test results; disables normal Go test logging.")
-	debug = flag.Bool("debug", false, "Whether the test is run in debug mode")
+	heartbeat = flag.Duration("heartbeat_interval", 5*time.Minute, "Interval at which to send reservation heartbeats, "+
+		"if the binding supports renewing reservations. A zero value disables heartbeats. Must be a non-negative value.")
+	xml      = flag.String("xml", "", "File path to write JUnit XML test results; disables normal Go test logging.")
+	traceFmt = flag.String("trace_format", "", "Format of the trace of the test timeline, written next to the XML results; "+
+		"either 'otlp' for OTLP-JSON or 'chrome' for Chrome trace events. Requires the xml flag.")
+	record = flag.String("record", "", "File path to record all device RPC traffic, for later replay with the replaybind binding.")
+	debug  = flag.Bool("debug", false, "Whether the test is run in debug mode")
+	secret = flag.String("secrets", "", "Provider of the secrets in configs and binding credentials, "+
+		"either 'file:<dir>', 'env:<prefix>', or 'exec:<command>'; see the secrets package.")
 )
 
 // Values is the set of parsed and validated flag values.
 type Values struct {
-	TestbedPath string
-	RunTime     time.Duration
-	WaitTime    time.Duration
-	ResvID      string
-	ResvPartial map[string]string
-	XMLPath     string
-	Debug       bool
+	TestbedPath       string
+	TestbedPaths      []string
+	RunTime           time.Duration
+	WaitTime          time.Duration
+	ResvID            string
+	ResvPartial       map[string]string
+	HeartbeatInterval time.Duration
+	XMLPath           string
+	TraceFormat       trace.Format
+	TracePath         string
+	RecordPath        string
+	Debug             bool
+	Secrets           secrets.Provider
 }
 
 // Parse parse and validates the flag values.
@@ -66,6 +84,9 @@ func Parse() (*Values, error) {
 	if *waitTime < 0 {
 		return nil, fmt.Errorf("wait timeout is negative: %d", *waitTime)
 	}
+	if *heartbeat < 0 {
+		return nil, fmt.Errorf("heartbeat interval is negative: %d", *heartbeat)
+	}
 	if *reserve != "" && !*debug {
 		return nil, fmt.Errorf("reserve flag is only allowed in debug mode")
 	}
@@ -73,14 +94,43 @@ func Parse() (*Values, error) {
 	if err != nil {
 		return nil, err
 	}
+	testbedPaths := strings.Split(*testbed, ",")
+	for _, p := range testbedPaths {
+		if p == "" {
+			return nil, fmt.Errorf("empty path in testbed list %q", *testbed)
+		}
+	}
+	var traceFormat trace.Format
+	var tracePath string
+	if *traceFmt != "" {
+		if *xml == "" {
+			return nil, fmt.Errorf("trace_format flag requires the xml flag")
+		}
+		if traceFormat, err = trace.ParseFormat(*traceFmt); err != nil {
+			return nil, err
+		}
+		tracePath = strings.TrimSuffix(*xml, filepath.Ext(*xml)) + ".trace.json"
+	}
+	var secretsProvider secrets.Provider
+	if *secret != "" {
+		if secretsProvider, err = secrets.ParseProvider(*secret); err != nil {
+			return nil, err
+		}
+	}
 	return &Values{
-		TestbedPath: *testbed,
-		RunTime:     *runTime,
-		WaitTime:    *waitTime,
-		ResvID:      resvID,
-		ResvPartial: resvPartial,
-		XMLPath:     *xml,
-		Debug:       *debug,
+		TestbedPath:       testbedPaths[0],
+		TestbedPaths:      testbedPaths,
+		RunTime:           *runTime,
+		WaitTime:          *waitTime,
+		ResvID:            resvID,
+		ResvPartial:       resvPartial,
+		HeartbeatInterval: *heartbeat,
+		XMLPath:           *xml,
+		TraceFormat:       traceFormat,
+		TracePath:         tracePath,
+		RecordPath:        *record,
+		Debug:             *debug,
+		Secrets:           secretsProvider,
 	}, nil
 }
 
@@ -0,0 +1,192 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health checks that the reserved DUTs are reachable and, if they are
+// not, attempts to recover them by restoring their base configuration.
+package health
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/internal/rawapis"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	spb "github.com/openconfig/gnoi/system"
+)
+
+// Config specifies which health checks to run.
+type Config struct {
+	// GNMI probes the gNMI endpoint with a Capabilities request.
+	GNMI bool
+	// GNOI probes the gNOI endpoint with a System Time request.
+	GNOI bool
+	// CLI probes the CLI endpoint by running CLICommand.
+	CLI bool
+	// CLICommand is the command run by the CLI probe.
+	// If empty, the CLI probe only checks that the CLI can be dialed.
+	CLICommand string
+	// ResetConfig restores the base config of a DUT that fails a probe, by
+	// pushing an empty config with reset=true, and then probes the DUT again.
+	ResetConfig bool
+	// Timeout is the time allowed for each probe. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// Probe names, which are also the keys of Result.Probes.
+const (
+	ProbeGNMI = "gnmi"
+	ProbeGNOI = "gnoi"
+	ProbeCLI  = "cli"
+)
+
+// Result is the result of checking the health of a DUT.
+type Result struct {
+	// Probes maps each probe name to its error, which is nil if the probe passed.
+	// If the DUT was recovered, these are the results of the probes after recovery.
+	Probes map[string]error
+	// Recovered is true if the DUT initially failed a probe and its base config
+	// was restored.
+	Recovered bool
+	// RecoveryErr is the error restoring the base config, if any.
+	RecoveryErr error
+}
+
+// Healthy reports whether the DUT passed every probe.
+func (r *Result) Healthy() bool {
+	return r.Err() == nil
+}
+
+// Err returns an error describing all the failed probes, or nil if there are none.
+func (r *Result) Err() error {
+	var names []string
+	for name := range r.Probes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		if err := r.Probes[name]; err != nil {
+			errs = append(errs, fmt.Errorf("%s probe failed: %w", name, err))
+		}
+	}
+	if r.RecoveryErr != nil {
+		errs = append(errs, fmt.Errorf("restoring base config failed: %w", r.RecoveryErr))
+	}
+	return errors.Join(errs...)
+}
+
+// Check probes the specified DUT and, if it is unhealthy and the config
+// requests it, restores its base config and probes it again.
+func Check(ctx context.Context, dut binding.DUT, cfg *Config) *Result {
+	res := &Result{Probes: probe(ctx, dut, cfg)}
+	if res.Healthy() || !cfg.ResetConfig {
+		return res
+	}
+	res.Recovered = true
+	if err := dut.PushConfig(ctx, "", true); err != nil {
+		res.RecoveryErr = err
+		return res
+	}
+	res.Probes = probe(ctx, dut, cfg)
+	return res
+}
+
+// CheckAll checks the health of all the specified DUTs in parallel and returns
+// a map from DUT ID to result.
+func CheckAll(ctx context.Context, duts map[string]binding.DUT, cfg *Config) map[string]*Result {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]*Result)
+	)
+	for id, dut := range duts {
+		wg.Add(1)
+		go func(id string, dut binding.DUT) {
+			defer wg.Done()
+			res := Check(ctx, dut, cfg)
+			mu.Lock()
+			defer mu.Unlock()
+			results[id] = res
+		}(id, dut)
+	}
+	wg.Wait()
+	return results
+}
+
+func probe(ctx context.Context, dut binding.DUT, cfg *Config) map[string]error {
+	probes := make(map[string]error)
+	run := func(name string, fn func(context.Context, binding.DUT, *Config) error) {
+		ctx := ctx
+		if cfg.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+			defer cancel()
+		}
+		probes[name] = fn(ctx, dut, cfg)
+	}
+	if cfg.GNMI {
+		run(ProbeGNMI, probeGNMI)
+	}
+	if cfg.GNOI {
+		run(ProbeGNOI, probeGNOI)
+	}
+	if cfg.CLI {
+		run(ProbeCLI, probeCLI)
+	}
+	return probes
+}
+
+func probeGNMI(ctx context.Context, dut binding.DUT, _ *Config) error {
+	c, err := rawapis.FetchGNMI(ctx, dut)
+	if err != nil {
+		return err
+	}
+	_, err = c.Capabilities(ctx, &gpb.CapabilityRequest{})
+	return err
+}
+
+func probeGNOI(ctx context.Context, dut binding.DUT, _ *Config) error {
+	c, err := rawapis.FetchGNOI(ctx, dut)
+	if err != nil {
+		return err
+	}
+	_, err = c.System().Time(ctx, &spb.TimeRequest{})
+	return err
+}
+
+func probeCLI(ctx context.Context, dut binding.DUT, cfg *Config) error {
+	c, err := rawapis.NewCLI(ctx, dut)
+	if err != nil {
+		return err
+	}
+	if cfg.CLICommand == "" {
+		return nil
+	}
+	res, err := c.RunCommand(ctx, cfg.CLICommand)
+	if err != nil {
+		return err
+	}
+	if msg := strings.TrimSpace(res.Error()); msg != "" {
+		return fmt.Errorf("command %q failed: %s", cfg.CLICommand, msg)
+	}
+	return nil
+}
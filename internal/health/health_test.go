@@ -0,0 +1,155 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/fakebind"
+	"github.com/openconfig/ondatra/simbind/emulator"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"google.golang.org/grpc"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+type fakeCLI struct {
+	*binding.AbstractCLIClient
+	errMsg string
+}
+
+func (c *fakeCLI) RunCommand(context.Context, string) (binding.CommandResult, error) {
+	return &fakeResult{errMsg: c.errMsg}, nil
+}
+
+type fakeResult struct {
+	*binding.AbstractCommandResult
+	errMsg string
+}
+
+func (r *fakeResult) Error() string {
+	return r.errMsg
+}
+
+// newDUT returns a fake DUT whose gNMI endpoint is unreachable until its
+// config is reset, if wedged is true.
+func newDUT(t *testing.T, wedged bool) (*fakebind.DUT, *int) {
+	t.Helper()
+	dev, err := emulator.New("dut", exampleoc.Schema)
+	if err != nil {
+		t.Fatalf("emulator.New() got error: %v", err)
+	}
+	t.Cleanup(func() { dev.Close() })
+	var resets int
+	dut := &fakebind.DUT{AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{Name: "dut"}}}
+	dut.DialGNMIFn = func(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+		if wedged {
+			return nil, errors.New("connection refused")
+		}
+		conn, err := dev.Dial(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return gpb.NewGNMIClient(conn), nil
+	}
+	dut.PushConfigFn = func(_ context.Context, _ string, reset bool) error {
+		if reset {
+			resets++
+			wedged = false
+		}
+		return nil
+	}
+	dut.DialCLIFn = func(context.Context) (binding.CLIClient, error) {
+		return &fakeCLI{}, nil
+	}
+	return dut, &resets
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		desc          string
+		wedged        bool
+		cfg           *Config
+		wantHealthy   bool
+		wantRecovered bool
+		wantErr       string
+	}{{
+		desc:        "healthy",
+		cfg:         &Config{GNMI: true, CLI: true, CLICommand: "show version"},
+		wantHealthy: true,
+	}, {
+		desc:    "wedged without reset",
+		wedged:  true,
+		cfg:     &Config{GNMI: true},
+		wantErr: "gnmi probe failed",
+	}, {
+		desc:          "wedged with reset",
+		wedged:        true,
+		cfg:           &Config{GNMI: true, ResetConfig: true},
+		wantHealthy:   true,
+		wantRecovered: true,
+	}, {
+		desc:        "no probes",
+		wedged:      true,
+		cfg:         &Config{},
+		wantHealthy: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			dut, resets := newDUT(t, test.wedged)
+			res := Check(context.Background(), dut, test.cfg)
+			if got := res.Healthy(); got != test.wantHealthy {
+				t.Errorf("Check() got healthy %t, want %t", got, test.wantHealthy)
+			}
+			if res.Recovered != test.wantRecovered {
+				t.Errorf("Check() got recovered %t, want %t", res.Recovered, test.wantRecovered)
+			}
+			if wantResets := map[bool]int{true: 1}[test.wantRecovered]; *resets != wantResets {
+				t.Errorf("Check() reset the config %d times, want %d", *resets, wantResets)
+			}
+			if gotErr := res.Err(); (gotErr == nil) != (test.wantErr == "") || (gotErr != nil && !strings.Contains(gotErr.Error(), test.wantErr)) {
+				t.Errorf("Check() got err %v, want %q", gotErr, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckCLIError(t *testing.T) {
+	dut, _ := newDUT(t, false)
+	dut.DialCLIFn = func(context.Context) (binding.CLIClient, error) {
+		return &fakeCLI{errMsg: "% Invalid input"}, nil
+	}
+	res := Check(context.Background(), dut, &Config{CLI: true, CLICommand: "show version"})
+	if err := res.Err(); err == nil || !strings.Contains(err.Error(), "cli probe failed") {
+		t.Errorf("Check() got err %v, want cli probe failure", err)
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	dut1, _ := newDUT(t, false)
+	dut2, _ := newDUT(t, true)
+	results := CheckAll(context.Background(), map[string]binding.DUT{"dut1": dut1, "dut2": dut2}, &Config{GNMI: true})
+	if !results["dut1"].Healthy() {
+		t.Errorf("CheckAll() got dut1 unhealthy: %v", results["dut1"].Err())
+	}
+	if results["dut2"].Healthy() {
+		t.Errorf("CheckAll() got dut2 healthy, want unhealthy")
+	}
+}
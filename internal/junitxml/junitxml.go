@@ -21,10 +21,12 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jstemmer/go-junit-report/v2/junit"
 	"github.com/jstemmer/go-junit-report/v2/parser/gotest"
+	"github.com/openconfig/ondatra/secrets"
 )
 
 var (
@@ -84,10 +86,14 @@ func startConverter(src, dst io.Writer) (*converter, error) {
 type converter struct {
 	file  *os.File
 	errCh <-chan error
+
+	mu    sync.Mutex // Guards props, which parallel tests may add to.
 	props []junit.Property
 }
 
 func (c *converter) addProperty(test, name, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.props = append(c.props, encodeProperty(test, name, value))
 }
 
@@ -99,17 +105,25 @@ func (c *converter) Stop() error {
 }
 
 func (c *converter) logToXML(r io.Reader, w io.Writer) error {
+	// Redact the secrets after reading the entire log, because a secret may
+	// only be registered after it is first written to the log.
+	logBytes, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error reading test log: %w", err)
+	}
 	parser := gotest.NewParser(gotest.TimestampFunc(timeNowFn))
-	report, err := parser.Parse(r)
+	report, err := parser.Parse(strings.NewReader(secrets.Redact(string(logBytes))))
 	if err != nil {
 		return fmt.Errorf("error parsing test log: %w", err)
 	}
 	if len(report.Packages) != 1 {
 		return fmt.Errorf("expecting 1 generated package but got: %v", report.Packages)
 	}
+	c.mu.Lock()
 	for _, p := range c.props {
-		report.Packages[0].AddProperty(p.Name, p.Value)
+		report.Packages[0].AddProperty(p.Name, secrets.Redact(p.Value))
 	}
+	c.mu.Unlock()
 
 	testsuites := junit.CreateFromReport(report, "")
 	if err := writeXML(&testsuites, w); err != nil {
@@ -18,12 +18,14 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/jstemmer/go-junit-report/v2/junit"
 	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ondatra/secrets"
 )
 
 const (
@@ -117,6 +119,36 @@ func TestDstError(t *testing.T) {
 	}
 }
 
+func TestConverterRedactsSecrets(t *testing.T) {
+	dstBuf := new(bytes.Buffer)
+	conv, err := startConverter(io.Discard, dstBuf)
+	if err != nil {
+		t.Fatalf("startConverting failed: %v", err)
+	}
+	conv.addProperty("TestSecret", "password", "s3cr3t-prop")
+	const log = `=== RUN   TestSecret
+    secret_test.go:6: logged in with s3cr3t-log
+--- FAIL: TestSecret (0.01s)
+FAIL
+FAIL  	example_test	0.01s`
+	if _, err := conv.file.Write([]byte(log)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// Secrets registered after they are logged must also be redacted.
+	secrets.Register("s3cr3t-log")
+	secrets.Register("s3cr3t-prop")
+	if err := conv.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	got := dstBuf.String()
+	if strings.Contains(got, "s3cr3t") {
+		t.Errorf("Converter wrote secrets to XML:\n%s", got)
+	}
+	if want := "logged in with " + secrets.Redacted; !strings.Contains(got, want) {
+		t.Errorf("Converter wrote XML without %q:\n%s", want, got)
+	}
+}
+
 func mustStart(t *testing.T, src, dst io.Writer) *converter {
 	conv, err := startConverter(src, dst)
 	if err != nil {
@@ -20,12 +20,26 @@ import (
 
 	"golang.org/x/net/context"
 
+	"github.com/openconfig/ondatra/internal/record"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 )
 
+// withRecording records every RPC to the specified device, if recording is
+// enabled. It must follow the other interceptors, so that it records the
+// messages and errors exactly as sent and received on the wire.
+func withRecording(dev interface{ Name() string }) []grpc.DialOption {
+	if !record.Enabled() {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(record.UnaryInterceptor(dev.Name())),
+		grpc.WithChainStreamInterceptor(record.StreamInterceptor(dev.Name())),
+	}
+}
+
 // withUnaryAnnotateErrors annotates every gRPC error returned by an RPC with
 // the RPC request message.
 func withUnaryAnnotateErrors() grpc.DialOption {
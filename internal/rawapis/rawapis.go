@@ -31,6 +31,7 @@ import (
 	"github.com/open-traffic-generator/snappi/gosnappi"
 	"github.com/openconfig/gnoigo"
 	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/internal/record"
 	"google.golang.org/grpc"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
@@ -46,9 +47,18 @@ var CommonDialOpts = []grpc.DialOption{
 	withStreamAnnotateErrors(),
 }
 
+// dialOpts returns the dial options for the specified device.
+func dialOpts(dev interface{ Name() string }) []grpc.DialOption {
+	return append(append([]grpc.DialOption{}, CommonDialOpts...), withRecording(dev)...)
+}
+
 // NewCLI creates a CLI client for the specified DUT.
 func NewCLI(ctx context.Context, dut binding.DUT) (binding.CLIClient, error) {
-	return dut.DialCLI(ctx)
+	c, err := dut.DialCLI(ctx)
+	if err != nil || !record.Enabled() {
+		return c, err
+	}
+	return record.CLIClient(dut.Name(), c), nil
 }
 
 // NewConsole creates a console client for the specified DUT.
@@ -63,12 +73,13 @@ var (
 
 // GNMIDialer is an interface for devices that can dial gNMI.
 type GNMIDialer interface {
+	Name() string
 	DialGNMI(context.Context, ...grpc.DialOption) (gpb.GNMIClient, error)
 }
 
 // NewGNMI creates a new gNMI client for the specified DUT.
 func NewGNMI(ctx context.Context, dialer GNMIDialer) (gpb.GNMIClient, error) {
-	return dialer.DialGNMI(ctx, CommonDialOpts...)
+	return dialer.DialGNMI(ctx, dialOpts(dialer)...)
 }
 
 // FetchGNMI fetches the cached gNMI client for the specified DUT.
@@ -94,7 +105,7 @@ var (
 
 // NewGNOI creates a gNOI client for the specified DUT.
 func NewGNOI(ctx context.Context, dut binding.DUT) (gnoigo.Clients, error) {
-	return dut.DialGNOI(ctx, CommonDialOpts...)
+	return dut.DialGNOI(ctx, dialOpts(dut)...)
 }
 
 // FetchGNOI fetches the cached gNOI client for the specified DUT.
@@ -120,7 +131,7 @@ var (
 
 // NewGNSI creates a gNSI client for the specified DUT.
 func NewGNSI(ctx context.Context, dut binding.DUT) (binding.GNSIClients, error) {
-	return dut.DialGNSI(ctx, CommonDialOpts...)
+	return dut.DialGNSI(ctx, dialOpts(dut)...)
 }
 
 // FetchGNSI fetches the cached gNSI client for the specified DUT.
@@ -146,7 +157,7 @@ var (
 
 // NewGRIBI creates a new gRIBI client for the specified DUT.
 func NewGRIBI(ctx context.Context, dut binding.DUT) (grpb.GRIBIClient, error) {
-	return dut.DialGRIBI(ctx, CommonDialOpts...)
+	return dut.DialGRIBI(ctx, dialOpts(dut)...)
 }
 
 // FetchGRIBI fetches the cached gRIBI client for the specified DUT.
@@ -172,7 +183,7 @@ var (
 
 // NewP4RT creates a new P4RT client for the specified DUT.
 func NewP4RT(ctx context.Context, dut binding.DUT) (p4pb.P4RuntimeClient, error) {
-	return dut.DialP4RT(ctx, CommonDialOpts...)
+	return dut.DialP4RT(ctx, dialOpts(dut)...)
 }
 
 // FetchP4RT fetches the cached P4RT client for the specified DUT.
@@ -224,7 +235,7 @@ func FetchOTG(ctx context.Context, ate binding.ATE) (gosnappi.GosnappiApi, error
 	c, ok := otgs[ate]
 	if !ok {
 		var err error
-		c, err = ate.DialOTG(ctx, CommonDialOpts...)
+		c, err = ate.DialOTG(ctx, dialOpts(ate)...)
 		if err != nil {
 			return nil, fmt.Errorf("error dialing OTG: %w", err)
 		}
@@ -245,7 +256,7 @@ func FetchOTGGNMI(ctx context.Context, ate binding.ATE) (gpb.GNMIClient, error)
 	c, ok := otgGNMIs[ate]
 	if !ok {
 		var err error
-		c, err = ate.DialGNMI(ctx, CommonDialOpts...)
+		c, err = ate.DialGNMI(ctx, dialOpts(ate)...)
 		if err != nil {
 			return nil, fmt.Errorf("error dialing OTG GNMI: %w", err)
 		}
@@ -0,0 +1,402 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package record records the RPC traffic between a test and its devices to a
+// log file, from which the traffic can later be replayed.
+//
+// The log is a file of JSON lines, each of which is an [Entry]. The first
+// entry describes the reservation, and every following entry is an event of
+// a gRPC call or CLI command, in the order in which the events occurred.
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/secrets"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// Kind is the kind of a log entry.
+type Kind string
+
+const (
+	// KindReservation describes the reserved devices.
+	KindReservation Kind = "reservation"
+	// KindStart is the start of a gRPC stream.
+	KindStart Kind = "start"
+	// KindSend is a message sent by the client: a gRPC request or a CLI command.
+	KindSend Kind = "send"
+	// KindCloseSend is the client closing the send direction of a gRPC stream.
+	KindCloseSend Kind = "close_send"
+	// KindRecv is a message received by the client: a gRPC response or the
+	// output of a CLI command.
+	KindRecv Kind = "recv"
+	// KindEnd is the end of a call, with its final status.
+	KindEnd Kind = "end"
+)
+
+// CLIMethod is the method name of CLI command entries.
+const CLIMethod = "CLI"
+
+// Entry is an entry of the log.
+type Entry struct {
+	Time        time.Time    `json:"time"`
+	Kind        Kind         `json:"kind"`
+	Device      string       `json:"device,omitempty"`
+	Method      string       `json:"method,omitempty"`
+	Call        uint64       `json:"call,omitempty"`
+	Type        string       `json:"type,omitempty"`
+	Message     []byte       `json:"message,omitempty"`
+	Code        codes.Code   `json:"code,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Reservation *Reservation `json:"reservation,omitempty"`
+}
+
+// Reservation describes the reserved devices of a recording.
+type Reservation struct {
+	ID   string             `json:"id"`
+	DUTs map[string]*Device `json:"duts,omitempty"`
+	ATEs map[string]*Device `json:"ates,omitempty"`
+}
+
+// Device describes a reserved device.
+type Device struct {
+	Name            string            `json:"name"`
+	Vendor          opb.Device_Vendor `json:"vendor"`
+	HardwareModel   string            `json:"hardware_model,omitempty"`
+	SoftwareVersion string            `json:"software_version,omitempty"`
+	Ports           map[string]string `json:"ports,omitempty"`
+}
+
+func newDevice(d binding.Device) *Device {
+	dev := &Device{
+		Name:            d.Name(),
+		Vendor:          d.Vendor(),
+		HardwareModel:   d.HardwareModel(),
+		SoftwareVersion: d.SoftwareVersion(),
+		Ports:           make(map[string]string),
+	}
+	for id, p := range d.Ports() {
+		dev.Ports[id] = p.Name
+	}
+	return dev
+}
+
+var (
+	mu    sync.Mutex
+	file  *os.File
+	w     *bufio.Writer
+	enc   *json.Encoder
+	calls atomic.Uint64
+)
+
+// Start starts recording to a log file at the specified path, whose first
+// entry describes the specified reservation.
+func Start(path string, res *binding.Reservation) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if file != nil {
+		return errors.New("recording already started")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating record file: %w", err)
+	}
+	file, w = f, bufio.NewWriter(f)
+	enc = json.NewEncoder(w)
+	r := &Reservation{
+		ID:   res.ID,
+		DUTs: make(map[string]*Device),
+		ATEs: make(map[string]*Device),
+	}
+	for id, d := range res.DUTs {
+		r.DUTs[id] = newDevice(d)
+	}
+	for id, a := range res.ATEs {
+		r.ATEs[id] = newDevice(a)
+	}
+	return writeLocked(&Entry{Time: time.Now(), Kind: KindReservation, Reservation: r})
+}
+
+// Stop stops recording and closes the log file.
+func Stop() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if file == nil {
+		return nil
+	}
+	err := errors.Join(w.Flush(), file.Close())
+	file, w, enc = nil, nil, nil
+	return err
+}
+
+// Enabled reports whether recording is in progress.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return file != nil
+}
+
+func write(e *Entry) {
+	mu.Lock()
+	defer mu.Unlock()
+	if file == nil {
+		return
+	}
+	e.Time = time.Now()
+	if err := writeLocked(e); err != nil {
+		// Recording is best-effort and must not fail the test.
+		log.Warningf("Error writing record entry: %v", err)
+	}
+}
+
+func writeLocked(e *Entry) error {
+	e.Error = secrets.Redact(e.Error)
+	return enc.Encode(e)
+}
+
+func messageEntry(e *Entry, m any) *Entry {
+	if pm, ok := m.(proto.Message); ok {
+		e.Type = string(pm.ProtoReflect().Descriptor().FullName())
+		b, err := marshalRedacted(pm)
+		if err != nil {
+			e.Error = err.Error()
+		}
+		e.Message = b
+	}
+	return e
+}
+
+// marshalRedacted marshals the message with secrets redacted from its string
+// and bytes fields. Those fields appear verbatim in the wire format, so the
+// message is only copied and redacted if its encoding contains a secret.
+func marshalRedacted(m proto.Message) ([]byte, error) {
+	opts := proto.MarshalOptions{Deterministic: true}
+	b, err := opts.Marshal(m)
+	if err != nil || secrets.Redact(string(b)) == string(b) {
+		return b, err
+	}
+	m = proto.Clone(m)
+	redactMessage(m.ProtoReflect())
+	return opts.Marshal(m)
+}
+
+// redactMessage redacts secrets from the string and bytes fields of the
+// message, in place.
+func redactMessage(m protoreflect.Message) {
+	type change struct {
+		fd protoreflect.FieldDescriptor
+		v  protoreflect.Value
+	}
+	var changes []change
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				if nv, ok := redactValue(fd, l.Get(i)); ok {
+					l.Set(i, nv)
+				}
+			}
+		case fd.IsMap():
+			mv := v.Map()
+			var keys []protoreflect.MapKey
+			mv.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			for _, k := range keys {
+				if nv, ok := redactValue(fd.MapValue(), mv.Get(k)); ok {
+					mv.Set(k, nv)
+				}
+			}
+		default:
+			if nv, ok := redactValue(fd, v); ok {
+				changes = append(changes, change{fd, nv})
+			}
+		}
+		return true
+	})
+	for _, c := range changes {
+		m.Set(c.fd, c.v)
+	}
+}
+
+// redactValue returns the value with secrets redacted and whether it changed.
+// Message values are redacted in place.
+func redactValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (protoreflect.Value, bool) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if r := secrets.Redact(v.String()); r != v.String() {
+			return protoreflect.ValueOfString(r), true
+		}
+	case protoreflect.BytesKind:
+		if r := secrets.Redact(string(v.Bytes())); r != string(v.Bytes()) {
+			return protoreflect.ValueOfBytes([]byte(r)), true
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		redactMessage(v.Message())
+	}
+	return v, false
+}
+
+func endEntry(e *Entry, err error) *Entry {
+	e.Kind = KindEnd
+	if err != nil && err != io.EOF {
+		st := status.Convert(err)
+		e.Code = st.Code()
+		e.Error = st.Message()
+	}
+	return e
+}
+
+// UnaryInterceptor returns an interceptor that records the unary calls to the
+// device with the specified name.
+func UnaryInterceptor(device string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		call := calls.Add(1)
+		newEntry := func(kind Kind) *Entry {
+			return &Entry{Kind: kind, Device: device, Method: method, Call: call}
+		}
+		write(messageEntry(newEntry(KindSend), req))
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			write(messageEntry(newEntry(KindRecv), reply))
+		}
+		write(endEntry(newEntry(KindEnd), err))
+		return err
+	}
+}
+
+// StreamInterceptor returns an interceptor that records the streaming calls
+// to the device with the specified name.
+func StreamInterceptor(device string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		rs := &recordStream{device: device, method: method, call: calls.Add(1)}
+		write(rs.newEntry(KindStart))
+		client, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			write(endEntry(rs.newEntry(KindEnd), err))
+			return nil, err
+		}
+		rs.ClientStream = client
+		return rs, nil
+	}
+}
+
+type recordStream struct {
+	grpc.ClientStream
+	device, method string
+	call           uint64
+	ended          atomic.Bool
+}
+
+func (s *recordStream) newEntry(kind Kind) *Entry {
+	return &Entry{Kind: kind, Device: s.device, Method: s.method, Call: s.call}
+}
+
+func (s *recordStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		write(messageEntry(s.newEntry(KindSend), m))
+	}
+	return err
+}
+
+func (s *recordStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err == nil {
+		write(s.newEntry(KindCloseSend))
+	}
+	return err
+}
+
+func (s *recordStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		write(messageEntry(s.newEntry(KindRecv), m))
+	} else if !s.ended.Swap(true) {
+		write(endEntry(s.newEntry(KindEnd), err))
+	}
+	return err
+}
+
+// CLIClient returns a CLI client that records the commands run by the
+// specified client on the device with the specified name.
+func CLIClient(device string, c binding.CLIClient) binding.CLIClient {
+	return &recordCLI{CLIClient: c, device: device}
+}
+
+type recordCLI struct {
+	binding.CLIClient
+	device string
+}
+
+func (c *recordCLI) RunCommand(ctx context.Context, cmd string) (binding.CommandResult, error) {
+	call := calls.Add(1)
+	newEntry := func(kind Kind) *Entry {
+		return &Entry{Kind: kind, Device: c.device, Method: CLIMethod, Call: call}
+	}
+	send := newEntry(KindSend)
+	send.Message = []byte(secrets.Redact(cmd))
+	write(send)
+	res, err := c.CLIClient.RunCommand(ctx, cmd)
+	if err == nil {
+		e := newEntry(KindRecv)
+		e.Message = []byte(secrets.Redact(res.Output()))
+		e.Error = res.Error()
+		write(e)
+	}
+	write(endEntry(newEntry(KindEnd), err))
+	return res, err
+}
+
+// ReadLog reads all the entries of the log file at the specified path.
+func ReadLog(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening record file: %w", err)
+	}
+	defer f.Close()
+	var entries []*Entry
+	dec := json.NewDecoder(f)
+	for {
+		e := new(Entry)
+		if err := dec.Decode(e); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error decoding record file %s: %w", path, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package record
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/secrets"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+type fakeCLI struct {
+	*binding.AbstractCLIClient
+	output string
+}
+
+func (c *fakeCLI) RunCommand(context.Context, string) (binding.CommandResult, error) {
+	return &fakeResult{output: c.output}, nil
+}
+
+type fakeResult struct {
+	*binding.AbstractCommandResult
+	output string
+}
+
+func (r *fakeResult) Output() string { return r.output }
+func (r *fakeResult) Error() string  { return "" }
+
+func TestRedactSecrets(t *testing.T) {
+	const secret = "record-s3cret"
+	secrets.Register(secret)
+	path := filepath.Join(t.TempDir(), "record.json")
+	if err := Start(path, &binding.Reservation{ID: "resv"}); err != nil {
+		t.Fatalf("Start() got error: %v", err)
+	}
+	ctx := context.Background()
+
+	cli := CLIClient("dut", &fakeCLI{output: "password " + secret})
+	if _, err := cli.RunCommand(ctx, "set password "+secret); err != nil {
+		t.Fatalf("RunCommand() got error: %v", err)
+	}
+	req := &gpb.SetRequest{Update: []*gpb.Update{{
+		Path: &gpb.Path{Elem: []*gpb.PathElem{{Name: "password"}}},
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"` + secret + `"`)}},
+	}}}
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return errors.New("wrong password " + secret)
+	}
+	UnaryInterceptor("dut")(ctx, "/gnmi.gNMI/Set", req, &gpb.SetResponse{}, nil, invoker)
+	if err := Stop(); err != nil {
+		t.Fatalf("Stop() got error: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() got error: %v", err)
+	}
+	if strings.Contains(string(b), secret) {
+		t.Errorf("Record file contains the secret: %s", b)
+	}
+	entries, err := ReadLog(path)
+	if err != nil {
+		t.Fatalf("ReadLog() got error: %v", err)
+	}
+	var gotReq *gpb.SetRequest
+	for _, e := range entries {
+		if e.Method == "/gnmi.gNMI/Set" && e.Kind == KindSend {
+			gotReq = new(gpb.SetRequest)
+			if err := proto.Unmarshal(e.Message, gotReq); err != nil {
+				t.Fatalf("Unmarshal() of recorded request got error: %v", err)
+			}
+		}
+	}
+	if got, want := string(gotReq.GetUpdate()[0].GetVal().GetJsonIetfVal()), `"`+secrets.Redacted+`"`; got != want {
+		t.Errorf("Recorded request got value %s, want %s", got, want)
+	}
+	if got := req.GetUpdate()[0].GetVal().GetJsonIetfVal(); !strings.Contains(string(got), secret) {
+		t.Errorf("Recording modified the sent request to %s", got)
+	}
+}
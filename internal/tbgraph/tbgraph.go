@@ -0,0 +1,367 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tbgraph translates Ondatra testbeds to abstract port graphs, for
+// bindings that solve testbeds against their topologies with portgraph.Solve.
+//
+// The nodes and ports of the concrete graph of a topology must have the
+// attributes defined below for the constraints of the abstract graph to match
+// them.
+package tbgraph
+
+import (
+	"fmt"
+	"regexp"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/binding/portgraph"
+	"github.com/openconfig/ondatra/internal/orderedmap"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// Roles of devices, as the values of the role attribute.
+const (
+	RoleDUT = "DUT"
+	RoleATE = "ATE"
+)
+
+const (
+	// Attribute names mapping message fields to graph attributes/constraints.
+	VendorAttr = "vendor"
+	RoleAttr   = "role"
+	HWAttr     = "hardware_model"
+	SWAttr     = "software_version"
+	SpeedAttr  = "speed"
+	CardAttr   = "card_model"
+	PMDAttr    = "pmd"
+	GroupAttr  = "group"
+	NameAttr   = "name"
+
+	// BreakoutGroupAttr maps the breakout group of a port to a graph
+	// attribute/constraint. Ports that are not breakout channels must not have
+	// the attribute, so that they do not satisfy a breakout group.
+	BreakoutGroupAttr = "breakout_group"
+
+	// Attribute names mapping device capabilities to graph attributes/constraints.
+	GribiAttr    = "gribi"
+	P4RTAttr     = "p4rt"
+	BreakoutAttr = "breakout"
+	ATEAPIAttr   = "ate_api"
+)
+
+var (
+	reAny = portgraph.Regex(regexp.MustCompile(".*"))
+)
+
+// Abstract translates an Ondatra testbed to an AbstractGraph for solving, and
+// returns the maps from the nodes and ports of the graph to the devices and
+// ports of the testbed. The partial map constrains the names of the devices
+// and ports, as in binding.Binding.Reserve. The description of the graph is
+// used in errors from portgraph.Solve.
+func Abstract(tb *opb.Testbed, partial map[string]string, desc string) (*portgraph.AbstractGraph, map[*portgraph.AbstractNode]*opb.Device, map[*portgraph.AbstractPort]*opb.Port, error) {
+	var nodes []*portgraph.AbstractNode
+	var edges []*portgraph.AbstractEdge
+	name2Port := make(map[string]*portgraph.AbstractPort) // Name of the port to the AbstractPort.
+	node2Dev := make(map[*portgraph.AbstractNode]*opb.Device)
+	port2Port := make(map[*portgraph.AbstractPort]*opb.Port)
+
+	// addDevice creates an AbstractNode from a Device.
+	// If the field is empty, there is not a Constraint on that field.
+	addDevice := func(dev *opb.Device, isATE bool) {
+		nodeConstraints := make(map[string]portgraph.NodeConstraint)
+		if isATE {
+			nodeConstraints[RoleAttr] = portgraph.Equal(RoleATE)
+		} else {
+			nodeConstraints[RoleAttr] = portgraph.Equal(RoleDUT)
+		}
+		if v := dev.GetVendor(); v != opb.Device_VENDOR_UNSPECIFIED {
+			nodeConstraints[VendorAttr] = portgraph.Equal(v.String())
+		}
+		if hw, ok := modelConstraint(dev); ok {
+			nodeConstraints[HWAttr] = hw
+		}
+		if sw, ok := versionConstraint(dev); ok {
+			nodeConstraints[SWAttr] = sw
+		}
+		if name, ok := partial[dev.GetId()]; ok {
+			nodeConstraints[NameAttr] = portgraph.Equal(name)
+		}
+		for k, v := range dev.GetExtraDimensions() {
+			nodeConstraints[k] = portgraph.Equal(v)
+		}
+		for k, v := range capsConstraints(dev.GetCapabilities()) {
+			nodeConstraints[k] = v
+		}
+		// Process each port on the device.
+		var ports []*portgraph.AbstractPort
+		group2PortNames := orderedmap.NewOrderedMap[string, []string]()
+		breakout2Ports := orderedmap.NewOrderedMap[string, []*portgraph.AbstractPort]()
+		absPortName2DevPort := make(map[string]*opb.Port)
+		for _, port := range dev.GetPorts() {
+			portConstraints := make(map[string]portgraph.PortConstraint)
+			if s := port.GetSpeed(); s != opb.Port_SPEED_UNSPECIFIED {
+				portConstraints[SpeedAttr] = portgraph.Equal(s.String())
+			}
+			if cm, ok := cardModelConstraint(port); ok {
+				portConstraints[CardAttr] = cm
+			}
+			if pmd, ok := pmdConstraint(port); ok {
+				portConstraints[PMDAttr] = pmd
+			}
+			portName := fmt.Sprintf("%s:%s", dev.GetId(), port.GetId())
+			if name, ok := partial[portName]; ok {
+				portConstraints[NameAttr] = portgraph.Equal(name)
+			}
+			// Create the AbstractPort, but do not add group constraints until all ports are processed.
+			p := &portgraph.AbstractPort{Desc: portName, Constraints: portConstraints}
+			name2Port[portName] = p
+			if bg := port.GetBreakoutGroup(); bg != "" {
+				bps, _ := breakout2Ports.Get(bg)
+				breakout2Ports.Set(bg, append(bps, p))
+			}
+
+			// Check if the port has a group. If it does, additional constraint(s) need to be added.
+			if port.GetGroup() != "" {
+				pns, _ := group2PortNames.Get(port.GetGroup())
+				group2PortNames.Set(port.GetGroup(), append(pns, portName))
+				absPortName2DevPort[portName] = port
+			} else { // This port is not part of a group; no further processing.
+				port2Port[p] = port
+				ports = append(ports, p)
+			}
+		}
+		processedGroups := map[string]struct{}{}
+		groups := group2PortNames.Keys()
+		for _, group := range groups {
+			portNames, _ := group2PortNames.Get(group)
+			processedGroups[group] = struct{}{}
+			var constraints []portgraph.LeafPortConstraint
+			pn := portNames[0]
+			for _, pn2 := range portNames[1:] {
+				p2 := name2Port[pn2]
+				constraints = append(constraints, portgraph.SameAsPort(p2))
+				name2Port[pn2].Constraints[GroupAttr] = reAny
+				port2Port[p2] = absPortName2DevPort[pn2]
+				ports = append(ports, p2)
+			}
+			for _, g2 := range groups {
+				if _, ok := processedGroups[g2]; !ok {
+					p, _ := group2PortNames.Get(g2)
+					constraints = append(constraints, portgraph.NotSameAsPort(name2Port[p[0]]))
+				} else if len(constraints) == 0 {
+					constraints = append(constraints, reAny)
+				}
+			}
+			p := name2Port[pn]
+			if len(constraints) == 1 {
+				p.Constraints[GroupAttr] = constraints[0]
+			} else {
+				p.Constraints[GroupAttr] = portgraph.AndPort(constraints...)
+			}
+			name2Port[pn] = p
+			ports = append(ports, p)
+			port2Port[p] = absPortName2DevPort[pn]
+		}
+		var breakouts [][]*portgraph.AbstractPort
+		for _, bg := range breakout2Ports.Keys() {
+			bps, _ := breakout2Ports.Get(bg)
+			breakouts = append(breakouts, bps)
+		}
+		addBreakoutConstraints(breakouts)
+		ports = append(ports, portCountPorts(dev)...)
+		n := &portgraph.AbstractNode{Desc: dev.GetId(), Constraints: nodeConstraints, Ports: ports}
+		node2Dev[n] = dev
+		nodes = append(nodes, n)
+	}
+
+	for _, dev := range tb.GetDuts() {
+		addDevice(dev, false)
+	}
+	for _, dev := range tb.GetAtes() {
+		addDevice(dev, true)
+	}
+	for _, link := range tb.GetLinks() {
+		src, ok := name2Port[link.GetA()]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("port %q in links not present in specified testbed", link.GetA())
+		}
+		dst, ok := name2Port[link.GetB()]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("port %q in links not present in specified testbed", link.GetB())
+		}
+		edges = append(edges, &portgraph.AbstractEdge{Src: src, Dst: dst})
+	}
+	return &portgraph.AbstractGraph{Desc: desc, Nodes: nodes, Edges: edges}, node2Dev, port2Port, nil
+}
+
+// CapsAttrs returns the node attributes of a device with the capabilities.
+// Port counts are not included, because they are matched by the ports of the
+// concrete graph.
+func CapsAttrs(caps *opb.Capabilities) map[string]string {
+	attrs := make(map[string]string)
+	if caps.GetGribi() {
+		attrs[GribiAttr] = "true"
+	}
+	if caps.GetP4Rt() {
+		attrs[P4RTAttr] = "true"
+	}
+	if caps.GetBreakout() {
+		attrs[BreakoutAttr] = "true"
+	}
+	if api := caps.GetAteApi(); api != opb.Capabilities_ATE_API_UNSPECIFIED {
+		attrs[ATEAPIAttr] = api.String()
+	}
+	return attrs
+}
+
+// capsConstraints returns the node constraints that require the capabilities.
+func capsConstraints(caps *opb.Capabilities) map[string]portgraph.NodeConstraint {
+	cs := make(map[string]portgraph.NodeConstraint)
+	if caps.GetGribi() {
+		cs[GribiAttr] = portgraph.Equal("true")
+	}
+	if caps.GetP4Rt() {
+		cs[P4RTAttr] = portgraph.Equal("true")
+	}
+	if caps.GetBreakout() {
+		cs[BreakoutAttr] = portgraph.Equal("true")
+	}
+	if api := caps.GetAteApi(); api != opb.Capabilities_ATE_API_UNSPECIFIED {
+		cs[ATEAPIAttr] = portgraph.Equal(api.String())
+	}
+	return cs
+}
+
+// portCountPorts returns the additional ports that a device requires to
+// satisfy its required port counts, beyond the ports listed in the testbed.
+// The additional ports are not linked and are not part of the reservation.
+func portCountPorts(dev *opb.Device) []*portgraph.AbstractPort {
+	var ports []*portgraph.AbstractPort
+	for _, pc := range dev.GetCapabilities().GetPortCounts() {
+		extra := int(pc.GetCount())
+		for _, p := range dev.GetPorts() {
+			if pc.GetSpeed() == opb.Port_SPEED_UNSPECIFIED || p.GetSpeed() == pc.GetSpeed() {
+				extra--
+			}
+		}
+		for i := 0; i < extra; i++ {
+			constraints := make(map[string]portgraph.PortConstraint)
+			if s := pc.GetSpeed(); s != opb.Port_SPEED_UNSPECIFIED {
+				constraints[SpeedAttr] = portgraph.Equal(s.String())
+			}
+			ports = append(ports, &portgraph.AbstractPort{
+				Desc:        fmt.Sprintf("%s:%v_%d", dev.GetId(), pc.GetSpeed(), i),
+				Constraints: constraints,
+			})
+		}
+	}
+	return ports
+}
+
+func modelConstraint(d *opb.Device) (portgraph.NodeConstraint, bool) {
+	switch v := d.GetHardwareModelValue().(type) {
+	case nil:
+		// handled after switch
+	case *opb.Device_HardwareModel:
+		if v.HardwareModel != "" {
+			return portgraph.Equal(v.HardwareModel), true
+		}
+	case *opb.Device_HardwareModelRegex:
+		if v.HardwareModelRegex != "" {
+			return portgraph.Regex(regexp.MustCompile(v.HardwareModelRegex)), true
+		}
+	default:
+		log.Fatalf("unknown hardware model type: %v(%T)", v, v)
+	}
+	return nil, false
+}
+
+func versionConstraint(d *opb.Device) (portgraph.NodeConstraint, bool) {
+	switch v := d.GetSoftwareVersionValue().(type) {
+	case nil:
+		// handled after switch
+	case *opb.Device_SoftwareVersion:
+		if v.SoftwareVersion != "" {
+			return portgraph.Equal(v.SoftwareVersion), true
+		}
+	case *opb.Device_SoftwareVersionRegex:
+		if v.SoftwareVersionRegex != "" {
+			return portgraph.Regex(regexp.MustCompile(v.SoftwareVersionRegex)), true
+		}
+	default:
+		log.Fatalf("unknown software version type: %v(%T)", v, v)
+	}
+	return nil, false
+}
+
+func cardModelConstraint(p *opb.Port) (portgraph.PortConstraint, bool) {
+	switch v := p.GetCardModelValue().(type) {
+	case nil:
+		// handled after switch
+	case *opb.Port_CardModel:
+		if v.CardModel != "" {
+			return portgraph.Equal(v.CardModel), true
+		}
+	case *opb.Port_CardModelRegex:
+		if v.CardModelRegex != "" {
+			return portgraph.Regex(regexp.MustCompile(v.CardModelRegex)), true
+		}
+	default:
+		log.Fatalf("unknown card model type: %v(%T)", v, v)
+	}
+	return nil, false
+}
+
+// addBreakoutConstraints constrains the ports of each breakout group to be
+// channels of the same physical port, and the ports of different breakout
+// groups to be channels of different physical ports.
+func addBreakoutConstraints(groups [][]*portgraph.AbstractPort) {
+	for i, ports := range groups {
+		var constraints []portgraph.LeafPortConstraint
+		for _, p := range ports[1:] {
+			constraints = append(constraints, portgraph.SameAsPort(p))
+			p.Constraints[BreakoutGroupAttr] = reAny
+		}
+		for _, other := range groups[i+1:] {
+			constraints = append(constraints, portgraph.NotSameAsPort(other[0]))
+		}
+		switch len(constraints) {
+		case 0:
+			ports[0].Constraints[BreakoutGroupAttr] = reAny
+		case 1:
+			ports[0].Constraints[BreakoutGroupAttr] = constraints[0]
+		default:
+			ports[0].Constraints[BreakoutGroupAttr] = portgraph.AndPort(constraints...)
+		}
+	}
+}
+
+func pmdConstraint(p *opb.Port) (portgraph.PortConstraint, bool) {
+	switch v := p.GetPmdValue().(type) {
+	case nil:
+		// handled after switch
+	case *opb.Port_Pmd_:
+		if v.Pmd != opb.Port_PMD_UNSPECIFIED {
+			return portgraph.Equal(v.Pmd.String()), true
+		}
+	case *opb.Port_PmdRegex:
+		if v.PmdRegex != "" {
+			return portgraph.Regex(regexp.MustCompile(v.PmdRegex)), true
+		}
+	default:
+		log.Fatalf("unknown PMD type: %v(%T)", v, v)
+	}
+	return nil, false
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tbgraph
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding/portgraph"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+func TestCapsAttrs(t *testing.T) {
+	caps := &opb.Capabilities{Gribi: true, Breakout: true, AteApi: opb.Capabilities_OTG}
+	want := map[string]string{GribiAttr: "true", BreakoutAttr: "true", ATEAPIAttr: "OTG"}
+	if diff := cmp.Diff(want, CapsAttrs(caps)); diff != "" {
+		t.Errorf("CapsAttrs() got unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestAbstractSolve(t *testing.T) {
+	tb := &opb.Testbed{
+		Duts: []*opb.Device{{
+			Id:           "dut",
+			Vendor:       opb.Device_ARISTA,
+			Capabilities: &opb.Capabilities{P4Rt: true},
+			Ports:        []*opb.Port{{Id: "port1", Speed: opb.Port_S_100GB}},
+		}},
+	}
+	absGraph, node2Dev, _, err := Abstract(tb, nil, "test testbed")
+	if err != nil {
+		t.Fatalf("Abstract() got unexpected error: %v", err)
+	}
+	node := func(name string, attrs map[string]string) *portgraph.ConcreteNode {
+		return &portgraph.ConcreteNode{
+			Desc:  name,
+			Attrs: attrs,
+			Ports: []*portgraph.ConcretePort{{
+				Desc:  name + ":p1",
+				Attrs: map[string]string{SpeedAttr: opb.Port_S_100GB.String()},
+			}},
+		}
+	}
+	withP4RT := map[string]string{RoleAttr: RoleDUT, VendorAttr: "ARISTA"}
+	for k, v := range CapsAttrs(&opb.Capabilities{P4Rt: true}) {
+		withP4RT[k] = v
+	}
+	conGraph := &portgraph.ConcreteGraph{Nodes: []*portgraph.ConcreteNode{
+		node("noP4RT", map[string]string{RoleAttr: RoleDUT, VendorAttr: "ARISTA"}),
+		node("p4rt", withP4RT),
+	}}
+	a, err := portgraph.Solve(context.Background(), absGraph, conGraph)
+	if err != nil {
+		t.Fatalf("Solve() got unexpected error: %v", err)
+	}
+	for absNode, conNode := range a.Node2Node {
+		if got, want := conNode.Desc, "p4rt"; got != want {
+			t.Errorf("Solve() assigned device %q to node %q, want %q", node2Dev[absNode].GetId(), got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testbed
+
+import (
+	"fmt"
+
+	"github.com/openconfig/ondatra/binding"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// validateBreakouts checks that the ports of each breakout group of a testbed
+// device do not require different speeds, because the channels of a broken-out
+// physical port all have the same speed.
+func validateBreakouts(dev *opb.Device) error {
+	group2Port := make(map[string]*opb.Port)
+	for _, p := range dev.GetPorts() {
+		group, speed := p.GetBreakoutGroup(), p.GetSpeed()
+		if group == "" || speed == opb.Port_SPEED_UNSPECIFIED {
+			continue
+		}
+		if prev, ok := group2Port[group]; ok && prev.GetSpeed() != speed {
+			return fmt.Errorf("ports %q and %q in breakout group %q of device %q require different speeds %v and %v",
+				prev.GetId(), p.GetId(), group, dev.GetId(), prev.GetSpeed(), speed)
+		}
+		group2Port[group] = p
+	}
+	return nil
+}
+
+// checkBreakouts checks that the reserved ports of a device are channels of
+// the same physical port if and only if they are in the same breakout group.
+func checkBreakouts(dev *opb.Device, rd binding.Device) error {
+	tb2Res := make(map[string]string)
+	res2TB := make(map[string]string)
+	for _, p := range dev.GetPorts() {
+		group := p.GetBreakoutGroup()
+		if group == "" {
+			continue
+		}
+		rp, err := Port(rd, p.GetId())
+		if err != nil {
+			return err
+		}
+		if rp.BreakoutGroup == "" {
+			return fmt.Errorf("reserved port %q is not a breakout channel, but port %q is in breakout group %q", rp.Name, p.GetId(), group)
+		}
+		if rg, ok := tb2Res[group]; ok && rg != rp.BreakoutGroup {
+			return fmt.Errorf("reserved ports of breakout group %q are channels of different physical ports %q and %q", group, rg, rp.BreakoutGroup)
+		}
+		if tg, ok := res2TB[rp.BreakoutGroup]; ok && tg != group {
+			return fmt.Errorf("reserved ports of breakout groups %q and %q are channels of the same physical port %q", tg, group, rp.BreakoutGroup)
+		}
+		tb2Res[group] = rp.BreakoutGroup
+		res2TB[rp.BreakoutGroup] = group
+	}
+	return nil
+}
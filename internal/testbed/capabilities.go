@@ -0,0 +1,77 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testbed
+
+import (
+	"fmt"
+
+	"github.com/openconfig/ondatra/binding"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// validateCaps checks that the capability requirements of a testbed device
+// are well-formed.
+func validateCaps(dev *opb.Device, isATE bool) error {
+	caps := dev.GetCapabilities()
+	if caps.GetAteApi() != opb.Capabilities_ATE_API_UNSPECIFIED && !isATE {
+		return fmt.Errorf("ATE API required of DUT %q", dev.GetId())
+	}
+	speeds := make(map[opb.Port_Speed]bool)
+	for _, pc := range caps.GetPortCounts() {
+		if pc.GetCount() <= 0 {
+			return fmt.Errorf("non-positive count %d of %v ports required of device %q", pc.GetCount(), pc.GetSpeed(), dev.GetId())
+		}
+		if speeds[pc.GetSpeed()] {
+			return fmt.Errorf("multiple counts of %v ports required of device %q", pc.GetSpeed(), dev.GetId())
+		}
+		speeds[pc.GetSpeed()] = true
+	}
+	return nil
+}
+
+// checkCaps checks that a reserved device has the capabilities required of
+// it by the testbed, if the binding reports the capabilities of the device.
+func checkCaps(dev *opb.Device, rd binding.Device) error {
+	want, got := dev.GetCapabilities(), rd.Capabilities()
+	if want == nil || got == nil {
+		return nil
+	}
+	if want.GetGribi() && !got.GetGribi() {
+		return fmt.Errorf("reserved device %q does not support gRIBI", rd.Name())
+	}
+	if want.GetP4Rt() && !got.GetP4Rt() {
+		return fmt.Errorf("reserved device %q does not support P4RT", rd.Name())
+	}
+	if want.GetBreakout() && !got.GetBreakout() {
+		return fmt.Errorf("reserved device %q does not support port breakout", rd.Name())
+	}
+	if api := want.GetAteApi(); api != opb.Capabilities_ATE_API_UNSPECIFIED && api != got.GetAteApi() {
+		return fmt.Errorf("reserved device %q has ATE API %v, want %v", rd.Name(), got.GetAteApi(), api)
+	}
+	counts := make(map[opb.Port_Speed]int32)
+	for _, pc := range got.GetPortCounts() {
+		counts[pc.GetSpeed()] += pc.GetCount()
+		if pc.GetSpeed() != opb.Port_SPEED_UNSPECIFIED {
+			counts[opb.Port_SPEED_UNSPECIFIED] += pc.GetCount()
+		}
+	}
+	for _, pc := range want.GetPortCounts() {
+		if n := counts[pc.GetSpeed()]; n < pc.GetCount() {
+			return fmt.Errorf("reserved device %q has %d %v ports, want at least %d", rd.Name(), n, pc.GetSpeed(), pc.GetCount())
+		}
+	}
+	return nil
+}
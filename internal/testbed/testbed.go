@@ -21,6 +21,7 @@ import (
 	"os"
 	"regexp"
 	"sync"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -32,49 +33,166 @@ import (
 )
 
 var (
-	resMu   sync.RWMutex
+	resMu sync.RWMutex
+	// shards holds one entry per binding; the first is the primary shard,
+	// which serves every test that has not acquired a shard of its own.
+	shards = []*shard{{}}
+	// free holds the indices of the shards not acquired by any test.
+	free chan int
+	// acquired maps the name of a top-level test to its acquired shard.
+	acquired map[string]int
+)
+
+// shard is a binding and the testbed reserved with it.
+type shard struct {
+	bind    binding.Binding
+	path    string
+	tb      *opb.Testbed
 	res     *binding.Reservation
 	fetched bool
-	bind    binding.Binding
-)
+}
 
 // SetBinding sets the Ondatra binding.
 func SetBinding(b binding.Binding) {
-	bind = b
+	SetBindings(b)
+}
+
+// SetBindings sets the Ondatra bindings, one per testbed to reserve.
+// The bindings must be distinct instances, because each holds a reservation.
+func SetBindings(bs ...binding.Binding) {
+	resMu.Lock()
+	defer resMu.Unlock()
+	shards = nil
+	for _, b := range bs {
+		shards = append(shards, &shard{bind: b})
+	}
+	free, acquired = nil, nil
 }
 
 // SetReservationForTesting sets Ondatra to a state in which the specified
 // reservation has been reserved. A nil reservation argument sets Ondatra to
 // an unreserved state. This is only called by fakebind for testing purposes.
 func SetReservationForTesting(r *binding.Reservation) {
-	res = r
-	fetched = false
+	resMu.Lock()
+	defer resMu.Unlock()
+	sh := &shard{res: r}
+	if len(shards) > 0 {
+		sh.bind = shards[0].bind
+	}
+	shards = []*shard{sh}
+	free, acquired = nil, nil
+	if r != nil {
+		free = make(chan int, 1)
+		free <- 0
+		acquired = make(map[string]int)
+	}
 }
 
-// Reservation returns the current reservation.
+// Reservation returns the current reservation of the primary testbed.
 func Reservation() (*binding.Reservation, error) {
 	resMu.RLock()
 	defer resMu.RUnlock()
-	if res == nil {
+	return reservationLocked(0)
+}
+
+func reservationLocked(i int) (*binding.Reservation, error) {
+	if shards[i].res == nil {
 		return nil, errors.New("testbed is not reserved; Did you forget to call ondatra.RunTests in TestMain?")
 	}
-	return res, nil
+	return shards[i].res, nil
+}
+
+// Reservations returns the current reservations of all the testbeds, in the
+// order of their bindings.
+func Reservations() ([]*binding.Reservation, error) {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	var rs []*binding.Reservation
+	for i := range shards {
+		r, err := reservationLocked(i)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// TestbedPaths returns the paths of the reserved testbeds, in the order of
+// their bindings.
+func TestbedPaths() []string {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	var paths []string
+	for _, sh := range shards {
+		paths = append(paths, sh.path)
+	}
+	return paths
 }
 
-// Reserve reserves the testbed.
+// Reserve reserves a testbed with each binding. If the flags specify a single
+// testbed, every binding reserves that testbed; otherwise the i-th binding
+// reserves the i-th testbed. The testbeds are reserved concurrently, and if
+// any reservation fails, the successful ones are released.
 func Reserve(ctx context.Context, fv *flags.Values) error {
 	resMu.Lock()
 	defer resMu.Unlock()
-	if res != nil {
+	if shards[0].res != nil {
 		return errors.New("testbed is already reserved; Did you call ondatra.RunTests multiple times?")
 	}
+	paths := fv.TestbedPaths
+	if len(paths) == 0 {
+		paths = []string{fv.TestbedPath}
+	}
+	if len(paths) == 1 {
+		for len(paths) < len(shards) {
+			paths = append(paths, paths[0])
+		}
+	}
+	if len(paths) != len(shards) {
+		return fmt.Errorf("got %d testbeds for %d bindings", len(paths), len(shards))
+	}
+	if fv.ResvID != "" && len(shards) > 1 {
+		return errors.New("cannot fetch a reservation by ID with multiple testbeds")
+	}
+
+	errs := make([]error, len(shards))
+	var wg sync.WaitGroup
+	for i, sh := range shards {
+		wg.Add(1)
+		go func(i int, sh *shard) {
+			defer wg.Done()
+			sh.path = paths[i]
+			errs[i] = sh.reserve(ctx, fv)
+		}(i, sh)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		for _, sh := range shards {
+			if sh.res != nil {
+				if rerr := sh.release(ctx); rerr != nil {
+					err = errors.Join(err, rerr)
+				}
+			}
+		}
+		return err
+	}
+	free = make(chan int, len(shards))
+	for i := range shards {
+		free <- i
+	}
+	acquired = make(map[string]int)
+	return nil
+}
+
+func (sh *shard) reserve(ctx context.Context, fv *flags.Values) error {
 	tb := &opb.Testbed{}
-	s, err := os.ReadFile(fv.TestbedPath)
+	s, err := os.ReadFile(sh.path)
 	if err != nil {
-		return fmt.Errorf("failed to read testbed proto %s: %w", fv.TestbedPath, err)
+		return fmt.Errorf("failed to read testbed proto %s: %w", sh.path, err)
 	}
 	if err := prototext.Unmarshal(s, tb); err != nil {
-		return fmt.Errorf("failed to parse testbed proto %s: %w", fv.TestbedPath, err)
+		return fmt.Errorf("failed to parse testbed proto %s: %w", sh.path, err)
 	}
 	if err := validateTB(tb); err != nil {
 		return err
@@ -82,10 +200,10 @@ func Reserve(ctx context.Context, fv *flags.Values) error {
 
 	var r *binding.Reservation
 	if fv.ResvID == "" {
-		r, err = bind.Reserve(ctx, tb, fv.RunTime, fv.WaitTime, fv.ResvPartial)
+		r, err = sh.bind.Reserve(ctx, tb, fv.RunTime, fv.WaitTime, fv.ResvPartial)
 	} else {
-		r, err = bind.FetchReservation(ctx, fv.ResvID)
-		fetched = true
+		r, err = sh.bind.FetchReservation(ctx, fv.ResvID)
+		sh.fetched = true
 	}
 	if err != nil {
 		return err
@@ -93,10 +211,63 @@ func Reserve(ctx context.Context, fv *flags.Values) error {
 	if err := validateRes(tb, r); err != nil {
 		return err
 	}
-	res = r
+	sh.tb, sh.res = tb, r
 	return nil
 }
 
+func (sh *shard) release(ctx context.Context) error {
+	if sh.res == nil || sh.fetched {
+		return nil
+	}
+	sh.tb, sh.res = nil, nil
+	return sh.bind.Release(ctx)
+}
+
+// Acquire waits until a testbed is not in use by any other test that called
+// Acquire, and then assigns it to the top-level test with the specified name.
+// Returns a function that frees the testbed once the test is done with it.
+func Acquire(ctx context.Context, test string) (func(), error) {
+	resMu.RLock()
+	ch := free
+	resMu.RUnlock()
+	if ch == nil {
+		return nil, errors.New("testbed is not reserved; Did you forget to call ondatra.RunTests in TestMain?")
+	}
+	var i int
+	select {
+	case i = <-ch:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	resMu.Lock()
+	acquired[test] = i
+	resMu.Unlock()
+	return func() {
+		resMu.Lock()
+		delete(acquired, test)
+		resMu.Unlock()
+		ch <- i
+	}, nil
+}
+
+// ReservationFor returns the reservation of the testbed assigned to the
+// top-level test with the specified name, or of the primary testbed if no
+// testbed is assigned to the test.
+func ReservationFor(test string) (*binding.Reservation, error) {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	return reservationLocked(acquired[test])
+}
+
+// TestbedPathFor returns the path of the testbed assigned to the top-level
+// test with the specified name, or of the primary testbed if no testbed is
+// assigned to the test.
+func TestbedPathFor(test string) string {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	return shards[acquired[test]].path
+}
+
 // portMap registers which ports are connected to which other ports, in the format "<device-id>:<port-id>".
 // Non-connected ports map to "", which allows to check for validity of port IDs in links.
 // Each pair of connected ports A and B must be in the map twice: port A's ID mapping to port B's ID and port B's ID mapping to port A's ID.
@@ -104,10 +275,16 @@ type portMap map[string]string
 
 func validateTB(tb *opb.Testbed) error {
 	pm := make(portMap)
-	for _, d := range append(tb.GetDuts(), tb.GetAtes()...) {
+	for i, d := range append(tb.GetDuts(), tb.GetAtes()...) {
 		if err := checkID(d.GetId()); err != nil {
 			return err
 		}
+		if err := validateCaps(d, i >= len(tb.GetDuts())); err != nil {
+			return err
+		}
+		if err := validateBreakouts(d); err != nil {
+			return err
+		}
 		for _, p := range d.GetPorts() {
 			if err := checkID(p.GetId()); err != nil {
 				return err
@@ -177,6 +354,9 @@ func validateDevice(dev *opb.Device, rd binding.Device) error {
 	if rd.Name() == "" {
 		return fmt.Errorf("no name for reserved device: %v", rd)
 	}
+	if err := checkCaps(dev, rd); err != nil {
+		return err
+	}
 	for _, p := range dev.GetPorts() {
 		rp, err := Port(rd, p.GetId())
 		if err != nil {
@@ -186,19 +366,102 @@ func validateDevice(dev *opb.Device, rd binding.Device) error {
 			return fmt.Errorf("no name for reserved port: %v", rp)
 		}
 	}
-	return nil
+	return checkBreakouts(dev, rd)
 }
 
-// Release releases the testbed. This is a noop if the reservation is not
-// currently reserved or if the reservation was fetched and not created.
+// Release releases all the testbeds. This is a noop for the testbeds that
+// are not currently reserved or whose reservations were fetched and not
+// created.
 func Release(ctx context.Context) error {
 	resMu.Lock()
 	defer resMu.Unlock()
-	if res == nil || fetched {
-		return nil
+	return forEachShard(func(sh *shard) error {
+		return sh.release(ctx)
+	})
+}
+
+// forEachShard concurrently calls fn for each shard and joins the errors.
+func forEachShard(fn func(*shard) error) error {
+	errs := make([]error, len(shards))
+	var wg sync.WaitGroup
+	for i, sh := range shards {
+		wg.Add(1)
+		go func(i int, sh *shard) {
+			defer wg.Done()
+			errs[i] = fn(sh)
+		}(i, sh)
 	}
-	res = nil
-	return bind.Release(ctx)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// CanRenew reports whether the bindings support renewing reservations.
+func CanRenew() bool {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	for _, sh := range shards {
+		if _, ok := sh.bind.(binding.Renewer); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckExtend returns an error if the current reservations cannot be extended
+// for the specified duration, because they are not held, a binding does not
+// support renewing them, or the duration is not positive.
+func CheckExtend(d time.Duration) error {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	return checkExtendLocked(d)
+}
+
+func checkExtendLocked(d time.Duration) error {
+	if err := checkRenewable(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return fmt.Errorf("extension duration must be positive: %v", d)
+	}
+	return nil
+}
+
+// Extend extends all the current reservations for at least the specified
+// duration.
+func Extend(ctx context.Context, d time.Duration) error {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	if err := checkExtendLocked(d); err != nil {
+		return err
+	}
+	return forEachShard(func(sh *shard) error {
+		return sh.bind.(binding.Renewer).Extend(ctx, d)
+	})
+}
+
+// Heartbeat notifies the bindings that all the current reservations are
+// still in use.
+func Heartbeat(ctx context.Context) error {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	if err := checkRenewable(); err != nil {
+		return err
+	}
+	return forEachShard(func(sh *shard) error {
+		return sh.bind.(binding.Renewer).Heartbeat(ctx)
+	})
+}
+
+func checkRenewable() error {
+	for _, sh := range shards {
+		if sh.res == nil {
+			return errors.New("testbed is not reserved")
+		}
+		if _, ok := sh.bind.(binding.Renewer); !ok {
+			return fmt.Errorf("binding %T does not support renewing reservations", sh.bind)
+		}
+	}
+	return nil
 }
 
 // Device returns the Device in the specified reservation with the specified ID.
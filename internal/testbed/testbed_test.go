@@ -15,14 +15,17 @@
 package testbed_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"golang.org/x/net/context"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/openconfig/ondatra/binding"
 	"github.com/openconfig/ondatra/fakebind"
 	"github.com/openconfig/ondatra/internal/flags"
@@ -188,6 +191,73 @@ func TestReserveBadTestbeds(t *testing.T) {
 			&binding.Dims{Vendor: opb.Device_ARISTA, HardwareModel: "m", SoftwareVersion: "v"},
 		}}},
 		wantErr: "no name",
+	}, {
+		name:    "ATE API required of DUT",
+		tbProto: `duts{id:"dut" capabilities{ate_api:OTG}}`,
+		wantErr: "ATE API required of DUT",
+	}, {
+		name:    "Non-positive port count",
+		tbProto: `duts{id:"dut" capabilities{port_counts{speed:S_400GB}}}`,
+		wantErr: "non-positive count",
+	}, {
+		name:    "Duplicate port count",
+		tbProto: `duts{id:"dut" capabilities{port_counts{speed:S_400GB count:1} port_counts{speed:S_400GB count:2}}}`,
+		wantErr: "multiple counts",
+	}, {
+		name:    "Missing gRIBI",
+		tbProto: `duts{id:"dut" capabilities{gribi:true}}`,
+		res: &binding.Reservation{DUTs: map[string]binding.DUT{"dut": &binding.AbstractDUT{
+			&binding.Dims{Name: "d1", Capabilities: &opb.Capabilities{P4Rt: true}},
+		}}},
+		wantErr: "does not support gRIBI",
+	}, {
+		name:    "Wrong ATE API",
+		tbProto: `ates{id:"ate" capabilities{ate_api:IXNETWORK}}`,
+		res: &binding.Reservation{ATEs: map[string]binding.ATE{"ate": &binding.AbstractATE{
+			&binding.Dims{Name: "a1", Capabilities: &opb.Capabilities{AteApi: opb.Capabilities_OTG}},
+		}}},
+		wantErr: "has ATE API OTG, want IXNETWORK",
+	}, {
+		name:    "Too few ports",
+		tbProto: `duts{id:"dut" capabilities{port_counts{count:3}}}`,
+		res: &binding.Reservation{DUTs: map[string]binding.DUT{"dut": &binding.AbstractDUT{
+			&binding.Dims{Name: "d1", Capabilities: &opb.Capabilities{PortCounts: []*opb.Capabilities_PortCount{
+				{Speed: opb.Port_S_100GB, Count: 1},
+				{Speed: opb.Port_S_400GB, Count: 1},
+			}}},
+		}}},
+		wantErr: "has 2 SPEED_UNSPECIFIED ports, want at least 3",
+	}, {
+		name:    "Breakout group speed mismatch",
+		tbProto: `duts{id:"dut" ports{id:"port1" speed:S_100GB breakout_group:"bg"} ports{id:"port2" speed:S_200GB breakout_group:"bg"}}`,
+		wantErr: "require different speeds",
+	}, {
+		name:    "Port not a breakout channel",
+		tbProto: `duts{id:"dut" ports{id:"port1" breakout_group:"bg"}}`,
+		res: &binding.Reservation{DUTs: map[string]binding.DUT{"dut": &binding.AbstractDUT{
+			&binding.Dims{Name: "d1", Ports: map[string]*binding.Port{"port1": {Name: "p1"}}},
+		}}},
+		wantErr: "not a breakout channel",
+	}, {
+		name:    "Breakout group split",
+		tbProto: `duts{id:"dut" ports{id:"port1" breakout_group:"bg"} ports{id:"port2" breakout_group:"bg"}}`,
+		res: &binding.Reservation{DUTs: map[string]binding.DUT{"dut": &binding.AbstractDUT{
+			&binding.Dims{Name: "d1", Ports: map[string]*binding.Port{
+				"port1": {Name: "p1/1", BreakoutGroup: "p1"},
+				"port2": {Name: "p2/1", BreakoutGroup: "p2"},
+			}},
+		}}},
+		wantErr: "different physical ports",
+	}, {
+		name:    "Breakout groups merged",
+		tbProto: `duts{id:"dut" ports{id:"port1" breakout_group:"bg1"} ports{id:"port2" breakout_group:"bg2"}}`,
+		res: &binding.Reservation{DUTs: map[string]binding.DUT{"dut": &binding.AbstractDUT{
+			&binding.Dims{Name: "d1", Ports: map[string]*binding.Port{
+				"port1": {Name: "p1/1", BreakoutGroup: "p1"},
+				"port2": {Name: "p1/2", BreakoutGroup: "p1"},
+			}},
+		}}},
+		wantErr: "same physical port",
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -203,6 +273,186 @@ func TestReserveBadTestbeds(t *testing.T) {
 	}
 }
 
+func TestReserveCapabilities(t *testing.T) {
+	tbPath := writeTestbedFile(t, t.TempDir(), `duts{id:"dut" capabilities{gribi:true port_counts{speed:S_400GB count:2}}}`)
+	tests := []struct {
+		desc string
+		caps *opb.Capabilities
+	}{{
+		desc: "satisfied",
+		caps: &opb.Capabilities{Gribi: true, P4Rt: true, PortCounts: []*opb.Capabilities_PortCount{
+			{Speed: opb.Port_S_400GB, Count: 4},
+		}},
+	}, {
+		desc: "unknown",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			bind := fakebind.Setup()
+			bind.ReserveFn = func(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+				return &binding.Reservation{DUTs: map[string]binding.DUT{"dut": &binding.AbstractDUT{
+					&binding.Dims{Name: "d1", Capabilities: test.caps},
+				}}}, nil
+			}
+			if err := testbed.Reserve(context.Background(), &flags.Values{TestbedPath: tbPath}); err != nil {
+				t.Errorf("Reserve() got unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReserveMultiple(t *testing.T) {
+	dir := t.TempDir()
+	tbPath1 := writeTestbedFile(t, dir, `duts{id:"dut"}`)
+	tbPath2 := writeTestbedFile(t, dir, `duts{id:"dut"}`)
+	var mu sync.Mutex
+	newBind := func(id string, reserveErr error, released *[]string) *fakebind.Binding {
+		return &fakebind.Binding{
+			ReserveFn: func(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+				if reserveErr != nil {
+					return nil, reserveErr
+				}
+				return &binding.Reservation{ID: id, DUTs: map[string]binding.DUT{
+					"dut": &binding.AbstractDUT{&binding.Dims{Name: id + "DUT"}},
+				}}, nil
+			},
+			ReleaseFn: func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				*released = append(*released, id)
+				return nil
+			},
+		}
+	}
+
+	t.Run("multiple testbeds", func(t *testing.T) {
+		var released []string
+		testbed.SetBindings(newBind("res1", nil, &released), newBind("res2", nil, &released))
+		fv := &flags.Values{TestbedPath: tbPath1, TestbedPaths: []string{tbPath1, tbPath2}}
+		if err := testbed.Reserve(context.Background(), fv); err != nil {
+			t.Fatalf("Reserve() got unexpected error: %v", err)
+		}
+		defer testbed.Release(context.Background())
+		rs, err := testbed.Reservations()
+		if err != nil {
+			t.Fatalf("Reservations() got unexpected error: %v", err)
+		}
+		if len(rs) != 2 || rs[0].ID != "res1" || rs[1].ID != "res2" {
+			t.Errorf("Reservations() got %v, want res1 and res2", rs)
+		}
+		if got, want := testbed.TestbedPaths(), []string{tbPath1, tbPath2}; !cmp.Equal(got, want) {
+			t.Errorf("TestbedPaths() got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("shards of one testbed", func(t *testing.T) {
+		var released []string
+		testbed.SetBindings(newBind("res1", nil, &released), newBind("res2", nil, &released))
+		if err := testbed.Reserve(context.Background(), &flags.Values{TestbedPath: tbPath1}); err != nil {
+			t.Fatalf("Reserve() got unexpected error: %v", err)
+		}
+		defer testbed.Release(context.Background())
+		if got, want := testbed.TestbedPaths(), []string{tbPath1, tbPath1}; !cmp.Equal(got, want) {
+			t.Errorf("TestbedPaths() got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("partial failure releases", func(t *testing.T) {
+		var released []string
+		testbed.SetBindings(newBind("res1", nil, &released), newBind("res2", errors.New("reserve error"), &released))
+		if err := testbed.Reserve(context.Background(), &flags.Values{TestbedPath: tbPath1}); err == nil {
+			t.Fatalf("Reserve() got no error, want error")
+		}
+		if want := []string{"res1"}; !cmp.Equal(released, want) {
+			t.Errorf("Reserve() released %v, want %v", released, want)
+		}
+	})
+
+	t.Run("mismatched count", func(t *testing.T) {
+		var released []string
+		testbed.SetBindings(newBind("res1", nil, &released), newBind("res2", nil, &released))
+		fv := &flags.Values{TestbedPath: tbPath1, TestbedPaths: []string{tbPath1, tbPath2, tbPath2}}
+		if err := testbed.Reserve(context.Background(), fv); err == nil {
+			t.Errorf("Reserve() got no error, want error")
+		}
+	})
+}
+
+func TestAcquire(t *testing.T) {
+	tbPath := writeTestbedFile(t, t.TempDir(), `duts{id:"dut"}`)
+	var binds []binding.Binding
+	for _, id := range []string{"res1", "res2"} {
+		id := id
+		binds = append(binds, &fakebind.Binding{
+			ReserveFn: func(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+				return &binding.Reservation{ID: id, DUTs: map[string]binding.DUT{
+					"dut": &binding.AbstractDUT{&binding.Dims{Name: id + "DUT"}},
+				}}, nil
+			},
+			ReleaseFn: func(context.Context) error { return nil },
+		})
+	}
+	testbed.SetBindings(binds...)
+	ctx := context.Background()
+	if err := testbed.Reserve(ctx, &flags.Values{TestbedPath: tbPath}); err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	defer testbed.Release(ctx)
+
+	releaseA, err := testbed.Acquire(ctx, "TestA")
+	if err != nil {
+		t.Fatalf("Acquire(TestA) got unexpected error: %v", err)
+	}
+	releaseB, err := testbed.Acquire(ctx, "TestB")
+	if err != nil {
+		t.Fatalf("Acquire(TestB) got unexpected error: %v", err)
+	}
+	resA, err := testbed.ReservationFor("TestA")
+	if err != nil {
+		t.Fatalf("ReservationFor(TestA) got unexpected error: %v", err)
+	}
+	resB, err := testbed.ReservationFor("TestB")
+	if err != nil {
+		t.Fatalf("ReservationFor(TestB) got unexpected error: %v", err)
+	}
+	if resA == resB {
+		t.Errorf("ReservationFor() got the same reservation %q for both tests, want distinct", resA.ID)
+	}
+	if got := testbed.TestbedPathFor("TestB"); got != tbPath {
+		t.Errorf("TestbedPathFor(TestB) got %q, want %q", got, tbPath)
+	}
+	if resOther, err := testbed.ReservationFor("TestOther"); err != nil || resOther.ID != "res1" {
+		t.Errorf("ReservationFor(TestOther) got %v, %v, want primary reservation res1", resOther, err)
+	}
+
+	// No testbed is free until one of the tests is done with it.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := testbed.Acquire(timeoutCtx, "TestC"); err == nil {
+		t.Fatalf("Acquire(TestC) got no error, want timeout")
+	}
+	releaseA()
+	releaseC, err := testbed.Acquire(ctx, "TestC")
+	if err != nil {
+		t.Fatalf("Acquire(TestC) got unexpected error: %v", err)
+	}
+	if resC, err := testbed.ReservationFor("TestC"); err != nil || resC != resA {
+		t.Errorf("ReservationFor(TestC) got %v, %v, want %v", resC, err, resA)
+	}
+	releaseB()
+	releaseC()
+}
+
+func TestSetReservationForTestingNoBindings(t *testing.T) {
+	testbed.SetBindings()
+	want := &binding.Reservation{ID: "res"}
+	testbed.SetReservationForTesting(want)
+	defer testbed.SetReservationForTesting(nil)
+	if got, err := testbed.Reservation(); err != nil || got != want {
+		t.Errorf("Reservation() got %v, %v, want %v", got, err, want)
+	}
+}
+
 func TestRelease(t *testing.T) {
 	t.Run("not reserved", func(t *testing.T) {
 		bind := fakebind.Setup()
@@ -237,6 +487,101 @@ func TestRelease(t *testing.T) {
 	})
 }
 
+func TestExtend(t *testing.T) {
+	t.Run("not reserved", func(t *testing.T) {
+		fakebind.SetupRenewable()
+		if err := testbed.CheckExtend(time.Hour); err == nil {
+			t.Errorf("CheckExtend() got no error, want error")
+		}
+		if err := testbed.Extend(context.Background(), time.Hour); err == nil {
+			t.Errorf("Extend() got no error, want error")
+		}
+	})
+
+	t.Run("not renewer", func(t *testing.T) {
+		fakebind.Setup().WithReservation(new(binding.Reservation))
+		if testbed.CanRenew() {
+			t.Errorf("CanRenew() got true, want false")
+		}
+		if err := testbed.CheckExtend(time.Hour); err == nil {
+			t.Errorf("CheckExtend() got no error, want error")
+		}
+		if err := testbed.Extend(context.Background(), time.Hour); err == nil {
+			t.Errorf("Extend() got no error, want error")
+		}
+	})
+
+	t.Run("nonpositive duration", func(t *testing.T) {
+		fakebind.SetupRenewable().WithReservation(new(binding.Reservation))
+		if err := testbed.CheckExtend(0); err == nil {
+			t.Errorf("CheckExtend() got no error, want error")
+		}
+		if err := testbed.Extend(context.Background(), 0); err == nil {
+			t.Errorf("Extend() got no error, want error")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		bind := fakebind.SetupRenewable()
+		bind.WithReservation(new(binding.Reservation))
+		var gotDur time.Duration
+		bind.ExtendFn = func(_ context.Context, d time.Duration) error {
+			gotDur = d
+			return nil
+		}
+		if !testbed.CanRenew() {
+			t.Errorf("CanRenew() got false, want true")
+		}
+		if err := testbed.CheckExtend(time.Hour); err != nil {
+			t.Errorf("CheckExtend() got unexpected error: %v", err)
+		}
+		if err := testbed.Extend(context.Background(), time.Hour); err != nil {
+			t.Fatalf("Extend() got unexpected error: %v", err)
+		}
+		if gotDur != time.Hour {
+			t.Errorf("Extend() extended by %v, want %v", gotDur, time.Hour)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		wantErr := "extend error"
+		bind := fakebind.SetupRenewable()
+		bind.WithReservation(new(binding.Reservation))
+		bind.ExtendFn = func(context.Context, time.Duration) error {
+			return fmt.Errorf(wantErr)
+		}
+		gotErr := testbed.Extend(context.Background(), time.Hour)
+		if gotErr == nil || !strings.Contains(gotErr.Error(), wantErr) {
+			t.Errorf("Extend() got error %v, want %q", gotErr, wantErr)
+		}
+	})
+}
+
+func TestHeartbeat(t *testing.T) {
+	t.Run("not reserved", func(t *testing.T) {
+		fakebind.SetupRenewable()
+		if err := testbed.Heartbeat(context.Background()); err == nil {
+			t.Errorf("Heartbeat() got no error, want error")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		bind := fakebind.SetupRenewable()
+		bind.WithReservation(new(binding.Reservation))
+		var beats int
+		bind.HeartbeatFn = func(context.Context) error {
+			beats++
+			return nil
+		}
+		if err := testbed.Heartbeat(context.Background()); err != nil {
+			t.Fatalf("Heartbeat() got unexpected error: %v", err)
+		}
+		if beats != 1 {
+			t.Errorf("Heartbeat() sent %d heartbeats, want 1", beats)
+		}
+	})
+}
+
 func writeTestbedFile(t *testing.T, dir, text string) string {
 	t.Helper()
 	f, err := os.CreateTemp(dir, "*.textproto")
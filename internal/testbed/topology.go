@@ -0,0 +1,105 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testbed
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/ondatra/binding"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// SetTestbedForTesting sets the testbed proto of the reservation set by
+// SetReservationForTesting. This is only called by tests that query the
+// topology of the testbed, such as the port groups and links.
+func SetTestbedForTesting(tb *opb.Testbed) {
+	resMu.Lock()
+	defer resMu.Unlock()
+	shards[0].tb = tb
+}
+
+// PortsOfGroup returns the sorted IDs of the ports of the reserved device
+// that are in the specified group in the testbed.
+func PortsOfGroup(rd binding.Device, group string) ([]string, error) {
+	dev, _, err := deviceProto(rd)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, p := range dev.GetPorts() {
+		if p.GetGroup() == group {
+			ids = append(ids, p.GetId())
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no ports in group %q of reserved device %q", group, rd.Name())
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// LinkPeer returns the reserved device and port at the other end of the link
+// from the port of the reserved device with the specified ID.
+func LinkPeer(rd binding.Device, portID string) (binding.Device, *binding.Port, error) {
+	dev, sh, err := deviceProto(rd)
+	if err != nil {
+		return nil, nil, err
+	}
+	local := dev.GetId() + ":" + portID
+	var peer string
+	for _, ln := range sh.tb.GetLinks() {
+		switch local {
+		case ln.GetA():
+			peer = ln.GetB()
+		case ln.GetB():
+			peer = ln.GetA()
+		}
+	}
+	if peer == "" {
+		return nil, nil, fmt.Errorf("port ID %q of reserved device %q is not linked", portID, rd.Name())
+	}
+	devID, peerPortID, _ := strings.Cut(peer, ":")
+	peerDev, err := Device(sh.res, devID)
+	if err != nil {
+		return nil, nil, err
+	}
+	peerPort, err := Port(peerDev, peerPortID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return peerDev, peerPort, nil
+}
+
+// deviceProto returns the testbed proto of the reserved device and the shard
+// in which it is reserved.
+func deviceProto(rd binding.Device) (*opb.Device, *shard, error) {
+	resMu.RLock()
+	defer resMu.RUnlock()
+	for _, sh := range shards {
+		if sh.res == nil || sh.tb == nil {
+			continue
+		}
+		for _, dev := range append(append([]*opb.Device{}, sh.tb.GetDuts()...), sh.tb.GetAtes()...) {
+			// Match by identity, as the devices of every shard may have the same names.
+			if d, err := Device(sh.res, dev.GetId()); err == nil && d == rd {
+				return dev, sh, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("reserved device %q not found in any testbed", rd.Name())
+}
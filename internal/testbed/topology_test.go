@@ -0,0 +1,162 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testbed_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/fakebind"
+	"github.com/openconfig/ondatra/internal/flags"
+	"github.com/openconfig/ondatra/internal/testbed"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+func TestTopology(t *testing.T) {
+	tbPath := writeTestbedFile(t, t.TempDir(), `
+duts {
+  id: "dut"
+  ports { id: "port1" group: "lag1" }
+  ports { id: "port2" group: "lag1" }
+  ports { id: "port3" }
+}
+ates {
+  id: "ate"
+  ports { id: "port1" }
+}
+links { a: "ate:port1" b: "dut:port2" }
+`)
+	dut := &binding.AbstractDUT{&binding.Dims{
+		Name: "myDUT",
+		Ports: map[string]*binding.Port{
+			"port1": {Name: "Eth1"},
+			"port2": {Name: "Eth2"},
+			"port3": {Name: "Eth3"},
+		},
+	}}
+	ate := &binding.AbstractATE{&binding.Dims{
+		Name:  "myATE",
+		Ports: map[string]*binding.Port{"port1": {Name: "1/1"}},
+	}}
+	bind := fakebind.Setup()
+	bind.ReserveFn = func(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+		return &binding.Reservation{
+			DUTs: map[string]binding.DUT{"dut": dut},
+			ATEs: map[string]binding.ATE{"ate": ate},
+		}, nil
+	}
+	if err := testbed.Reserve(context.Background(), &flags.Values{TestbedPath: tbPath}); err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+
+	t.Run("PortsOfGroup", func(t *testing.T) {
+		got, err := testbed.PortsOfGroup(dut, "lag1")
+		if err != nil {
+			t.Fatalf("PortsOfGroup() got unexpected error: %v", err)
+		}
+		if want := []string{"port1", "port2"}; !cmp.Equal(got, want) {
+			t.Errorf("PortsOfGroup() got %v, want %v", got, want)
+		}
+		if _, err := testbed.PortsOfGroup(dut, "lag2"); err == nil || !strings.Contains(err.Error(), "no ports in group") {
+			t.Errorf("PortsOfGroup() got error %v, want no ports in group", err)
+		}
+	})
+
+	t.Run("LinkPeer", func(t *testing.T) {
+		gotDev, gotPort, err := testbed.LinkPeer(dut, "port2")
+		if err != nil {
+			t.Fatalf("LinkPeer() got unexpected error: %v", err)
+		}
+		if gotDev.Name() != "myATE" || gotPort.Name != "1/1" {
+			t.Errorf("LinkPeer() got %s port %s, want myATE port 1/1", gotDev.Name(), gotPort.Name)
+		}
+		gotDev, gotPort, err = testbed.LinkPeer(ate, "port1")
+		if err != nil {
+			t.Fatalf("LinkPeer() got unexpected error: %v", err)
+		}
+		if gotDev.Name() != "myDUT" || gotPort.Name != "Eth2" {
+			t.Errorf("LinkPeer() got %s port %s, want myDUT port Eth2", gotDev.Name(), gotPort.Name)
+		}
+		if _, _, err := testbed.LinkPeer(dut, "port3"); err == nil || !strings.Contains(err.Error(), "not linked") {
+			t.Errorf("LinkPeer() got error %v, want not linked", err)
+		}
+	})
+
+	t.Run("unknown device", func(t *testing.T) {
+		other := &binding.AbstractDUT{&binding.Dims{Name: "other"}}
+		if _, err := testbed.PortsOfGroup(other, "lag1"); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("PortsOfGroup() got error %v, want not found", err)
+		}
+	})
+}
+
+func TestTopologyShards(t *testing.T) {
+	tbPath := writeTestbedFile(t, t.TempDir(), `
+duts {
+  id: "dut"
+  ports { id: "port1" }
+}
+ates {
+  id: "ate"
+  ports { id: "port1" }
+}
+links { a: "ate:port1" b: "dut:port1" }
+`)
+	// Every shard reserves devices with the same names but distinct ports.
+	var duts []binding.DUT
+	var binds []binding.Binding
+	for _, shard := range []string{"shard1", "shard2"} {
+		dut := &binding.AbstractDUT{&binding.Dims{
+			Name:  "myDUT",
+			Ports: map[string]*binding.Port{"port1": {Name: shard + ":Eth1"}},
+		}}
+		ate := &binding.AbstractATE{&binding.Dims{
+			Name:  "myATE",
+			Ports: map[string]*binding.Port{"port1": {Name: shard + ":1/1"}},
+		}}
+		duts = append(duts, dut)
+		binds = append(binds, &fakebind.Binding{
+			ReserveFn: func(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+				return &binding.Reservation{
+					DUTs: map[string]binding.DUT{"dut": dut},
+					ATEs: map[string]binding.ATE{"ate": ate},
+				}, nil
+			},
+			ReleaseFn: func(context.Context) error { return nil },
+		})
+	}
+	testbed.SetBindings(binds...)
+	ctx := context.Background()
+	if err := testbed.Reserve(ctx, &flags.Values{TestbedPath: tbPath}); err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	defer testbed.Release(ctx)
+
+	for i, want := range []string{"shard1:1/1", "shard2:1/1"} {
+		_, gotPort, err := testbed.LinkPeer(duts[i], "port1")
+		if err != nil {
+			t.Fatalf("LinkPeer() got unexpected error: %v", err)
+		}
+		if gotPort.Name != want {
+			t.Errorf("LinkPeer() of DUT %d got port %s, want %s", i, gotPort.Name, want)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace records a timeline of the operations of a test, as spans, and
+// exports it as an OTLP-JSON or Chrome trace-event file.
+//
+// Spans are only recorded while tracing is enabled, so instrumented code can
+// unconditionally start and end spans:
+//
+//	span := trace.Start(t, "cli.Run", "device", dut.Name())
+//	res, err := runCommand()
+//	span.End(err)
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/secrets"
+)
+
+// Format is a trace file format.
+type Format string
+
+const (
+	// OTLP is the OpenTelemetry protocol JSON encoding of an
+	// ExportTraceServiceRequest.
+	OTLP Format = "otlp"
+	// Chrome is the Chrome trace-event format, which can be loaded in
+	// chrome://tracing or https://ui.perfetto.dev.
+	Chrome Format = "chrome"
+)
+
+// ParseFormat parses a trace format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case OTLP, Chrome:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown trace format %q, must be %q or %q", s, OTLP, Chrome)
+	}
+}
+
+// Span is a timed operation. A nil *Span is valid and records nothing.
+type Span struct {
+	name       string
+	test       string
+	attrs      []string
+	start, end time.Time
+	err        error
+}
+
+// maxSpans is the number of spans kept in memory. Once it is reached, the
+// oldest span is dropped for each new one, so that long runs do not grow the
+// trace without bound.
+var maxSpans = 100000
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	spans   []*Span
+	dropped int
+)
+
+// Enable starts recording spans.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+	spans, dropped = nil, 0
+}
+
+// Enabled reports whether spans are being recorded, so that callers can skip
+// computing expensive attributes when they are not.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Start starts a span with the specified name and attributes, specified as
+// alternating keys and values. If t is not nil, the span belongs to the test
+// of t; otherwise it belongs to the test suite. Returns nil if tracing is not
+// enabled.
+func Start(t testing.TB, name string, attrs ...string) *Span {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return nil
+	}
+	s := &Span{name: name, attrs: attrs, start: time.Now()}
+	if t != nil {
+		s.test = t.Name()
+	}
+	if len(spans) >= maxSpans {
+		spans[0] = nil
+		spans = spans[1:]
+		dropped++
+	}
+	spans = append(spans, s)
+	return s
+}
+
+// End ends the span, which failed if err is not nil.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if s.end.IsZero() {
+		s.end, s.err = time.Now(), err
+	}
+}
+
+// Write writes all the spans recorded so far to a file at the specified path
+// in the specified format. Spans that have not ended are written as ending now.
+// Secrets are redacted from the attributes and errors of the spans.
+func Write(path string, format Format) error {
+	mu.Lock()
+	if dropped > 0 {
+		log.Warningf("Trace dropped the %d oldest spans beyond the limit of %d", dropped, maxSpans)
+	}
+	snapshot := make([]Span, len(spans))
+	now := time.Now()
+	for i, s := range spans {
+		snapshot[i] = *s
+		if snapshot[i].end.IsZero() {
+			snapshot[i].end = now
+		}
+		snapshot[i].attrs = make([]string, len(s.attrs))
+		for j, a := range s.attrs {
+			snapshot[i].attrs[j] = secrets.Redact(a)
+		}
+		if s.err != nil {
+			snapshot[i].err = errors.New(secrets.Redact(s.err.Error()))
+		}
+	}
+	mu.Unlock()
+
+	var v any
+	switch format {
+	case OTLP:
+		v = toOTLP(snapshot)
+	case Chrome:
+		v = toChrome(snapshot)
+	default:
+		return fmt.Errorf("unknown trace format %q", format)
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling trace: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("error writing trace file: %w", err)
+	}
+	return nil
+}
+
+// testGroup is the spans of a single test, in start order.
+type testGroup struct {
+	name       string
+	spans      []Span
+	start, end time.Time
+}
+
+// groupByTest groups the spans by test, in order of the first span of each
+// test. Spans of the test suite are in a group with an empty name.
+func groupByTest(spans []Span) []*testGroup {
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start.Before(spans[j].start)
+	})
+	var groups []*testGroup
+	byName := make(map[string]*testGroup)
+	for _, s := range spans {
+		g, ok := byName[s.test]
+		if !ok {
+			g = &testGroup{name: s.test, start: s.start, end: s.end}
+			byName[s.test] = g
+			groups = append(groups, g)
+		}
+		g.spans = append(g.spans, s)
+		if s.end.After(g.end) {
+			g.end = s.end
+		}
+	}
+	return groups
+}
+
+// OTLP JSON types, a subset of the ExportTraceServiceRequest message.
+// https://github.com/open-telemetry/opentelemetry-proto/blob/main/docs/specification.md#json-protobuf-encoding
+type (
+	otlpRequest struct {
+		ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   *otlpResource     `json:"resource"`
+		ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []*otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope *otlpScope  `json:"scope"`
+		Spans []*otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		Name              string          `json:"name"`
+		Kind              int             `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []*otlpKeyValue `json:"attributes,omitempty"`
+		Status            *otlpStatus     `json:"status,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string     `json:"key"`
+		Value *otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue string `json:"stringValue"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func otlpAttrs(attrs []string) []*otlpKeyValue {
+	var kvs []*otlpKeyValue
+	for i := 0; i+1 < len(attrs); i += 2 {
+		kvs = append(kvs, &otlpKeyValue{Key: attrs[i], Value: &otlpValue{StringValue: attrs[i+1]}})
+	}
+	return kvs
+}
+
+// toOTLP converts the spans to a single trace, in which every test is a span
+// that is the parent of the spans of the test.
+func toOTLP(spans []Span) *otlpRequest {
+	traceID := randomID(16)
+	var out []*otlpSpan
+	for _, g := range groupByTest(spans) {
+		var parentID string
+		if g.name != "" {
+			parentID = randomID(8)
+			out = append(out, &otlpSpan{
+				TraceID:           traceID,
+				SpanID:            parentID,
+				Name:              g.name,
+				Kind:              otlpSpanKindInternal,
+				StartTimeUnixNano: unixNano(g.start),
+				EndTimeUnixNano:   unixNano(g.end),
+			})
+		}
+		for _, s := range g.spans {
+			st := &otlpStatus{Code: otlpStatusOK}
+			if s.err != nil {
+				st = &otlpStatus{Code: otlpStatusError, Message: s.err.Error()}
+			}
+			out = append(out, &otlpSpan{
+				TraceID:           traceID,
+				SpanID:            randomID(8),
+				ParentSpanID:      parentID,
+				Name:              s.name,
+				Kind:              otlpSpanKindInternal,
+				StartTimeUnixNano: unixNano(s.start),
+				EndTimeUnixNano:   unixNano(s.end),
+				Attributes:        otlpAttrs(s.attrs),
+				Status:            st,
+			})
+		}
+	}
+	return &otlpRequest{ResourceSpans: []*otlpResourceSpans{{
+		Resource:   &otlpResource{Attributes: otlpAttrs([]string{"service.name", "ondatra"})},
+		ScopeSpans: []*otlpScopeSpans{{Scope: &otlpScope{Name: "ondatra"}, Spans: out}},
+	}}}
+}
+
+// chromeTrace is a Chrome trace-event file.
+// https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type chromeTrace struct {
+	TraceEvents     []*chromeEvent `json:"traceEvents"`
+	DisplayTimeUnit string         `json:"displayTimeUnit"`
+}
+
+type chromeEvent struct {
+	Name string            `json:"name"`
+	Cat  string            `json:"cat,omitempty"`
+	Ph   string            `json:"ph"`
+	TS   int64             `json:"ts"`
+	Dur  int64             `json:"dur,omitempty"`
+	PID  int               `json:"pid"`
+	TID  int               `json:"tid"`
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// toChrome converts the spans to complete trace events, in which every test
+// is a separate thread.
+func toChrome(spans []Span) *chromeTrace {
+	ct := &chromeTrace{DisplayTimeUnit: "ms"}
+	for tid, g := range groupByTest(spans) {
+		name := g.name
+		if name == "" {
+			name = "TestMain"
+		}
+		ct.TraceEvents = append(ct.TraceEvents, &chromeEvent{
+			Name: "thread_name",
+			Ph:   "M",
+			PID:  1,
+			TID:  tid,
+			Args: map[string]string{"name": name},
+		})
+		for _, s := range g.spans {
+			args := make(map[string]string)
+			for i := 0; i+1 < len(s.attrs); i += 2 {
+				args[s.attrs[i]] = s.attrs[i+1]
+			}
+			if s.err != nil {
+				args["error"] = s.err.Error()
+			}
+			ct.TraceEvents = append(ct.TraceEvents, &chromeEvent{
+				Name: s.name,
+				Cat:  category(s.name),
+				Ph:   "X",
+				TS:   s.start.UnixMicro(),
+				Dur:  s.end.Sub(s.start).Microseconds(),
+				PID:  1,
+				TID:  tid,
+				Args: args,
+			})
+		}
+	}
+	return ct
+}
+
+// category returns the part of a span name before the first dot.
+func category(name string) string {
+	cat, _, _ := strings.Cut(name, ".")
+	return cat
+}
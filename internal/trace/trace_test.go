@@ -0,0 +1,176 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/ondatra/secrets"
+)
+
+func TestNotEnabled(t *testing.T) {
+	mu.Lock()
+	enabled = false
+	mu.Unlock()
+	s := Start(t, "op")
+	if s != nil {
+		t.Errorf("Start() got %v, want nil", s)
+	}
+	s.End(nil) // Must not panic.
+}
+
+func recordSpans(t *testing.T) {
+	t.Helper()
+	Enable()
+	Start(nil, "ondatra.Reserve").End(nil)
+	Start(t, "cli.Run", "device", "dut", "command", "show version").End(nil)
+	Start(t, "gnmi.Replace", "device", "dut").End(errors.New("set failed"))
+	Start(t, "console.Capture") // never ended
+}
+
+func TestMaxSpans(t *testing.T) {
+	defer func(n int) { maxSpans = n }(maxSpans)
+	maxSpans = 2
+	Enable()
+	Start(t, "first").End(nil)
+	Start(t, "second").End(nil)
+	Start(t, "third").End(nil)
+	mu.Lock()
+	var got []string
+	for _, s := range spans {
+		got = append(got, s.name)
+	}
+	mu.Unlock()
+	if want := []string{"second", "third"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Start() kept spans %v, want %v", got, want)
+	}
+	if dropped != 1 {
+		t.Errorf("Start() dropped %d spans, want 1", dropped)
+	}
+}
+
+func TestWriteOTLP(t *testing.T) {
+	recordSpans(t)
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := Write(path, OTLP); err != nil {
+		t.Fatalf("Write() got error: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() got error: %v", err)
+	}
+	var got otlpRequest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal() got error: %v", err)
+	}
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	// One suite span, one test span, and three spans of the test.
+	if len(spans) != 5 {
+		t.Fatalf("Write() got %d spans, want 5", len(spans))
+	}
+	testSpan := spans[1]
+	if testSpan.Name != t.Name() {
+		t.Errorf("Write() got test span name %q, want %q", testSpan.Name, t.Name())
+	}
+	for _, s := range spans[2:] {
+		if s.ParentSpanID != testSpan.SpanID {
+			t.Errorf("Span %q got parent %q, want %q", s.Name, s.ParentSpanID, testSpan.SpanID)
+		}
+	}
+	if spans[0].ParentSpanID != "" {
+		t.Errorf("Suite span got parent %q, want none", spans[0].ParentSpanID)
+	}
+	if got := spans[3].Status; got.Code != otlpStatusError || got.Message != "set failed" {
+		t.Errorf("Failed span got status %+v, want error %q", got, "set failed")
+	}
+	if got := spans[2].Attributes; len(got) != 2 || got[1].Key != "command" || got[1].Value.StringValue != "show version" {
+		t.Errorf("Span got attributes %v, want device and command", got)
+	}
+}
+
+func TestWriteChrome(t *testing.T) {
+	recordSpans(t)
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := Write(path, Chrome); err != nil {
+		t.Fatalf("Write() got error: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() got error: %v", err)
+	}
+	var got chromeTrace
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal() got error: %v", err)
+	}
+	threads := make(map[int]string)
+	var complete int
+	for _, e := range got.TraceEvents {
+		switch e.Ph {
+		case "M":
+			threads[e.TID] = e.Args["name"]
+		case "X":
+			complete++
+			if e.Name == "gnmi.Replace" && (e.Cat != "gnmi" || e.Args["error"] != "set failed") {
+				t.Errorf("Event %q got category %q and args %v", e.Name, e.Cat, e.Args)
+			}
+		}
+	}
+	if complete != 4 {
+		t.Errorf("Write() got %d complete events, want 4", complete)
+	}
+	if threads[0] != "TestMain" || threads[1] != t.Name() {
+		t.Errorf("Write() got threads %v, want TestMain and %s", threads, t.Name())
+	}
+}
+
+func TestWriteRedactsSecrets(t *testing.T) {
+	const secret = "trace-s3cret"
+	secrets.Register(secret)
+	Enable()
+	Start(t, "cli.Run", "command", "username admin secret "+secret).End(errors.New("bad password " + secret))
+	for _, format := range []Format{OTLP, Chrome} {
+		path := filepath.Join(t.TempDir(), "trace.json")
+		if err := Write(path, format); err != nil {
+			t.Fatalf("Write() got error: %v", err)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile() got error: %v", err)
+		}
+		if strings.Contains(string(b), secret) {
+			t.Errorf("Write(%s) got a trace containing the secret: %s", format, b)
+		}
+		// JSON escapes the angle brackets of the redaction.
+		if !strings.Contains(string(b), "redacted") {
+			t.Errorf("Write(%s) got a trace without the redaction: %s", format, b)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"otlp", "chrome"} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q) got error: %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("zipkin"); err == nil {
+		t.Errorf("ParseFormat(%q) got no error, want error", "zipkin")
+	}
+}
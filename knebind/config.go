@@ -17,8 +17,10 @@ package knebind
 import (
 	"fmt"
 	"os"
+	"time"
 
 	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/binding/portgraph"
 	"github.com/openconfig/ondatra/knebind/creds"
 	"gopkg.in/yaml.v2"
 )
@@ -31,6 +33,21 @@ type Config struct {
 	Topology           string             `yaml:"topology"`
 	Kubeconfig         string             `yaml:"kubecfg"`
 	SkipReset          bool               `yaml:"skip_reset"`
+	// Objective, if set, is minimized when solving for a reservation.
+	Objective *portgraph.Objective `yaml:"-"`
+	// SolveTimeout bounds the search for an assignment minimizing the
+	// Objective, after which the best assignment found so far is used.
+	// Defaults to defaultSolveTimeout if unset.
+	SolveTimeout time.Duration `yaml:"solve_timeout"`
+}
+
+const defaultSolveTimeout = 30 * time.Second
+
+func (c *Config) solveTimeout() time.Duration {
+	if c.SolveTimeout > 0 {
+		return c.SolveTimeout
+	}
+	return defaultSolveTimeout
 }
 
 func (c *Config) String() string {
@@ -21,17 +21,22 @@ import (
 
 	"flag"
 
+	"github.com/openconfig/ondatra/secrets"
+
 	tpb "github.com/openconfig/kne/proto/topo"
 )
 
 // UserPass is a username/password combination.
+// The password may be a reference of the form "secret:<key>" to the secret
+// with that key from the provider set by the --secrets flag.
 type UserPass struct {
 	Username string
 	Password string
 }
 
+// String returns the username and a redacted password.
 func (up *UserPass) String() string {
-	return fmt.Sprintf("%s/%s", up.Username, up.Password)
+	return fmt.Sprintf("%s/%s", up.Username, secrets.Redacted)
 }
 
 // Credentials contains credential info for nodes in the KNE topology.
@@ -191,3 +191,10 @@ func TestParseFlagsErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestUserPassString(t *testing.T) {
+	up := &UserPass{Username: "admin", Password: "hunter2"}
+	if got, want := up.String(), "admin/<redacted>"; got != want {
+		t.Errorf("String() got %q, want %q", got, want)
+	}
+}
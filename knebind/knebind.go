@@ -38,6 +38,7 @@ import (
 	"github.com/openconfig/ondatra/binding/introspect"
 	"github.com/openconfig/ondatra/knebind/creds"
 	"github.com/openconfig/ondatra/knebind/solver"
+	"github.com/openconfig/ondatra/secrets"
 	"golang.org/x/crypto/ssh"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -102,7 +103,17 @@ func (b *Bind) Reserve(ctx context.Context, tb *opb.Testbed, runTime time.Durati
 	if err != nil {
 		return nil, err
 	}
-	res, err := solver.Solve(ctx, tb, resp.GetTopology(), partial)
+	solveCtx := ctx
+	var opts []solver.Option
+	if b.cfg.Objective != nil {
+		// Bound the search, so it neither runs exhaustively nor uses up the
+		// deadline needed to reset the configs of the reserved DUTs.
+		var cancel context.CancelFunc
+		solveCtx, cancel = context.WithTimeout(ctx, b.cfg.solveTimeout())
+		defer cancel()
+		opts = append(opts, solver.WithObjective(b.cfg.Objective))
+	}
+	res, err := solver.Solve(solveCtx, tb, resp.GetTopology(), partial, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -296,9 +307,13 @@ type rpcCredentials struct {
 }
 
 func (r *rpcCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	password, err := secrets.Resolve(ctx, r.Password)
+	if err != nil {
+		return nil, err
+	}
 	return map[string]string{
 		"username": r.Username,
-		"password": r.Password,
+		"password": password,
 	}, nil
 }
 
@@ -344,7 +359,7 @@ type kneCLI struct {
 	dut *kneDUT
 }
 
-func (c *kneCLI) RunCommand(_ context.Context, cmd string) (_ binding.CommandResult, rerr error) {
+func (c *kneCLI) RunCommand(ctx context.Context, cmd string) (_ binding.CommandResult, rerr error) {
 	s, err := c.dut.Service("ssh")
 	if err != nil {
 		return nil, err
@@ -355,11 +370,15 @@ func (c *kneCLI) RunCommand(_ context.Context, cmd string) (_ binding.CommandRes
 		return nil, errors.New("RunCommand requires node credentials be provided")
 	}
 	log.Infof("Using credentials %v to SSH", userPass)
+	password, err := secrets.Resolve(ctx, userPass.Password)
+	if err != nil {
+		return nil, err
+	}
 	cfg := &ssh.ClientConfig{
 		User: userPass.Username,
 		Auth: []ssh.AuthMethod{ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
 			if len(questions) > 0 {
-				return []string{userPass.Password}, nil
+				return []string{password}, nil
 			}
 			return nil, nil
 		})},
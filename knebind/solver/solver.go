@@ -17,7 +17,6 @@ package solver
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -27,7 +26,7 @@ import (
 	"github.com/openconfig/ondatra/binding"
 	"github.com/openconfig/ondatra/binding/introspect"
 	"github.com/openconfig/ondatra/binding/portgraph"
-	"github.com/openconfig/ondatra/internal/orderedmap"
+	"github.com/openconfig/ondatra/internal/tbgraph"
 	"github.com/pborman/uuid"
 
 	tpb "github.com/openconfig/kne/proto/topo"
@@ -36,8 +35,24 @@ import (
 
 const (
 	roleLabel = "ondatra-role"
-	roleDUT   = "DUT"
-	roleATE   = "ATE"
+	roleDUT   = tbgraph.RoleDUT
+	roleATE   = tbgraph.RoleATE
+)
+
+const (
+	// Attribute names mapping message fields to graph attributes/constraints.
+	vendorAttr = tbgraph.VendorAttr
+	roleAttr   = tbgraph.RoleAttr
+	hwAttr     = tbgraph.HWAttr
+	swAttr     = tbgraph.SWAttr
+	speedAttr  = tbgraph.SpeedAttr
+	groupAttr  = tbgraph.GroupAttr
+	nameAttr   = tbgraph.NameAttr
+	mtuAttr    = "mtu"
+
+	// KNE interfaces are never breakout channels, so they never have the
+	// breakout group attribute, and no KNE interface satisfies a breakout group.
+	breakoutGroupAttr = tbgraph.BreakoutGroupAttr
 )
 
 var (
@@ -114,216 +129,9 @@ func filterTopology(topo *tpb.Topology) *tpb.Topology {
 	return t
 }
 
-const (
-	// Attribute names mapping message fields to graph attributes/constraints.
-	vendorAttr = "vendor"
-	roleAttr   = "role"
-	hwAttr     = "hardware_model"
-	swAttr     = "software_version"
-	speedAttr  = "speed"
-	cardAttr   = "card_model"
-	pmdAttr    = "pmd"
-	groupAttr  = "group"
-	mtuAttr    = "mtu"
-	nameAttr   = "name"
-)
-
-var (
-	reAny = portgraph.Regex(regexp.MustCompile(".*"))
-)
-
 // testbedToAbstractGraph translates an Ondatra testbed to an AbstractGraph for solving.
 func testbedToAbstractGraph(tb *opb.Testbed, partial map[string]string) (*portgraph.AbstractGraph, map[*portgraph.AbstractNode]*opb.Device, map[*portgraph.AbstractPort]*opb.Port, error) {
-	var nodes []*portgraph.AbstractNode
-	var edges []*portgraph.AbstractEdge
-	name2Port := make(map[string]*portgraph.AbstractPort) // Name of the port to the AbstractPort.
-	node2Dev := make(map[*portgraph.AbstractNode]*opb.Device)
-	port2Port := make(map[*portgraph.AbstractPort]*opb.Port)
-
-	// addDevice creates an AbstractNode from a Device.
-	// If the field is empty, there is not a Constraint on that field.
-	addDevice := func(dev *opb.Device, isATE bool) {
-		nodeConstraints := make(map[string]portgraph.NodeConstraint)
-		if isATE {
-			nodeConstraints[roleAttr] = portgraph.Equal(roleATE)
-		} else {
-			nodeConstraints[roleAttr] = portgraph.Equal(roleDUT)
-		}
-		if v := dev.GetVendor(); v != opb.Device_VENDOR_UNSPECIFIED {
-			nodeConstraints[vendorAttr] = portgraph.Equal(v.String())
-		}
-		if hw, ok := modelConstraint(dev); ok {
-			nodeConstraints[hwAttr] = hw
-		}
-		if sw, ok := versionConstraint(dev); ok {
-			nodeConstraints[swAttr] = sw
-		}
-		if name, ok := partial[dev.GetId()]; ok {
-			nodeConstraints[nameAttr] = portgraph.Equal(name)
-		}
-		for k, v := range dev.GetExtraDimensions() {
-			nodeConstraints[k] = portgraph.Equal(v)
-		}
-		// Process each port on the device.
-		var ports []*portgraph.AbstractPort
-		group2PortNames := orderedmap.NewOrderedMap[string, []string]()
-		absPortName2DevPort := make(map[string]*opb.Port)
-		for _, port := range dev.GetPorts() {
-			portConstraints := make(map[string]portgraph.PortConstraint)
-			if s := port.GetSpeed(); s != opb.Port_SPEED_UNSPECIFIED {
-				portConstraints[speedAttr] = portgraph.Equal(s.String())
-			}
-			if cm, ok := cardModelConstraint(port); ok {
-				portConstraints[cardAttr] = cm
-			}
-			if pmd, ok := pmdConstraint(port); ok {
-				portConstraints[pmdAttr] = pmd
-			}
-			portName := fmt.Sprintf("%s:%s", dev.GetId(), port.GetId())
-			if name, ok := partial[portName]; ok {
-				portConstraints[nameAttr] = portgraph.Equal(name)
-			}
-			// Create the AbstractPort, but do not add group constraints until all ports are processed.
-			p := &portgraph.AbstractPort{Desc: portName, Constraints: portConstraints}
-			name2Port[portName] = p
-
-			// Check if the port has a group. If it does, additional constraint(s) need to be added.
-			if port.GetGroup() != "" {
-				pns, _ := group2PortNames.Get(port.GetGroup())
-				group2PortNames.Set(port.GetGroup(), append(pns, portName))
-				absPortName2DevPort[portName] = port
-			} else { // This port is not part of a group; no further processing.
-				port2Port[p] = port
-				ports = append(ports, p)
-			}
-		}
-		processedGroups := map[string]struct{}{}
-		groups := group2PortNames.Keys()
-		for _, group := range groups {
-			portNames, _ := group2PortNames.Get(group)
-			processedGroups[group] = struct{}{}
-			var constraints []portgraph.LeafPortConstraint
-			pn := portNames[0]
-			for _, pn2 := range portNames[1:] {
-				p2 := name2Port[pn2]
-				constraints = append(constraints, portgraph.SameAsPort(p2))
-				name2Port[pn2].Constraints[groupAttr] = reAny
-				port2Port[p2] = absPortName2DevPort[pn2]
-				ports = append(ports, p2)
-			}
-			for _, g2 := range groups {
-				if _, ok := processedGroups[g2]; !ok {
-					p, _ := group2PortNames.Get(g2)
-					constraints = append(constraints, portgraph.NotSameAsPort(name2Port[p[0]]))
-				} else if len(constraints) == 0 {
-					constraints = append(constraints, reAny)
-				}
-			}
-			p := name2Port[pn]
-			if len(constraints) == 1 {
-				p.Constraints[groupAttr] = constraints[0]
-			} else {
-				p.Constraints[groupAttr] = portgraph.AndPort(constraints...)
-			}
-			name2Port[pn] = p
-			ports = append(ports, p)
-			port2Port[p] = absPortName2DevPort[pn]
-		}
-		n := &portgraph.AbstractNode{Desc: dev.GetId(), Constraints: nodeConstraints, Ports: ports}
-		node2Dev[n] = dev
-		nodes = append(nodes, n)
-	}
-
-	for _, dev := range tb.GetDuts() {
-		addDevice(dev, false)
-	}
-	for _, dev := range tb.GetAtes() {
-		addDevice(dev, true)
-	}
-	for _, link := range tb.GetLinks() {
-		src, ok := name2Port[link.GetA()]
-		if !ok {
-			return nil, nil, nil, fmt.Errorf("port %q in links not present in specified testbed", link.GetA())
-		}
-		dst, ok := name2Port[link.GetB()]
-		if !ok {
-			return nil, nil, nil, fmt.Errorf("port %q in links not present in specified testbed", link.GetB())
-		}
-		edges = append(edges, &portgraph.AbstractEdge{Src: src, Dst: dst})
-	}
-	return &portgraph.AbstractGraph{Desc: "KNE testbed", Nodes: nodes, Edges: edges}, node2Dev, port2Port, nil
-}
-
-func modelConstraint(d *opb.Device) (portgraph.NodeConstraint, bool) {
-	switch v := d.GetHardwareModelValue().(type) {
-	case nil:
-		// handled after switch
-	case *opb.Device_HardwareModel:
-		if v.HardwareModel != "" {
-			return portgraph.Equal(v.HardwareModel), true
-		}
-	case *opb.Device_HardwareModelRegex:
-		if v.HardwareModelRegex != "" {
-			return portgraph.Regex(regexp.MustCompile(v.HardwareModelRegex)), true
-		}
-	default:
-		log.Fatalf("unknown hardware model type: %v(%T)", v, v)
-	}
-	return nil, false
-}
-
-func versionConstraint(d *opb.Device) (portgraph.NodeConstraint, bool) {
-	switch v := d.GetSoftwareVersionValue().(type) {
-	case nil:
-		// handled after switch
-	case *opb.Device_SoftwareVersion:
-		if v.SoftwareVersion != "" {
-			return portgraph.Equal(v.SoftwareVersion), true
-		}
-	case *opb.Device_SoftwareVersionRegex:
-		if v.SoftwareVersionRegex != "" {
-			return portgraph.Regex(regexp.MustCompile(v.SoftwareVersionRegex)), true
-		}
-	default:
-		log.Fatalf("unknown software version type: %v(%T)", v, v)
-	}
-	return nil, false
-}
-
-func cardModelConstraint(p *opb.Port) (portgraph.PortConstraint, bool) {
-	switch v := p.GetCardModelValue().(type) {
-	case nil:
-		// handled after switch
-	case *opb.Port_CardModel:
-		if v.CardModel != "" {
-			return portgraph.Equal(v.CardModel), true
-		}
-	case *opb.Port_CardModelRegex:
-		if v.CardModelRegex != "" {
-			return portgraph.Regex(regexp.MustCompile(v.CardModelRegex)), true
-		}
-	default:
-		log.Fatalf("unknown card model type: %v(%T)", v, v)
-	}
-	return nil, false
-}
-
-func pmdConstraint(p *opb.Port) (portgraph.PortConstraint, bool) {
-	switch v := p.GetPmdValue().(type) {
-	case nil:
-		// handled after switch
-	case *opb.Port_Pmd_:
-		if v.Pmd != opb.Port_PMD_UNSPECIFIED {
-			return portgraph.Equal(v.Pmd.String()), true
-		}
-	case *opb.Port_PmdRegex:
-		if v.PmdRegex != "" {
-			return portgraph.Regex(regexp.MustCompile(v.PmdRegex)), true
-		}
-	default:
-		log.Fatalf("unknown PMD type: %v(%T)", v, v)
-	}
-	return nil, false
+	return tbgraph.Abstract(tb, partial, "KNE testbed")
 }
 
 // topoToConcreteGraph translates a Topology to a ConcreteGraph.
@@ -363,6 +171,11 @@ func topoToConcreteGraph(topo *tpb.Topology) (*portgraph.ConcreteGraph, map[*por
 		if name := node.GetName(); name != "" {
 			attrs[nameAttr] = name
 		}
+		// KNE nodes do not support port breakout, so they never have the
+		// breakout attribute, and no KNE node satisfies a breakout requirement.
+		for k, v := range tbgraph.CapsAttrs(nodeCaps(node)) {
+			attrs[k] = v
+		}
 		var ports []*portgraph.ConcretePort
 		for intfName, nodeIntf := range node.GetInterfaces() {
 			portAttrs := make(map[string]string)
@@ -428,7 +241,9 @@ func assignmentToReservation(
 		dev2Node[absNode2Dev[absNode]] = conNode2Node[conNode]
 	}
 	for absPort, conPort := range assignment.Port2Port {
-		port2Intf[absPort2Port[absPort]] = conNode2Intf[conPort]
+		if port, ok := absPort2Port[absPort]; ok {
+			port2Intf[port] = conNode2Intf[conPort]
+		}
 	}
 
 	a := &assign{dev2Node, port2Intf}
@@ -455,8 +270,31 @@ func assignmentToReservation(
 	return res, nil
 }
 
+// Option is an option for Solve.
+type Option func(*options)
+
+type options struct {
+	obj *portgraph.Objective
+}
+
+// WithObjective makes Solve return the Reservation that minimizes the
+// objective, searching until it has tried every assignment of nodes or the
+// context is done, rather than the first Reservation it finds. The nodes and
+// ports of the topology have the same attributes as in the testbed, such as
+// "vendor", "hardware_model" and "name" for nodes and "name" and "group" for
+// ports.
+func WithObjective(obj *portgraph.Objective) Option {
+	return func(o *options) {
+		o.obj = obj
+	}
+}
+
 // Solve creates a new Reservation from a desired testbed and an available topology.
-func Solve(ctx context.Context, tb *opb.Testbed, topo *tpb.Topology, partial map[string]string) (*binding.Reservation, error) {
+func Solve(ctx context.Context, tb *opb.Testbed, topo *tpb.Topology, partial map[string]string, opts ...Option) (*binding.Reservation, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
 	topo = filterTopology(topo)
 	devs := append(append([]*opb.Device{}, tb.GetDuts()...), tb.GetAtes()...)
 	if numDevs, numNodes := len(devs), len(topo.GetNodes()); numDevs > numNodes {
@@ -477,7 +315,12 @@ func Solve(ctx context.Context, tb *opb.Testbed, topo *tpb.Topology, partial map
 		return nil, fmt.Errorf("could not parse specified testbed: %w", err)
 	}
 
-	assignment, err := portgraph.Solve(ctx, abstractGraph, superGraph)
+	var assignment *portgraph.Assignment
+	if o.obj != nil {
+		assignment, err = portgraph.Optimize(ctx, abstractGraph, superGraph, o.obj)
+	} else {
+		assignment, err = portgraph.Solve(ctx, abstractGraph, superGraph)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("could not solve for specified testbed: %w", err)
 	}
@@ -523,6 +366,27 @@ func (a *ServiceATE) Service(service string) (*tpb.Service, error) {
 	return srv, nil
 }
 
+// nodeCaps returns the capabilities of a KNE node: the gRIBI and P4RT
+// services it exposes, and the OTG API if it is an ATE. Port counts are not
+// included, because they are matched by the ports of the concrete graph.
+func nodeCaps(node *tpb.Node) *opb.Capabilities {
+	caps := new(opb.Capabilities)
+	gribiName, _ := ServiceName(introspect.GRIBI)
+	p4rtName, _ := ServiceName(introspect.P4RT)
+	for _, svc := range node.GetServices() {
+		switch svc.GetName() {
+		case gribiName:
+			caps.Gribi = true
+		case p4rtName:
+			caps.P4Rt = true
+		}
+	}
+	if role(node) == roleATE {
+		caps.AteApi = opb.Capabilities_OTG
+	}
+	return caps
+}
+
 type assign struct {
 	dev2Node  map[*opb.Device]*tpb.Node
 	port2Intf map[*opb.Port]*intf
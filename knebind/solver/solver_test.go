@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/binding/portgraph"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/testing/protocmp"
 
@@ -709,6 +710,51 @@ func TestTestbedToAbstractGraph(t *testing.T) {
 	}
 }
 
+func TestBreakoutConstraints(t *testing.T) {
+	tb := &opb.Testbed{Duts: []*opb.Device{{
+		Id: "dut",
+		Ports: []*opb.Port{
+			{Id: "port1", Speed: opb.Port_S_100GB, BreakoutGroup: "bg1"},
+			{Id: "port2", Speed: opb.Port_S_100GB, BreakoutGroup: "bg1"},
+			{Id: "port3", BreakoutGroup: "bg2"},
+		},
+	}}}
+	absGraph, _, _, err := testbedToAbstractGraph(tb, nil)
+	if err != nil {
+		t.Fatalf("testbedToAbstractGraph() got error %v, want nil", err)
+	}
+	conPort := func(name, speed, group string) *portgraph.ConcretePort {
+		attrs := map[string]string{speedAttr: speed}
+		if group != "" {
+			attrs[breakoutGroupAttr] = group
+		}
+		return &portgraph.ConcretePort{Desc: name, Attrs: attrs}
+	}
+	conGraph := &portgraph.ConcreteGraph{Nodes: []*portgraph.ConcreteNode{{
+		Desc:  "node",
+		Attrs: map[string]string{roleAttr: roleDUT},
+		Ports: []*portgraph.ConcretePort{
+			conPort("eth1", "S_400GB", ""),
+			conPort("eth2/1", "S_100GB", "eth2"),
+			conPort("eth3/1", "S_100GB", "eth3"),
+			conPort("eth3/2", "S_100GB", "eth3"),
+			conPort("eth3/3", "S_100GB", "eth3"),
+		},
+	}}}
+	assign, err := portgraph.Solve(context.Background(), absGraph, conGraph)
+	if err != nil {
+		t.Fatalf("Solve() got error %v, want nil", err)
+	}
+	got := make(map[string]string)
+	for abs, con := range assign.Port2Port {
+		got[abs.Desc] = con.Attrs[breakoutGroupAttr]
+	}
+	want := map[string]string{"dut:port1": "eth3", "dut:port2": "eth3", "dut:port3": "eth2"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Solve() returned unexpected breakout groups diff (-want +got):\n%s", diff)
+	}
+}
+
 func TestTopologyToConcreteGraph(t *testing.T) {
 	intf1 := &tpb.Interface{
 		Name:  "Ethernet1",
@@ -831,6 +877,130 @@ func TestRole(t *testing.T) {
 	}
 }
 
+func TestSolveCapabilities(t *testing.T) {
+	topo := unmarshalTopo(t, `
+		nodes: {
+		  name: "node1"
+		  vendor: ARISTA
+		  interfaces: {
+		    key: "eth1"
+		    value: {}
+		  }
+		}
+		nodes: {
+		  name: "node2"
+		  vendor: ARISTA
+		  services: {
+		    key: 1234
+		    value: {
+		      name: "gribi"
+		    }
+		  }
+		  interfaces: {
+		    key: "eth1"
+		    value: {}
+		  }
+		}
+		nodes: {
+		  name: "node3"
+		  vendor: ARISTA
+		  interfaces: {
+		    key: "eth1"
+		    value: {}
+		  }
+		  interfaces: {
+		    key: "eth2"
+		    value: {}
+		  }
+		  interfaces: {
+		    key: "eth3"
+		    value: {}
+		  }
+		}
+		nodes: {
+		  name: "node4"
+		  vendor: KEYSIGHT
+		}`)
+
+	tests := []struct {
+		desc     string
+		dev      *opb.Device
+		isATE    bool
+		wantNode string
+	}{{
+		desc:     "gRIBI",
+		dev:      &opb.Device{Id: "dut", Capabilities: &opb.Capabilities{Gribi: true}},
+		wantNode: "node2",
+	}, {
+		desc: "port count",
+		dev: &opb.Device{
+			Id:    "dut",
+			Ports: []*opb.Port{{Id: "port1"}},
+			Capabilities: &opb.Capabilities{PortCounts: []*opb.Capabilities_PortCount{
+				{Count: 3},
+			}},
+		},
+		wantNode: "node3",
+	}, {
+		desc:     "OTG",
+		dev:      &opb.Device{Id: "ate", Capabilities: &opb.Capabilities{AteApi: opb.Capabilities_OTG}},
+		isATE:    true,
+		wantNode: "node4",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			tb := &opb.Testbed{Duts: []*opb.Device{test.dev}}
+			if test.isATE {
+				tb = &opb.Testbed{Ates: []*opb.Device{test.dev}}
+			}
+			res, err := Solve(context.Background(), tb, topo, nil)
+			if err != nil {
+				t.Fatalf("Solve() got unexpected error: %v", err)
+			}
+			var got binding.Device = res.DUTs[test.dev.GetId()]
+			if test.isATE {
+				got = res.ATEs[test.dev.GetId()]
+			}
+			if got.Name() != test.wantNode {
+				t.Errorf("Solve() reserved node %q, want %q", got.Name(), test.wantNode)
+			}
+			if n := len(got.Ports()); n != len(test.dev.GetPorts()) {
+				t.Errorf("Solve() reserved %d ports, want %d", n, len(test.dev.GetPorts()))
+			}
+		})
+	}
+}
+
+func TestSolveObjective(t *testing.T) {
+	topo := unmarshalTopo(t, `
+		nodes: {
+		  name: "node1"
+		  vendor: ARISTA
+		  model: "big"
+		}
+		nodes: {
+		  name: "node2"
+		  vendor: ARISTA
+		  model: "small"
+		}
+		nodes: {
+		  name: "node3"
+		  vendor: ARISTA
+		  model: "big"
+		}`)
+	tb := &opb.Testbed{Duts: []*opb.Device{{Id: "dut"}}}
+	obj := &portgraph.Objective{NodeCosts: []portgraph.NodeCost{
+		portgraph.NodeAttrCost(hwAttr, map[string]float64{"big": 10}),
+	}}
+	res, err := Solve(context.Background(), tb, topo, nil, WithObjective(obj))
+	if err != nil {
+		t.Fatalf("Solve() got unexpected error: %v", err)
+	}
+	if got, want := res.DUTs["dut"].Name(), "node2"; got != want {
+		t.Errorf("Solve() reserved node %q, want %q", got, want)
+	}
+}
+
 func TestSolveErrors(t *testing.T) {
 	tests := []struct {
 		desc    string
@@ -879,6 +1049,97 @@ func TestSolveErrors(t *testing.T) {
 			  vendor: CISCO
 			}`,
 		wantErr: "Node \"dut1\" was not assigned",
+	}, {
+		desc: "no match for DUT - no gRIBI",
+		tb: &opb.Testbed{
+			Duts: []*opb.Device{{
+				Id:           "dut1",
+				Capabilities: &opb.Capabilities{Gribi: true},
+			}},
+		},
+		topo: `
+			nodes: {
+			  name: "node1"
+			  vendor: ARISTA
+			  services: {
+			    key: 1234
+			    value: {
+			      name: "gnmi"
+			    }
+			  }
+			}`,
+		wantErr: "Node \"dut1\" was not assigned",
+	}, {
+		desc: "no match for DUT - breakout",
+		tb: &opb.Testbed{
+			Duts: []*opb.Device{{
+				Id:           "dut1",
+				Capabilities: &opb.Capabilities{Breakout: true},
+			}},
+		},
+		topo: `
+			nodes: {
+			  name: "node1"
+			  vendor: ARISTA
+			}`,
+		wantErr: "Node \"dut1\" was not assigned",
+	}, {
+		desc: "no match for DUT - breakout group",
+		tb: &opb.Testbed{
+			Duts: []*opb.Device{{
+				Id:    "dut1",
+				Ports: []*opb.Port{{Id: "port1", BreakoutGroup: "bg"}},
+			}},
+		},
+		topo: `
+			nodes: {
+			  name: "node1"
+			  vendor: ARISTA
+			  interfaces: {
+			    key: "eth1"
+			    value: {}
+			  }
+			}`,
+		wantErr: "Port \"dut1:port1\" was not assigned",
+	}, {
+		desc: "no match for ATE - IxNetwork",
+		tb: &opb.Testbed{
+			Ates: []*opb.Device{{
+				Id:           "ate1",
+				Capabilities: &opb.Capabilities{AteApi: opb.Capabilities_IXNETWORK},
+			}},
+		},
+		topo: `
+			nodes: {
+			  name: "node1"
+			  vendor: KEYSIGHT
+			}`,
+		wantErr: "Node \"ate1\" was not assigned",
+	}, {
+		desc: "no match for DUT - too few ports",
+		tb: &opb.Testbed{
+			Duts: []*opb.Device{{
+				Id:    "dut1",
+				Ports: []*opb.Port{{Id: "port1"}},
+				Capabilities: &opb.Capabilities{PortCounts: []*opb.Capabilities_PortCount{
+					{Count: 3},
+				}},
+			}},
+		},
+		topo: `
+			nodes: {
+			  name: "node1"
+			  vendor: ARISTA
+			  interfaces: {
+			    key: "eth1"
+			    value: {}
+			  }
+			  interfaces: {
+			    key: "eth2"
+			    value: {}
+			  }
+			}`,
+		wantErr: "Node \"dut1\" was not assigned",
 	}, {
 		desc: "no match for DUT - wrong hardware model",
 		tb: &opb.Testbed{
@@ -25,7 +25,8 @@ import (
 // MeanRate calculates the average rate across the supplied interval
 // from the values supplied in the vals slice. It sorts the values by
 // timestamp and uses the earliest and latest to calculate the rate. Errors
-// are reported using the supplied testing.TB.
+// are reported using the supplied testing.TB. For counters that may wrap or
+// reset, use the netutil/telemetry package instead.
 func MeanRate[T constraints.Integer | constraints.Float](t testing.TB, vals []*ygnmi.Value[T]) float64 {
 	t.Helper()
 
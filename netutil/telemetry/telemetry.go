@@ -0,0 +1,246 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry provides statistics over telemetry samples, such as
+// those returned by gnmi.Collect and gnmi.CollectAll, for traffic tests.
+//
+// Counter functions handle counters that wrap around at the maximum value of
+// their type and counters that reset to zero, for example when a line card
+// restarts. A decrease of a counter is taken to be a wrap if the previous
+// value is in the upper quarter of the range of the type and the new value is
+// in the lower quarter; otherwise, it is taken to be a reset, after which the
+// counter counted up from zero to the new value.
+//
+// For example, to compute the loss between the packets an ATE flow sent and
+// the packets a DUT port received over the same period:
+//
+//	txVals, _ := gnmi.Collect(t, otg, flowTxPktsQ, time.Minute).Await(t)
+//	rxVals, _ := gnmi.Collect(t, dut, intfInPktsQ, time.Minute).Await(t)
+//	loss := telemetry.LossPct(t, txVals, rxVals)
+//
+// Samples that are not present, as when a path is deleted, are ignored.
+package telemetry
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/openconfig/ygnmi/ygnmi"
+	"golang.org/x/exp/constraints"
+)
+
+// Delta is the increase of a counter between two consecutive samples.
+type Delta struct {
+	// Start and End are the timestamps of the two samples.
+	Start, End time.Time
+	// Value is the increase of the counter.
+	Value uint64
+	// Wrapped reports whether the counter wrapped around between the samples.
+	Wrapped bool
+	// Reset reports whether the counter was reset between the samples.
+	Reset bool
+}
+
+// Deltas returns the increases of a counter between each pair of
+// consecutive present samples, in timestamp order. The input is not
+// modified. It fails fatally if there are fewer than two present samples.
+func Deltas[T constraints.Unsigned](t testing.TB, vals []*ygnmi.Value[T]) []Delta {
+	t.Helper()
+	present := sortedPresent(vals)
+	if len(present) < 2 {
+		t.Fatalf("Cannot calculate deltas from %d present values, got %v", len(present), vals)
+	}
+	max := uint64(^T(0))
+	var deltas []Delta
+	for i := 1; i < len(present); i++ {
+		prevVal, _ := present[i-1].Val()
+		curVal, _ := present[i].Val()
+		prev, cur := uint64(prevVal), uint64(curVal)
+		d := Delta{Start: present[i-1].Timestamp, End: present[i].Timestamp}
+		switch {
+		case cur >= prev:
+			d.Value = cur - prev
+		case prev > max-max/4 && cur < max/4:
+			d.Value = max - prev + cur + 1
+			d.Wrapped = true
+		default:
+			d.Value = cur
+			d.Reset = true
+		}
+		deltas = append(deltas, d)
+	}
+	return deltas
+}
+
+// Increase returns the total increase of a counter over the samples,
+// accounting for wraps and resets. It fails fatally if there are fewer than
+// two present samples.
+func Increase[T constraints.Unsigned](t testing.TB, vals []*ygnmi.Value[T]) uint64 {
+	t.Helper()
+	var total uint64
+	for _, d := range Deltas(t, vals) {
+		total += d.Value
+	}
+	return total
+}
+
+// Rate returns the average rate per second at which a counter increased over
+// the samples, accounting for wraps and resets. It fails fatally if there
+// are fewer than two present samples or they all have the same timestamp.
+func Rate[T constraints.Unsigned](t testing.TB, vals []*ygnmi.Value[T]) float64 {
+	t.Helper()
+	deltas := Deltas(t, vals)
+	secs := deltas[len(deltas)-1].End.Sub(deltas[0].Start).Seconds()
+	if secs <= 0 {
+		t.Fatalf("Cannot calculate rate from values with the same timestamp, got %v", vals)
+	}
+	var total uint64
+	for _, d := range deltas {
+		total += d.Value
+	}
+	return float64(total) / secs
+}
+
+// RateOverWindow returns the average rate per second at which a counter
+// increased over the trailing window that ends at the last present sample,
+// accounting for wraps and resets. It fails fatally if there are fewer than
+// two present samples within the window.
+func RateOverWindow[T constraints.Unsigned](t testing.TB, vals []*ygnmi.Value[T], window time.Duration) float64 {
+	t.Helper()
+	present := sortedPresent(vals)
+	if len(present) == 0 {
+		t.Fatalf("Cannot calculate rate from 0 present values, got %v", vals)
+	}
+	start := present[len(present)-1].Timestamp.Add(-window)
+	i := sort.Search(len(present), func(i int) bool {
+		return !present[i].Timestamp.Before(start)
+	})
+	if len(present)-i < 2 {
+		t.Fatalf("Cannot calculate rate from %d present values in the last %v, got %v", len(present)-i, window, vals)
+	}
+	return Rate(t, present[i:])
+}
+
+// LossPct returns the percentage of the increase of a TX counter that is
+// missing from the increase of an RX counter over the same period, such as
+// the packets sent by an ATE flow and received by a DUT port. It is negative
+// if the RX counter increased more than the TX counter. It fails fatally if
+// either counter has fewer than two present samples or the TX counter did
+// not increase.
+func LossPct[T, U constraints.Unsigned](t testing.TB, tx []*ygnmi.Value[T], rx []*ygnmi.Value[U]) float64 {
+	t.Helper()
+	txInc := Increase(t, tx)
+	rxInc := Increase(t, rx)
+	if txInc == 0 {
+		t.Fatalf("Cannot calculate loss when the TX counter did not increase, got %v", tx)
+	}
+	return (float64(txInc) - float64(rxInc)) * 100 / float64(txInc)
+}
+
+// Percentile returns the pth percentile, for p from 0 to 100, of the present
+// sample values, interpolating linearly between the closest ranks. It fails
+// fatally if there are no present samples or p is out of range.
+func Percentile[T constraints.Integer | constraints.Float](t testing.TB, vals []*ygnmi.Value[T], p float64) float64 {
+	t.Helper()
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		t.Fatalf("Percentile %v is not between 0 and 100", p)
+	}
+	var xs []float64
+	for _, v := range vals {
+		if x, ok := v.Val(); ok {
+			xs = append(xs, float64(x))
+		}
+	}
+	if len(xs) == 0 {
+		t.Fatalf("Cannot calculate percentile from 0 present values, got %v", vals)
+	}
+	sort.Float64s(xs)
+	rank := p / 100 * float64(len(xs)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return xs[lo] + (xs[hi]-xs[lo])*(rank-float64(lo))
+}
+
+// Pair is a pair of samples from two series with close timestamps.
+type Pair[T, U any] struct {
+	A *ygnmi.Value[T]
+	B *ygnmi.Value[U]
+}
+
+// Skew returns the difference between the timestamps of the samples.
+func (p Pair[T, U]) Skew() time.Duration {
+	return p.B.Timestamp.Sub(p.A.Timestamp)
+}
+
+// Align pairs the samples of two series, such as samples collected from two
+// devices, by timestamp. Each sample of a is paired with the sample of b with
+// the closest timestamp, if that is within the tolerance, and each sample of b
+// is paired at most once. Samples that cannot be paired are dropped, and the
+// pairs are in timestamp order. The inputs are not modified.
+func Align[T, U any](a []*ygnmi.Value[T], b []*ygnmi.Value[U], tolerance time.Duration) []Pair[T, U] {
+	as, bs := sortedByTime(a), sortedByTime(b)
+	var pairs []Pair[T, U]
+	j := 0
+	for _, av := range as {
+		// Skip the samples of b that are too early for this or any later
+		// sample of a.
+		for j < len(bs) && bs[j].Timestamp.Before(av.Timestamp.Add(-tolerance)) {
+			j++
+		}
+		if j == len(bs) {
+			break
+		}
+		best := j
+		for k := j + 1; k < len(bs) && !bs[k].Timestamp.After(av.Timestamp.Add(tolerance)); k++ {
+			if absDuration(bs[k].Timestamp.Sub(av.Timestamp)) < absDuration(bs[best].Timestamp.Sub(av.Timestamp)) {
+				best = k
+			}
+		}
+		if absDuration(bs[best].Timestamp.Sub(av.Timestamp)) > tolerance {
+			continue
+		}
+		pairs = append(pairs, Pair[T, U]{A: av, B: bs[best]})
+		// Samples of b before the one paired can no longer be closest to a
+		// later sample of a than that one.
+		j = best + 1
+	}
+	return pairs
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func sortedByTime[T any](vals []*ygnmi.Value[T]) []*ygnmi.Value[T] {
+	sorted := append([]*ygnmi.Value[T]{}, vals...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+	return sorted
+}
+
+func sortedPresent[T any](vals []*ygnmi.Value[T]) []*ygnmi.Value[T] {
+	var present []*ygnmi.Value[T]
+	for _, v := range sortedByTime(vals) {
+		if v.IsPresent() {
+			present = append(present, v)
+		}
+	}
+	return present
+}
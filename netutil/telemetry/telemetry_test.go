@@ -0,0 +1,170 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/testt"
+	"github.com/openconfig/ygnmi/ygnmi"
+)
+
+func val[T any](sec int, v T) *ygnmi.Value[T] {
+	return (&ygnmi.Value[T]{Timestamp: time.Unix(int64(sec), 0)}).SetVal(v)
+}
+
+func notPresent[T any](sec int) *ygnmi.Value[T] {
+	return &ygnmi.Value[T]{Timestamp: time.Unix(int64(sec), 0)}
+}
+
+func TestDeltas(t *testing.T) {
+	tests := []struct {
+		desc string
+		vals []*ygnmi.Value[uint32]
+		want []Delta
+	}{{
+		desc: "increasing and unsorted",
+		vals: []*ygnmi.Value[uint32]{val[uint32](2, 30), val[uint32](1, 10), notPresent[uint32](3), val[uint32](4, 30)},
+		want: []Delta{
+			{Start: time.Unix(1, 0), End: time.Unix(2, 0), Value: 20},
+			{Start: time.Unix(2, 0), End: time.Unix(4, 0), Value: 0},
+		},
+	}, {
+		desc: "wrap",
+		vals: []*ygnmi.Value[uint32]{val[uint32](1, math.MaxUint32-9), val[uint32](2, 5)},
+		want: []Delta{{Start: time.Unix(1, 0), End: time.Unix(2, 0), Value: 15, Wrapped: true}},
+	}, {
+		desc: "reset",
+		vals: []*ygnmi.Value[uint32]{val[uint32](1, 1000), val[uint32](2, 5), val[uint32](3, 25)},
+		want: []Delta{
+			{Start: time.Unix(1, 0), End: time.Unix(2, 0), Value: 5, Reset: true},
+			{Start: time.Unix(2, 0), End: time.Unix(3, 0), Value: 20},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := Deltas(t, test.vals)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Deltas() got unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	got := testt.ExpectFatal(t, func(t testing.TB) {
+		Deltas(t, []*ygnmi.Value[uint32]{val[uint32](1, 1), notPresent[uint32](2)})
+	})
+	if want := "Cannot calculate deltas from 1 present values"; !strings.Contains(got, want) {
+		t.Errorf("Deltas() got failure %q, want %q", got, want)
+	}
+}
+
+func TestRates(t *testing.T) {
+	vals := []*ygnmi.Value[uint64]{
+		val[uint64](0, 0),
+		val[uint64](10, 100),
+		val[uint64](20, 10), // reset
+		val[uint64](30, 410),
+	}
+	if got, want := Increase(t, vals), uint64(510); got != want {
+		t.Errorf("Increase() got %d, want %d", got, want)
+	}
+	if got, want := Rate(t, vals), 17.0; got != want {
+		t.Errorf("Rate() got %v, want %v", got, want)
+	}
+	if got, want := RateOverWindow(t, vals, 10*time.Second), 40.0; got != want {
+		t.Errorf("RateOverWindow() got %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		desc string
+		fn   func(t testing.TB)
+		want string
+	}{{
+		desc: "rate same timestamp",
+		fn: func(t testing.TB) {
+			Rate(t, []*ygnmi.Value[uint64]{val[uint64](1, 1), val[uint64](1, 2)})
+		},
+		want: "same timestamp",
+	}, {
+		desc: "window too short",
+		fn: func(t testing.TB) {
+			RateOverWindow(t, vals, 5*time.Second)
+		},
+		want: "from 1 present values in the last 5s",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := testt.ExpectFatal(t, test.fn); !strings.Contains(got, test.want) {
+				t.Errorf("got failure %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLossPct(t *testing.T) {
+	tx := []*ygnmi.Value[uint64]{val[uint64](0, 1000), val[uint64](10, 2000)}
+	rx := []*ygnmi.Value[uint32]{val[uint32](1, math.MaxUint32-49), val[uint32](11, 925)}
+	if got, want := LossPct(t, tx, rx), 2.5; got != want {
+		t.Errorf("LossPct() got %v, want %v", got, want)
+	}
+	got := testt.ExpectFatal(t, func(t testing.TB) {
+		LossPct(t, []*ygnmi.Value[uint64]{val[uint64](0, 5), val[uint64](1, 5)}, rx)
+	})
+	if want := "TX counter did not increase"; !strings.Contains(got, want) {
+		t.Errorf("LossPct() got failure %q, want %q", got, want)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	vals := []*ygnmi.Value[float64]{val(0, 4.0), val(1, 1.0), notPresent[float64](2), val(3, 3.0), val(4, 2.0)}
+	tests := []struct {
+		p, want float64
+	}{
+		{p: 0, want: 1},
+		{p: 50, want: 2.5},
+		{p: 90, want: 3.7},
+		{p: 100, want: 4},
+	}
+	for _, test := range tests {
+		if got := Percentile(t, vals, test.p); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("Percentile(%v) got %v, want %v", test.p, got, test.want)
+		}
+	}
+	for _, p := range []float64{-1, 101} {
+		got := testt.ExpectFatal(t, func(t testing.TB) {
+			Percentile(t, vals, p)
+		})
+		if want := "not between 0 and 100"; !strings.Contains(got, want) {
+			t.Errorf("Percentile(%v) got failure %q, want %q", p, got, want)
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	a := []*ygnmi.Value[uint64]{val[uint64](10, 1), val[uint64](0, 0), val[uint64](20, 2), val[uint64](30, 3)}
+	b := []*ygnmi.Value[uint32]{val[uint32](1, 0), val[uint32](9, 1), val[uint32](12, 1), val[uint32](50, 3)}
+	got := Align(a, b, 2*time.Second)
+	want := []Pair[uint64, uint32]{{A: a[1], B: b[0]}, {A: a[0], B: b[1]}}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(ygnmi.Value[uint64]{}, ygnmi.Value[uint32]{})); diff != "" {
+		t.Errorf("Align() got unexpected diff (-want +got):\n%s", diff)
+	}
+	if got, want := got[1].Skew(), -time.Second; got != want {
+		t.Errorf("Skew() got %v, want %v", got, want)
+	}
+}
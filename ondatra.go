@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"testing"
 	"time"
 
@@ -34,8 +35,11 @@ import (
 	"github.com/openconfig/ondatra/internal/flags"
 	"github.com/openconfig/ondatra/internal/junitxml"
 	"github.com/openconfig/ondatra/internal/rawapis"
+	"github.com/openconfig/ondatra/internal/record"
 	"github.com/openconfig/ondatra/internal/testbed"
+	"github.com/openconfig/ondatra/internal/trace"
 	"github.com/openconfig/ondatra/report"
+	"github.com/openconfig/ondatra/secrets"
 	"golang.org/x/sys/unix"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
@@ -45,9 +49,10 @@ var sigc = make(chan os.Signal, 1)
 
 // RunTests acquires the testbed of devices and runs the tests. Every device is
 // initialized with a baseline configuration that allows it to be managed.
-func RunTests(m *testing.M, newBindFn func() (binding.Binding, error)) {
+func RunTests(m *testing.M, newBindFn func() (binding.Binding, error), opts ...Option) {
+	checkHealthOnStart(m)
 	// Careful to only exit at the very end, because exiting skips all pending defers.
-	if err := runTests(m.Run, newBindFn); err != nil {
+	if err := runTests(m.Run, newBindFn, opts...); err != nil {
 		// If runTests returns an error, no test cases will be executed and the XML
 		// result file will be empty. To avoid user confusion over empty XML
 		// results, print the output of a fake TestMain test case, so that the
@@ -61,31 +66,65 @@ func RunTests(m *testing.M, newBindFn func() (binding.Binding, error)) {
 	}
 }
 
-func runTests(runFn func() int, newBindFn func() (binding.Binding, error)) (rerr error) {
+func runTests(runFn func() int, newBindFn func() (binding.Binding, error), opts ...Option) (rerr error) {
 	flagVals, err := flags.Parse()
 	if err != nil {
 		return err
 	}
-	bind, err := newBindFn()
-	if err != nil {
-		return fmt.Errorf("failed to create binding: %w", err)
+	ro := new(runOpts)
+	for _, opt := range opts {
+		opt(ro)
+	}
+	setHealthChecks(ro.healthCfg)
+	if flagVals.Secrets != nil {
+		secrets.SetProvider(flagVals.Secrets)
+	}
+	numTestbeds := len(flagVals.TestbedPaths)
+	if ro.shards > 0 {
+		if numTestbeds > 1 {
+			return fmt.Errorf("cannot shard %d testbeds; specify a single testbed to shard", numTestbeds)
+		}
+		numTestbeds = ro.shards
+	}
+	if numTestbeds > 1 && flagVals.RecordPath != "" {
+		return fmt.Errorf("cannot record the traffic of %d testbeds; specify a single testbed to record", numTestbeds)
 	}
-	testbed.SetBinding(bind)
+	var binds []binding.Binding
+	for i := 0; i < max(numTestbeds, 1); i++ {
+		bind, err := newBindFn()
+		if err != nil {
+			return fmt.Errorf("failed to create binding: %w", err)
+		}
+		binds = append(binds, bind)
+	}
+	testbed.SetBindings(binds...)
 
 	if flagVals.XMLPath != "" {
 		if err := junitxml.StartConverting(flagVals.XMLPath); err != nil {
 			return fmt.Errorf("error starting JUnit XML converter: %w", err)
 		}
 	}
+	if flagVals.TraceFormat != "" {
+		trace.Enable()
+		defer closer.Close(&rerr, func() error {
+			return trace.Write(flagVals.TracePath, flagVals.TraceFormat)
+		}, "error writing trace")
+	}
 
 	events.TestStarted(flagVals.Debug)
 	ctx := context.Background()
-	if err := testbed.Reserve(ctx, flagVals); err != nil {
+	span := trace.Start(nil, "ondatra.Reserve")
+	err = testbed.Reserve(ctx, flagVals)
+	span.End(err)
+	if err != nil {
 		return err
 	}
 	go releaseOnSignal(ctx)
 	defer closer.Close(&rerr, func() error {
-		return testbed.Release(ctx)
+		span := trace.Start(nil, "ondatra.Release")
+		err := testbed.Release(ctx)
+		span.End(err)
+		return err
 	}, "error releasing testbed")
 
 	var exitCode *int
@@ -93,14 +132,32 @@ func runTests(runFn func() int, newBindFn func() (binding.Binding, error)) (rerr
 		return events.TestsDone(exitCode)
 	}, "error notifying tests are done")
 
+	if flagVals.RecordPath != "" {
+		res, err := testbed.Reservation()
+		if err != nil {
+			return err
+		}
+		if err := record.Start(flagVals.RecordPath, res); err != nil {
+			return err
+		}
+		defer closer.Close(&rerr, record.Stop, "error stopping recording")
+	}
+
+	if numTestbeds > 1 {
+		if err := reportTestbeds(); err != nil {
+			return err
+		}
+	}
 	if err := events.ReservationDone(); err != nil {
 		return err
 	}
 	if flagVals.RunTime > 0 {
-		go func() {
-			time.Sleep(flagVals.RunTime)
-			log.Exitf("Ondatra test timed out after %v", flagVals.RunTime)
-		}()
+		startRunTimer(flagVals.RunTime)
+		defer stopRunTimer()
+	}
+	if testbed.CanRenew() && flagVals.HeartbeatInterval > 0 {
+		stop := startHeartbeats(ctx, flagVals.HeartbeatInterval)
+		defer stop()
 	}
 	code := runFn()
 	exitCode = &code
@@ -113,6 +170,72 @@ func runTests(runFn func() int, newBindFn func() (binding.Binding, error)) (rerr
 	return nil
 }
 
+// runTimer exits the test once the run time of the reservation elapses.
+var runTimer struct {
+	mu       sync.Mutex
+	timer    *time.Timer
+	deadline time.Time
+}
+
+func startRunTimer(runTime time.Duration) {
+	runTimer.mu.Lock()
+	defer runTimer.mu.Unlock()
+	start := time.Now()
+	runTimer.deadline = start.Add(runTime)
+	runTimer.timer = time.AfterFunc(runTime, func() {
+		log.Exitf("Ondatra test timed out after %v", time.Since(start).Round(time.Second))
+	})
+}
+
+func stopRunTimer() {
+	runTimer.mu.Lock()
+	defer runTimer.mu.Unlock()
+	if runTimer.timer != nil {
+		runTimer.timer.Stop()
+		runTimer.timer = nil
+	}
+}
+
+// extendRunTimer pushes back the run time deadline, if one is set, so that it
+// is at least the specified duration from now.
+func extendRunTimer(d time.Duration) {
+	runTimer.mu.Lock()
+	defer runTimer.mu.Unlock()
+	if runTimer.timer == nil {
+		return
+	}
+	if deadline := time.Now().Add(d); deadline.After(runTimer.deadline) {
+		runTimer.deadline = deadline
+		runTimer.timer.Reset(d)
+	}
+}
+
+// startHeartbeats periodically sends a heartbeat for the reservation until the
+// returned function is called.
+func startHeartbeats(ctx context.Context, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := testbed.Heartbeat(ctx); err != nil && ctx.Err() == nil {
+					events.RenewalFailed(err)
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
 func releaseOnSignal(ctx context.Context) {
 	signal.Notify(sigc, unix.SIGINT, unix.SIGTERM)
 	s := <-sigc
@@ -139,10 +262,11 @@ func Debug() *debug.Debug {
 
 func checkRes(t testing.TB) *binding.Reservation {
 	t.Helper()
-	res, err := testbed.Reservation()
+	res, err := testbed.ReservationFor(topLevelTest(t))
 	if err != nil {
 		t.Fatal(err)
 	}
+	checkHealth(t, res)
 	return res
 }
 
@@ -208,6 +332,23 @@ func newATE(id string, res binding.ATE) *ATEDevice {
 	}}
 }
 
+// ExtendReservation extends the reservation of the testbed, so that it is held
+// for at least the specified duration from now. If the test has a run time
+// limit, the limit is extended to at least the same time.
+// Fails the test if the binding does not support renewing reservations.
+func ExtendReservation(t testing.TB, d time.Duration) {
+	t.Helper()
+	checkRes(t)
+	if err := testbed.CheckExtend(d); err != nil {
+		t.Fatalf("ExtendReservation(t, %v): %v", d, err)
+	}
+	if err := testbed.Extend(context.Background(), d); err != nil {
+		events.RenewalFailed(err)
+		t.Fatalf("ExtendReservation(t, %v): %v", d, err)
+	}
+	extendRunTimer(d)
+}
+
 // ReservationID returns the reservation ID for the testbed.
 func ReservationID(t testing.TB) string {
 	t.Helper()
@@ -27,6 +27,9 @@ import (
 
 	"github.com/openconfig/ondatra/binding"
 	"github.com/openconfig/ondatra/fakebind"
+	"github.com/openconfig/ondatra/internal/flags"
+	"github.com/openconfig/ondatra/internal/health"
+	"github.com/openconfig/ondatra/internal/testbed"
 	"github.com/openconfig/testt"
 
 	opb "github.com/openconfig/ondatra/proto"
@@ -107,9 +110,10 @@ func TestGetters(t *testing.T) {
 					HardwareModel:   "fakeDUTModel",
 					SoftwareVersion: "fakeDUTVersion",
 					Ports: map[string]*binding.Port{portID: &binding.Port{
-						CardModel: "fakeDUTCardModel",
-						PMD:       opb.Port_PMD_10GBASE_LR,
-						Speed:     opb.Port_S_10GB,
+						CardModel:     "fakeDUTCardModel",
+						PMD:           opb.Port_PMD_10GBASE_LR,
+						Speed:         opb.Port_S_10GB,
+						BreakoutGroup: "fakeDUTPort",
 					}},
 				}},
 			},
@@ -229,6 +233,9 @@ func TestGetters(t *testing.T) {
 		if got, want := p.PMD(), PMD10GBASELR; got != want {
 			t.Errorf("pmd = %q, want %q", got, want)
 		}
+		if got, want := p.BreakoutGroup(), "fakeDUTPort"; got != want {
+			t.Errorf("breakout group = %q, want %q", got, want)
+		}
 	})
 
 	t.Run("DUT Port failure", func(t *testing.T) {
@@ -259,6 +266,9 @@ func TestGetters(t *testing.T) {
 		if got, want := p.PMD(), PMD100GBASEFR; got != want {
 			t.Errorf("pmd = %q, want %q", got, want)
 		}
+		if got, want := p.BreakoutGroup(), ""; got != want {
+			t.Errorf("breakout group = %q, want %q", got, want)
+		}
 	})
 
 	t.Run("ATE Port failure", func(t *testing.T) {
@@ -272,3 +282,101 @@ func TestGetters(t *testing.T) {
 		}
 	})
 }
+
+func TestExtendReservation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		bind := fakebind.SetupRenewable()
+		bind.WithReservation(new(binding.Reservation))
+		var gotDur time.Duration
+		bind.ExtendFn = func(_ context.Context, d time.Duration) error {
+			gotDur = d
+			return nil
+		}
+		ExtendReservation(t, time.Hour)
+		if gotDur != time.Hour {
+			t.Errorf("ExtendReservation() extended by %v, want %v", gotDur, time.Hour)
+		}
+	})
+
+	t.Run("not renewer", func(t *testing.T) {
+		fakebind.Setup().WithReservation(new(binding.Reservation))
+		got := testt.ExpectFatal(t, func(t testing.TB) {
+			ExtendReservation(t, time.Hour)
+		})
+		if want := "does not support"; !strings.Contains(got, want) {
+			t.Errorf("ExtendReservation() failed with message %q, want %q", got, want)
+		}
+	})
+}
+
+func TestHealthChecks(t *testing.T) {
+	dut := &fakebind.DUT{
+		AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{Name: "fakeDUT.net"}},
+		DialCLIFn: func(context.Context) (binding.CLIClient, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	fakebind.Setup().WithReservation(&binding.Reservation{DUTs: map[string]binding.DUT{"dut": dut}})
+	setHealthChecks(&health.Config{CLI: true})
+	defer setHealthChecks(nil)
+
+	got := testt.ExpectFatal(t, func(t testing.TB) {
+		DUT(t, "dut")
+	})
+	if want := "connection refused"; !strings.Contains(got, want) {
+		t.Errorf("DUT() failed with message %q, want %q", got, want)
+	}
+	// The checks only run once per top-level test.
+	DUT(t, "dut")
+}
+
+func TestAcquiredTestbed(t *testing.T) {
+	tbFile, err := os.CreateTemp(t.TempDir(), "*.textproto")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	if _, err := tbFile.WriteString(`duts{id:"dut"}`); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+	if err := tbFile.Close(); err != nil {
+		t.Errorf("Failed to close temp file: %v", err)
+	}
+	var binds []binding.Binding
+	for _, id := range []string{"res1", "res2"} {
+		id := id
+		binds = append(binds, &fakebind.Binding{
+			ReserveFn: func(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+				return &binding.Reservation{ID: id, DUTs: map[string]binding.DUT{
+					"dut": &binding.AbstractDUT{Dims: &binding.Dims{Name: id + "DUT"}},
+				}}, nil
+			},
+			ReleaseFn: func(context.Context) error { return nil },
+		})
+	}
+	testbed.SetBindings(binds...)
+	ctx := context.Background()
+	if err := testbed.Reserve(ctx, &flags.Values{TestbedPath: tbFile.Name()}); err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	defer testbed.Release(ctx)
+
+	// Occupy the first testbed, so the test is assigned the second one.
+	releaseOther, err := testbed.Acquire(ctx, "TestOther")
+	if err != nil {
+		t.Fatalf("Acquire() got unexpected error: %v", err)
+	}
+	defer releaseOther()
+	release, err := testbed.Acquire(ctx, t.Name())
+	if err != nil {
+		t.Fatalf("Acquire() got unexpected error: %v", err)
+	}
+	defer release()
+	t.Run("subtest", func(t *testing.T) {
+		if got, want := ReservationID(t), "res2"; got != want {
+			t.Errorf("ReservationID() got %q, want %q", got, want)
+		}
+		if got, want := DUT(t, "dut").Name(), "res2DUT"; got != want {
+			t.Errorf("DUT().Name() got %q, want %q", got, want)
+		}
+	})
+}
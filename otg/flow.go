@@ -0,0 +1,117 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otg
+
+import (
+	"fmt"
+
+	"github.com/openconfig/ondatra/gnmi"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ondatra/gnmi/oc/ateflow"
+	"github.com/openconfig/ygnmi/ygnmi"
+
+	otgtelem "github.com/openconfig/ondatra/gnmi/otg"
+	otgflow "github.com/openconfig/ondatra/gnmi/otg/flow"
+)
+
+// The functions below map the flow telemetry of the IxNetwork API, at
+// gnmi.OC().Flow(), to the flow telemetry of OTG, at gnmi.OTG().Flow(), and
+// back. The flows of an ATE topology translated by ATETopology.OTGConfig have
+// the same names on either API, so a test can switch its telemetry queries to
+// OTG by wrapping its flow paths and converting the returned flow structs.
+
+// OTGFlowPath returns the OTG path of the flow at an OC flow path.
+func OTGFlowPath(p *ateflow.FlowPath) (*otgflow.FlowPath, error) {
+	name, err := flowName(p)
+	if err != nil {
+		return nil, err
+	}
+	return gnmi.OTG().Flow(name), nil
+}
+
+// OCFlowPath returns the OC path of the flow at an OTG flow path.
+func OCFlowPath(p *otgflow.FlowPath) (*ateflow.FlowPath, error) {
+	name, err := flowName(p)
+	if err != nil {
+		return nil, err
+	}
+	return gnmi.OC().Flow(name), nil
+}
+
+func flowName(p ygnmi.PathStruct) (string, error) {
+	path, _, err := ygnmi.ResolvePath(p)
+	if err != nil {
+		return "", err
+	}
+	elems := path.GetElem()
+	if len(elems) != 2 || elems[0].GetName() != "flows" || elems[1].GetName() != "flow" {
+		return "", fmt.Errorf("path %v is not a flow path", path)
+	}
+	name, ok := elems[1].GetKey()["name"]
+	if !ok || name == "*" {
+		return "", fmt.Errorf("path %v has no flow name", path)
+	}
+	return name, nil
+}
+
+// OTGFlow converts an OC flow to an OTG flow. Only the name, counters and
+// rates of the flow have an OTG equivalent and are converted.
+func OTGFlow(f *oc.Flow) *otgtelem.Flow {
+	if f == nil {
+		return nil
+	}
+	of := &otgtelem.Flow{
+		Name:         f.Name,
+		InFrameRate:  otgtelem.Binary(f.InFrameRate),
+		InRate:       otgtelem.Binary(f.InRate),
+		LossPct:      otgtelem.Binary(f.LossPct),
+		OutFrameRate: otgtelem.Binary(f.OutFrameRate),
+		OutRate:      otgtelem.Binary(f.OutRate),
+	}
+	if c := f.Counters; c != nil {
+		of.Counters = &otgtelem.Flow_Counters{
+			InOctets:  c.InOctets,
+			InPkts:    c.InPkts,
+			OutOctets: c.OutOctets,
+			OutPkts:   c.OutPkts,
+		}
+	}
+	return of
+}
+
+// OCFlow converts an OTG flow to an OC flow. Only the name, counters and
+// rates of the flow have an OC equivalent and are converted.
+func OCFlow(f *otgtelem.Flow) *oc.Flow {
+	if f == nil {
+		return nil
+	}
+	of := &oc.Flow{
+		Name:         f.Name,
+		InFrameRate:  oc.Binary(f.InFrameRate),
+		InRate:       oc.Binary(f.InRate),
+		LossPct:      oc.Binary(f.LossPct),
+		OutFrameRate: oc.Binary(f.OutFrameRate),
+		OutRate:      oc.Binary(f.OutRate),
+	}
+	if c := f.Counters; c != nil {
+		of.Counters = &oc.Flow_Counters{
+			InOctets:  c.InOctets,
+			InPkts:    c.InPkts,
+			OutOctets: c.OutOctets,
+			OutPkts:   c.OutPkts,
+		}
+	}
+	return of
+}
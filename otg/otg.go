@@ -34,6 +34,7 @@ import (
 	"github.com/openconfig/ondatra/gnmi"
 	"github.com/openconfig/ondatra/internal/events"
 	"github.com/openconfig/ondatra/internal/rawapis"
+	"github.com/openconfig/ondatra/internal/trace"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 )
@@ -60,7 +61,9 @@ func (o *OTG) String() string {
 func (o *OTG) PushConfig(t testing.TB, cfg gosnappi.Config) {
 	t.Helper()
 	t = events.ActionStarted(t, "Pushing config to %s", o.ate)
+	span := trace.Start(t, "otg.PushConfig", "device", o.ate.Name())
 	warns, err := pushConfig(context.Background(), o.ate, cfg)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("PushConfig(t) on %s: %v", o.ate, err)
 	}
@@ -96,7 +99,9 @@ func pushConfig(ctx context.Context, ate binding.ATE, cfg gosnappi.Config) ([]st
 func (o *OTG) GetConfig(t testing.TB) gosnappi.Config {
 	t.Helper()
 	t = events.ActionStarted(t, "Getting config from %s", o.ate)
+	span := trace.Start(t, "otg.GetConfig", "device", o.ate.Name())
 	cfg, err := getConfig(context.Background(), o.ate)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("GetConfig(t) on %s: %v", o.ate, err)
 	}
@@ -127,7 +132,9 @@ func getConfig(ctx context.Context, ate binding.ATE) (gosnappi.Config, error) {
 func (o *OTG) StartProtocols(t testing.TB) {
 	t.Helper()
 	t = events.ActionStarted(t, "Starting protocols on %s", o.ate)
+	span := trace.Start(t, "otg.StartProtocols", "device", o.ate.Name())
 	warns, err := o.setProtocolState(context.Background(), gosnappi.StateProtocolAllState.START)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("StartProtocols(t) on %s: %v", o.ate, err)
 	}
@@ -140,7 +147,9 @@ func (o *OTG) StartProtocols(t testing.TB) {
 func (o *OTG) StopProtocols(t testing.TB) {
 	t.Helper()
 	t = events.ActionStarted(t, "Stopping protocols on %s", o.ate)
+	span := trace.Start(t, "otg.StopProtocols", "device", o.ate.Name())
 	warns, err := o.setProtocolState(context.Background(), gosnappi.StateProtocolAllState.STOP)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("StopProtocols(t) on %s: %v", o.ate, err)
 	}
@@ -165,7 +174,9 @@ func (o *OTG) StartTraffic(t testing.TB) {
 	// TODO(greg-dennis): Remove sleep when Keysight fixes a MAC resolution bug.
 	time.Sleep(2 * time.Second)
 	t = events.ActionStarted(t, "Starting traffic on %s", o.ate)
+	span := trace.Start(t, "otg.StartTraffic", "device", o.ate.Name())
 	warns, err := o.setTransmitState(context.Background(), gosnappi.StateTrafficFlowTransmitState.START)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("StartTraffic(t) on %s: %v", o.ate, err)
 	}
@@ -178,7 +189,9 @@ func (o *OTG) StartTraffic(t testing.TB) {
 func (o *OTG) StopTraffic(t testing.TB) {
 	t.Helper()
 	t = events.ActionStarted(t, "Stopping traffic on %s", o.ate)
+	span := trace.Start(t, "otg.StopTraffic", "device", o.ate.Name())
 	warns, err := o.setTransmitState(context.Background(), gosnappi.StateTrafficFlowTransmitState.STOP)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("StopTraffic(t) on %s: %v", o.ate, err)
 	}
@@ -201,7 +214,9 @@ func (o *OTG) setTransmitState(ctx context.Context, state gosnappi.StateTrafficF
 func (o *OTG) SetControlState(t testing.TB, state gosnappi.ControlState) {
 	t.Helper()
 	t = events.ActionStarted(t, "SetControlState on %v", o.ate)
+	span := trace.Start(t, "otg.SetControlState", "device", o.ate.Name())
 	resp, err := o.setControlState(context.Background(), state)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("SetControlState(t) on %s: %v", o.ate, err)
 	}
@@ -222,7 +237,9 @@ func (o *OTG) setControlState(ctx context.Context, state gosnappi.ControlState)
 func (o *OTG) SetControlAction(t testing.TB, action gosnappi.ControlAction) {
 	t.Helper()
 	t = events.ActionStarted(t, "SetControlAction on %v", o.ate)
+	span := trace.Start(t, "otg.SetControlAction", "device", o.ate.Name())
 	resp, err := o.setControlAction(action)
+	span.End(err)
 	if err != nil {
 		t.Fatalf("SetControlAction(t) on %s: %v", o.ate, err)
 	}
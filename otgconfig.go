@@ -0,0 +1,69 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ondatra
+
+import (
+	"testing"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/ondatra/internal/ateotg"
+	"github.com/openconfig/ondatra/internal/events"
+	"github.com/openconfig/ondatra/otg"
+
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// OTGConfig translates the topology and the specified flows to an Open
+// Traffic Generator config, so that tests written against the IxNetwork API
+// can run on OTG-based ATEs. Each interface becomes an OTG device with the
+// interface's name, and each flow an OTG flow with the flow's name, so the
+// telemetry of the flows is at the same keys on either API.
+//
+// Only the subset of the IxNetwork API with a direct OTG equivalent can be
+// translated; the test fails if the topology or flows use anything else.
+func (at *ATETopology) OTGConfig(t testing.TB, flows ...*Flow) gosnappi.Config {
+	t.Helper()
+	cfg, err := at.otgConfig(flows)
+	if err != nil {
+		t.Fatalf("OTGConfig(t) on %s: %v", at, err)
+	}
+	return cfg
+}
+
+// PushOTG translates the topology and the specified flows to an Open Traffic
+// Generator config, as OTGConfig does, and pushes that config to the ATE.
+// The pushed config is returned, so the test can amend and push it again.
+func (at *ATETopology) PushOTG(t testing.TB, flows ...*Flow) gosnappi.Config {
+	t.Helper()
+	t = events.ActionStarted(t, "Pushing OTG topology to %s", at.ate)
+	cfg, err := at.otgConfig(flows)
+	if err != nil {
+		t.Fatalf("PushOTG(t) on %s: %v", at, err)
+	}
+	otg.New(at.ate).PushConfig(t, cfg)
+	return cfg
+}
+
+func (at *ATETopology) otgConfig(flows []*Flow) (gosnappi.Config, error) {
+	portIDs := make(map[string]string)
+	for id, p := range at.ate.Ports() {
+		portIDs[p.Name] = id
+	}
+	var pbs []*opb.Flow
+	for _, f := range flows {
+		pbs = append(pbs, f.pb)
+	}
+	return ateotg.Config(at.top.Interfaces, at.top.LAGs, pbs, portIDs)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ondatra
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ondatra/internal/testbed"
+)
+
+// WithShards reserves the testbed the specified number of times, each with a
+// binding of its own, so that tests that call Parallel run concurrently on
+// the independent reservations. To reserve several distinct testbeds instead,
+// pass a comma-separated list of testbed files to the -testbed flag.
+func WithShards(n int) Option {
+	return func(o *runOpts) {
+		o.shards = n
+	}
+}
+
+// Parallel signals that the top-level test is to be run in parallel with the
+// other top-level tests that call Parallel, each on a testbed of its own.
+//
+// Parallel calls t.Parallel and then waits until a testbed is not in use by
+// any other parallel test, so at most as many tests run at once as there are
+// testbeds. Every DUT and ATE that the test and its subtests fetch is from
+// that testbed. Tests that do not call Parallel run before the parallel
+// tests, on the first testbed.
+func Parallel(t *testing.T) {
+	t.Helper()
+	if strings.Contains(t.Name(), "/") {
+		t.Fatalf("Parallel(t) called from subtest %s, but only a top-level test can run on a testbed of its own", t.Name())
+	}
+	t.Parallel()
+	release, err := testbed.Acquire(context.Background(), t.Name())
+	if err != nil {
+		t.Fatalf("Parallel(t): %v", err)
+	}
+	t.Cleanup(release)
+	res, err := testbed.ReservationFor(t.Name())
+	if err != nil {
+		t.Fatalf("Parallel(t): %v", err)
+	}
+	Report().AddTestProperty(t, "testbed", testbed.TestbedPathFor(t.Name()))
+	Report().AddTestProperty(t, "reservation_id", res.ID)
+	checkHealth(t, res)
+}
+
+// topLevelTest returns the name of the top-level test of t.
+func topLevelTest(t testing.TB) string {
+	return strings.SplitN(t.Name(), "/", 2)[0]
+}
+
+// reportTestbeds adds the testbed file and reservation ID of every testbed
+// to the XML report, so results can be attributed to the testbed they ran on.
+func reportTestbeds() error {
+	rs, err := testbed.Reservations()
+	if err != nil {
+		return err
+	}
+	paths := testbed.TestbedPaths()
+	for i, r := range rs {
+		idx := strconv.Itoa(i)
+		Report().AddSuiteProperty("testbed:"+idx, paths[i])
+		Report().AddSuiteProperty("reservation_id:"+idx, r.ID)
+	}
+	return nil
+}
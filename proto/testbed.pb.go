@@ -112,6 +112,56 @@ func (Device_Vendor) EnumDescriptor() ([]byte, []int) {
 	return file_testbed_proto_rawDescGZIP(), []int{1, 0}
 }
 
+// API with which an ATE is controlled.
+type Capabilities_AteApi int32
+
+const (
+	Capabilities_ATE_API_UNSPECIFIED Capabilities_AteApi = 0
+	Capabilities_OTG                 Capabilities_AteApi = 1
+	Capabilities_IXNETWORK           Capabilities_AteApi = 2
+)
+
+// Enum value maps for Capabilities_AteApi.
+var (
+	Capabilities_AteApi_name = map[int32]string{
+		0: "ATE_API_UNSPECIFIED",
+		1: "OTG",
+		2: "IXNETWORK",
+	}
+	Capabilities_AteApi_value = map[string]int32{
+		"ATE_API_UNSPECIFIED": 0,
+		"OTG":                 1,
+		"IXNETWORK":           2,
+	}
+)
+
+func (x Capabilities_AteApi) Enum() *Capabilities_AteApi {
+	p := new(Capabilities_AteApi)
+	*p = x
+	return p
+}
+
+func (x Capabilities_AteApi) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Capabilities_AteApi) Descriptor() protoreflect.EnumDescriptor {
+	return file_testbed_proto_enumTypes[1].Descriptor()
+}
+
+func (Capabilities_AteApi) Type() protoreflect.EnumType {
+	return &file_testbed_proto_enumTypes[1]
+}
+
+func (x Capabilities_AteApi) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Capabilities_AteApi.Descriptor instead.
+func (Capabilities_AteApi) EnumDescriptor() ([]byte, []int) {
+	return file_testbed_proto_rawDescGZIP(), []int{2, 0}
+}
+
 // Speed of the port.
 type Port_Speed int32
 
@@ -120,8 +170,13 @@ const (
 	Port_S_1GB             Port_Speed = 1
 	Port_S_5GB             Port_Speed = 5
 	Port_S_10GB            Port_Speed = 10
+	Port_S_25GB            Port_Speed = 25
+	Port_S_40GB            Port_Speed = 40
+	Port_S_50GB            Port_Speed = 50
 	Port_S_100GB           Port_Speed = 100
+	Port_S_200GB           Port_Speed = 200
 	Port_S_400GB           Port_Speed = 400
+	Port_S_800GB           Port_Speed = 800
 )
 
 // Enum value maps for Port_Speed.
@@ -131,16 +186,26 @@ var (
 		1:   "S_1GB",
 		5:   "S_5GB",
 		10:  "S_10GB",
+		25:  "S_25GB",
+		40:  "S_40GB",
+		50:  "S_50GB",
 		100: "S_100GB",
+		200: "S_200GB",
 		400: "S_400GB",
+		800: "S_800GB",
 	}
 	Port_Speed_value = map[string]int32{
 		"SPEED_UNSPECIFIED": 0,
 		"S_1GB":             1,
 		"S_5GB":             5,
 		"S_10GB":            10,
+		"S_25GB":            25,
+		"S_40GB":            40,
+		"S_50GB":            50,
 		"S_100GB":           100,
+		"S_200GB":           200,
 		"S_400GB":           400,
+		"S_800GB":           800,
 	}
 )
 
@@ -155,11 +220,11 @@ func (x Port_Speed) String() string {
 }
 
 func (Port_Speed) Descriptor() protoreflect.EnumDescriptor {
-	return file_testbed_proto_enumTypes[1].Descriptor()
+	return file_testbed_proto_enumTypes[2].Descriptor()
 }
 
 func (Port_Speed) Type() protoreflect.EnumType {
-	return &file_testbed_proto_enumTypes[1]
+	return &file_testbed_proto_enumTypes[2]
 }
 
 func (x Port_Speed) Number() protoreflect.EnumNumber {
@@ -168,7 +233,7 @@ func (x Port_Speed) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Port_Speed.Descriptor instead.
 func (Port_Speed) EnumDescriptor() ([]byte, []int) {
-	return file_testbed_proto_rawDescGZIP(), []int{2, 0}
+	return file_testbed_proto_rawDescGZIP(), []int{3, 0}
 }
 
 // Physical medium dependent of the port.
@@ -177,36 +242,49 @@ func (Port_Speed) EnumDescriptor() ([]byte, []int) {
 type Port_Pmd int32
 
 const (
-	Port_PMD_UNSPECIFIED    Port_Pmd = 0
-	Port_PMD_10GBASE_LRM    Port_Pmd = 1
-	Port_PMD_10GBASE_LR     Port_Pmd = 2
-	Port_PMD_10GBASE_ZR     Port_Pmd = 3
-	Port_PMD_10GBASE_ER     Port_Pmd = 4
-	Port_PMD_10GBASE_SR     Port_Pmd = 5
-	Port_PMD_40GBASE_CR4    Port_Pmd = 6
-	Port_PMD_40GBASE_SR4    Port_Pmd = 7
-	Port_PMD_40GBASE_LR4    Port_Pmd = 8
-	Port_PMD_40GBASE_ER4    Port_Pmd = 9
-	Port_PMD_40GBASE_PSM4   Port_Pmd = 10
-	Port_PMD_4X10GBASE_LR   Port_Pmd = 11
-	Port_PMD_4X10GBASE_SR   Port_Pmd = 12
-	Port_PMD_100G_AOC       Port_Pmd = 13
-	Port_PMD_100G_ACC       Port_Pmd = 14
-	Port_PMD_100GBASE_SR10  Port_Pmd = 15
-	Port_PMD_100GBASE_SR4   Port_Pmd = 16
-	Port_PMD_100GBASE_LR4   Port_Pmd = 17
-	Port_PMD_100GBASE_ER4   Port_Pmd = 18
-	Port_PMD_100GBASE_CWDM4 Port_Pmd = 19
-	Port_PMD_100GBASE_CLR4  Port_Pmd = 20
-	Port_PMD_100GBASE_PSM4  Port_Pmd = 21
-	Port_PMD_100GBASE_CR4   Port_Pmd = 22
-	Port_PMD_100GBASE_FR    Port_Pmd = 23
-	Port_PMD_400GBASE_ZR    Port_Pmd = 24
-	Port_PMD_400GBASE_LR4   Port_Pmd = 25
-	Port_PMD_400GBASE_FR4   Port_Pmd = 26
-	Port_PMD_400GBASE_LR8   Port_Pmd = 27
-	Port_PMD_400GBASE_DR4   Port_Pmd = 28
-	Port_PMD_100GBASE_DR    Port_Pmd = 29
+	Port_PMD_UNSPECIFIED      Port_Pmd = 0
+	Port_PMD_10GBASE_LRM      Port_Pmd = 1
+	Port_PMD_10GBASE_LR       Port_Pmd = 2
+	Port_PMD_10GBASE_ZR       Port_Pmd = 3
+	Port_PMD_10GBASE_ER       Port_Pmd = 4
+	Port_PMD_10GBASE_SR       Port_Pmd = 5
+	Port_PMD_40GBASE_CR4      Port_Pmd = 6
+	Port_PMD_40GBASE_SR4      Port_Pmd = 7
+	Port_PMD_40GBASE_LR4      Port_Pmd = 8
+	Port_PMD_40GBASE_ER4      Port_Pmd = 9
+	Port_PMD_40GBASE_PSM4     Port_Pmd = 10
+	Port_PMD_4X10GBASE_LR     Port_Pmd = 11
+	Port_PMD_4X10GBASE_SR     Port_Pmd = 12
+	Port_PMD_100G_AOC         Port_Pmd = 13
+	Port_PMD_100G_ACC         Port_Pmd = 14
+	Port_PMD_100GBASE_SR10    Port_Pmd = 15
+	Port_PMD_100GBASE_SR4     Port_Pmd = 16
+	Port_PMD_100GBASE_LR4     Port_Pmd = 17
+	Port_PMD_100GBASE_ER4     Port_Pmd = 18
+	Port_PMD_100GBASE_CWDM4   Port_Pmd = 19
+	Port_PMD_100GBASE_CLR4    Port_Pmd = 20
+	Port_PMD_100GBASE_PSM4    Port_Pmd = 21
+	Port_PMD_100GBASE_CR4     Port_Pmd = 22
+	Port_PMD_100GBASE_FR      Port_Pmd = 23
+	Port_PMD_400GBASE_ZR      Port_Pmd = 24
+	Port_PMD_400GBASE_LR4     Port_Pmd = 25
+	Port_PMD_400GBASE_FR4     Port_Pmd = 26
+	Port_PMD_400GBASE_LR8     Port_Pmd = 27
+	Port_PMD_400GBASE_DR4     Port_Pmd = 28
+	Port_PMD_100GBASE_DR      Port_Pmd = 29
+	Port_PMD_400GBASE_ZR_PLUS Port_Pmd = 30
+	Port_PMD_800GBASE_DR8     Port_Pmd = 31
+	Port_PMD_800GBASE_SR8     Port_Pmd = 32
+	Port_PMD_800GBASE_CR8     Port_Pmd = 33
+	Port_PMD_800GBASE_2XFR4   Port_Pmd = 34
+	Port_PMD_800GBASE_ZR      Port_Pmd = 35
+	// Breakout PMDs, of the form <channels>X<channel PMD>, for a transceiver
+	// that is broken out into multiple channels, each of which is a port.
+	Port_PMD_4X25GBASE_SR   Port_Pmd = 36
+	Port_PMD_4X100GBASE_DR  Port_Pmd = 37
+	Port_PMD_4X100GBASE_FR  Port_Pmd = 38
+	Port_PMD_8X100GBASE_DR  Port_Pmd = 39
+	Port_PMD_2X400GBASE_DR4 Port_Pmd = 40
 )
 
 // Enum value maps for Port_Pmd.
@@ -242,38 +320,60 @@ var (
 		27: "PMD_400GBASE_LR8",
 		28: "PMD_400GBASE_DR4",
 		29: "PMD_100GBASE_DR",
+		30: "PMD_400GBASE_ZR_PLUS",
+		31: "PMD_800GBASE_DR8",
+		32: "PMD_800GBASE_SR8",
+		33: "PMD_800GBASE_CR8",
+		34: "PMD_800GBASE_2XFR4",
+		35: "PMD_800GBASE_ZR",
+		36: "PMD_4X25GBASE_SR",
+		37: "PMD_4X100GBASE_DR",
+		38: "PMD_4X100GBASE_FR",
+		39: "PMD_8X100GBASE_DR",
+		40: "PMD_2X400GBASE_DR4",
 	}
 	Port_Pmd_value = map[string]int32{
-		"PMD_UNSPECIFIED":    0,
-		"PMD_10GBASE_LRM":    1,
-		"PMD_10GBASE_LR":     2,
-		"PMD_10GBASE_ZR":     3,
-		"PMD_10GBASE_ER":     4,
-		"PMD_10GBASE_SR":     5,
-		"PMD_40GBASE_CR4":    6,
-		"PMD_40GBASE_SR4":    7,
-		"PMD_40GBASE_LR4":    8,
-		"PMD_40GBASE_ER4":    9,
-		"PMD_40GBASE_PSM4":   10,
-		"PMD_4X10GBASE_LR":   11,
-		"PMD_4X10GBASE_SR":   12,
-		"PMD_100G_AOC":       13,
-		"PMD_100G_ACC":       14,
-		"PMD_100GBASE_SR10":  15,
-		"PMD_100GBASE_SR4":   16,
-		"PMD_100GBASE_LR4":   17,
-		"PMD_100GBASE_ER4":   18,
-		"PMD_100GBASE_CWDM4": 19,
-		"PMD_100GBASE_CLR4":  20,
-		"PMD_100GBASE_PSM4":  21,
-		"PMD_100GBASE_CR4":   22,
-		"PMD_100GBASE_FR":    23,
-		"PMD_400GBASE_ZR":    24,
-		"PMD_400GBASE_LR4":   25,
-		"PMD_400GBASE_FR4":   26,
-		"PMD_400GBASE_LR8":   27,
-		"PMD_400GBASE_DR4":   28,
-		"PMD_100GBASE_DR":    29,
+		"PMD_UNSPECIFIED":      0,
+		"PMD_10GBASE_LRM":      1,
+		"PMD_10GBASE_LR":       2,
+		"PMD_10GBASE_ZR":       3,
+		"PMD_10GBASE_ER":       4,
+		"PMD_10GBASE_SR":       5,
+		"PMD_40GBASE_CR4":      6,
+		"PMD_40GBASE_SR4":      7,
+		"PMD_40GBASE_LR4":      8,
+		"PMD_40GBASE_ER4":      9,
+		"PMD_40GBASE_PSM4":     10,
+		"PMD_4X10GBASE_LR":     11,
+		"PMD_4X10GBASE_SR":     12,
+		"PMD_100G_AOC":         13,
+		"PMD_100G_ACC":         14,
+		"PMD_100GBASE_SR10":    15,
+		"PMD_100GBASE_SR4":     16,
+		"PMD_100GBASE_LR4":     17,
+		"PMD_100GBASE_ER4":     18,
+		"PMD_100GBASE_CWDM4":   19,
+		"PMD_100GBASE_CLR4":    20,
+		"PMD_100GBASE_PSM4":    21,
+		"PMD_100GBASE_CR4":     22,
+		"PMD_100GBASE_FR":      23,
+		"PMD_400GBASE_ZR":      24,
+		"PMD_400GBASE_LR4":     25,
+		"PMD_400GBASE_FR4":     26,
+		"PMD_400GBASE_LR8":     27,
+		"PMD_400GBASE_DR4":     28,
+		"PMD_100GBASE_DR":      29,
+		"PMD_400GBASE_ZR_PLUS": 30,
+		"PMD_800GBASE_DR8":     31,
+		"PMD_800GBASE_SR8":     32,
+		"PMD_800GBASE_CR8":     33,
+		"PMD_800GBASE_2XFR4":   34,
+		"PMD_800GBASE_ZR":      35,
+		"PMD_4X25GBASE_SR":     36,
+		"PMD_4X100GBASE_DR":    37,
+		"PMD_4X100GBASE_FR":    38,
+		"PMD_8X100GBASE_DR":    39,
+		"PMD_2X400GBASE_DR4":   40,
 	}
 )
 
@@ -288,11 +388,11 @@ func (x Port_Pmd) String() string {
 }
 
 func (Port_Pmd) Descriptor() protoreflect.EnumDescriptor {
-	return file_testbed_proto_enumTypes[2].Descriptor()
+	return file_testbed_proto_enumTypes[3].Descriptor()
 }
 
 func (Port_Pmd) Type() protoreflect.EnumType {
-	return &file_testbed_proto_enumTypes[2]
+	return &file_testbed_proto_enumTypes[3]
 }
 
 func (x Port_Pmd) Number() protoreflect.EnumNumber {
@@ -301,7 +401,7 @@ func (x Port_Pmd) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Port_Pmd.Descriptor instead.
 func (Port_Pmd) EnumDescriptor() ([]byte, []int) {
-	return file_testbed_proto_rawDescGZIP(), []int{2, 1}
+	return file_testbed_proto_rawDescGZIP(), []int{3, 1}
 }
 
 // A testbed.
@@ -406,6 +506,8 @@ type Device struct {
 	//   value: "foo",
 	// }
 	ExtraDimensions map[string]string `protobuf:"bytes,6,rep,name=extra_dimensions,json=extraDimensions,proto3" json:"extra_dimensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Capabilities that the reserved device is required to have.
+	Capabilities *Capabilities `protobuf:"bytes,9,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 }
 
 func (x *Device) Reset() {
@@ -510,6 +612,13 @@ func (x *Device) GetExtraDimensions() map[string]string {
 	return nil
 }
 
+func (x *Device) GetCapabilities() *Capabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
 type isDevice_HardwareModelValue interface {
 	isDevice_HardwareModelValue()
 }
@@ -542,6 +651,95 @@ func (*Device_SoftwareVersion) isDevice_SoftwareVersionValue() {}
 
 func (*Device_SoftwareVersionRegex) isDevice_SoftwareVersionValue() {}
 
+// Capabilities of a device.
+//
+// In a testbed, the capabilities are requirements that a reserved device must
+// satisfy; unset fields impose no requirement. When reported by a binding for
+// a reserved device, the capabilities are those that the device actually has.
+type Capabilities struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether the device supports gRIBI.
+	Gribi bool `protobuf:"varint,1,opt,name=gribi,proto3" json:"gribi,omitempty"`
+	// Whether the device supports P4Runtime.
+	P4Rt bool `protobuf:"varint,2,opt,name=p4rt,proto3" json:"p4rt,omitempty"`
+	// Whether the device supports breaking out ports into multiple ports.
+	Breakout bool `protobuf:"varint,3,opt,name=breakout,proto3" json:"breakout,omitempty"`
+	// API of the ATE. Only valid for ATEs.
+	AteApi Capabilities_AteApi `protobuf:"varint,4,opt,name=ate_api,json=ateApi,proto3,enum=ondatra.Capabilities_AteApi" json:"ate_api,omitempty"`
+	// Numbers of ports of given speeds. At most one count per speed.
+	PortCounts []*Capabilities_PortCount `protobuf:"bytes,5,rep,name=port_counts,json=portCounts,proto3" json:"port_counts,omitempty"`
+}
+
+func (x *Capabilities) Reset() {
+	*x = Capabilities{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_testbed_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Capabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capabilities) ProtoMessage() {}
+
+func (x *Capabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_testbed_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capabilities.ProtoReflect.Descriptor instead.
+func (*Capabilities) Descriptor() ([]byte, []int) {
+	return file_testbed_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Capabilities) GetGribi() bool {
+	if x != nil {
+		return x.Gribi
+	}
+	return false
+}
+
+func (x *Capabilities) GetP4Rt() bool {
+	if x != nil {
+		return x.P4Rt
+	}
+	return false
+}
+
+func (x *Capabilities) GetBreakout() bool {
+	if x != nil {
+		return x.Breakout
+	}
+	return false
+}
+
+func (x *Capabilities) GetAteApi() Capabilities_AteApi {
+	if x != nil {
+		return x.AteApi
+	}
+	return Capabilities_ATE_API_UNSPECIFIED
+}
+
+func (x *Capabilities) GetPortCounts() []*Capabilities_PortCount {
+	if x != nil {
+		return x.PortCounts
+	}
+	return nil
+}
+
 // A port.
 //
 // All "regex" fields expect an RE2 regular expression and match a port
@@ -569,12 +767,19 @@ type Port struct {
 	// hint is necessary to create a LAG, because a LAG requires additional
 	// topological constraints that can't otherwise be expressed in the testbed.
 	Group string `protobuf:"bytes,6,opt,name=group,proto3" json:"group,omitempty"`
+	// A requirement that the port be a channel of a physical port that is
+	// broken out into multiple ports. All ports on a device with the same
+	// breakout group are required to be channels of the same physical port, and
+	// ports in different breakout groups channels of different physical ports.
+	// For example, four 100G ports in the same breakout group may be reserved as
+	// the channels of a 400G port in 4x100G breakout mode.
+	BreakoutGroup string `protobuf:"bytes,7,opt,name=breakout_group,json=breakoutGroup,proto3" json:"breakout_group,omitempty"`
 }
 
 func (x *Port) Reset() {
 	*x = Port{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testbed_proto_msgTypes[2]
+		mi := &file_testbed_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -587,7 +792,7 @@ func (x *Port) String() string {
 func (*Port) ProtoMessage() {}
 
 func (x *Port) ProtoReflect() protoreflect.Message {
-	mi := &file_testbed_proto_msgTypes[2]
+	mi := &file_testbed_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -600,7 +805,7 @@ func (x *Port) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Port.ProtoReflect.Descriptor instead.
 func (*Port) Descriptor() ([]byte, []int) {
-	return file_testbed_proto_rawDescGZIP(), []int{2}
+	return file_testbed_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Port) GetId() string {
@@ -666,6 +871,13 @@ func (x *Port) GetGroup() string {
 	return ""
 }
 
+func (x *Port) GetBreakoutGroup() string {
+	if x != nil {
+		return x.BreakoutGroup
+	}
+	return ""
+}
+
 type isPort_CardModelValue interface {
 	isPort_CardModelValue()
 }
@@ -714,7 +926,7 @@ type Link struct {
 func (x *Link) Reset() {
 	*x = Link{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testbed_proto_msgTypes[3]
+		mi := &file_testbed_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -727,7 +939,7 @@ func (x *Link) String() string {
 func (*Link) ProtoMessage() {}
 
 func (x *Link) ProtoReflect() protoreflect.Message {
-	mi := &file_testbed_proto_msgTypes[3]
+	mi := &file_testbed_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -740,7 +952,7 @@ func (x *Link) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Link.ProtoReflect.Descriptor instead.
 func (*Link) Descriptor() ([]byte, []int) {
-	return file_testbed_proto_rawDescGZIP(), []int{3}
+	return file_testbed_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Link) GetA() string {
@@ -757,6 +969,65 @@ func (x *Link) GetB() string {
 	return ""
 }
 
+// Number of ports of a given speed.
+type Capabilities_PortCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Speed of the ports. An unspecified speed counts ports of any speed.
+	Speed Port_Speed `protobuf:"varint,1,opt,name=speed,proto3,enum=ondatra.Port_Speed" json:"speed,omitempty"`
+	// Minimum number of ports of the speed, including the ports listed in the
+	// testbed. When reported by a binding, the number of ports of the speed.
+	Count int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *Capabilities_PortCount) Reset() {
+	*x = Capabilities_PortCount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_testbed_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Capabilities_PortCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capabilities_PortCount) ProtoMessage() {}
+
+func (x *Capabilities_PortCount) ProtoReflect() protoreflect.Message {
+	mi := &file_testbed_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capabilities_PortCount.ProtoReflect.Descriptor instead.
+func (*Capabilities_PortCount) Descriptor() ([]byte, []int) {
+	return file_testbed_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *Capabilities_PortCount) GetSpeed() Port_Speed {
+	if x != nil {
+		return x.Speed
+	}
+	return Port_SPEED_UNSPECIFIED
+}
+
+func (x *Capabilities_PortCount) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
 var File_testbed_proto protoreflect.FileDescriptor
 
 var file_testbed_proto_rawDesc = []byte{
@@ -769,7 +1040,7 @@ var file_testbed_proto_rawDesc = []byte{
 	0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x04, 0x61, 0x74, 0x65, 0x73, 0x12, 0x23, 0x0a,
 	0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6f,
 	0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x52, 0x05, 0x6c, 0x69, 0x6e,
-	0x6b, 0x73, 0x22, 0x99, 0x05, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x0a,
+	0x6b, 0x73, 0x22, 0xd4, 0x05, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x0a,
 	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2e, 0x0a,
 	0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e,
 	0x6f, 0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x56,
@@ -793,93 +1064,140 @@ var file_testbed_proto_rawDesc = []byte{
 	0x2e, 0x6f, 0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x2e,
 	0x45, 0x78, 0x74, 0x72, 0x61, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45,
 	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x65, 0x78, 0x74, 0x72, 0x61, 0x44, 0x69, 0x6d, 0x65, 0x6e,
-	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x42, 0x0a, 0x14, 0x45, 0x78, 0x74, 0x72, 0x61, 0x44, 0x69,
-	0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa0, 0x01, 0x0a, 0x06, 0x56, 0x65,
-	0x6e, 0x64, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x12, 0x56, 0x45, 0x4e, 0x44, 0x4f, 0x52, 0x5f, 0x55,
-	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06,
-	0x41, 0x52, 0x49, 0x53, 0x54, 0x41, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x49, 0x53, 0x43,
-	0x4f, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x58, 0x49, 0x41, 0x10, 0x03, 0x12, 0x0b, 0x0a,
-	0x07, 0x4a, 0x55, 0x4e, 0x49, 0x50, 0x45, 0x52, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x49,
-	0x45, 0x4e, 0x41, 0x10, 0x05, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x41, 0x4c, 0x4f, 0x41, 0x4c, 0x54,
-	0x4f, 0x10, 0x06, 0x12, 0x09, 0x0a, 0x05, 0x4e, 0x4f, 0x4b, 0x49, 0x41, 0x10, 0x07, 0x12, 0x07,
-	0x0a, 0x03, 0x5a, 0x50, 0x45, 0x10, 0x08, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x45, 0x4c, 0x4c, 0x10,
-	0x09, 0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x50, 0x45, 0x4e, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10,
-	0x0a, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x52, 0x55, 0x42, 0x41, 0x10, 0x0b, 0x42, 0x16, 0x0a, 0x14,
-	0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x42, 0x18, 0x0a, 0x16, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65,
-	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xf0,
-	0x07, 0x0a, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6f, 0x6e, 0x64, 0x61, 0x74, 0x72, 0x61,
-	0x2e, 0x50, 0x6f, 0x72, 0x74, 0x2e, 0x53, 0x70, 0x65, 0x65, 0x64, 0x52, 0x05, 0x73, 0x70, 0x65,
-	0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0a, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x63, 0x61, 0x72, 0x64, 0x4d, 0x6f,
-	0x64, 0x65, 0x6c, 0x12, 0x2a, 0x0a, 0x10, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
-	0x6c, 0x5f, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
-	0x0e, 0x63, 0x61, 0x72, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x65, 0x78, 0x12,
-	0x25, 0x0a, 0x03, 0x70, 0x6d, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6f,
-	0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x2e, 0x50, 0x6d, 0x64, 0x48,
-	0x01, 0x52, 0x03, 0x70, 0x6d, 0x64, 0x12, 0x1d, 0x0a, 0x09, 0x70, 0x6d, 0x64, 0x5f, 0x72, 0x65,
-	0x67, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x70, 0x6d, 0x64,
-	0x52, 0x65, 0x67, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x22, 0x5b, 0x0a, 0x05, 0x53,
-	0x70, 0x65, 0x65, 0x64, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x50, 0x45, 0x45, 0x44, 0x5f, 0x55, 0x4e,
-	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x53,
-	0x5f, 0x31, 0x47, 0x42, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x5f, 0x35, 0x47, 0x42, 0x10,
-	0x05, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x10, 0x0a, 0x12, 0x0b, 0x0a,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x39, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6f, 0x6e,
+	0x64, 0x61, 0x74, 0x72, 0x61, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x52, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x1a, 0x42, 0x0a, 0x14, 0x45, 0x78, 0x74, 0x72, 0x61, 0x44, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0xa0, 0x01, 0x0a, 0x06, 0x56, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12,
+	0x16, 0x0a, 0x12, 0x56, 0x45, 0x4e, 0x44, 0x4f, 0x52, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x52, 0x49, 0x53, 0x54,
+	0x41, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x49, 0x53, 0x43, 0x4f, 0x10, 0x02, 0x12, 0x08,
+	0x0a, 0x04, 0x49, 0x58, 0x49, 0x41, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x4a, 0x55, 0x4e, 0x49,
+	0x50, 0x45, 0x52, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x49, 0x45, 0x4e, 0x41, 0x10, 0x05,
+	0x12, 0x0c, 0x0a, 0x08, 0x50, 0x41, 0x4c, 0x4f, 0x41, 0x4c, 0x54, 0x4f, 0x10, 0x06, 0x12, 0x09,
+	0x0a, 0x05, 0x4e, 0x4f, 0x4b, 0x49, 0x41, 0x10, 0x07, 0x12, 0x07, 0x0a, 0x03, 0x5a, 0x50, 0x45,
+	0x10, 0x08, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x45, 0x4c, 0x4c, 0x10, 0x09, 0x12, 0x0e, 0x0a, 0x0a,
+	0x4f, 0x50, 0x45, 0x4e, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x0a, 0x12, 0x09, 0x0a, 0x05,
+	0x41, 0x52, 0x55, 0x42, 0x41, 0x10, 0x0b, 0x42, 0x16, 0x0a, 0x14, 0x68, 0x61, 0x72, 0x64, 0x77,
+	0x61, 0x72, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42,
+	0x18, 0x0a, 0x16, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xd6, 0x02, 0x0a, 0x0c, 0x43, 0x61,
+	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72,
+	0x69, 0x62, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x67, 0x72, 0x69, 0x62, 0x69,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x34, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04,
+	0x70, 0x34, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75, 0x74,
+	0x12, 0x35, 0x0a, 0x07, 0x61, 0x74, 0x65, 0x5f, 0x61, 0x70, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1c, 0x2e, 0x6f, 0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2e, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2e, 0x41, 0x74, 0x65, 0x41, 0x70, 0x69, 0x52,
+	0x06, 0x61, 0x74, 0x65, 0x41, 0x70, 0x69, 0x12, 0x40, 0x0a, 0x0b, 0x70, 0x6f, 0x72, 0x74, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6f,
+	0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x0a, 0x70,
+	0x6f, 0x72, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x1a, 0x4c, 0x0a, 0x09, 0x50, 0x6f, 0x72,
+	0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6f, 0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2e,
+	0x50, 0x6f, 0x72, 0x74, 0x2e, 0x53, 0x70, 0x65, 0x65, 0x64, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x39, 0x0a, 0x06, 0x41, 0x74, 0x65, 0x41, 0x70,
+	0x69, 0x12, 0x17, 0x0a, 0x13, 0x41, 0x54, 0x45, 0x5f, 0x41, 0x50, 0x49, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x54,
+	0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x58, 0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b,
+	0x10, 0x02, 0x22, 0xd4, 0x0a, 0x0a, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x29, 0x0a, 0x05, 0x73,
+	0x70, 0x65, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6f, 0x6e, 0x64,
+	0x61, 0x74, 0x72, 0x61, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x2e, 0x53, 0x70, 0x65, 0x65, 0x64, 0x52,
+	0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0a, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x63, 0x61,
+	0x72, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x2a, 0x0a, 0x10, 0x63, 0x61, 0x72, 0x64, 0x5f,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x0e, 0x63, 0x61, 0x72, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x65,
+	0x67, 0x65, 0x78, 0x12, 0x25, 0x0a, 0x03, 0x70, 0x6d, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x11, 0x2e, 0x6f, 0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x2e,
+	0x50, 0x6d, 0x64, 0x48, 0x01, 0x52, 0x03, 0x70, 0x6d, 0x64, 0x12, 0x1d, 0x0a, 0x09, 0x70, 0x6d,
+	0x64, 0x5f, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52,
+	0x08, 0x70, 0x6d, 0x64, 0x52, 0x65, 0x67, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x25, 0x0a, 0x0e, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75, 0x74, 0x5f, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x6f, 0x75,
+	0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x22, 0x9b, 0x01, 0x0a, 0x05, 0x53, 0x70, 0x65, 0x65, 0x64,
+	0x12, 0x15, 0x0a, 0x11, 0x53, 0x50, 0x45, 0x45, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x5f, 0x31, 0x47, 0x42,
+	0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x5f, 0x35, 0x47, 0x42, 0x10, 0x05, 0x12, 0x0a, 0x0a,
+	0x06, 0x53, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x10, 0x0a, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x5f, 0x32,
+	0x35, 0x47, 0x42, 0x10, 0x19, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x5f, 0x34, 0x30, 0x47, 0x42, 0x10,
+	0x28, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x5f, 0x35, 0x30, 0x47, 0x42, 0x10, 0x32, 0x12, 0x0b, 0x0a,
 	0x07, 0x53, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x10, 0x64, 0x12, 0x0c, 0x0a, 0x07, 0x53, 0x5f,
-	0x34, 0x30, 0x30, 0x47, 0x42, 0x10, 0x90, 0x03, 0x22, 0x85, 0x05, 0x0a, 0x03, 0x50, 0x6d, 0x64,
-	0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
-	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x47,
-	0x42, 0x41, 0x53, 0x45, 0x5f, 0x4c, 0x52, 0x4d, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x4d,
-	0x44, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x4c, 0x52, 0x10, 0x02, 0x12, 0x12,
-	0x0a, 0x0e, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x5a, 0x52,
-	0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53,
-	0x45, 0x5f, 0x45, 0x52, 0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30,
-	0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x10, 0x05, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d,
-	0x44, 0x5f, 0x34, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x43, 0x52, 0x34, 0x10, 0x06, 0x12,
-	0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53,
-	0x52, 0x34, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x47, 0x42,
-	0x41, 0x53, 0x45, 0x5f, 0x4c, 0x52, 0x34, 0x10, 0x08, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44,
-	0x5f, 0x34, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x45, 0x52, 0x34, 0x10, 0x09, 0x12, 0x14,
-	0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x50, 0x53,
-	0x4d, 0x34, 0x10, 0x0a, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x58, 0x31, 0x30,
-	0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x4c, 0x52, 0x10, 0x0b, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d,
-	0x44, 0x5f, 0x34, 0x58, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x10, 0x0c,
-	0x12, 0x10, 0x0a, 0x0c, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x5f, 0x41, 0x4f, 0x43,
-	0x10, 0x0d, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x5f, 0x41,
-	0x43, 0x43, 0x10, 0x0e, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47,
-	0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x31, 0x30, 0x10, 0x0f, 0x12, 0x14, 0x0a, 0x10, 0x50,
-	0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x34, 0x10,
-	0x10, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53,
-	0x45, 0x5f, 0x4c, 0x52, 0x34, 0x10, 0x11, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x31,
-	0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x45, 0x52, 0x34, 0x10, 0x12, 0x12, 0x16, 0x0a,
-	0x12, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x43, 0x57,
-	0x44, 0x4d, 0x34, 0x10, 0x13, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30,
-	0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x43, 0x4c, 0x52, 0x34, 0x10, 0x14, 0x12, 0x15, 0x0a, 0x11,
-	0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x50, 0x53, 0x4d,
-	0x34, 0x10, 0x15, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42,
-	0x41, 0x53, 0x45, 0x5f, 0x43, 0x52, 0x34, 0x10, 0x16, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44,
-	0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x46, 0x52, 0x10, 0x17, 0x12, 0x13,
-	0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x5a,
-	0x52, 0x10, 0x18, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x30, 0x47, 0x42,
-	0x41, 0x53, 0x45, 0x5f, 0x4c, 0x52, 0x34, 0x10, 0x19, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44,
-	0x5f, 0x34, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x46, 0x52, 0x34, 0x10, 0x1a, 0x12,
+	0x32, 0x30, 0x30, 0x47, 0x42, 0x10, 0xc8, 0x01, 0x12, 0x0c, 0x0a, 0x07, 0x53, 0x5f, 0x34, 0x30,
+	0x30, 0x47, 0x42, 0x10, 0x90, 0x03, 0x12, 0x0c, 0x0a, 0x07, 0x53, 0x5f, 0x38, 0x30, 0x30, 0x47,
+	0x42, 0x10, 0xa0, 0x06, 0x22, 0x81, 0x07, 0x0a, 0x03, 0x50, 0x6d, 0x64, 0x12, 0x13, 0x0a, 0x0f,
+	0x50, 0x4d, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45,
+	0x5f, 0x4c, 0x52, 0x4d, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30,
+	0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x4c, 0x52, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x4d,
+	0x44, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x5a, 0x52, 0x10, 0x03, 0x12, 0x12,
+	0x0a, 0x0e, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x45, 0x52,
+	0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53,
+	0x45, 0x5f, 0x53, 0x52, 0x10, 0x05, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30,
+	0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x43, 0x52, 0x34, 0x10, 0x06, 0x12, 0x13, 0x0a, 0x0f, 0x50,
+	0x4d, 0x44, 0x5f, 0x34, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x34, 0x10, 0x07,
+	0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f,
+	0x4c, 0x52, 0x34, 0x10, 0x08, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x47,
+	0x42, 0x41, 0x53, 0x45, 0x5f, 0x45, 0x52, 0x34, 0x10, 0x09, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d,
+	0x44, 0x5f, 0x34, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x50, 0x53, 0x4d, 0x34, 0x10, 0x0a,
+	0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x58, 0x31, 0x30, 0x47, 0x42, 0x41, 0x53,
+	0x45, 0x5f, 0x4c, 0x52, 0x10, 0x0b, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x58,
+	0x31, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x10, 0x0c, 0x12, 0x10, 0x0a, 0x0c,
+	0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x5f, 0x41, 0x4f, 0x43, 0x10, 0x0d, 0x12, 0x10,
+	0x0a, 0x0c, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x5f, 0x41, 0x43, 0x43, 0x10, 0x0e,
+	0x12, 0x15, 0x0a, 0x11, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45,
+	0x5f, 0x53, 0x52, 0x31, 0x30, 0x10, 0x0f, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x31,
+	0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x34, 0x10, 0x10, 0x12, 0x14, 0x0a,
+	0x10, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x4c, 0x52,
+	0x34, 0x10, 0x11, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42,
+	0x41, 0x53, 0x45, 0x5f, 0x45, 0x52, 0x34, 0x10, 0x12, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x4d, 0x44,
+	0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x43, 0x57, 0x44, 0x4d, 0x34, 0x10,
+	0x13, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53,
+	0x45, 0x5f, 0x43, 0x4c, 0x52, 0x34, 0x10, 0x14, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x4d, 0x44, 0x5f,
+	0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x50, 0x53, 0x4d, 0x34, 0x10, 0x15, 0x12,
+	0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f,
+	0x43, 0x52, 0x34, 0x10, 0x16, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30,
+	0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x46, 0x52, 0x10, 0x17, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d,
+	0x44, 0x5f, 0x34, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x5a, 0x52, 0x10, 0x18, 0x12,
 	0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f,
-	0x4c, 0x52, 0x38, 0x10, 0x1b, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x30,
-	0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x44, 0x52, 0x34, 0x10, 0x1c, 0x12, 0x13, 0x0a, 0x0f, 0x50,
-	0x4d, 0x44, 0x5f, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x44, 0x52, 0x10, 0x1d,
-	0x42, 0x12, 0x0a, 0x10, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x70, 0x6d, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x22, 0x22, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x0c, 0x0a, 0x01, 0x61, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x62, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x01, 0x62, 0x42, 0x25, 0x5a, 0x23, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x6f,
-	0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x4c, 0x52, 0x34, 0x10, 0x19, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x30,
+	0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x46, 0x52, 0x34, 0x10, 0x1a, 0x12, 0x14, 0x0a, 0x10, 0x50,
+	0x4d, 0x44, 0x5f, 0x34, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x4c, 0x52, 0x38, 0x10,
+	0x1b, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53,
+	0x45, 0x5f, 0x44, 0x52, 0x34, 0x10, 0x1c, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x31,
+	0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x44, 0x52, 0x10, 0x1d, 0x12, 0x18, 0x0a, 0x14,
+	0x50, 0x4d, 0x44, 0x5f, 0x34, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x5a, 0x52, 0x5f,
+	0x50, 0x4c, 0x55, 0x53, 0x10, 0x1e, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x38, 0x30,
+	0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x44, 0x52, 0x38, 0x10, 0x1f, 0x12, 0x14, 0x0a, 0x10,
+	0x50, 0x4d, 0x44, 0x5f, 0x38, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x38,
+	0x10, 0x20, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x38, 0x30, 0x30, 0x47, 0x42, 0x41,
+	0x53, 0x45, 0x5f, 0x43, 0x52, 0x38, 0x10, 0x21, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x4d, 0x44, 0x5f,
+	0x38, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x32, 0x58, 0x46, 0x52, 0x34, 0x10, 0x22,
+	0x12, 0x13, 0x0a, 0x0f, 0x50, 0x4d, 0x44, 0x5f, 0x38, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45,
+	0x5f, 0x5a, 0x52, 0x10, 0x23, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x58, 0x32,
+	0x35, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x52, 0x10, 0x24, 0x12, 0x15, 0x0a, 0x11, 0x50,
+	0x4d, 0x44, 0x5f, 0x34, 0x58, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x44, 0x52,
+	0x10, 0x25, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x4d, 0x44, 0x5f, 0x34, 0x58, 0x31, 0x30, 0x30, 0x47,
+	0x42, 0x41, 0x53, 0x45, 0x5f, 0x46, 0x52, 0x10, 0x26, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x4d, 0x44,
+	0x5f, 0x38, 0x58, 0x31, 0x30, 0x30, 0x47, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x44, 0x52, 0x10, 0x27,
+	0x12, 0x16, 0x0a, 0x12, 0x50, 0x4d, 0x44, 0x5f, 0x32, 0x58, 0x34, 0x30, 0x30, 0x47, 0x42, 0x41,
+	0x53, 0x45, 0x5f, 0x44, 0x52, 0x34, 0x10, 0x28, 0x42, 0x12, 0x0a, 0x10, 0x63, 0x61, 0x72, 0x64,
+	0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x0b, 0x0a, 0x09,
+	0x70, 0x6d, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x22, 0x0a, 0x04, 0x4c, 0x69, 0x6e,
+	0x6b, 0x12, 0x0c, 0x0a, 0x01, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x61, 0x12,
+	0x0c, 0x0a, 0x01, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x62, 0x42, 0x25, 0x5a,
+	0x23, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x6f, 0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -894,32 +1212,39 @@ func file_testbed_proto_rawDescGZIP() []byte {
 	return file_testbed_proto_rawDescData
 }
 
-var file_testbed_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_testbed_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_testbed_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_testbed_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_testbed_proto_goTypes = []interface{}{
-	(Device_Vendor)(0), // 0: ondatra.Device.Vendor
-	(Port_Speed)(0),    // 1: ondatra.Port.Speed
-	(Port_Pmd)(0),      // 2: ondatra.Port.Pmd
-	(*Testbed)(nil),    // 3: ondatra.Testbed
-	(*Device)(nil),     // 4: ondatra.Device
-	(*Port)(nil),       // 5: ondatra.Port
-	(*Link)(nil),       // 6: ondatra.Link
-	nil,                // 7: ondatra.Device.ExtraDimensionsEntry
+	(Device_Vendor)(0),             // 0: ondatra.Device.Vendor
+	(Capabilities_AteApi)(0),       // 1: ondatra.Capabilities.AteApi
+	(Port_Speed)(0),                // 2: ondatra.Port.Speed
+	(Port_Pmd)(0),                  // 3: ondatra.Port.Pmd
+	(*Testbed)(nil),                // 4: ondatra.Testbed
+	(*Device)(nil),                 // 5: ondatra.Device
+	(*Capabilities)(nil),           // 6: ondatra.Capabilities
+	(*Port)(nil),                   // 7: ondatra.Port
+	(*Link)(nil),                   // 8: ondatra.Link
+	nil,                            // 9: ondatra.Device.ExtraDimensionsEntry
+	(*Capabilities_PortCount)(nil), // 10: ondatra.Capabilities.PortCount
 }
 var file_testbed_proto_depIdxs = []int32{
-	4, // 0: ondatra.Testbed.duts:type_name -> ondatra.Device
-	4, // 1: ondatra.Testbed.ates:type_name -> ondatra.Device
-	6, // 2: ondatra.Testbed.links:type_name -> ondatra.Link
-	0, // 3: ondatra.Device.vendor:type_name -> ondatra.Device.Vendor
-	5, // 4: ondatra.Device.ports:type_name -> ondatra.Port
-	7, // 5: ondatra.Device.extra_dimensions:type_name -> ondatra.Device.ExtraDimensionsEntry
-	1, // 6: ondatra.Port.speed:type_name -> ondatra.Port.Speed
-	2, // 7: ondatra.Port.pmd:type_name -> ondatra.Port.Pmd
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	5,  // 0: ondatra.Testbed.duts:type_name -> ondatra.Device
+	5,  // 1: ondatra.Testbed.ates:type_name -> ondatra.Device
+	8,  // 2: ondatra.Testbed.links:type_name -> ondatra.Link
+	0,  // 3: ondatra.Device.vendor:type_name -> ondatra.Device.Vendor
+	7,  // 4: ondatra.Device.ports:type_name -> ondatra.Port
+	9,  // 5: ondatra.Device.extra_dimensions:type_name -> ondatra.Device.ExtraDimensionsEntry
+	6,  // 6: ondatra.Device.capabilities:type_name -> ondatra.Capabilities
+	1,  // 7: ondatra.Capabilities.ate_api:type_name -> ondatra.Capabilities.AteApi
+	10, // 8: ondatra.Capabilities.port_counts:type_name -> ondatra.Capabilities.PortCount
+	2,  // 9: ondatra.Port.speed:type_name -> ondatra.Port.Speed
+	3,  // 10: ondatra.Port.pmd:type_name -> ondatra.Port.Pmd
+	2,  // 11: ondatra.Capabilities.PortCount.speed:type_name -> ondatra.Port.Speed
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_testbed_proto_init() }
@@ -953,7 +1278,7 @@ func file_testbed_proto_init() {
 			}
 		}
 		file_testbed_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Port); i {
+			switch v := v.(*Capabilities); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -965,6 +1290,18 @@ func file_testbed_proto_init() {
 			}
 		}
 		file_testbed_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Port); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_testbed_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Link); i {
 			case 0:
 				return &v.state
@@ -976,6 +1313,18 @@ func file_testbed_proto_init() {
 				return nil
 			}
 		}
+		file_testbed_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Capabilities_PortCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_testbed_proto_msgTypes[1].OneofWrappers = []interface{}{
 		(*Device_HardwareModel)(nil),
@@ -983,7 +1332,7 @@ func file_testbed_proto_init() {
 		(*Device_SoftwareVersion)(nil),
 		(*Device_SoftwareVersionRegex)(nil),
 	}
-	file_testbed_proto_msgTypes[2].OneofWrappers = []interface{}{
+	file_testbed_proto_msgTypes[3].OneofWrappers = []interface{}{
 		(*Port_CardModel)(nil),
 		(*Port_CardModelRegex)(nil),
 		(*Port_Pmd_)(nil),
@@ -994,8 +1343,8 @@ func file_testbed_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_testbed_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   5,
+			NumEnums:      4,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
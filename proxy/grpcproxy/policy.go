@@ -0,0 +1,204 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"math/rand"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AccessLogEntry is a record of a call handled by the proxy.
+type AccessLogEntry struct {
+	Method string
+	// Peer is the address of the caller, if known.
+	Peer string
+	// Start is the time the proxy received the call.
+	Start time.Time
+	// Duration is how long the proxy took to handle the call.
+	Duration time.Duration
+	// RecvMsgs and RecvBytes count the messages received from the caller.
+	RecvMsgs, RecvBytes int64
+	// SentMsgs and SentBytes count the messages sent to the caller.
+	SentMsgs, SentBytes int64
+	// Code is the status code of the call.
+	Code codes.Code
+	// Err is the error of the call, if any.
+	Err error
+}
+
+// AccessLogFn receives a record of every call handled by the proxy, after the
+// call has ended. It may be called concurrently.
+type AccessLogFn func(*AccessLogEntry)
+
+// LogAccess is an AccessLogFn that writes every record to the info log.
+func LogAccess(e *AccessLogEntry) {
+	log.Infof("grpcproxy access: method=%s peer=%s start=%s duration=%v recv_msgs=%d recv_bytes=%d sent_msgs=%d sent_bytes=%d code=%s err=%v",
+		e.Method, e.Peer, e.Start.Format(time.RFC3339Nano), e.Duration, e.RecvMsgs, e.RecvBytes, e.SentMsgs, e.SentBytes, e.Code, e.Err)
+}
+
+// WithAccessLog sets a function to receive a record of every call handled by
+// the proxy server.
+func WithAccessLog(fn AccessLogFn) Option {
+	return func(p *proxy) {
+		p.accessLog = fn
+	}
+}
+
+// RateLimit is a token-bucket limit on the rate of calls to the methods that
+// match a regular expression, such as "^/gnmi\.gNMI/". The calls to all the
+// matching methods share one bucket. A call over the limit fails with code
+// ResourceExhausted without being forwarded.
+type RateLimit struct {
+	// Method matches the full method names, in the form "/service/method".
+	Method *regexp.Regexp
+	// Rate is the number of calls per second added to the bucket.
+	Rate float64
+	// Burst is the size of the bucket.
+	Burst int
+}
+
+// WithRateLimits sets rate limits on the calls to the proxy server. A call is
+// limited by the first limit whose method expression it matches.
+func WithRateLimits(limits ...RateLimit) Option {
+	return func(p *proxy) {
+		for _, l := range limits {
+			p.limits = append(p.limits, &limiter{method: l.Method, lim: rate.NewLimiter(rate.Limit(l.Rate), l.Burst)})
+		}
+	}
+}
+
+// Fault is a fault to inject into calls to the methods that match a regular
+// expression, for testing how clients behave on an unreliable network.
+type Fault struct {
+	// Method matches the full method names, in the form "/service/method".
+	Method *regexp.Regexp
+	// Probability is the probability, from 0 to 1, that a matching call is
+	// faulted. Zero faults every matching call.
+	Probability float64
+	// Delay is the latency added before a faulted call is forwarded.
+	Delay time.Duration
+	// Code, if not OK, is the code with which a faulted call fails after the
+	// delay, without being forwarded.
+	Code codes.Code
+	// DropAfter, if positive, is the number of messages sent to the caller
+	// after which a faulted call is dropped with code Unavailable.
+	DropAfter int
+}
+
+// WithFaults sets faults to inject into the calls to the proxy server. A call
+// is faulted by the first fault whose method expression it matches, subject to
+// the probability of the fault.
+func WithFaults(faults ...Fault) Option {
+	return func(p *proxy) {
+		p.faults = append(p.faults, faults...)
+	}
+}
+
+type limiter struct {
+	method *regexp.Regexp
+	lim    *rate.Limiter
+}
+
+// call holds the state of a call that the proxy policies act on.
+type call struct {
+	recvMsgs, recvBytes atomic.Int64
+	sentMsgs, sentBytes atomic.Int64
+	// dropAfter, if positive, is the number of messages sent to the caller
+	// after which the call is dropped.
+	dropAfter int64
+}
+
+// received counts a message received from the caller.
+func (c *call) received(p *rawProto) {
+	c.recvMsgs.Add(1)
+	c.recvBytes.Add(int64(len(p.data)))
+}
+
+// sent counts a message sent to the caller and reports whether the call must
+// be dropped.
+func (c *call) sent(p *rawProto) bool {
+	n := c.sentMsgs.Add(1)
+	c.sentBytes.Add(int64(len(p.data)))
+	return c.dropAfter > 0 && n >= c.dropAfter
+}
+
+// applyPolicies applies the rate limits and faults of the proxy to a call
+// before it is forwarded, returning an error if the call must fail.
+func (p *proxy) applyPolicies(ctx context.Context, method string, c *call) error {
+	for _, l := range p.limits {
+		if l.method.MatchString(method) {
+			if !l.lim.Allow() {
+				return status.Errorf(codes.ResourceExhausted, "proxy_error: rate limit of %s exceeded", l.method)
+			}
+			break
+		}
+	}
+	for _, f := range p.faults {
+		if !f.Method.MatchString(method) {
+			continue
+		}
+		if f.Probability > 0 && rand.Float64() >= f.Probability {
+			break
+		}
+		log.Infof("Injecting fault into %s: %+v", method, f)
+		if f.Delay > 0 {
+			t := time.NewTimer(f.Delay)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+		if f.Code != codes.OK {
+			return status.Errorf(f.Code, "injected fault: %s", f.Code)
+		}
+		c.dropAfter = int64(f.DropAfter)
+		break
+	}
+	return nil
+}
+
+// logAccess sends the record of a call to the access log, if any.
+func (p *proxy) logAccess(ctx context.Context, method string, start time.Time, c *call, err error) {
+	if p.accessLog == nil {
+		return
+	}
+	e := &AccessLogEntry{
+		Method:    method,
+		Start:     start,
+		Duration:  time.Since(start),
+		RecvMsgs:  c.recvMsgs.Load(),
+		RecvBytes: c.recvBytes.Load(),
+		SentMsgs:  c.sentMsgs.Load(),
+		SentBytes: c.sentBytes.Load(),
+		Code:      status.Code(err),
+		Err:       err,
+	}
+	if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
+		e.Peer = pr.Addr.String()
+	}
+	p.accessLog(e)
+}
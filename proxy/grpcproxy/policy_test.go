@@ -0,0 +1,176 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"net"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ondatra/internal/fakegnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// startProxy starts a proxy to the fake with the options and returns a
+// gNMI client of the proxy.
+func startProxy(t *testing.T, fake *fakegnmi.StubGNMI, opts ...Option) gnmipb.GNMIClient {
+	t.Helper()
+	dp := func(metadata.MD, string) (string, metadata.MD, error) {
+		return fake.Addr(), nil, nil
+	}
+	p, err := NewServer(dp, append(opts, WithDialer(&dialer{}))...)
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	go p.Serve(lis)
+	t.Cleanup(p.Stop)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return gnmipb.NewGNMIClient(conn)
+}
+
+// subscribe subscribes and returns the number of responses received and the
+// final error of the stream.
+func subscribe(ctx context.Context, c gnmipb.GNMIClient) (int, error) {
+	sub, err := c.Subscribe(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := sub.Send(&gnmipb.SubscribeRequest{}); err != nil {
+		return 0, err
+	}
+	for n := 0; ; n++ {
+		if _, err := sub.Recv(); err != nil {
+			return n, err
+		}
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	fake, err := fakegnmi.StartStubGNMI(0)
+	if err != nil {
+		t.Fatalf("StartStubGNMI() failed: %v", err)
+	}
+	defer fake.Stop()
+	gnmiRE := regexp.MustCompile(`^/gnmi\.gNMI/`)
+	getRE := regexp.MustCompile(`/Get$`)
+	ctx := context.Background()
+
+	t.Run("rate limit", func(t *testing.T) {
+		fake.Stub().GetResponse(&gnmipb.GetResponse{}).GetResponse(&gnmipb.GetResponse{})
+		c := startProxy(t, fake, WithRateLimits(
+			RateLimit{Method: regexp.MustCompile(`/Capabilities$`), Rate: 100, Burst: 100},
+			RateLimit{Method: gnmiRE, Rate: 0.001, Burst: 1},
+		))
+		if _, err := c.Get(ctx, &gnmipb.GetRequest{}); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if _, err := c.Get(ctx, &gnmipb.GetRequest{}); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("Get() over rate limit got error %v, want code %v", err, codes.ResourceExhausted)
+		}
+	})
+
+	t.Run("injected error", func(t *testing.T) {
+		c := startProxy(t, fake, WithFaults(Fault{Method: getRE, Code: codes.Unavailable}))
+		if _, err := c.Get(ctx, &gnmipb.GetRequest{}); status.Code(err) != codes.Unavailable {
+			t.Errorf("Get() got error %v, want code %v", err, codes.Unavailable)
+		}
+	})
+
+	t.Run("injected delay", func(t *testing.T) {
+		fake.Stub().GetResponse(&gnmipb.GetResponse{})
+		c := startProxy(t, fake, WithFaults(Fault{Method: getRE, Delay: 100 * time.Millisecond}))
+		start := time.Now()
+		if _, err := c.Get(ctx, &gnmipb.GetRequest{}); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if got, want := time.Since(start), 100*time.Millisecond; got < want {
+			t.Errorf("Get() took %v, want at least %v", got, want)
+		}
+	})
+
+	t.Run("fault not matched", func(t *testing.T) {
+		fake.Stub().GetResponse(&gnmipb.GetResponse{})
+		c := startProxy(t, fake, WithFaults(Fault{Method: regexp.MustCompile(`/Set$`), Code: codes.Internal}))
+		if _, err := c.Get(ctx, &gnmipb.GetRequest{}); err != nil {
+			t.Errorf("Get() failed: %v", err)
+		}
+	})
+
+	t.Run("stream drop", func(t *testing.T) {
+		fake.Stub().Notification(&gnmipb.Notification{}).Notification(&gnmipb.Notification{}).Sync()
+		c := startProxy(t, fake, WithFaults(Fault{Method: gnmiRE, DropAfter: 2}))
+		n, err := subscribe(ctx, c)
+		if n != 2 || status.Code(err) != codes.Unavailable {
+			t.Errorf("Subscribe() got %d responses and error %v, want 2 and code %v", n, err, codes.Unavailable)
+		}
+	})
+}
+
+func TestAccessLog(t *testing.T) {
+	fake, err := fakegnmi.StartStubGNMI(0)
+	if err != nil {
+		t.Fatalf("StartStubGNMI() failed: %v", err)
+	}
+	defer fake.Stop()
+	fake.Stub().Notification(&gnmipb.Notification{Timestamp: 1}).Sync()
+
+	var mu sync.Mutex
+	var entries []*AccessLogEntry
+	c := startProxy(t, fake, WithAccessLog(func(e *AccessLogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, e)
+	}), WithFaults(Fault{Method: regexp.MustCompile(`/Get$`), Code: codes.NotFound}))
+	ctx := context.Background()
+
+	if n, err := subscribe(ctx, c); n != 2 {
+		t.Fatalf("Subscribe() got %d responses and error %v, want 2", n, err)
+	}
+	c.Get(ctx, &gnmipb.GetRequest{})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(entries) != 2 {
+		t.Fatalf("Access log got %d entries, want 2", len(entries))
+	}
+	sub, get := entries[0], entries[1]
+	if sub.Method != "/gnmi.gNMI/Subscribe" || sub.Code != codes.OK || sub.Peer == "" {
+		t.Errorf("Subscribe access log entry got method %q, code %v, peer %q; want /gnmi.gNMI/Subscribe, OK, non-empty", sub.Method, sub.Code, sub.Peer)
+	}
+	if sub.RecvMsgs != 1 || sub.SentMsgs != 2 || sub.SentBytes == 0 {
+		t.Errorf("Subscribe access log entry got %d/%d messages received/sent and %d bytes sent, want 1/2 and non-zero", sub.RecvMsgs, sub.SentMsgs, sub.SentBytes)
+	}
+	if get.Method != "/gnmi.gNMI/Get" || get.Code != codes.NotFound || get.Err == nil {
+		t.Errorf("Get access log entry got method %q, code %v, error %v; want /gnmi.gNMI/Get, NotFound, non-nil", get.Method, get.Code, get.Err)
+	}
+}
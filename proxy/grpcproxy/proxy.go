@@ -16,6 +16,10 @@
 // tranparently forward both unary and bidirectional streams across different
 // gRPC connections. The main use case was to provide connections across
 // auth domains.
+//
+// Options can add policies to the calls the proxy forwards: access logs, rate
+// limits and injected faults, such as latency, errors and dropped streams, for
+// testing how clients behave on an unreliable network.
 package grpcproxy
 
 import (
@@ -23,6 +27,7 @@ import (
 	"io"
 	"net"
 	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -79,6 +84,9 @@ type proxy struct {
 	destProvider   DestProviderFn
 	serverProvider ServerProviderFn
 	grpcDialer     GRPCDialer
+	accessLog      AccessLogFn
+	limits         []*limiter
+	faults         []Fault
 }
 
 // Option configures the proxy server.
@@ -164,7 +172,13 @@ func (p *proxy) instrumentedStreamHandler(srv any, stream grpc.ServerStream) err
 
 	ctx, _ = tag.New(ctx, tag.Upsert(methodKey, method))
 	stats.Record(ctx, mNumRequests.M(1))
-	err := p.streamHandler(ctx, method, stream)
+	start := time.Now()
+	c := new(call)
+	err := p.applyPolicies(ctx, method, c)
+	if err == nil {
+		err = p.streamHandler(ctx, method, stream, c)
+	}
+	p.logAccess(ctx, method, start, c, err)
 	if err != nil {
 		mut := []tag.Mutator{tag.Upsert(errorKey, err.Error())}
 		if strings.Contains(err.Error(), "proxy_error:") {
@@ -176,7 +190,7 @@ func (p *proxy) instrumentedStreamHandler(srv any, stream grpc.ServerStream) err
 	return err
 }
 
-func (p *proxy) streamHandler(ctx context.Context, method string, stream grpc.ServerStream) error {
+func (p *proxy) streamHandler(ctx context.Context, method string, stream grpc.ServerStream, c *call) error {
 	// Establish connection to dest server.
 	destCtx, destConn, err := p.dialTarget(ctx, method)
 	if err != nil {
@@ -191,14 +205,14 @@ func (p *proxy) streamHandler(ctx context.Context, method string, stream grpc.Se
 	if err != nil {
 		return status.Errorf(codes.Internal, fmt.Sprintf("proxy_error: %v", err))
 	}
-	return p.bidiStream(clientStream, stream)
+	return p.bidiStream(clientStream, stream, c)
 }
 
-func (p *proxy) bidiStream(cs grpc.ClientStream, ss grpc.ServerStream) error {
+func (p *proxy) bidiStream(cs grpc.ClientStream, ss grpc.ServerStream, c *call) error {
 	c2sErr := make(chan error, 1)
 	s2cErr := make(chan error, 1)
-	go func() { c2sErr <- forwardClientToServer(ss, cs) }()
-	go func() { s2cErr <- forwardServerToClient(cs, ss) }()
+	go func() { c2sErr <- forwardClientToServer(ss, cs, c) }()
+	go func() { s2cErr <- forwardServerToClient(cs, ss, c) }()
 	for {
 		select {
 		// If ClientToServer ends, we always want to return instead of waiting for
@@ -216,7 +230,7 @@ func (p *proxy) bidiStream(cs grpc.ClientStream, ss grpc.ServerStream) error {
 	}
 }
 
-func forwardServerToClient(dst grpc.ClientStream, src grpc.ServerStream) error {
+func forwardServerToClient(dst grpc.ClientStream, src grpc.ServerStream, c *call) error {
 	p := &rawProto{}
 	// Headers already transferred as part of grpc client connection context.
 	// Only body transfer needed.
@@ -230,13 +244,14 @@ func forwardServerToClient(dst grpc.ClientStream, src grpc.ServerStream) error {
 			}
 			return status.Errorf(codes.Internal, fmt.Sprintf("proxy_error: %v", err))
 		}
+		c.received(p)
 		if err := dst.SendMsg(p); err != nil {
 			return status.Errorf(codes.Internal, fmt.Sprintf("proxy_error: %v", err))
 		}
 	}
 }
 
-func forwardClientToServer(dst grpc.ServerStream, src grpc.ClientStream) error {
+func forwardClientToServer(dst grpc.ServerStream, src grpc.ClientStream, c *call) error {
 	p := &rawProto{}
 	// Transfer Headers.
 	md, err := src.Header()
@@ -259,5 +274,8 @@ func forwardClientToServer(dst grpc.ServerStream, src grpc.ClientStream) error {
 		if err := dst.SendMsg(p); err != nil {
 			return err
 		}
+		if c.sent(p) {
+			return status.Errorf(codes.Unavailable, "injected fault: stream dropped after %d messages", c.dropAfter)
+		}
 	}
 }
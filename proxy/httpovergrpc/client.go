@@ -0,0 +1,179 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpovergrpc
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"google.golang.org/grpc"
+
+	hpb "github.com/openconfig/ondatra/proxy/proto/httpovergrpc"
+)
+
+// Client is an HTTP client that sends requests to an HTTP over gRPC service,
+// streaming the request and response bodies. If the response switches
+// protocols, as for a WebSocket, the body of the response is an
+// io.ReadWriteCloser of the upgraded connection, as with an http.Client.
+type Client struct {
+	client hpb.HTTPOverGRPCClient
+}
+
+// NewClient creates a new client of the HTTP over gRPC service at the other
+// end of the connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: hpb.NewHTTPOverGRPCClient(conn)}
+}
+
+// Do sends the HTTP request and returns the HTTP response. The caller must
+// close the response body, which is streamed as it is read.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	resp, err := c.do(ctx, cancel, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) do(ctx context.Context, cancel func(), req *http.Request) (*http.Response, error) {
+	stream, err := c.client.HTTPStream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting HTTP stream: %w", err)
+	}
+	header := req.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if req.ContentLength > 0 || !hasBody {
+		header.Set("Content-Length", strconv.FormatInt(req.ContentLength, 10))
+	}
+	if err := stream.Send(&hpb.StreamRequest{Frame: &hpb.StreamRequest_Head{Head: &hpb.Request{
+		Method:  req.Method,
+		Url:     req.URL.String(),
+		Headers: fromHTTPHeader(header),
+	}}}); err != nil {
+		return nil, fmt.Errorf("error sending HTTP request head: %w", err)
+	}
+
+	sendBody := func() error {
+		if !hasBody {
+			return nil
+		}
+		defer req.Body.Close()
+		_, err := sendChunks(req.Body, func(b []byte) error {
+			return stream.Send(&hpb.StreamRequest{Frame: &hpb.StreamRequest_Body{Body: b}})
+		})
+		return err
+	}
+	upgrade := isUpgrade(req.Header)
+	if upgrade {
+		// The stream stays open to carry the data of the upgraded connection.
+		if err := sendBody(); err != nil {
+			return nil, fmt.Errorf("error sending HTTP request body: %w", err)
+		}
+	} else {
+		go func() {
+			if err := sendBody(); err != nil {
+				log.Errorf("Error sending HTTP request body: %v", err)
+				cancel()
+				return
+			}
+			if err := stream.CloseSend(); err != nil {
+				log.Errorf("Error closing HTTP request stream: %v", err)
+			}
+		}()
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("error receiving HTTP response head: %w", err)
+	}
+	head := first.GetHead()
+	if head == nil {
+		return nil, fmt.Errorf("first frame of the stream has no response head")
+	}
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", head.GetStatus(), http.StatusText(int(head.GetStatus()))),
+		StatusCode:    int(head.GetStatus()),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        toHTTPHeader(head.GetHeaders()),
+		ContentLength: -1,
+		Request:       req,
+	}
+	if n, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+		resp.ContentLength = n
+	}
+	body := &streamBody{stream: stream, cancel: cancel, buf: head.GetBody()}
+	if upgrade && resp.StatusCode == http.StatusSwitchingProtocols {
+		resp.Body = &upgradedBody{body}
+	} else {
+		resp.Body = body
+	}
+	return resp, nil
+}
+
+// streamBody reads the body of a response from an HTTP stream.
+type streamBody struct {
+	stream hpb.HTTPOverGRPC_HTTPStreamClient
+	cancel func()
+	buf    []byte
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	for len(b.buf) == 0 {
+		frame, err := b.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if frame.GetHead() != nil {
+			return 0, fmt.Errorf("response head received after the first frame")
+		}
+		b.buf = frame.GetBody()
+	}
+	n := copy(p, b.buf)
+	b.buf = b.buf[n:]
+	return n, nil
+}
+
+// Close ends the stream.
+func (b *streamBody) Close() error {
+	b.cancel()
+	return nil
+}
+
+var _ io.ReadWriteCloser = (*upgradedBody)(nil)
+
+// upgradedBody is the body of a response that switched protocols, which
+// reads from and writes to the upgraded connection.
+type upgradedBody struct {
+	*streamBody
+}
+
+func (b *upgradedBody) Write(p []byte) (int, error) {
+	if err := b.stream.Send(&hpb.StreamRequest{Frame: &hpb.StreamRequest_Body{Body: p}}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -25,6 +25,9 @@ var (
 	mNumRequests  = stats.Int64("num_requests", "number of requests received.", "1")
 	mProxyErrors  = stats.Int64("num_proxy_errors", "number of proxy errors", "1")
 	mClientErrors = stats.Int64("num_client_errors", "number of errors that originate from end client", "1")
+	mNumStreams   = stats.Int64("num_streams", "number of streamed requests received.", "1")
+	mRequestBytes = stats.Int64("request_bytes", "number of request body bytes sent", "By")
+	mRespBytes    = stats.Int64("response_bytes", "number of response body bytes received", "By")
 
 	// Tag Keys
 	urlKey, _   = tag.NewKey("url")
@@ -52,9 +55,30 @@ var (
 		Aggregation: view.Count(),
 		TagKeys:     []tag.Key{urlKey, errorKey},
 	}
+	numStreamsView = &view.View{
+		Name:        "num_streams",
+		Description: "total number of streamed requests received",
+		Measure:     mNumStreams,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{urlKey},
+	}
+	requestBytesView = &view.View{
+		Name:        "request_bytes",
+		Description: "total number of request body bytes sent",
+		Measure:     mRequestBytes,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{urlKey},
+	}
+	responseBytesView = &view.View{
+		Name:        "response_bytes",
+		Description: "total number of response body bytes received",
+		Measure:     mRespBytes,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{urlKey},
+	}
 )
 
 // RegisterMetricViews registers all metric views for proxy for Opencensus to actively record.
 func RegisterMetricViews() error {
-	return view.Register(numRequestsView, proxyErrorsView, clientErrorsView)
+	return view.Register(numRequestsView, proxyErrorsView, clientErrorsView, numStreamsView, requestBytesView, responseBytesView)
 }
@@ -16,6 +16,9 @@
 // The server side implementation will be responsible for dialing the proxied
 // destination with the proper http method and credentials and then proxying
 // the responses back to the caller.
+//
+// Large or chunked bodies and upgraded connections, such as WebSockets, are
+// streamed with the HTTPStream RPC, which the Client uses to send requests.
 package httpovergrpc
 
 import (
@@ -98,6 +101,7 @@ func (s *Service) HTTPRequest(ctx context.Context, req *hpb.Request) (*hpb.Respo
 		return nil, fmt.Errorf("failed to read HTTP response: %w", err)
 	}
 	log.Infof("Response status: %q body:\n%s", response.Status, string(body))
+	stats.Record(ctx, mRequestBytes.M(int64(len(req.GetBody()))), mRespBytes.M(int64(len(body))))
 
 	var rHeaders []*hpb.Header
 	for k, vals := range response.Header {
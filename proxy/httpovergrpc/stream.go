@@ -0,0 +1,190 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpovergrpc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	log "github.com/golang/glog"
+	closer "github.com/openconfig/gocloser"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"golang.org/x/net/http/httpguts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	hpb "github.com/openconfig/ondatra/proxy/proto/httpovergrpc"
+)
+
+// chunkSize is the maximum size of the body chunk in a stream frame.
+const chunkSize = 32 * 1024
+
+// HTTPStream performs an HTTP request, streaming the request and response
+// bodies, and the data of the upgraded connection if the response switches
+// protocols.
+func (s *Service) HTTPStream(stream hpb.HTTPOverGRPC_HTTPStreamServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	head := first.GetHead()
+	if head == nil {
+		return status.Errorf(codes.InvalidArgument, "first frame of the stream has no request head")
+	}
+	ctx, _ := tag.New(stream.Context(), tag.Upsert(urlKey, head.GetMethod()))
+	stats.Record(ctx, mNumRequests.M(1), mNumStreams.M(1))
+	recordErr := func(m *stats.Int64Measure, err error) {
+		stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(errorKey, err.Error())}, m.M(1))
+	}
+
+	// Receive the rest of the request body, or the data to send on the upgraded
+	// connection, into a pipe.
+	var reqBytes atomic.Int64
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		for {
+			frame, err := stream.Recv()
+			if err == io.EOF {
+				pw.Close()
+				return
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if frame.GetHead() != nil {
+				pw.CloseWithError(fmt.Errorf("request head received after the first frame"))
+				return
+			}
+			reqBytes.Add(int64(len(frame.GetBody())))
+			if _, err := pw.Write(frame.GetBody()); err != nil {
+				return
+			}
+		}
+	}()
+	in := io.MultiReader(bytes.NewReader(head.GetBody()), pr)
+
+	header := toHTTPHeader(head.GetHeaders())
+	upgrade := isUpgrade(header)
+	body := in
+	if upgrade {
+		body = http.NoBody
+	}
+	request, err := http.NewRequestWithContext(ctx, head.GetMethod(), head.GetUrl(), body)
+	if err != nil {
+		return fmt.Errorf("error creating a new HTTP request: %v", err)
+	}
+	request.Header = header
+	if n, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil && !upgrade {
+		request.ContentLength = n
+		if n == 0 {
+			request.Body = http.NoBody
+		}
+	}
+
+	log.Infof("Streaming request method: %s URL: %s", head.GetMethod(), head.GetUrl())
+	response, err := s.client.Do(request)
+	if err != nil {
+		recordErr(mClientErrors, err)
+		return fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer closer.CloseAndLog(response.Body.Close, "failed to close response body")
+	log.Infof("Streaming response status: %q", response.Status)
+	if err := stream.Send(&hpb.StreamResponse{Frame: &hpb.StreamResponse_Head{Head: &hpb.Response{
+		Status:  int32(response.StatusCode),
+		Headers: fromHTTPHeader(response.Header),
+	}}}); err != nil {
+		return err
+	}
+
+	if response.StatusCode == http.StatusSwitchingProtocols {
+		conn, ok := response.Body.(io.Writer)
+		if !ok {
+			err := fmt.Errorf("upgraded connection of type %T is not writable", response.Body)
+			recordErr(mProxyErrors, err)
+			return err
+		}
+		// Unblock the read of the upgraded connection when the stream ends.
+		go func() {
+			<-ctx.Done()
+			response.Body.Close()
+		}()
+		go func() {
+			if _, err := io.Copy(conn, in); err != nil {
+				log.Warningf("Error writing to upgraded connection: %v", err)
+			}
+		}()
+	}
+	n, err := sendChunks(response.Body, func(b []byte) error {
+		return stream.Send(&hpb.StreamResponse{Frame: &hpb.StreamResponse_Body{Body: b}})
+	})
+	stats.Record(ctx, mRequestBytes.M(reqBytes.Load()), mRespBytes.M(n))
+	if err != nil {
+		recordErr(mProxyErrors, err)
+		return fmt.Errorf("failed to stream HTTP response: %w", err)
+	}
+	return nil
+}
+
+// sendChunks reads r to the end and sends what it reads in chunks, returning
+// the number of bytes sent.
+func sendChunks(r io.Reader, send func([]byte) error) (int64, error) {
+	var n int64
+	buf := make([]byte, chunkSize)
+	for {
+		m, err := r.Read(buf)
+		if m > 0 {
+			if err := send(buf[:m]); err != nil {
+				return n, err
+			}
+			n += int64(m)
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// isUpgrade reports whether the headers request a protocol upgrade.
+func isUpgrade(h http.Header) bool {
+	return h.Get("Upgrade") != "" && httpguts.HeaderValuesContainsToken(h["Connection"], "Upgrade")
+}
+
+func toHTTPHeader(headers []*hpb.Header) http.Header {
+	h := make(http.Header)
+	for _, header := range headers {
+		for _, v := range header.GetValues() {
+			h.Add(header.GetKey(), v)
+		}
+	}
+	return h
+}
+
+func fromHTTPHeader(h http.Header) []*hpb.Header {
+	var headers []*hpb.Header
+	for k, vals := range h {
+		headers = append(headers, &hpb.Header{Key: k, Values: append([]string{}, vals...)})
+	}
+	return headers
+}
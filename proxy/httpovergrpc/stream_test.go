@@ -0,0 +1,212 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpovergrpc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/local"
+
+	hpb "github.com/openconfig/ondatra/proxy/proto/httpovergrpc"
+)
+
+// bigBody is larger than several stream chunks.
+var bigBody = bytes.Repeat([]byte("0123456789abcdef"), 10*chunkSize/16+7)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		// Flush in pieces, so that the response is chunked.
+		for i := 0; i < len(bigBody); i += 1000 {
+			w.Write(bigBody[i:min(i+1000, len(bigBody))])
+			w.(http.Flusher).Flush()
+		}
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "%d %t", len(b), bytes.Equal(b, bigBody))
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "echo" {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		rw.Flush()
+		for {
+			line, err := rw.ReadString('\n')
+			if err != nil {
+				return
+			}
+			rw.WriteString(strings.ToUpper(line))
+			rw.Flush()
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	srv := grpc.NewServer(grpc.Creds(local.NewCredentials()))
+	hpb.RegisterHTTPOverGRPCServer(srv, New())
+	conn, err := grpc.DialContext(context.Background(), startServer(t, srv), grpc.WithTransportCredentials(local.NewCredentials()))
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn)
+}
+
+func TestStream(t *testing.T) {
+	httpSrv := newTestServer(t)
+	c := newTestClient(t)
+
+	tests := []struct {
+		desc     string
+		method   string
+		path     string
+		body     io.Reader
+		wantCode int
+		wantBody []byte
+	}{{
+		desc:     "chunked download",
+		method:   http.MethodGet,
+		path:     "/download",
+		wantCode: http.StatusOK,
+		wantBody: bigBody,
+	}, {
+		desc:     "upload of unknown length",
+		method:   http.MethodPost,
+		path:     "/upload",
+		body:     io.MultiReader(bytes.NewReader(bigBody)),
+		wantCode: http.StatusOK,
+		wantBody: []byte(fmt.Sprintf("%d true", len(bigBody))),
+	}, {
+		desc:     "upload of known length",
+		method:   http.MethodPost,
+		path:     "/upload",
+		body:     bytes.NewReader(bigBody),
+		wantCode: http.StatusOK,
+		wantBody: []byte(fmt.Sprintf("%d true", len(bigBody))),
+	}, {
+		desc:     "upgrade refused",
+		method:   http.MethodGet,
+		path:     "/echo",
+		wantCode: http.StatusUpgradeRequired,
+		wantBody: []byte("upgrade required\n"),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, httpSrv.URL+tt.path, tt.body)
+			if err != nil {
+				t.Fatalf("NewRequest() failed: %v", err)
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("Do() failed: %v", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("ReadAll() failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("Do() got status %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if !bytes.Equal(body, tt.wantBody) {
+				t.Errorf("Do() got body of %d bytes, want %d bytes", len(body), len(tt.wantBody))
+			}
+		})
+	}
+}
+
+func TestStreamUpgrade(t *testing.T) {
+	httpSrv := newTestServer(t)
+	c := newTestClient(t)
+
+	req, err := http.NewRequest(http.MethodGet, httpSrv.URL+"/echo", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "echo")
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Do() got status %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		t.Fatalf("Do() got body of type %T, want io.ReadWriteCloser", resp.Body)
+	}
+	r := bufio.NewReader(conn)
+	for _, msg := range []string{"hello\n", "world\n"} {
+		if _, err := io.WriteString(conn, msg); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+		got, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("ReadString() failed: %v", err)
+		}
+		if want := strings.ToUpper(msg); got != want {
+			t.Errorf("ReadString() got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestStreamNoHead(t *testing.T) {
+	srv := grpc.NewServer(grpc.Creds(local.NewCredentials()))
+	hpb.RegisterHTTPOverGRPCServer(srv, New())
+	conn, err := grpc.DialContext(context.Background(), startServer(t, srv), grpc.WithTransportCredentials(local.NewCredentials()))
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	stream, err := hpb.NewHTTPOverGRPCClient(conn).HTTPStream(context.Background())
+	if err != nil {
+		t.Fatalf("HTTPStream() failed: %v", err)
+	}
+	if err := stream.Send(&hpb.StreamRequest{Frame: &hpb.StreamRequest_Body{Body: []byte("body")}}); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+	if _, err := stream.Recv(); err == nil || !strings.Contains(err.Error(), "no request head") {
+		t.Errorf("Recv() got error %v, want no request head", err)
+	}
+}
@@ -20,7 +20,8 @@ set -e
 
 cd "$( dirname "${BASH_SOURCE[0]}" )"
 mkdir -p reservation
-protoc -I=.:../.. --go_out=./reservation --go_opt=paths=source_relative reservation.proto
+protoc -I=.:../.. --go_out=./reservation --go_opt=paths=source_relative \
+  --go-grpc_out=./reservation --go-grpc_opt=paths=source_relative reservation.proto
 mkdir -p httpovergrpc
 protoc -I=.:../.. --go_out=./httpovergrpc --go_opt=paths=source_relative \
   --go-grpc_out=./httpovergrpc --go-grpc_opt=paths=source_relative  httpovergrpc.proto
@@ -227,6 +227,176 @@ func (x *Response) GetBody() []byte {
 	return nil
 }
 
+// A frame of an HTTP 1.1 request streamed over gRPC.
+type StreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Frame:
+	//	*StreamRequest_Head
+	//	*StreamRequest_Body
+	Frame isStreamRequest_Frame `protobuf_oneof:"frame"`
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_httpovergrpc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_httpovergrpc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_httpovergrpc_proto_rawDescGZIP(), []int{3}
+}
+
+func (m *StreamRequest) GetFrame() isStreamRequest_Frame {
+	if m != nil {
+		return m.Frame
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetHead() *Request {
+	if x, ok := x.GetFrame().(*StreamRequest_Head); ok {
+		return x.Head
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetBody() []byte {
+	if x, ok := x.GetFrame().(*StreamRequest_Body); ok {
+		return x.Body
+	}
+	return nil
+}
+
+type isStreamRequest_Frame interface {
+	isStreamRequest_Frame()
+}
+
+type StreamRequest_Head struct {
+	// Head of the request, sent in the first frame. Its body, if any, is
+	// sent before the body chunks.
+	Head *Request `protobuf:"bytes,1,opt,name=head,proto3,oneof"`
+}
+
+type StreamRequest_Body struct {
+	// Chunk of the request body or, after the protocol has been upgraded,
+	// of the data sent on the upgraded connection.
+	Body []byte `protobuf:"bytes,2,opt,name=body,proto3,oneof"`
+}
+
+func (*StreamRequest_Head) isStreamRequest_Frame() {}
+
+func (*StreamRequest_Body) isStreamRequest_Frame() {}
+
+// A frame of an HTTP 1.1 response streamed over gRPC.
+type StreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Frame:
+	//	*StreamResponse_Head
+	//	*StreamResponse_Body
+	Frame isStreamResponse_Frame `protobuf_oneof:"frame"`
+}
+
+func (x *StreamResponse) Reset() {
+	*x = StreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_httpovergrpc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamResponse) ProtoMessage() {}
+
+func (x *StreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_httpovergrpc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamResponse.ProtoReflect.Descriptor instead.
+func (*StreamResponse) Descriptor() ([]byte, []int) {
+	return file_httpovergrpc_proto_rawDescGZIP(), []int{4}
+}
+
+func (m *StreamResponse) GetFrame() isStreamResponse_Frame {
+	if m != nil {
+		return m.Frame
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetHead() *Response {
+	if x, ok := x.GetFrame().(*StreamResponse_Head); ok {
+		return x.Head
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetBody() []byte {
+	if x, ok := x.GetFrame().(*StreamResponse_Body); ok {
+		return x.Body
+	}
+	return nil
+}
+
+type isStreamResponse_Frame interface {
+	isStreamResponse_Frame()
+}
+
+type StreamResponse_Head struct {
+	// Head of the response, sent in the first frame. Its body, if any, is
+	// sent before the body chunks.
+	Head *Response `protobuf:"bytes,1,opt,name=head,proto3,oneof"`
+}
+
+type StreamResponse_Body struct {
+	// Chunk of the response body or, after the protocol has been upgraded,
+	// of the data received on the upgraded connection.
+	Body []byte `protobuf:"bytes,2,opt,name=body,proto3,oneof"`
+}
+
+func (*StreamResponse_Head) isStreamResponse_Frame() {}
+
+func (*StreamResponse_Body) isStreamResponse_Frame() {}
+
 var File_httpovergrpc_proto protoreflect.FileDescriptor
 
 var file_httpovergrpc_proto_rawDesc = []byte{
@@ -249,16 +419,32 @@ var file_httpovergrpc_proto_rawDesc = []byte{
 	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x6f, 0x76, 0x65, 0x72, 0x67,
 	0x72, 0x70, 0x63, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64,
 	0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x32, 0x4e, 0x0a, 0x0c, 0x48, 0x54, 0x54, 0x50, 0x4f,
-	0x76, 0x65, 0x72, 0x47, 0x52, 0x50, 0x43, 0x12, 0x3e, 0x0a, 0x0b, 0x48, 0x54, 0x54, 0x50, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x6f, 0x76, 0x65,
-	0x72, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
-	0x68, 0x74, 0x74, 0x70, 0x6f, 0x76, 0x65, 0x72, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x2f, 0x6f, 0x6e, 0x64, 0x61, 0x74, 0x72, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x68,
-	0x74, 0x74, 0x70, 0x6f, 0x76, 0x65, 0x72, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x5b, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x68, 0x65, 0x61, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x6f, 0x76, 0x65,
+	0x72, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52,
+	0x04, 0x68, 0x65, 0x61, 0x64, 0x12, 0x14, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x42, 0x07, 0x0a, 0x05, 0x66,
+	0x72, 0x61, 0x6d, 0x65, 0x22, 0x5d, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x68, 0x65, 0x61, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x6f, 0x76, 0x65, 0x72, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x04,
+	0x68, 0x65, 0x61, 0x64, 0x12, 0x14, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x42, 0x07, 0x0a, 0x05, 0x66, 0x72,
+	0x61, 0x6d, 0x65, 0x32, 0x9d, 0x01, 0x0a, 0x0c, 0x48, 0x54, 0x54, 0x50, 0x4f, 0x76, 0x65, 0x72,
+	0x47, 0x52, 0x50, 0x43, 0x12, 0x3e, 0x0a, 0x0b, 0x48, 0x54, 0x54, 0x50, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x15, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x6f, 0x76, 0x65, 0x72, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x68, 0x74, 0x74,
+	0x70, 0x6f, 0x76, 0x65, 0x72, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0a, 0x48, 0x54, 0x54, 0x50, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x1b, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x6f, 0x76, 0x65, 0x72, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x6f, 0x76, 0x65, 0x72, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28,
+	0x01, 0x30, 0x01, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x6f, 0x6e, 0x64,
+	0x61, 0x74, 0x72, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x68, 0x74, 0x74, 0x70, 0x6f,
+	0x76, 0x65, 0x72, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -273,22 +459,28 @@ func file_httpovergrpc_proto_rawDescGZIP() []byte {
 	return file_httpovergrpc_proto_rawDescData
 }
 
-var file_httpovergrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_httpovergrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_httpovergrpc_proto_goTypes = []interface{}{
-	(*Header)(nil),   // 0: httpovergrpc.Header
-	(*Request)(nil),  // 1: httpovergrpc.Request
-	(*Response)(nil), // 2: httpovergrpc.Response
+	(*Header)(nil),         // 0: httpovergrpc.Header
+	(*Request)(nil),        // 1: httpovergrpc.Request
+	(*Response)(nil),       // 2: httpovergrpc.Response
+	(*StreamRequest)(nil),  // 3: httpovergrpc.StreamRequest
+	(*StreamResponse)(nil), // 4: httpovergrpc.StreamResponse
 }
 var file_httpovergrpc_proto_depIdxs = []int32{
 	0, // 0: httpovergrpc.Request.headers:type_name -> httpovergrpc.Header
 	0, // 1: httpovergrpc.Response.headers:type_name -> httpovergrpc.Header
-	1, // 2: httpovergrpc.HTTPOverGRPC.HTTPRequest:input_type -> httpovergrpc.Request
-	2, // 3: httpovergrpc.HTTPOverGRPC.HTTPRequest:output_type -> httpovergrpc.Response
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1, // 2: httpovergrpc.StreamRequest.head:type_name -> httpovergrpc.Request
+	2, // 3: httpovergrpc.StreamResponse.head:type_name -> httpovergrpc.Response
+	1, // 4: httpovergrpc.HTTPOverGRPC.HTTPRequest:input_type -> httpovergrpc.Request
+	3, // 5: httpovergrpc.HTTPOverGRPC.HTTPStream:input_type -> httpovergrpc.StreamRequest
+	2, // 6: httpovergrpc.HTTPOverGRPC.HTTPRequest:output_type -> httpovergrpc.Response
+	4, // 7: httpovergrpc.HTTPOverGRPC.HTTPStream:output_type -> httpovergrpc.StreamResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_httpovergrpc_proto_init() }
@@ -333,6 +525,38 @@ func file_httpovergrpc_proto_init() {
 				return nil
 			}
 		}
+		file_httpovergrpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_httpovergrpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_httpovergrpc_proto_msgTypes[3].OneofWrappers = []interface{}{
+		(*StreamRequest_Head)(nil),
+		(*StreamRequest_Body)(nil),
+	}
+	file_httpovergrpc_proto_msgTypes[4].OneofWrappers = []interface{}{
+		(*StreamResponse_Head)(nil),
+		(*StreamResponse_Body)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -340,7 +564,7 @@ func file_httpovergrpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_httpovergrpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
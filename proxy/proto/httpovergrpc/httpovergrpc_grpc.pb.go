@@ -21,6 +21,12 @@ const _ = grpc.SupportPackageIsVersion7
 type HTTPOverGRPCClient interface {
 	// Perform the given HTTP request over gRPC.
 	HTTPRequest(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	// Perform an HTTP request over gRPC, streaming the request and response
+	// bodies in chunks. The client ends the request body by closing its side of
+	// the stream, and the server ends the response body by ending the stream.
+	// If the response switches protocols, as for a WebSocket, the stream then
+	// carries the data of the upgraded connection in both directions.
+	HTTPStream(ctx context.Context, opts ...grpc.CallOption) (HTTPOverGRPC_HTTPStreamClient, error)
 }
 
 type hTTPOverGRPCClient struct {
@@ -40,12 +46,49 @@ func (c *hTTPOverGRPCClient) HTTPRequest(ctx context.Context, in *Request, opts
 	return out, nil
 }
 
+func (c *hTTPOverGRPCClient) HTTPStream(ctx context.Context, opts ...grpc.CallOption) (HTTPOverGRPC_HTTPStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HTTPOverGRPC_ServiceDesc.Streams[0], "/httpovergrpc.HTTPOverGRPC/HTTPStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hTTPOverGRPCHTTPStreamClient{stream}
+	return x, nil
+}
+
+type HTTPOverGRPC_HTTPStreamClient interface {
+	Send(*StreamRequest) error
+	Recv() (*StreamResponse, error)
+	grpc.ClientStream
+}
+
+type hTTPOverGRPCHTTPStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *hTTPOverGRPCHTTPStreamClient) Send(m *StreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *hTTPOverGRPCHTTPStreamClient) Recv() (*StreamResponse, error) {
+	m := new(StreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // HTTPOverGRPCServer is the server API for HTTPOverGRPC service.
 // All implementations must embed UnimplementedHTTPOverGRPCServer
 // for forward compatibility
 type HTTPOverGRPCServer interface {
 	// Perform the given HTTP request over gRPC.
 	HTTPRequest(context.Context, *Request) (*Response, error)
+	// Perform an HTTP request over gRPC, streaming the request and response
+	// bodies in chunks. The client ends the request body by closing its side of
+	// the stream, and the server ends the response body by ending the stream.
+	// If the response switches protocols, as for a WebSocket, the stream then
+	// carries the data of the upgraded connection in both directions.
+	HTTPStream(HTTPOverGRPC_HTTPStreamServer) error
 	mustEmbedUnimplementedHTTPOverGRPCServer()
 }
 
@@ -56,6 +99,9 @@ type UnimplementedHTTPOverGRPCServer struct {
 func (UnimplementedHTTPOverGRPCServer) HTTPRequest(context.Context, *Request) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HTTPRequest not implemented")
 }
+func (UnimplementedHTTPOverGRPCServer) HTTPStream(HTTPOverGRPC_HTTPStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method HTTPStream not implemented")
+}
 func (UnimplementedHTTPOverGRPCServer) mustEmbedUnimplementedHTTPOverGRPCServer() {}
 
 // UnsafeHTTPOverGRPCServer may be embedded to opt out of forward compatibility for this service.
@@ -87,6 +133,32 @@ func _HTTPOverGRPC_HTTPRequest_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HTTPOverGRPC_HTTPStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(HTTPOverGRPCServer).HTTPStream(&hTTPOverGRPCHTTPStreamServer{stream})
+}
+
+type HTTPOverGRPC_HTTPStreamServer interface {
+	Send(*StreamResponse) error
+	Recv() (*StreamRequest, error)
+	grpc.ServerStream
+}
+
+type hTTPOverGRPCHTTPStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *hTTPOverGRPCHTTPStreamServer) Send(m *StreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *hTTPOverGRPCHTTPStreamServer) Recv() (*StreamRequest, error) {
+	m := new(StreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // HTTPOverGRPC_ServiceDesc is the grpc.ServiceDesc for HTTPOverGRPC service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,6 +171,13 @@ var HTTPOverGRPC_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _HTTPOverGRPC_HTTPRequest_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "HTTPStream",
+			Handler:       _HTTPOverGRPC_HTTPStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "httpovergrpc.proto",
 }
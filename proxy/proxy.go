@@ -127,6 +127,9 @@ func New(d Dialer, sOpts ...grpc.ServerOption) (*Proxy, error) {
 		}
 	}
 	for k, a := range resv.GetAtes() {
+		if err := p.addGRPC(a); err != nil {
+			return nil, err
+		}
 		if err := p.addHTTP(k, a); err != nil {
 			return nil, err
 		}
@@ -195,9 +198,9 @@ func (p *Proxy) Stop() error {
 
 func (p *Proxy) addGRPC(d *rpb.ResolvedDevice) error {
 	for name, s := range d.GetServices() {
-		// Target has no services exported, nothing to do.
+		// Service is not proxied, nothing to do.
 		if s == nil || s.GetProxiedGrpc().GetAddress() == "" {
-			return nil
+			continue
 		}
 		targetAddr := s.GetProxiedGrpc().GetAddress()
 		if _, ok := p.gProxies[targetAddr]; ok {
@@ -256,6 +259,8 @@ func (p *Proxy) addHTTP(key string, d *rpb.ResolvedDevice) error {
 	if err != nil {
 		return fmt.Errorf("failed to create new HTTP client: %w", err)
 	}
+	// The service forwards both unary and streamed requests to the client, so
+	// the client receives streamed request bodies as they arrive.
 	sProxy := httpovergrpc.New(httpovergrpc.WithClient(c))
 	srv := grpc.NewServer(p.sOpts...)
 	hpb.RegisterHTTPOverGRPCServer(srv, sProxy)
@@ -26,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/ondatra/internal/fakegnmi"
+	"github.com/openconfig/ondatra/proxy/httpovergrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/credentials/local"
@@ -389,3 +390,39 @@ func TestHTTPProxy(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPStreamProxy(t *testing.T) {
+	f, b := setupHTTP(t, "fake.target.com")
+	f.responseCode = http.StatusOK
+	f.responseBody = bytes.Repeat([]byte("export"), 100000)
+	m, err := New(b, grpc.Creds(local.NewCredentials()))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer m.Stop()
+	conn, err := grpc.DialContext(context.Background(), m.Endpoints()["ate1"].Addr, grpc.WithTransportCredentials(local.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial proxy: %v", err)
+	}
+	defer conn.Close()
+
+	req, err := http.NewRequest(http.MethodPost, "https://fake.target.com/api/export", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+	resp, err := httpovergrpc.NewClient(conn).Do(req)
+	if err != nil {
+		t.Fatalf("Do() failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(body, f.responseBody) {
+		t.Errorf("Do() got status %d and body of %d bytes, want %d and %d bytes", resp.StatusCode, len(body), http.StatusOK, len(f.responseBody))
+	}
+	if got, want := f.req.URL.String(), "https://fake.target.com/api/export"; got != want {
+		t.Errorf("HTTP request got URL %q, want %q", got, want)
+	}
+}
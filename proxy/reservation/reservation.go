@@ -0,0 +1,304 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reservation implements a reference reservation service on top of
+// Ondatra bindings, so that a shared lab controller can hand reservations to
+// many test runners, each of which uses the proxybind binding to reach the
+// reserved devices.
+//
+// The service holds a pool of bindings, each of which can hold one reservation
+// at a time. A reservation that has a run time is released by the service
+// when it expires, unless it is extended first.
+package reservation
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/binding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	rpb "github.com/openconfig/ondatra/proxy/proto/reservation"
+)
+
+// defaultWaitTime is how long Reserve waits for a free binding and for the
+// binding to reserve the testbed when the request has no wait time.
+const defaultWaitTime = 5 * time.Minute
+
+// ServicesFn returns the services of a reserved device, keyed by service ID,
+// given the ID of the device in the testbed.
+type ServicesFn func(id string, dev binding.Device) (map[string]*rpb.Service, error)
+
+// Service implements the reservation service.
+type Service struct {
+	rpb.UnimplementedReservationServiceServer
+	servicesFn ServicesFn
+	free       chan binding.Binding
+
+	mu     sync.Mutex
+	leases map[string]*lease
+	nextID int
+}
+
+// lease is a reservation held by one of the bindings of the service.
+type lease struct {
+	bind  binding.Binding
+	resv  *rpb.Reservation
+	timer *time.Timer
+}
+
+// Option defines the options to configure the reservation service.
+type Option func(s *Service)
+
+// WithServices sets the function that returns the services of each reserved
+// device. By default, reserved devices have no services.
+func WithServices(fn ServicesFn) Option {
+	return func(s *Service) {
+		s.servicesFn = fn
+	}
+}
+
+// New creates a new reservation service that reserves testbeds with the
+// specified bindings, none of which may be used by anything else.
+func New(binds []binding.Binding, opts ...Option) (*Service, error) {
+	if len(binds) == 0 {
+		return nil, fmt.Errorf("reservation service needs at least one binding")
+	}
+	s := &Service{
+		servicesFn: func(string, binding.Device) (map[string]*rpb.Service, error) { return nil, nil },
+		free:       make(chan binding.Binding, len(binds)),
+		leases:     make(map[string]*lease),
+	}
+	for _, b := range binds {
+		s.free <- b
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Reserve reserves the testbed with the first binding that is free within the
+// wait time.
+func (s *Service) Reserve(ctx context.Context, req *rpb.ReserveRequest) (*rpb.Reservation, error) {
+	if req.GetTestbed() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "no testbed in request")
+	}
+	runTime := req.GetRunTime().AsDuration()
+	waitTime := req.GetWaitTime().AsDuration()
+	if runTime < 0 || waitTime < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "negative run time %v or wait time %v", runTime, waitTime)
+	}
+	if waitTime == 0 {
+		waitTime = defaultWaitTime
+	}
+	deadline := time.Now().Add(waitTime)
+	timer := time.NewTimer(waitTime)
+	defer timer.Stop()
+
+	var b binding.Binding
+	select {
+	case b = <-s.free:
+	case <-timer.C:
+		return nil, status.Errorf(codes.ResourceExhausted, "no binding became free within %v", waitTime)
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	res, err := b.Reserve(ctx, req.GetTestbed(), runTime, time.Until(deadline), req.GetPartial())
+	if err != nil {
+		s.free <- b
+		return nil, status.Errorf(codes.Unavailable, "error reserving testbed: %v", err)
+	}
+	resv, err := s.toProto(res)
+	if err != nil {
+		if err := s.releaseBinding(ctx, b); err != nil {
+			log.Errorf("Error releasing unresolved reservation %q: %v", res.ID, err)
+		}
+		return nil, status.Errorf(codes.Internal, "error resolving reservation %q: %v", res.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resv.Id = s.uniqueID(res.ID)
+	l := &lease{bind: b, resv: resv}
+	if runTime > 0 {
+		resv.ExpireTime = timestamppb.New(time.Now().Add(runTime))
+		l.timer = time.AfterFunc(runTime, func() { s.expire(resv.GetId(), l) })
+	}
+	s.leases[resv.GetId()] = l
+	log.Infof("Reserved %q for %v", resv.GetId(), runTime)
+	return proto.Clone(resv).(*rpb.Reservation), nil
+}
+
+// Release releases a reservation and frees its binding.
+func (s *Service) Release(ctx context.Context, req *rpb.ReleaseRequest) (*rpb.ReleaseResponse, error) {
+	s.mu.Lock()
+	l, ok := s.leases[req.GetId()]
+	if ok {
+		delete(s.leases, req.GetId())
+		if l.timer != nil {
+			l.timer.Stop()
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "reservation %q not found", req.GetId())
+	}
+	if err := s.releaseBinding(ctx, l.bind); err != nil {
+		return nil, status.Errorf(codes.Internal, "error releasing reservation %q: %v", req.GetId(), err)
+	}
+	log.Infof("Released %q", req.GetId())
+	return &rpb.ReleaseResponse{}, nil
+}
+
+// Get returns a reservation.
+func (s *Service) Get(_ context.Context, req *rpb.GetRequest) (*rpb.Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.leases[req.GetId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "reservation %q not found", req.GetId())
+	}
+	return proto.Clone(l.resv).(*rpb.Reservation), nil
+}
+
+// List returns all current reservations, ordered by ID.
+func (s *Service) List(context.Context, *rpb.ListRequest) (*rpb.ListResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := new(rpb.ListResponse)
+	for _, l := range s.leases {
+		resp.Reservations = append(resp.Reservations, proto.Clone(l.resv).(*rpb.Reservation))
+	}
+	sort.Slice(resp.Reservations, func(i, j int) bool {
+		return resp.Reservations[i].GetId() < resp.Reservations[j].GetId()
+	})
+	return resp, nil
+}
+
+// Extend extends a reservation, so that it is held for at least the run time
+// from now. A reservation that does not expire is unchanged. If the binding of
+// the reservation implements binding.Renewer, it is extended as well.
+func (s *Service) Extend(ctx context.Context, req *rpb.ExtendRequest) (*rpb.Reservation, error) {
+	d := req.GetRunTime().AsDuration()
+	if d <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "non-positive run time %v", d)
+	}
+	s.mu.Lock()
+	l, ok := s.leases[req.GetId()]
+	s.mu.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "reservation %q not found", req.GetId())
+	}
+	if r, ok := l.bind.(binding.Renewer); ok {
+		if err := r.Extend(ctx, d); err != nil {
+			return nil, status.Errorf(codes.Unavailable, "error extending reservation %q: %v", req.GetId(), err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.leases[req.GetId()] != l {
+		return nil, status.Errorf(codes.NotFound, "reservation %q was released", req.GetId())
+	}
+	if expire := time.Now().Add(d); l.timer != nil && expire.After(l.resv.GetExpireTime().AsTime()) {
+		l.resv.ExpireTime = timestamppb.New(expire)
+		l.timer.Reset(d)
+	}
+	return proto.Clone(l.resv).(*rpb.Reservation), nil
+}
+
+// expire releases a lease when its run time has elapsed, unless it was
+// already released or extended.
+func (s *Service) expire(id string, l *lease) {
+	s.mu.Lock()
+	if s.leases[id] != l || time.Now().Before(l.resv.GetExpireTime().AsTime()) {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.leases, id)
+	s.mu.Unlock()
+	log.Infof("Reservation %q expired", id)
+	if err := s.releaseBinding(context.Background(), l.bind); err != nil {
+		log.Errorf("Error releasing expired reservation %q: %v", id, err)
+	}
+}
+
+// releaseBinding releases the reservation of a binding and returns the
+// binding to the pool, even if the release fails, so that the pool does not
+// shrink.
+func (s *Service) releaseBinding(ctx context.Context, b binding.Binding) error {
+	defer func() { s.free <- b }()
+	return b.Release(ctx)
+}
+
+// uniqueID returns the ID of the reservation of a binding if it is not empty
+// and not already in use, else a new ID. It must be called with mu held.
+func (s *Service) uniqueID(id string) string {
+	for id == "" || s.leases[id] != nil {
+		s.nextID++
+		id = fmt.Sprintf("resv%d", s.nextID)
+	}
+	return id
+}
+
+func (s *Service) toProto(res *binding.Reservation) (*rpb.Reservation, error) {
+	resv := &rpb.Reservation{
+		Devices: make(map[string]*rpb.ResolvedDevice),
+		Ates:    make(map[string]*rpb.ResolvedDevice),
+	}
+	for id, dut := range res.DUTs {
+		rd, err := s.resolveDevice(id, dut)
+		if err != nil {
+			return nil, err
+		}
+		resv.Devices[id] = rd
+	}
+	for id, ate := range res.ATEs {
+		rd, err := s.resolveDevice(id, ate)
+		if err != nil {
+			return nil, err
+		}
+		resv.Ates[id] = rd
+	}
+	return resv, nil
+}
+
+func (s *Service) resolveDevice(id string, dev binding.Device) (*rpb.ResolvedDevice, error) {
+	svcs, err := s.servicesFn(id, dev)
+	if err != nil {
+		return nil, fmt.Errorf("error getting services of device %q: %w", id, err)
+	}
+	rd := &rpb.ResolvedDevice{
+		Id:              id,
+		Name:            dev.Name(),
+		Vendor:          dev.Vendor(),
+		HardwareModel:   dev.HardwareModel(),
+		SoftwareVersion: dev.SoftwareVersion(),
+		Ports:           make(map[string]*rpb.ResolvedPort),
+		Services:        svcs,
+	}
+	for pid, p := range dev.Ports() {
+		rd.Ports[pid] = &rpb.ResolvedPort{Id: pid, Name: p.Name, Speed: p.Speed}
+	}
+	return rd, nil
+}
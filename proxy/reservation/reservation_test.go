@@ -0,0 +1,183 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reservation
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/fakebind"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	opb "github.com/openconfig/ondatra/proto"
+	rpb "github.com/openconfig/ondatra/proxy/proto/reservation"
+)
+
+// fakeBinding returns a fake binding that reserves one DUT and counts its
+// releases.
+func fakeBinding(id string, released *int, mu *sync.Mutex) *fakebind.Binding {
+	return &fakebind.Binding{
+		ReserveFn: func(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+			return &binding.Reservation{
+				ID: id,
+				DUTs: map[string]binding.DUT{"dut": &fakebind.DUT{AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{
+					Name:   "dut1.lab",
+					Vendor: opb.Device_ARISTA,
+					Ports:  map[string]*binding.Port{"port1": {Name: "Ethernet1", Speed: opb.Port_S_100GB}},
+				}}}},
+			}, nil
+		},
+		ReleaseFn: func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*released++
+			return nil
+		},
+	}
+}
+
+func TestService(t *testing.T) {
+	var mu sync.Mutex
+	var released int
+	svcs := map[string]*rpb.Service{
+		"gnmi": {Id: "gnmi", Endpoint: &rpb.Service_Grpc{Grpc: &rpb.GRPCEndpoint{Address: "dut1.lab:9339"}}},
+	}
+	s, err := New([]binding.Binding{fakeBinding("", &released, &mu), fakeBinding("", &released, &mu)},
+		WithServices(func(id string, dev binding.Device) (map[string]*rpb.Service, error) {
+			return svcs, nil
+		}))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	req := &rpb.ReserveRequest{Testbed: &opb.Testbed{}, WaitTime: durationpb.New(10 * time.Millisecond)}
+
+	resv1, err := s.Reserve(ctx, req)
+	if err != nil {
+		t.Fatalf("Reserve() failed: %v", err)
+	}
+	wantResv := &rpb.Reservation{
+		Id: "resv1",
+		Devices: map[string]*rpb.ResolvedDevice{"dut": {
+			Id:       "dut",
+			Name:     "dut1.lab",
+			Vendor:   opb.Device_ARISTA,
+			Ports:    map[string]*rpb.ResolvedPort{"port1": {Id: "port1", Name: "Ethernet1", Speed: opb.Port_S_100GB}},
+			Services: svcs,
+		}},
+		Ates: map[string]*rpb.ResolvedDevice{},
+	}
+	if diff := cmp.Diff(wantResv, resv1, protocmp.Transform()); diff != "" {
+		t.Errorf("Reserve() got unexpected diff (-want +got):\n%s", diff)
+	}
+	resv2, err := s.Reserve(ctx, req)
+	if err != nil {
+		t.Fatalf("Reserve() failed: %v", err)
+	}
+	if resv2.GetId() != "resv2" {
+		t.Errorf("Reserve() got ID %q, want %q", resv2.GetId(), "resv2")
+	}
+	if _, err := s.Reserve(ctx, req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Reserve() with no free binding got error %v, want code %v", err, codes.ResourceExhausted)
+	}
+
+	list, err := s.List(ctx, &rpb.ListRequest{})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if got, want := len(list.GetReservations()), 2; got != want {
+		t.Errorf("List() got %d reservations, want %d", got, want)
+	}
+	if _, err := s.Get(ctx, &rpb.GetRequest{Id: "resv1"}); err != nil {
+		t.Errorf("Get() failed: %v", err)
+	}
+	if _, err := s.Release(ctx, &rpb.ReleaseRequest{Id: "resv1"}); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if _, err := s.Get(ctx, &rpb.GetRequest{Id: "resv1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() of released reservation got error %v, want code %v", err, codes.NotFound)
+	}
+	if _, err := s.Release(ctx, &rpb.ReleaseRequest{Id: "resv1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Release() of released reservation got error %v, want code %v", err, codes.NotFound)
+	}
+	if _, err := s.Reserve(ctx, req); err != nil {
+		t.Errorf("Reserve() after Release() failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if released != 1 {
+		t.Errorf("Release() released %d bindings, want 1", released)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	var mu sync.Mutex
+	var released int
+	s, err := New([]binding.Binding{fakeBinding("lab1", &released, &mu)})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+	resv, err := s.Reserve(ctx, &rpb.ReserveRequest{Testbed: &opb.Testbed{}, RunTime: durationpb.New(100 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("Reserve() failed: %v", err)
+	}
+	if resv.GetId() != "lab1" {
+		t.Errorf("Reserve() got ID %q, want %q", resv.GetId(), "lab1")
+	}
+	if resv.GetExpireTime() == nil {
+		t.Errorf("Reserve() got no expire time, want one")
+	}
+
+	if _, err := s.Extend(ctx, &rpb.ExtendRequest{Id: "lab1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Extend() with no run time got error %v, want code %v", err, codes.InvalidArgument)
+	}
+	extended, err := s.Extend(ctx, &rpb.ExtendRequest{Id: "lab1", RunTime: durationpb.New(time.Second)})
+	if err != nil {
+		t.Fatalf("Extend() failed: %v", err)
+	}
+	if !extended.GetExpireTime().AsTime().After(resv.GetExpireTime().AsTime()) {
+		t.Errorf("Extend() got expire time %v, want after %v", extended.GetExpireTime().AsTime(), resv.GetExpireTime().AsTime())
+	}
+	time.Sleep(200 * time.Millisecond)
+	if _, err := s.Get(ctx, &rpb.GetRequest{Id: "lab1"}); err != nil {
+		t.Errorf("Get() of extended reservation failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := s.Get(ctx, &rpb.GetRequest{Id: "lab1"})
+		if status.Code(err) == codes.NotFound {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Get() of expired reservation got error %v, want code %v", err, codes.NotFound)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if released != 1 {
+		t.Errorf("Reservation expiry released %d bindings, want 1", released)
+	}
+}
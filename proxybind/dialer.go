@@ -0,0 +1,144 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxybind
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/ondatra/proxy"
+	"github.com/openconfig/ondatra/proxy/grpcproxy"
+	"github.com/openconfig/ondatra/proxy/httpovergrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	rpb "github.com/openconfig/ondatra/proxy/proto/reservation"
+)
+
+// HopsKey is the metadata key of the remaining hops of a proxied gRPC call:
+// the addresses of the proxies after the one called, in order, followed by
+// the address of the service.
+const HopsKey = "x-ondatra-proxy-hops"
+
+// HopDestProvider is a grpcproxy.DestProviderFn that forwards a call to the
+// next of its remaining hops and removes that hop from the metadata. A jump
+// host in the proxy chain of a proxied gRPC endpoint runs a gRPC proxy with
+// this provider and a dialer with the transport security of the next hops:
+//
+//	srv, err := grpcproxy.NewServer(proxybind.HopDestProvider, grpcproxy.WithDialer(d))
+var HopDestProvider grpcproxy.DestProviderFn = func(md metadata.MD, _ string) (string, metadata.MD, error) {
+	hops := md.Get(HopsKey)
+	if len(hops) == 0 {
+		return "", nil, status.Errorf(codes.InvalidArgument, "proxy_error: no %s metadata", HopsKey)
+	}
+	md = md.Copy()
+	md.Delete(HopsKey)
+	if len(hops) > 1 {
+		md.Set(HopsKey, hops[1:]...)
+	}
+	return hops[0], md, nil
+}
+
+// dialer dials the services of a reservation for its proxy.
+type dialer struct {
+	resv     *rpb.Reservation
+	dialOpts []grpc.DialOption
+}
+
+// DialGRPC dials a proxied gRPC service of the reservation: directly if its
+// endpoint has no proxies, else through the first proxy, with the remaining
+// hops in the metadata of every call.
+func (d *dialer) DialGRPC(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	var chain []string
+	for _, svc := range d.services() {
+		if ep := svc.GetProxiedGrpc(); ep.GetAddress() == target {
+			chain = ep.GetProxy()
+			break
+		}
+	}
+	opts = append(append([]grpc.DialOption{}, d.dialOpts...), opts...)
+	if len(chain) == 0 {
+		log.Infof("Dialing proxied service at %s", target)
+		return grpc.DialContext(ctx, target, opts...)
+	}
+	hops := append(append([]string{}, chain[1:]...), target)
+	log.Infof("Dialing proxied service at %s through proxy %s, then %v", target, chain[0], hops)
+	opts = append(opts,
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(metadata.AppendToOutgoingContext(ctx, hopPairs(hops)...), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(metadata.AppendToOutgoingContext(ctx, hopPairs(hops)...), desc, cc, method, opts...)
+		}),
+	)
+	return grpc.DialContext(ctx, chain[0], opts...)
+}
+
+func hopPairs(hops []string) []string {
+	var kv []string
+	for _, hop := range hops {
+		kv = append(kv, HopsKey, hop)
+	}
+	return kv
+}
+
+// Resolve returns the reservation.
+func (d *dialer) Resolve() (*rpb.Reservation, error) {
+	return d.resv, nil
+}
+
+// HTTPClient returns a client that sends HTTP requests to the HTTP-over-gRPC
+// service at the target address.
+func (d *dialer) HTTPClient(target string) (proxy.HTTPDoCloser, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), grpcDialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, target, d.dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error dialing HTTP-over-gRPC service at %s: %w", target, err)
+	}
+	return newHTTPClient(conn), nil
+}
+
+func (d *dialer) services() []*rpb.Service {
+	var svcs []*rpb.Service
+	for _, devs := range []map[string]*rpb.ResolvedDevice{d.resv.GetDevices(), d.resv.GetAtes()} {
+		for _, rd := range devs {
+			for _, svc := range rd.GetServices() {
+				svcs = append(svcs, svc)
+			}
+		}
+	}
+	return svcs
+}
+
+// httpClient sends HTTP requests over an HTTP-over-gRPC connection,
+// streaming the request and response bodies.
+type httpClient struct {
+	*httpovergrpc.Client
+	conn *grpc.ClientConn
+}
+
+func newHTTPClient(conn *grpc.ClientConn) *httpClient {
+	return &httpClient{Client: httpovergrpc.NewClient(conn), conn: conn}
+}
+
+// Close closes the connection.
+func (c *httpClient) Close() error {
+	return c.conn.Close()
+}
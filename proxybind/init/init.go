@@ -0,0 +1,56 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package init installs the Ondatra binding for testing with the devices of a
+// reservation proto. It also installs a --reservation flag, which specifies a
+// textproto reservation file, and a --reservation_service flag, which
+// specifies the address of a reservation service. Exactly one of them must be
+// provided. See the reservation proto for syntax details.
+package init
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/proxybind"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	reservation        = flag.String("reservation", "", "Path to a textproto reservation file")
+	reservationService = flag.String("reservation_service", "", "Address of a reservation service")
+)
+
+// Init provides a generator for a proxy bind instance which uses the
+// reservation set by the flag --reservation or reserved from the service set by
+// the flag --reservation_service. To be used with ondatra.RunTests.
+func Init() (binding.Binding, error) {
+	if (*reservation == "") == (*reservationService == "") {
+		return nil, errors.New("exactly one of --reservation and --reservation_service flags must be provided")
+	}
+	if *reservationService != "" {
+		conn, err := grpc.Dial(*reservationService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, err
+		}
+		return proxybind.New(proxybind.ServiceReserver(conn))
+	}
+	r, err := proxybind.FileReserver(*reservation)
+	if err != nil {
+		return nil, err
+	}
+	return proxybind.New(r)
+}
@@ -0,0 +1,539 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proxybind provides an Ondatra binding for devices described by a
+// reservation proto, such as the devices of a remote lab behind a jump host.
+//
+// The binding gets the reservation from a [Reserver]: from a reservation file
+// or from a reservation service. It starts a [proxy.Proxy] for the
+// reservation, which listens locally for every proxied gRPC service and every
+// HTTP-over-gRPC service of the devices, and the devices of the binding dial
+// their services through those local endpoints. The proxy dials a proxied
+// gRPC service through the chain of proxies of its endpoint, each of which is
+// a gRPC proxy that forwards to the next hop named by [HopDestProvider].
+package proxybind
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/gnoigo"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	acctzpb "github.com/openconfig/gnsi/acctz"
+	authzpb "github.com/openconfig/gnsi/authz"
+	certzpb "github.com/openconfig/gnsi/certz"
+	credzpb "github.com/openconfig/gnsi/credentialz"
+	pathzpb "github.com/openconfig/gnsi/pathz"
+	grpb "github.com/openconfig/gribi/v1/proto/service"
+	opb "github.com/openconfig/ondatra/proto"
+	rpb "github.com/openconfig/ondatra/proxy/proto/reservation"
+	p4pb "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+const grpcDialTimeout = 30 * time.Second
+
+// Names of the well-known services of a reserved device.
+const (
+	gnmiService  = "gnmi"
+	gnoiService  = "gnoi"
+	gnsiService  = "gnsi"
+	gribiService = "gribi"
+	otgService   = "otg"
+	p4rtService  = "p4rt"
+)
+
+// Reserver reserves testbeds as reservation protos.
+type Reserver interface {
+	// Reserve reserves devices and ports matching the testbed.
+	Reserve(ctx context.Context, tb *opb.Testbed, runTime, waitTime time.Duration, partial map[string]string) (*rpb.Reservation, error)
+	// Fetch returns the existing reservation with the specified ID.
+	Fetch(ctx context.Context, id string) (*rpb.Reservation, error)
+	// Release releases the reservation with the specified ID.
+	Release(ctx context.Context, id string) error
+}
+
+// Extender is an optional extension of the Reserver interface for reservers
+// whose reservations expire unless they are extended.
+type Extender interface {
+	// Extend extends the reservation with the specified ID, so that it is held
+	// for at least the specified duration from now.
+	Extend(ctx context.Context, id string, d time.Duration) error
+}
+
+// FileReserver returns a reserver of the fixed reservation in a textproto
+// file. It checks that the reservation has every device and port of the
+// testbed, with the same IDs.
+func FileReserver(path string) (Reserver, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading reservation file: %w", err)
+	}
+	resv := new(rpb.Reservation)
+	if err := prototext.Unmarshal(b, resv); err != nil {
+		return nil, fmt.Errorf("error parsing reservation file %s: %w", path, err)
+	}
+	return &fileReserver{resv: resv}, nil
+}
+
+type fileReserver struct {
+	resv *rpb.Reservation
+}
+
+func (r *fileReserver) Reserve(_ context.Context, tb *opb.Testbed, _, _ time.Duration, _ map[string]string) (*rpb.Reservation, error) {
+	if err := checkCovers(r.resv, tb); err != nil {
+		return nil, err
+	}
+	return r.resv, nil
+}
+
+func (r *fileReserver) Fetch(_ context.Context, id string) (*rpb.Reservation, error) {
+	if id != r.resv.GetId() {
+		return nil, fmt.Errorf("reservation %q not found, the reservation file has ID %q", id, r.resv.GetId())
+	}
+	return r.resv, nil
+}
+
+// Release is a no-op because the reservation file is not held by the binding.
+func (r *fileReserver) Release(context.Context, string) error {
+	return nil
+}
+
+// ServiceReserver returns a reserver of the reservations of a reservation
+// service, such as the one of a shared lab controller, at the other end of
+// the connection. The reserver is also an [Extender].
+func ServiceReserver(conn grpc.ClientConnInterface) Reserver {
+	return &serviceReserver{client: rpb.NewReservationServiceClient(conn)}
+}
+
+type serviceReserver struct {
+	client rpb.ReservationServiceClient
+}
+
+func (c *serviceReserver) Reserve(ctx context.Context, tb *opb.Testbed, runTime, waitTime time.Duration, partial map[string]string) (*rpb.Reservation, error) {
+	resv, err := c.client.Reserve(ctx, &rpb.ReserveRequest{
+		Testbed:  tb,
+		RunTime:  durationpb.New(runTime),
+		WaitTime: durationpb.New(waitTime),
+		Partial:  partial,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reserving testbed from reservation service: %w", err)
+	}
+	if err := checkCovers(resv, tb); err != nil {
+		if _, rerr := c.client.Release(ctx, &rpb.ReleaseRequest{Id: resv.GetId()}); rerr != nil {
+			log.Errorf("Error releasing reservation %q: %v", resv.GetId(), rerr)
+		}
+		return nil, err
+	}
+	return resv, nil
+}
+
+func (c *serviceReserver) Fetch(ctx context.Context, id string) (*rpb.Reservation, error) {
+	resv, err := c.client.Get(ctx, &rpb.GetRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reservation %q from reservation service: %w", id, err)
+	}
+	return resv, nil
+}
+
+func (c *serviceReserver) Release(ctx context.Context, id string) error {
+	if _, err := c.client.Release(ctx, &rpb.ReleaseRequest{Id: id}); err != nil {
+		return fmt.Errorf("error releasing reservation %q from reservation service: %w", id, err)
+	}
+	return nil
+}
+
+func (c *serviceReserver) Extend(ctx context.Context, id string, d time.Duration) error {
+	if _, err := c.client.Extend(ctx, &rpb.ExtendRequest{Id: id, RunTime: durationpb.New(d)}); err != nil {
+		return fmt.Errorf("error extending reservation %q from reservation service: %w", id, err)
+	}
+	return nil
+}
+
+// checkCovers checks that the reservation has every device and port of the
+// testbed.
+func checkCovers(resv *rpb.Reservation, tb *opb.Testbed) error {
+	check := func(kind string, devs map[string]*rpb.ResolvedDevice, want []*opb.Device) error {
+		for _, d := range want {
+			rd, ok := devs[d.GetId()]
+			if !ok {
+				return fmt.Errorf("reservation %q has no %s %q", resv.GetId(), kind, d.GetId())
+			}
+			for _, p := range d.GetPorts() {
+				if _, ok := rd.GetPorts()[p.GetId()]; !ok {
+					return fmt.Errorf("reservation %q has no port %q on %s %q", resv.GetId(), p.GetId(), kind, d.GetId())
+				}
+			}
+		}
+		return nil
+	}
+	if err := check("DUT", resv.GetDevices(), tb.GetDuts()); err != nil {
+		return err
+	}
+	return check("ATE", resv.GetAtes(), tb.GetAtes())
+}
+
+// New returns a new proxy binding for the reservations of the reserver.
+// The dial options are used to dial the gRPC services of the devices and the
+// proxies in front of them; if none are specified, they are dialed without
+// transport security.
+func New(r Reserver, dialOpts ...grpc.DialOption) (*Bind, error) {
+	if r == nil {
+		return nil, fmt.Errorf("reserver cannot be nil")
+	}
+	if len(dialOpts) == 0 {
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	return &Bind{r: r, dialOpts: dialOpts}, nil
+}
+
+var _ binding.Renewer = (*Bind)(nil)
+
+// Bind implements the ondatra Binding interface for reservation protos.
+type Bind struct {
+	binding.Binding
+	r        Reserver
+	dialOpts []grpc.DialOption
+
+	mu    sync.Mutex
+	resv  *rpb.Reservation
+	proxy *proxy.Proxy
+}
+
+// Reserve implements the binding Reserve method by reserving the testbed with
+// the reserver and proxying the services of the reserved devices.
+func (b *Bind) Reserve(ctx context.Context, tb *opb.Testbed, runTime, waitTime time.Duration, partial map[string]string) (*binding.Reservation, error) {
+	if err := b.checkNotHeld(); err != nil {
+		return nil, err
+	}
+	resv, err := b.r.Reserve(ctx, tb, runTime, waitTime, partial)
+	if err != nil {
+		return nil, err
+	}
+	res, err := b.bind(resv)
+	if err != nil {
+		if rerr := b.r.Release(ctx, resv.GetId()); rerr != nil {
+			log.Errorf("Error releasing reservation %q: %v", resv.GetId(), rerr)
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// FetchReservation implements the binding FetchReservation method by
+// fetching the reservation from the reserver and proxying the services of
+// its devices.
+func (b *Bind) FetchReservation(ctx context.Context, id string) (*binding.Reservation, error) {
+	if err := b.checkNotHeld(); err != nil {
+		return nil, err
+	}
+	resv, err := b.r.Fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return b.bind(resv)
+}
+
+// Release implements the binding Release method by stopping the proxies and
+// releasing the reservation with the reserver.
+func (b *Bind) Release(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.resv == nil {
+		return fmt.Errorf("no reservation to release")
+	}
+	if err := b.proxy.Stop(); err != nil {
+		log.Warningf("Error stopping proxies: %v", err)
+	}
+	id := b.resv.GetId()
+	b.resv, b.proxy = nil, nil
+	return b.r.Release(ctx, id)
+}
+
+// Extend implements the binding.Renewer Extend method by extending the
+// reservation with the reserver, if it is an [Extender].
+func (b *Bind) Extend(ctx context.Context, d time.Duration) error {
+	id, err := b.resvID()
+	if err != nil {
+		return err
+	}
+	e, ok := b.r.(Extender)
+	if !ok {
+		return fmt.Errorf("reserver does not support extending reservation %q", id)
+	}
+	return e.Extend(ctx, id, d)
+}
+
+// Heartbeat implements the binding.Renewer Heartbeat method by checking that
+// the reserver still has the reservation.
+func (b *Bind) Heartbeat(ctx context.Context) error {
+	id, err := b.resvID()
+	if err != nil {
+		return err
+	}
+	_, err = b.r.Fetch(ctx, id)
+	return err
+}
+
+func (b *Bind) resvID() (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.resv == nil {
+		return "", fmt.Errorf("no current reservation")
+	}
+	return b.resv.GetId(), nil
+}
+
+// checkNotHeld returns an error if the binding already holds a reservation,
+// whose proxies would otherwise be left running.
+func (b *Bind) checkNotHeld() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.resv != nil {
+		return fmt.Errorf("reservation %q is already held; release it first", b.resv.GetId())
+	}
+	return nil
+}
+
+// bind starts the proxies of the reservation and returns the binding
+// reservation with devices that dial through them.
+func (b *Bind) bind(resv *rpb.Reservation) (*binding.Reservation, error) {
+	p, err := proxy.New(&dialer{resv: resv, dialOpts: b.dialOpts})
+	if err != nil {
+		return nil, fmt.Errorf("error starting proxies for reservation %q: %w", resv.GetId(), err)
+	}
+	b.mu.Lock()
+	if b.resv != nil {
+		// Another reservation was bound concurrently.
+		held := b.resv.GetId()
+		b.mu.Unlock()
+		if err := p.Stop(); err != nil {
+			log.Warningf("Error stopping proxies: %v", err)
+		}
+		return nil, fmt.Errorf("reservation %q is already held; release it first", held)
+	}
+	b.resv, b.proxy = resv, p
+	b.mu.Unlock()
+
+	res := &binding.Reservation{
+		ID:   resv.GetId(),
+		DUTs: make(map[string]binding.DUT),
+		ATEs: make(map[string]binding.ATE),
+	}
+	for id, rd := range resv.GetDevices() {
+		res.DUTs[id] = &proxyDUT{
+			AbstractDUT: &binding.AbstractDUT{Dims: dims(rd)},
+			dev:         &device{id: id, rd: rd, proxy: p, dialOpts: b.dialOpts},
+		}
+	}
+	for id, rd := range resv.GetAtes() {
+		res.ATEs[id] = &proxyATE{
+			AbstractATE: &binding.AbstractATE{Dims: dims(rd)},
+			dev:         &device{id: id, rd: rd, proxy: p, dialOpts: b.dialOpts},
+		}
+	}
+	return res, nil
+}
+
+func dims(rd *rpb.ResolvedDevice) *binding.Dims {
+	ports := make(map[string]*binding.Port)
+	for id, p := range rd.GetPorts() {
+		ports[id] = &binding.Port{Name: p.GetName(), Speed: p.GetSpeed()}
+	}
+	return &binding.Dims{
+		Name:            rd.GetName(),
+		Vendor:          rd.GetVendor(),
+		HardwareModel:   rd.GetHardwareModel(),
+		SoftwareVersion: rd.GetSoftwareVersion(),
+		Ports:           ports,
+	}
+}
+
+// device is a reserved device and the proxy of its services.
+type device struct {
+	id       string
+	rd       *rpb.ResolvedDevice
+	proxy    *proxy.Proxy
+	dialOpts []grpc.DialOption
+}
+
+// dialGRPC dials the service of the device with the specified name: a proxied
+// service through its local proxy endpoint, and any other gRPC service
+// directly. The options of the caller are appended to the default options.
+func (d *device) dialGRPC(ctx context.Context, svcName string, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	svc, ok := d.rd.GetServices()[svcName]
+	if !ok {
+		return nil, fmt.Errorf("service %q not found on device %q", svcName, d.rd.GetName())
+	}
+	var addr string
+	var defaults []grpc.DialOption
+	switch {
+	case svc.GetProxiedGrpc() != nil:
+		ep, ok := d.proxy.Endpoints()[svc.GetProxiedGrpc().GetAddress()]
+		if !ok {
+			return nil, fmt.Errorf("no proxy for service %q on device %q", svcName, d.rd.GetName())
+		}
+		// The local proxy dials the service with the default options.
+		addr = ep.Addr
+		defaults = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	case svc.GetGrpc() != nil:
+		addr = svc.GetGrpc().GetAddress()
+		defaults = d.dialOpts
+	default:
+		return nil, fmt.Errorf("service %q on device %q is not a gRPC service", svcName, d.rd.GetName())
+	}
+	log.Infof("Dialing service %q on device %s at %s with options %v", svcName, d.rd.GetName(), addr, opts)
+	ctx, cancel := context.WithTimeout(ctx, grpcDialTimeout)
+	defer cancel()
+	return grpc.DialContext(ctx, addr, append(append([]grpc.DialOption{}, defaults...), opts...)...)
+}
+
+type proxyDUT struct {
+	*binding.AbstractDUT
+	dev *device
+}
+
+func (d *proxyDUT) DialGNMI(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+	conn, err := d.dev.dialGRPC(ctx, gnmiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gpb.NewGNMIClient(conn), nil
+}
+
+func (d *proxyDUT) DialGNOI(ctx context.Context, opts ...grpc.DialOption) (gnoigo.Clients, error) {
+	conn, err := d.dev.dialGRPC(ctx, gnoiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gnoigo.NewClients(conn), nil
+}
+
+func (d *proxyDUT) DialGRIBI(ctx context.Context, opts ...grpc.DialOption) (grpb.GRIBIClient, error) {
+	conn, err := d.dev.dialGRPC(ctx, gribiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return grpb.NewGRIBIClient(conn), nil
+}
+
+func (d *proxyDUT) DialP4RT(ctx context.Context, opts ...grpc.DialOption) (p4pb.P4RuntimeClient, error) {
+	conn, err := d.dev.dialGRPC(ctx, p4rtService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return p4pb.NewP4RuntimeClient(conn), nil
+}
+
+// gnsiConn implements the stub builder needed by the Ondatra
+// binding.Binding interface.
+type gnsiConn struct {
+	*binding.AbstractGNSIClients
+	conn *grpc.ClientConn
+}
+
+func (c *gnsiConn) Authz() authzpb.AuthzClient {
+	return authzpb.NewAuthzClient(c.conn)
+}
+
+func (c *gnsiConn) Pathz() pathzpb.PathzClient {
+	return pathzpb.NewPathzClient(c.conn)
+}
+
+func (c *gnsiConn) Certz() certzpb.CertzClient {
+	return certzpb.NewCertzClient(c.conn)
+}
+
+func (c *gnsiConn) Credentialz() credzpb.CredentialzClient {
+	return credzpb.NewCredentialzClient(c.conn)
+}
+
+func (c *gnsiConn) Acctz() acctzpb.AcctzClient {
+	return acctzpb.NewAcctzClient(c.conn)
+}
+
+func (d *proxyDUT) DialGNSI(ctx context.Context, opts ...grpc.DialOption) (binding.GNSIClients, error) {
+	conn, err := d.dev.dialGRPC(ctx, gnsiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &gnsiConn{conn: conn}, nil
+}
+
+// DialGRPC dials the service with the specified name.
+// It is exported so that test may use a type assertion to dial custom services.
+func (d *proxyDUT) DialGRPC(ctx context.Context, serviceName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return d.dev.dialGRPC(ctx, serviceName, opts)
+}
+
+type proxyATE struct {
+	*binding.AbstractATE
+	dev *device
+}
+
+func (a *proxyATE) DialOTG(ctx context.Context, opts ...grpc.DialOption) (gosnappi.GosnappiApi, error) {
+	conn, err := a.dev.dialGRPC(ctx, otgService, opts)
+	if err != nil {
+		return nil, err
+	}
+	api := gosnappi.NewApi()
+	api.NewGrpcTransport().
+		SetClientConnection(conn).
+		SetRequestTimeout(30 * time.Second)
+	return api, nil
+}
+
+func (a *proxyATE) DialGNMI(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+	conn, err := a.dev.dialGRPC(ctx, gnmiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gpb.NewGNMIClient(conn), nil
+}
+
+// DialGRPC dials the service with the specified name.
+// It is exported so that test may use a type assertion to dial custom services.
+func (a *proxyATE) DialGRPC(ctx context.Context, serviceName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return a.dev.dialGRPC(ctx, serviceName, opts)
+}
+
+// HTTPClient returns a client that sends HTTP requests to the ATE through the
+// local proxy endpoint of its HTTP-over-gRPC service. It is exported so that
+// tests may use a type assertion to make HTTP requests.
+func (a *proxyATE) HTTPClient(ctx context.Context) (proxy.HTTPDoCloser, error) {
+	ep, ok := a.dev.proxy.Endpoints()[a.dev.id]
+	if !ok {
+		return nil, fmt.Errorf("no HTTP proxy for ATE %q", a.Name())
+	}
+	ctx, cancel := context.WithTimeout(ctx, grpcDialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, ep.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return newHTTPClient(conn), nil
+}
@@ -0,0 +1,386 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxybind
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/fakebind"
+	"github.com/openconfig/ondatra/internal/fakegnmi"
+	"github.com/openconfig/ondatra/proxy/grpcproxy"
+	"github.com/openconfig/ondatra/proxy/httpovergrpc"
+	"github.com/openconfig/ondatra/proxy/reservation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	opb "github.com/openconfig/ondatra/proto"
+	hpb "github.com/openconfig/ondatra/proxy/proto/httpovergrpc"
+	rpb "github.com/openconfig/ondatra/proxy/proto/reservation"
+)
+
+// startServer serves the server on a local port and returns its address.
+func startServer(t *testing.T, srv grpcproxy.GRPCServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+type insecureDialer struct{}
+
+func (insecureDialer) DialGRPC(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return grpc.DialContext(ctx, target, append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+}
+
+type fakeHTTP struct {
+	req *http.Request
+}
+
+func (f *fakeHTTP) Do(req *http.Request) (*http.Response, error) {
+	f.req = req
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte("pong"))),
+	}, nil
+}
+
+func TestBind(t *testing.T) {
+	fake, err := fakegnmi.StartStubGNMI(0)
+	if err != nil {
+		t.Fatalf("StartStubGNMI() failed: %v", err)
+	}
+	defer fake.Stop()
+	fake.Stub().GetResponse(&gpb.GetResponse{})
+	ateFake, err := fakegnmi.StartStubGNMI(0)
+	if err != nil {
+		t.Fatalf("StartStubGNMI() failed: %v", err)
+	}
+	defer ateFake.Stop()
+	ateFake.Stub().GetResponse(&gpb.GetResponse{})
+
+	// Two jump hosts in front of the gNMI service.
+	jump1, err := grpcproxy.NewServer(HopDestProvider, grpcproxy.WithDialer(insecureDialer{}))
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	jump2, err := grpcproxy.NewServer(HopDestProvider, grpcproxy.WithDialer(insecureDialer{}))
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	jump1Addr, jump2Addr := startServer(t, jump1), startServer(t, jump2)
+
+	fakeHTTP := &fakeHTTP{}
+	httpSrv := grpc.NewServer()
+	hpb.RegisterHTTPOverGRPCServer(httpSrv, httpovergrpc.New(httpovergrpc.WithClient(fakeHTTP)))
+	httpAddr := startServer(t, httpSrv)
+
+	resvPath := filepath.Join(t.TempDir(), "reservation.textproto")
+	resvText := fmt.Sprintf(`
+id: "resv1"
+devices {
+  key: "dut"
+  value {
+    id: "dut"
+    name: "dut1.lab"
+    vendor: ARISTA
+    hardware_model: "7280"
+    ports {
+      key: "port1"
+      value { id: "port1" name: "Ethernet1" speed: S_100GB }
+    }
+    services {
+      key: "gnmi"
+      value { id: "gnmi" proxied_grpc { address: %q proxy: %q proxy: %q } }
+    }
+    services {
+      key: "gnoi"
+      value { id: "gnoi" http { address: "dut1.lab:443" } }
+    }
+  }
+}
+ates {
+  key: "ate"
+  value {
+    id: "ate"
+    name: "ate1.lab"
+    services {
+      key: "http"
+      value { id: "http" http_over_grpc { address: %q } }
+    }
+    services {
+      key: "gnmi"
+      value { id: "gnmi" proxied_grpc { address: %q proxy: %q } }
+    }
+  }
+}
+`, fake.Addr(), jump1Addr, jump2Addr, httpAddr, ateFake.Addr(), jump1Addr)
+	if err := os.WriteFile(resvPath, []byte(resvText), 0600); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	r, err := FileReserver(resvPath)
+	if err != nil {
+		t.Fatalf("FileReserver() failed: %v", err)
+	}
+	b, err := New(r)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	tb := &opb.Testbed{
+		Duts: []*opb.Device{{Id: "dut", Ports: []*opb.Port{{Id: "port1"}}}},
+		Ates: []*opb.Device{{Id: "ate"}},
+	}
+	res, err := b.Reserve(ctx, tb, time.Minute, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("Reserve() failed: %v", err)
+	}
+	defer func() {
+		if err := b.Release(ctx); err != nil {
+			t.Errorf("Release() failed: %v", err)
+		}
+	}()
+
+	dut := res.DUTs["dut"]
+	wantDims := &binding.Dims{
+		Name:          "dut1.lab",
+		Vendor:        opb.Device_ARISTA,
+		HardwareModel: "7280",
+		Ports:         map[string]*binding.Port{"port1": {Name: "Ethernet1", Speed: opb.Port_S_100GB}},
+	}
+	if diff := cmp.Diff(wantDims, dut.(*proxyDUT).Dims); diff != "" {
+		t.Errorf("Reserve() got DUT dims diff (-want +got):\n%s", diff)
+	}
+
+	t.Run("gNMI through jump hosts", func(t *testing.T) {
+		gnmiC, err := dut.DialGNMI(ctx)
+		if err != nil {
+			t.Fatalf("DialGNMI() failed: %v", err)
+		}
+		if _, err := gnmiC.Get(ctx, &gpb.GetRequest{}); err != nil {
+			t.Errorf("Get() failed: %v", err)
+		}
+	})
+
+	t.Run("ATE gNMI through jump host", func(t *testing.T) {
+		gnmiC, err := res.ATEs["ate"].DialGNMI(ctx)
+		if err != nil {
+			t.Fatalf("DialGNMI() failed: %v", err)
+		}
+		if _, err := gnmiC.Get(ctx, &gpb.GetRequest{}); err != nil {
+			t.Errorf("Get() failed: %v", err)
+		}
+	})
+
+	t.Run("reservation already held", func(t *testing.T) {
+		if _, err := b.Reserve(ctx, tb, time.Minute, time.Minute, nil); err == nil || !strings.Contains(err.Error(), "already held") {
+			t.Errorf("Reserve() got error %v, want already held", err)
+		}
+		if _, err := b.FetchReservation(ctx, "resv1"); err == nil || !strings.Contains(err.Error(), "already held") {
+			t.Errorf("FetchReservation() got error %v, want already held", err)
+		}
+	})
+
+	t.Run("not a gRPC service", func(t *testing.T) {
+		if _, err := dut.DialGNOI(ctx); err == nil || !strings.Contains(err.Error(), "not a gRPC service") {
+			t.Errorf("DialGNOI() got error %v, want not a gRPC service", err)
+		}
+	})
+
+	t.Run("HTTP over gRPC", func(t *testing.T) {
+		ate := res.ATEs["ate"].(*proxyATE)
+		c, err := ate.HTTPClient(ctx)
+		if err != nil {
+			t.Fatalf("HTTPClient() failed: %v", err)
+		}
+		defer c.Close()
+		req, err := http.NewRequest(http.MethodPost, "https://ate1.lab/api/ping", strings.NewReader("ping"))
+		if err != nil {
+			t.Fatalf("NewRequest() failed: %v", err)
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("Do() failed: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("ReadAll() failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || string(body) != "pong" || resp.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("Do() got status %d, body %q, headers %v; want 200, %q, text/plain", resp.StatusCode, body, resp.Header, "pong")
+		}
+		if got, want := fakeHTTP.req.URL.String(), "https://ate1.lab/api/ping"; got != want {
+			t.Errorf("HTTP request got URL %q, want %q", got, want)
+		}
+	})
+}
+
+func TestReserveErrors(t *testing.T) {
+	resvPath := filepath.Join(t.TempDir(), "reservation.textproto")
+	resvText := `
+id: "resv1"
+devices {
+  key: "dut"
+  value { id: "dut" ports { key: "port1" value { id: "port1" } } }
+}
+`
+	if err := os.WriteFile(resvPath, []byte(resvText), 0600); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	r, err := FileReserver(resvPath)
+	if err != nil {
+		t.Fatalf("FileReserver() failed: %v", err)
+	}
+	b, err := New(r)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		desc    string
+		tb      *opb.Testbed
+		wantErr string
+	}{{
+		desc:    "missing DUT",
+		tb:      &opb.Testbed{Duts: []*opb.Device{{Id: "dut2"}}},
+		wantErr: `has no DUT "dut2"`,
+	}, {
+		desc:    "missing port",
+		tb:      &opb.Testbed{Duts: []*opb.Device{{Id: "dut", Ports: []*opb.Port{{Id: "port2"}}}}},
+		wantErr: `has no port "port2" on DUT "dut"`,
+	}, {
+		desc:    "missing ATE",
+		tb:      &opb.Testbed{Ates: []*opb.Device{{Id: "ate"}}},
+		wantErr: `has no ATE "ate"`,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if _, err := b.Reserve(ctx, test.tb, 0, 0, nil); err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Reserve() got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+
+	if _, err := b.FetchReservation(ctx, "resv2"); err == nil || !strings.Contains(err.Error(), `"resv2" not found`) {
+		t.Errorf("FetchReservation() got error %v, want not found", err)
+	}
+	res, err := b.FetchReservation(ctx, "resv1")
+	if err != nil {
+		t.Fatalf("FetchReservation() failed: %v", err)
+	}
+	if res.ID != "resv1" {
+		t.Errorf("FetchReservation() got ID %q, want %q", res.ID, "resv1")
+	}
+}
+
+func TestServiceReserver(t *testing.T) {
+	var released bool
+	fake := &fakebind.Binding{
+		ReserveFn: func(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+			return &binding.Reservation{
+				ID: "resv1",
+				DUTs: map[string]binding.DUT{"dut": &fakebind.DUT{AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{
+					Name:  "dut1.lab",
+					Ports: map[string]*binding.Port{"port1": {Name: "Ethernet1"}},
+				}}}},
+			}, nil
+		},
+		ReleaseFn: func(context.Context) error {
+			released = true
+			return nil
+		},
+	}
+	svc, err := reservation.New([]binding.Binding{fake})
+	if err != nil {
+		t.Fatalf("reservation.New() failed: %v", err)
+	}
+	srv := grpc.NewServer()
+	rpb.RegisterReservationServiceServer(srv, svc)
+	conn, err := grpc.Dial(startServer(t, srv), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	b, err := New(ServiceReserver(conn))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	tb := &opb.Testbed{Duts: []*opb.Device{{Id: "dut", Ports: []*opb.Port{{Id: "port1"}}}}}
+	res, err := b.Reserve(ctx, tb, time.Minute, time.Second, nil)
+	if err != nil {
+		t.Fatalf("Reserve() failed: %v", err)
+	}
+	if got, want := res.DUTs["dut"].Name(), "dut1.lab"; got != want {
+		t.Errorf("Reserve() got DUT name %q, want %q", got, want)
+	}
+	if err := b.Extend(ctx, time.Hour); err != nil {
+		t.Errorf("Extend() failed: %v", err)
+	}
+	if err := b.Heartbeat(ctx); err != nil {
+		t.Errorf("Heartbeat() failed: %v", err)
+	}
+	if _, err := b.FetchReservation(ctx, "resv2"); err == nil {
+		t.Errorf("FetchReservation() of unknown reservation got no error, want error")
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if !released {
+		t.Errorf("Release() did not release the reservation of the service binding")
+	}
+	if err := b.Heartbeat(ctx); err == nil {
+		t.Errorf("Heartbeat() after Release() got no error, want error")
+	}
+}
+
+func TestHopDestProvider(t *testing.T) {
+	md := metadata.Pairs("username", "admin", HopsKey, "jump2:8080", HopsKey, "dut:9339")
+	dest, gotMD, err := HopDestProvider(md, "/gnmi.gNMI/Get")
+	if err != nil {
+		t.Fatalf("HopDestProvider() failed: %v", err)
+	}
+	if dest != "jump2:8080" {
+		t.Errorf("HopDestProvider() got dest %q, want %q", dest, "jump2:8080")
+	}
+	if want := metadata.Pairs("username", "admin", HopsKey, "dut:9339"); !cmp.Equal(gotMD, want) {
+		t.Errorf("HopDestProvider() got metadata %v, want %v", gotMD, want)
+	}
+	if _, _, err := HopDestProvider(metadata.Pairs("username", "admin"), "/gnmi.gNMI/Get"); err == nil {
+		t.Errorf("HopDestProvider() got no error, want error for no hops")
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replaybind provides an Ondatra binding that replays the device RPC
+// traffic recorded by a previous test run, so that the test can be re-executed
+// deterministically without any devices.
+//
+// To record a test run, run the test with the -record flag:
+//
+//	go test -testbed=testbed.textproto -record=/tmp/run.record
+//
+// To replay the run, run the same test with a replay binding:
+//
+//	func TestMain(m *testing.M) {
+//		ondatra.RunTests(m, func() (binding.Binding, error) {
+//			return replaybind.New("/tmp/run.record")
+//		})
+//	}
+//
+// Every gRPC call and CLI command of the test is answered with the next
+// recorded call of the same method on the same device. The recorded requests
+// are not compared to the replayed ones, so a test that diverges from the
+// recording receives responses out of context. Config pushes are accepted and
+// ignored, because they are not recorded.
+package replaybind
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/gnoigo"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/internal/record"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	acctzpb "github.com/openconfig/gnsi/acctz"
+	authzpb "github.com/openconfig/gnsi/authz"
+	certzpb "github.com/openconfig/gnsi/certz"
+	credzpb "github.com/openconfig/gnsi/credentialz"
+	pathzpb "github.com/openconfig/gnsi/pathz"
+	grpb "github.com/openconfig/gribi/v1/proto/service"
+	opb "github.com/openconfig/ondatra/proto"
+	p4pb "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+const bufSize = 1 << 20
+
+// New returns a new binding that replays the record file at the specified path.
+func New(path string) (*Binding, error) {
+	entries, err := record.ReadLog(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 || entries[0].Kind != record.KindReservation {
+		return nil, fmt.Errorf("record file %s does not start with a reservation", path)
+	}
+	b := &Binding{
+		recRes: entries[0].Reservation,
+		calls:  make(map[string]map[string][]*call),
+	}
+	byID := make(map[uint64]*call)
+	for _, e := range entries[1:] {
+		c, ok := byID[e.Call]
+		if !ok {
+			c = new(call)
+			byID[e.Call] = c
+			methods, ok := b.calls[e.Device]
+			if !ok {
+				methods = make(map[string][]*call)
+				b.calls[e.Device] = methods
+			}
+			methods[e.Method] = append(methods[e.Method], c)
+		}
+		c.events = append(c.events, e)
+	}
+	return b, nil
+}
+
+var _ binding.Binding = (*Binding)(nil)
+
+// Binding implements the Ondatra binding interface by replaying a recording.
+type Binding struct {
+	recRes *record.Reservation
+	// calls maps device name to method to recorded calls, in recorded order.
+	calls map[string]map[string][]*call
+
+	mu   sync.Mutex
+	res  *binding.Reservation
+	devs []*device
+}
+
+// call is a recorded gRPC call or CLI command.
+type call struct {
+	events []*record.Entry
+}
+
+// Reserve returns the recorded reservation.
+func (b *Binding) Reserve(context.Context, *opb.Testbed, time.Duration, time.Duration, map[string]string) (*binding.Reservation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	res := &binding.Reservation{
+		ID:   b.recRes.ID,
+		DUTs: make(map[string]binding.DUT),
+		ATEs: make(map[string]binding.ATE),
+	}
+	for id, d := range b.recRes.DUTs {
+		res.DUTs[id] = &replayDUT{AbstractDUT: &binding.AbstractDUT{Dims: dims(d)}, dev: b.newDevice(d.Name)}
+	}
+	for id, a := range b.recRes.ATEs {
+		res.ATEs[id] = &replayATE{AbstractATE: &binding.AbstractATE{Dims: dims(a)}, dev: b.newDevice(a.Name)}
+	}
+	b.res = res
+	return res, nil
+}
+
+func dims(d *record.Device) *binding.Dims {
+	ports := make(map[string]*binding.Port)
+	for id, name := range d.Ports {
+		ports[id] = &binding.Port{Name: name}
+	}
+	return &binding.Dims{
+		Name:            d.Name,
+		Vendor:          d.Vendor,
+		HardwareModel:   d.HardwareModel,
+		SoftwareVersion: d.SoftwareVersion,
+		Ports:           ports,
+	}
+}
+
+// Release stops replaying.
+func (b *Binding) Release(context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, d := range b.devs {
+		d.srv.Stop()
+	}
+	b.devs = nil
+	b.res = nil
+	return nil
+}
+
+// FetchReservation returns the recorded reservation, if it was reserved.
+func (b *Binding) FetchReservation(_ context.Context, id string) (*binding.Reservation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.res == nil || b.res.ID != id {
+		return nil, fmt.Errorf("reservation %q not found", id)
+	}
+	return b.res, nil
+}
+
+// device serves the recorded calls of a single device.
+type device struct {
+	name string
+	lis  *bufconn.Listener
+	srv  *grpc.Server
+
+	mu    sync.Mutex
+	calls map[string][]*call
+}
+
+func (b *Binding) newDevice(name string) *device {
+	d := &device{
+		name:  name,
+		lis:   bufconn.Listen(bufSize),
+		calls: make(map[string][]*call),
+	}
+	for method, calls := range b.calls[name] {
+		d.calls[method] = append([]*call(nil), calls...)
+	}
+	d.srv = grpc.NewServer(grpc.UnknownServiceHandler(d.handle))
+	go d.srv.Serve(d.lis)
+	b.devs = append(b.devs, d)
+	return d
+}
+
+func (d *device) next(method string) *call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	calls := d.calls[method]
+	if len(calls) == 0 {
+		return nil
+	}
+	d.calls[method] = calls[1:]
+	return calls[0]
+}
+
+func (d *device) dial(ctx context.Context, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return d.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	return grpc.DialContext(ctx, d.name, opts...)
+}
+
+// handle replays the next recorded call of the stream's method. Messages are
+// relayed as raw wire bytes, in the unknown fields of an empty message, so
+// that any service can be replayed without knowing its message types.
+func (d *device) handle(_ any, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	c := d.next(method)
+	if c == nil {
+		return status.Errorf(codes.FailedPrecondition, "no more recorded calls of %s on %s", method, d.name)
+	}
+	for _, e := range c.events {
+		switch e.Kind {
+		case record.KindSend:
+			if err := stream.RecvMsg(new(emptypb.Empty)); err != nil && err != io.EOF {
+				return err
+			}
+		case record.KindCloseSend:
+			// The client closed the stream, so the next receive returns io.EOF.
+			if err := stream.RecvMsg(new(emptypb.Empty)); err != nil && err != io.EOF {
+				return err
+			}
+		case record.KindRecv:
+			m := new(emptypb.Empty)
+			m.ProtoReflect().SetUnknown(e.Message)
+			if err := stream.SendMsg(m); err != nil {
+				return err
+			}
+		case record.KindEnd:
+			switch e.Code {
+			case codes.OK:
+				return nil
+			case codes.Canceled, codes.DeadlineExceeded:
+				// The call was ended by the client, so wait for the client to end it.
+			default:
+				return status.Error(e.Code, e.Error)
+			}
+		}
+	}
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+type replayDUT struct {
+	*binding.AbstractDUT
+	dev *device
+}
+
+func (d *replayDUT) PushConfig(context.Context, string, bool) error {
+	return nil
+}
+
+func (d *replayDUT) DialCLI(context.Context) (binding.CLIClient, error) {
+	return &replayCLI{dev: d.dev}, nil
+}
+
+func (d *replayDUT) DialGNMI(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+	conn, err := d.dev.dial(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gpb.NewGNMIClient(conn), nil
+}
+
+func (d *replayDUT) DialGNOI(ctx context.Context, opts ...grpc.DialOption) (gnoigo.Clients, error) {
+	conn, err := d.dev.dial(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gnoigo.NewClients(conn), nil
+}
+
+func (d *replayDUT) DialGNSI(ctx context.Context, opts ...grpc.DialOption) (binding.GNSIClients, error) {
+	conn, err := d.dev.dial(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &gnsiConn{conn: conn}, nil
+}
+
+func (d *replayDUT) DialGRIBI(ctx context.Context, opts ...grpc.DialOption) (grpb.GRIBIClient, error) {
+	conn, err := d.dev.dial(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return grpb.NewGRIBIClient(conn), nil
+}
+
+func (d *replayDUT) DialP4RT(ctx context.Context, opts ...grpc.DialOption) (p4pb.P4RuntimeClient, error) {
+	conn, err := d.dev.dial(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return p4pb.NewP4RuntimeClient(conn), nil
+}
+
+type gnsiConn struct {
+	*binding.AbstractGNSIClients
+	conn *grpc.ClientConn
+}
+
+func (c *gnsiConn) Authz() authzpb.AuthzClient {
+	return authzpb.NewAuthzClient(c.conn)
+}
+
+func (c *gnsiConn) Pathz() pathzpb.PathzClient {
+	return pathzpb.NewPathzClient(c.conn)
+}
+
+func (c *gnsiConn) Certz() certzpb.CertzClient {
+	return certzpb.NewCertzClient(c.conn)
+}
+
+func (c *gnsiConn) Credentialz() credzpb.CredentialzClient {
+	return credzpb.NewCredentialzClient(c.conn)
+}
+
+func (c *gnsiConn) Acctz() acctzpb.AcctzClient {
+	return acctzpb.NewAcctzClient(c.conn)
+}
+
+type replayCLI struct {
+	*binding.AbstractCLIClient
+	dev *device
+}
+
+func (c *replayCLI) RunCommand(_ context.Context, cmd string) (binding.CommandResult, error) {
+	rc := c.dev.next(record.CLIMethod)
+	if rc == nil {
+		return nil, fmt.Errorf("no more recorded CLI commands on %s", c.dev.name)
+	}
+	res := &cmdResult{}
+	for _, e := range rc.events {
+		switch e.Kind {
+		case record.KindRecv:
+			res.output, res.error = string(e.Message), e.Error
+		case record.KindEnd:
+			if e.Code != codes.OK {
+				return nil, status.Error(e.Code, e.Error)
+			}
+		}
+	}
+	return res, nil
+}
+
+type cmdResult struct {
+	*binding.AbstractCommandResult
+	output, error string
+}
+
+func (r *cmdResult) Output() string {
+	return r.output
+}
+
+func (r *cmdResult) Error() string {
+	return r.error
+}
+
+type replayATE struct {
+	*binding.AbstractATE
+	dev *device
+}
+
+func (a *replayATE) DialGNMI(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+	conn, err := a.dev.dial(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gpb.NewGNMIClient(conn), nil
+}
+
+func (a *replayATE) DialOTG(ctx context.Context, opts ...grpc.DialOption) (gosnappi.GosnappiApi, error) {
+	conn, err := a.dev.dial(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	api := gosnappi.NewApi()
+	api.NewGrpcTransport().
+		SetClientConnection(conn).
+		SetRequestTimeout(30 * time.Second)
+	return api, nil
+}
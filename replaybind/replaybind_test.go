@@ -0,0 +1,282 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replaybind
+
+import (
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/cli"
+	"github.com/openconfig/ondatra/fakebind"
+	"github.com/openconfig/ondatra/internal/rawapis"
+	"github.com/openconfig/ondatra/internal/record"
+	"github.com/openconfig/ondatra/simbind/emulator"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	otgpb "github.com/open-traffic-generator/snappi/gosnappi/otg"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+type emuCLI struct {
+	*binding.AbstractCLIClient
+	dev *emulator.Device
+}
+
+func (c *emuCLI) RunCommand(_ context.Context, cmd string) (binding.CommandResult, error) {
+	out, errMsg := c.dev.RunCommand(cmd)
+	return &cmdResult{output: out, error: errMsg}, nil
+}
+
+// session runs a fixed sequence of calls and returns their results.
+type session struct {
+	setErr   codes.Code
+	value    string
+	notifs   []*gpb.Notification
+	cliOut   string
+	cliError string
+}
+
+type fakeOTG struct {
+	otgpb.UnimplementedOpenapiServer
+	cfg *otgpb.Config
+}
+
+func (s *fakeOTG) GetConfig(context.Context, *emptypb.Empty) (*otgpb.GetConfigResponse, error) {
+	return &otgpb.GetConfigResponse{Config: s.cfg}, nil
+}
+
+func runSession(t *testing.T, gnmiC gpb.GNMIClient, cli binding.CLIClient) *session {
+	t.Helper()
+	ctx := context.Background()
+	s := new(session)
+	c, err := ygnmi.NewClient(gnmiC, ygnmi.WithTarget("dut"))
+	if err != nil {
+		t.Fatalf("NewClient() got error: %v", err)
+	}
+	one := exampleocpath.Root().Parent().Child().One()
+	if _, err := ygnmi.Replace(ctx, c, one.Config(), "foo"); err != nil {
+		t.Fatalf("Replace() got error: %v", err)
+	}
+	s.value, err = ygnmi.Get(ctx, c, one.State())
+	if err != nil {
+		t.Fatalf("Get() got error: %v", err)
+	}
+	_, err = gnmiC.Set(ctx, &gpb.SetRequest{Update: []*gpb.Update{{
+		Path: &gpb.Path{Elem: []*gpb.PathElem{{Name: "bogus"}}},
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "x"}},
+	}}})
+	s.setErr = status.Code(err)
+
+	sub, err := gnmiC.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe() got error: %v", err)
+	}
+	if err := sub.Send(&gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Subscribe{Subscribe: &gpb.SubscriptionList{
+		Mode:         gpb.SubscriptionList_ONCE,
+		Subscription: []*gpb.Subscription{{Path: &gpb.Path{Elem: []*gpb.PathElem{{Name: "parent"}}}}},
+	}}}); err != nil {
+		t.Fatalf("Send() got error: %v", err)
+	}
+	for {
+		resp, err := sub.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() got error: %v", err)
+		}
+		if resp.GetSyncResponse() {
+			break
+		}
+		n := proto.Clone(resp.GetUpdate()).(*gpb.Notification)
+		n.Timestamp = 0
+		s.notifs = append(s.notifs, n)
+	}
+
+	res, err := cli.RunCommand(ctx, "show running-config")
+	if err != nil {
+		t.Fatalf("RunCommand() got error: %v", err)
+	}
+	s.cliOut, s.cliError = res.Output(), res.Error()
+	return s
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.record")
+	dev, err := emulator.New("dut", exampleoc.Schema)
+	if err != nil {
+		t.Fatalf("emulator.New() got error: %v", err)
+	}
+	defer dev.Close()
+	dev.PushConfig("hostname dut", false)
+
+	res := &binding.Reservation{
+		ID: "res1",
+		DUTs: map[string]binding.DUT{"dut1": &binding.AbstractDUT{Dims: &binding.Dims{
+			Name:   "dut",
+			Vendor: opb.Device_ARISTA,
+			Ports:  map[string]*binding.Port{"port1": {Name: "Ethernet1"}},
+		}}},
+	}
+	if err := record.Start(path, res); err != nil {
+		t.Fatalf("record.Start() got error: %v", err)
+	}
+	conn, err := dev.Dial(context.Background(),
+		grpc.WithChainUnaryInterceptor(record.UnaryInterceptor("dut")),
+		grpc.WithChainStreamInterceptor(record.StreamInterceptor("dut")))
+	if err != nil {
+		t.Fatalf("Dial() got error: %v", err)
+	}
+	want := runSession(t, gpb.NewGNMIClient(conn), record.CLIClient("dut", &emuCLI{dev: dev}))
+	if err := record.Stop(); err != nil {
+		t.Fatalf("record.Stop() got error: %v", err)
+	}
+
+	b, err := New(path)
+	if err != nil {
+		t.Fatalf("New() got error: %v", err)
+	}
+	ctx := context.Background()
+	replayRes, err := b.Reserve(ctx, nil, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("Reserve() got error: %v", err)
+	}
+	defer b.Release(ctx)
+	dut := replayRes.DUTs["dut1"]
+	if got, want := dut.Name(), "dut"; got != want {
+		t.Errorf("DUT name got %q, want %q", got, want)
+	}
+	if got, want := dut.Ports()["port1"].Name, "Ethernet1"; got != want {
+		t.Errorf("DUT port name got %q, want %q", got, want)
+	}
+	gnmiC, err := dut.DialGNMI(ctx)
+	if err != nil {
+		t.Fatalf("DialGNMI() got error: %v", err)
+	}
+	cli, err := dut.DialCLI(ctx)
+	if err != nil {
+		t.Fatalf("DialCLI() got error: %v", err)
+	}
+	got := runSession(t, gnmiC, cli)
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(session{}), protocmp.Transform()); diff != "" {
+		t.Errorf("Replayed session differs from recorded session (-want +got):\n%s", diff)
+	}
+
+	// All recorded calls have been consumed.
+	if _, err := gnmiC.Capabilities(ctx, &gpb.CapabilityRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Capabilities() after replay got error %v, want FailedPrecondition", err)
+	}
+}
+
+func TestRecordAndReplayCLIAndOTG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.record")
+	emu, err := emulator.New("dut", exampleoc.Schema)
+	if err != nil {
+		t.Fatalf("emulator.New() got error: %v", err)
+	}
+	defer emu.Close()
+	emu.PushConfig("hostname dut", false)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Listen() got error: %v", err)
+	}
+	srv := grpc.NewServer()
+	otgpb.RegisterOpenapiServer(srv, &fakeOTG{cfg: &otgpb.Config{Ports: []*otgpb.Port{{Name: "port1"}}}})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	dut := &fakebind.DUT{
+		AbstractDUT: &binding.AbstractDUT{Dims: &binding.Dims{Name: "dut", Vendor: opb.Device_ARISTA}},
+		DialCLIFn: func(context.Context) (binding.CLIClient, error) {
+			return &emuCLI{dev: emu}, nil
+		},
+	}
+	ate := &fakebind.ATE{
+		AbstractATE: &binding.AbstractATE{Dims: &binding.Dims{Name: "ate"}},
+		DialOTGFn: func(ctx context.Context, opts ...grpc.DialOption) (gosnappi.GosnappiApi, error) {
+			conn, err := grpc.DialContext(ctx, lis.Addr().String(), append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+			if err != nil {
+				return nil, err
+			}
+			api := gosnappi.NewApi()
+			api.NewGrpcTransport().SetClientConnection(conn).SetRequestTimeout(30 * time.Second)
+			return api, nil
+		},
+	}
+	res := &binding.Reservation{
+		ID:   "res1",
+		DUTs: map[string]binding.DUT{"dut1": dut},
+		ATEs: map[string]binding.ATE{"ate1": ate},
+	}
+	if err := record.Start(path, res); err != nil {
+		t.Fatalf("record.Start() got error: %v", err)
+	}
+	ctx := context.Background()
+	wantOut := cli.New(dut).Run(t, "show running-config")
+	otg, err := rawapis.FetchOTG(ctx, ate)
+	if err != nil {
+		t.Fatalf("FetchOTG() got error: %v", err)
+	}
+	wantCfg, err := otg.GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig() got error: %v", err)
+	}
+	if err := record.Stop(); err != nil {
+		t.Fatalf("record.Stop() got error: %v", err)
+	}
+
+	b, err := New(path)
+	if err != nil {
+		t.Fatalf("New() got error: %v", err)
+	}
+	replayRes, err := b.Reserve(ctx, nil, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("Reserve() got error: %v", err)
+	}
+	defer b.Release(ctx)
+	if got := cli.New(replayRes.DUTs["dut1"]).Run(t, "show running-config"); got != wantOut {
+		t.Errorf("Replayed CLI output got %q, want %q", got, wantOut)
+	}
+	replayOTG, err := replayRes.ATEs["ate1"].DialOTG(ctx)
+	if err != nil {
+		t.Fatalf("DialOTG() got error: %v", err)
+	}
+	gotCfg, err := replayOTG.GetConfig()
+	if err != nil {
+		t.Fatalf("Replayed GetConfig() got error: %v", err)
+	}
+	if diff := cmp.Diff(wantCfg.Msg(), gotCfg.Msg(), protocmp.Transform()); diff != "" {
+		t.Errorf("Replayed OTG config differs from recorded config (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets provides the secrets used by tests and bindings, such as
+// device passwords, from a pluggable provider, and redacts them from the
+// test output.
+//
+// The provider is set with the --secrets flag of the test, in one of the
+// following forms:
+//
+//	file:<dir>        // the secret "a/b" is the content of the file <dir>/a/b
+//	env:<prefix>      // the secret "a/b" is the env variable <prefix>A_B
+//	exec:<command>    // the secret "a/b" is the output of "<command> a/b"
+//
+// Every secret value that is fetched or registered is replaced with
+// [Redacted] in the Ondatra logs, events, debug output, JUnit XML, trace
+// files, and record files.
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+// Redacted replaces secret values in test output.
+const Redacted = "<redacted>"
+
+// prefix is the prefix of binding credentials that are references to secrets.
+const prefix = "secret:"
+
+// Provider provides secrets.
+type Provider interface {
+	// Secret returns the value of the secret with the specified key.
+	Secret(ctx context.Context, key string) (string, error)
+}
+
+// FileProvider returns a provider that reads the secret with a key from the
+// file at that relative path in the specified directory. A trailing newline
+// is trimmed from the content of the file.
+func FileProvider(dir string) Provider {
+	return fileProvider(dir)
+}
+
+type fileProvider string
+
+func (p fileProvider) Secret(_ context.Context, key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("secret key %q is not a local path", key)
+	}
+	b, err := os.ReadFile(filepath.Join(string(p), key))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// EnvProvider returns a provider that reads the secret with a key from the
+// environment variable that is the specified prefix followed by the key, in
+// upper case and with every character other than a letter or digit replaced
+// by an underscore.
+func EnvProvider(prefix string) Provider {
+	return envProvider(prefix)
+}
+
+type envProvider string
+
+func (p envProvider) Secret(_ context.Context, key string) (string, error) {
+	name := string(p) + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return v, nil
+}
+
+// ExecProvider returns a provider that runs a plugin command with the
+// specified arguments followed by the key, and reads the secret from its
+// standard output. A trailing newline is trimmed from the output.
+func ExecProvider(path string, args ...string) Provider {
+	return &execProvider{path: path, args: args}
+}
+
+type execProvider struct {
+	path string
+	args []string
+}
+
+func (p *execProvider) Secret(ctx context.Context, key string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path, append(p.args, key)...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("secrets plugin %s failed: %w: %s", p.path, err, stderr.String())
+	}
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
+
+// ParseProvider parses a provider spec of the form accepted by the --secrets
+// flag: "file:<dir>", "env:<prefix>", or "exec:<command>", where the command
+// is split into a path and arguments at white space.
+func ParseProvider(spec string) (Provider, error) {
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid secrets provider %q: want <kind>:<arg>", spec)
+	}
+	switch kind {
+	case "file":
+		return FileProvider(arg), nil
+	case "env":
+		return EnvProvider(arg), nil
+	case "exec":
+		fields := strings.Fields(arg)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid secrets provider %q: no command", spec)
+		}
+		return ExecProvider(fields[0], fields[1:]...), nil
+	default:
+		return nil, fmt.Errorf("invalid secrets provider %q: unknown kind %q", spec, kind)
+	}
+}
+
+var (
+	mu       sync.RWMutex
+	provider Provider
+	values   = make(map[string]bool)
+	replacer *strings.Replacer
+)
+
+// SetProvider sets the provider of secrets. A nil provider unsets it.
+func SetProvider(p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	provider = p
+}
+
+// HasProvider reports whether a provider of secrets is set.
+func HasProvider() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return provider != nil
+}
+
+// Get returns the value of the secret with the specified key from the
+// provider and registers the value to be redacted.
+func Get(ctx context.Context, key string) (string, error) {
+	mu.RLock()
+	p := provider
+	mu.RUnlock()
+	if p == nil {
+		return "", errors.New("no secrets provider is set; set one with the --secrets flag")
+	}
+	v, err := p.Secret(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("error getting secret %q: %w", key, err)
+	}
+	Register(v)
+	return v, nil
+}
+
+// Resolve returns the value of a binding credential. A value of the form
+// "secret:<key>" is replaced with the secret with that key from the
+// provider; any other value is returned as is. Either way, the value is
+// registered to be redacted.
+func Resolve(ctx context.Context, value string) (string, error) {
+	if key, ok := strings.CutPrefix(value, prefix); ok {
+		return Get(ctx, key)
+	}
+	Register(value)
+	return value, nil
+}
+
+// Register registers a secret value to be redacted from the test output.
+// Empty values are ignored.
+func Register(value string) {
+	if value == "" {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if values[value] {
+		return
+	}
+	values[value] = true
+	replacer = nil
+}
+
+// Redact replaces every registered secret value in the string with
+// [Redacted].
+func Redact(s string) string {
+	mu.RLock()
+	r := replacer
+	mu.RUnlock()
+	if r == nil {
+		mu.Lock()
+		r = newReplacerLocked()
+		mu.Unlock()
+	}
+	if r == nil {
+		return s
+	}
+	return r.Replace(s)
+}
+
+func newReplacerLocked() *strings.Replacer {
+	if replacer != nil || len(values) == 0 {
+		return replacer
+	}
+	// Replace longer values first, in case one value contains another.
+	var vals []string
+	for v := range values {
+		vals = append(vals, v)
+	}
+	sort.Slice(vals, func(i, j int) bool {
+		return len(vals[i]) > len(vals[j])
+	})
+	var oldnew []string
+	for _, v := range vals {
+		oldnew = append(oldnew, v, Redacted)
+	}
+	replacer = strings.NewReplacer(oldnew...)
+	return replacer
+}
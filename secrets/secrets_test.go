@@ -0,0 +1,151 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestProviders(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "dut1"), 0700); err != nil {
+		t.Fatalf("MkdirAll() failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dut1", "password"), []byte("filesecret\n"), 0600); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	t.Setenv("ONDATRA_DUT1_PASSWORD", "envsecret")
+	plugin := filepath.Join(dir, "plugin.sh")
+	if err := os.WriteFile(plugin, []byte("#!/bin/sh\necho \"plugin-$1-$2\"\n"), 0700); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+
+	tests := []struct {
+		desc    string
+		spec    string
+		key     string
+		want    string
+		wantErr string
+	}{{
+		desc: "file",
+		spec: "file:" + dir,
+		key:  "dut1/password",
+		want: "filesecret",
+	}, {
+		desc:    "file not local",
+		spec:    "file:" + dir,
+		key:     "../password",
+		wantErr: "not a local path",
+	}, {
+		desc: "env",
+		spec: "env:ONDATRA_",
+		key:  "dut1/password",
+		want: "envsecret",
+	}, {
+		desc:    "env not set",
+		spec:    "env:ONDATRA_",
+		key:     "dut2/password",
+		wantErr: "ONDATRA_DUT2_PASSWORD is not set",
+	}, {
+		desc: "exec",
+		spec: "exec:" + plugin + " --vault",
+		key:  "dut1/password",
+		want: "plugin---vault-dut1/password",
+	}, {
+		desc:    "exec fails",
+		spec:    "exec:false",
+		key:     "dut1/password",
+		wantErr: "secrets plugin false failed",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			p, err := ParseProvider(test.spec)
+			if err != nil {
+				t.Fatalf("ParseProvider(%q) got unexpected error: %v", test.spec, err)
+			}
+			got, err := p.Secret(context.Background(), test.key)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("Secret(%q) got error %v, want %q", test.key, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Secret(%q) got unexpected error: %v", test.key, err)
+			}
+			if got != test.want {
+				t.Errorf("Secret(%q) got %q, want %q", test.key, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseProviderErrors(t *testing.T) {
+	for _, spec := range []string{"file", "exec:", "vault:foo"} {
+		if _, err := ParseProvider(spec); err == nil {
+			t.Errorf("ParseProvider(%q) got no error, want error", spec)
+		}
+	}
+}
+
+type mapProvider map[string]string
+
+func (p mapProvider) Secret(_ context.Context, key string) (string, error) {
+	if v, ok := p[key]; ok {
+		return v, nil
+	}
+	return "", os.ErrNotExist
+}
+
+func TestResolveAndRedact(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { SetProvider(nil) })
+
+	SetProvider(nil)
+	if _, err := Resolve(ctx, "secret:dut/password"); err == nil || !strings.Contains(err.Error(), "no secrets provider") {
+		t.Errorf("Resolve() got error %v, want no secrets provider", err)
+	}
+
+	SetProvider(mapProvider{"dut/password": "hunter2", "dut/enable": "hunter2-enable"})
+	tests := []struct {
+		value, want string
+	}{
+		{value: "secret:dut/password", want: "hunter2"},
+		{value: "secret:dut/enable", want: "hunter2-enable"},
+		{value: "plaintext-pass", want: "plaintext-pass"},
+	}
+	for _, test := range tests {
+		got, err := Resolve(ctx, test.value)
+		if err != nil {
+			t.Fatalf("Resolve(%q) got unexpected error: %v", test.value, err)
+		}
+		if got != test.want {
+			t.Errorf("Resolve(%q) got %q, want %q", test.value, got, test.want)
+		}
+	}
+	if _, err := Resolve(ctx, "secret:dut/missing"); err == nil || !strings.Contains(err.Error(), `"dut/missing"`) {
+		t.Errorf("Resolve() got error %v, want error for missing secret", err)
+	}
+
+	const in = "login hunter2-enable with hunter2 and plaintext-pass"
+	if got, want := Redact(in), "login <redacted> with <redacted> and <redacted>"; got != want {
+		t.Errorf("Redact(%q) got %q, want %q", in, got, want)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package emulator implements an in-process network device that serves gNMI,
+// gNOI, gRIBI and CLI requests without any real hardware.
+//
+// The gNMI service is backed by a ygot datastore: a Set writes the generated
+// structs of the device schema, and every config leaf is mirrored under its
+// corresponding state path. The gNOI service implements the System service,
+// and the gRIBI service acknowledges every valid operation as programmed.
+// CLI commands are answered by handlers registered by the test.
+package emulator
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	spb "github.com/openconfig/gnoi/system"
+	grpb "github.com/openconfig/gribi/v1/proto/service"
+)
+
+const bufSize = 1 << 20
+
+// CommandFunc returns the output of a CLI command or an error message if the
+// command fails.
+type CommandFunc func(cmd string) (output string, errMsg string)
+
+// Device is an emulated network device.
+type Device struct {
+	name  string
+	lis   *bufconn.Listener
+	srv   *grpc.Server
+	ds    *datastore
+	gribi *gribiServer
+
+	mu       sync.Mutex
+	config   []string
+	commands map[string]CommandFunc
+}
+
+// New starts an emulated device with the specified name, whose gNMI datastore
+// is created by the specified schema function, typically the generated
+// Schema function of a ygot package.
+func New(name string, newSchema func() (*ytypes.Schema, error)) (*Device, error) {
+	ds, err := newDatastore(newSchema)
+	if err != nil {
+		return nil, err
+	}
+	d := &Device{
+		name:     name,
+		lis:      bufconn.Listen(bufSize),
+		srv:      grpc.NewServer(),
+		ds:       ds,
+		gribi:    newGRIBIServer(),
+		commands: make(map[string]CommandFunc),
+	}
+	gpb.RegisterGNMIServer(d.srv, &gnmiServer{ds: ds})
+	grpb.RegisterGRIBIServer(d.srv, d.gribi)
+	spb.RegisterSystemServer(d.srv, &systemServer{rebootFn: d.gribi.flush})
+	d.HandleCommand("show running-config", func(string) (string, string) {
+		return d.RunningConfig(), ""
+	})
+	go d.srv.Serve(d.lis)
+	return d, nil
+}
+
+func (d *Device) String() string {
+	return fmt.Sprintf("Device(%s)", d.name)
+}
+
+// Name returns the name of the device.
+func (d *Device) Name() string {
+	return d.name
+}
+
+// Dial creates a client connection to the device's gRPC services.
+func (d *Device) Dial(ctx context.Context, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return d.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	return grpc.DialContext(ctx, d.name, opts...)
+}
+
+// Close stops the device's gRPC services.
+func (d *Device) Close() error {
+	d.srv.Stop()
+	return nil
+}
+
+// UpdateState writes the specified notifications to the gNMI datastore.
+// Unlike a gNMI Set, this may write state-only leaves, which lets a test
+// emulate operational state, such as counters or protocol sessions.
+func (d *Device) UpdateState(ns ...*gpb.Notification) error {
+	return d.ds.update(ns)
+}
+
+// PushConfig appends the specified config text to the running config.
+// If reset is true, the running config, gNMI datastore and gRIBI entries are
+// first reset to their initial empty state.
+func (d *Device) PushConfig(config string, reset bool) error {
+	if reset {
+		if err := d.ds.reset(); err != nil {
+			return err
+		}
+		d.gribi.flush()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if reset {
+		d.config = nil
+	}
+	if config != "" {
+		d.config = append(d.config, config)
+	}
+	return nil
+}
+
+// RunningConfig returns the config text pushed since the last reset.
+func (d *Device) RunningConfig() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return strings.Join(d.config, "\n")
+}
+
+// HandleCommand registers the function that answers the specified CLI
+// command, replacing any previous handler for that command.
+func (d *Device) HandleCommand(cmd string, fn CommandFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.commands[cmd] = fn
+}
+
+// RunCommand runs the specified CLI command and returns its output and an
+// error message that is empty if and only if the command succeeds.
+func (d *Device) RunCommand(cmd string) (string, string) {
+	d.mu.Lock()
+	fn, ok := d.commands[strings.TrimSpace(cmd)]
+	d.mu.Unlock()
+	if !ok {
+		return "", fmt.Sprintf("%% Invalid input: unknown command %q", cmd)
+	}
+	return fn(cmd)
+}
@@ -0,0 +1,299 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnoigo"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	spb "github.com/openconfig/gnoi/system"
+	aftpb "github.com/openconfig/gribi/v1/proto/gribi_aft"
+	grpb "github.com/openconfig/gribi/v1/proto/service"
+)
+
+func newDevice(t *testing.T) *Device {
+	t.Helper()
+	d, err := New("dev1", exampleoc.Schema)
+	if err != nil {
+		t.Fatalf("New() got error: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func newYGNMIClient(t *testing.T, d *Device) *ygnmi.Client {
+	t.Helper()
+	conn, err := d.Dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dial() got error: %v", err)
+	}
+	c, err := ygnmi.NewClient(gpb.NewGNMIClient(conn), ygnmi.WithTarget("dev1"))
+	if err != nil {
+		t.Fatalf("NewClient() got error: %v", err)
+	}
+	return c
+}
+
+func TestGNMIMirrorsConfigToState(t *testing.T) {
+	ctx := context.Background()
+	c := newYGNMIClient(t, newDevice(t))
+	one := exampleocpath.Root().Parent().Child().One()
+
+	if _, err := ygnmi.Replace(ctx, c, one.Config(), "foo"); err != nil {
+		t.Fatalf("Replace() got error: %v", err)
+	}
+	for _, q := range []ygnmi.SingletonQuery[string]{one.Config(), one.State()} {
+		got, err := ygnmi.Get(ctx, c, q)
+		if err != nil {
+			t.Fatalf("Get(%v) got error: %v", q, err)
+		}
+		if want := "foo"; got != want {
+			t.Errorf("Get(%v) got %q, want %q", q, got, want)
+		}
+	}
+
+	if _, err := ygnmi.Delete(ctx, c, one.Config()); err != nil {
+		t.Fatalf("Delete() got error: %v", err)
+	}
+	if v, err := ygnmi.Lookup(ctx, c, one.State()); err != nil || v.IsPresent() {
+		t.Errorf("Lookup() after Delete got %v, %v, want not present", v, err)
+	}
+}
+
+func TestGNMIReplaceList(t *testing.T) {
+	ctx := context.Background()
+	c := newYGNMIClient(t, newDevice(t))
+	sk := exampleocpath.Root().Model().SingleKey("k1")
+
+	want := &exampleoc.Model_SingleKey{Key: ygot.String("k1"), Value: ygot.Int64(10)}
+	if _, err := ygnmi.Replace(ctx, c, sk.Config(), want); err != nil {
+		t.Fatalf("Replace() got error: %v", err)
+	}
+	got, err := ygnmi.Get(ctx, c, sk.Value().State())
+	if err != nil {
+		t.Fatalf("Get() got error: %v", err)
+	}
+	if got != 10 {
+		t.Errorf("Get() got %v, want 10", got)
+	}
+}
+
+func TestGNMIRejectsStateSet(t *testing.T) {
+	ctx := context.Background()
+	d := newDevice(t)
+	conn, err := d.Dial(ctx)
+	if err != nil {
+		t.Fatalf("Dial() got error: %v", err)
+	}
+	_, err = gpb.NewGNMIClient(conn).Set(ctx, &gpb.SetRequest{
+		Update: []*gpb.Update{{
+			Path: &gpb.Path{Elem: []*gpb.PathElem{{Name: "parent"}, {Name: "child"}, {Name: "config"}, {Name: "not-a-leaf"}}},
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+		}},
+	})
+	if err == nil {
+		t.Errorf("Set() of unknown leaf got no error, want error")
+	}
+}
+
+func TestGNMIStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	d := newDevice(t)
+	c := newYGNMIClient(t, d)
+	two := exampleocpath.Root().Parent().Child().Two()
+
+	w := ygnmi.Watch(ctx, c, two.State(), func(v *ygnmi.Value[string]) error {
+		if val, ok := v.Val(); ok && val == "up" {
+			return nil
+		}
+		return ygnmi.Continue
+	})
+	root := &exampleoc.Root{}
+	root.GetOrCreateParent().GetOrCreateChild().Two = ygot.String("up")
+	ns, err := ygot.TogNMINotifications(root, time.Now().UnixNano(), ygot.GNMINotificationsConfig{UsePathElem: true})
+	if err != nil {
+		t.Fatalf("TogNMINotifications() got error: %v", err)
+	}
+	if err := d.UpdateState(ns...); err != nil {
+		t.Fatalf("UpdateState() got error: %v", err)
+	}
+	if _, err := w.Await(); err != nil {
+		t.Errorf("Await() got error: %v", err)
+	}
+}
+
+func TestSubscriberOverflow(t *testing.T) {
+	sub := &subscriber{
+		paths:    []*gpb.Path{{}},
+		ch:       make(chan *gpb.Notification, 1),
+		overflow: make(chan struct{}),
+	}
+	n := &gpb.Notification{Update: []*gpb.Update{{Path: &gpb.Path{Elem: []*gpb.PathElem{{Name: "a"}}}}}}
+	sub.notify(n)
+	select {
+	case <-sub.overflow:
+		t.Fatalf("notify() overflowed with room in the buffer")
+	default:
+	}
+	sub.notify(n)
+	select {
+	case <-sub.overflow:
+	default:
+		t.Fatalf("notify() did not overflow with a full buffer")
+	}
+	sub.notify(n) // Must not close the overflow channel twice.
+}
+
+func TestPushConfigReset(t *testing.T) {
+	ctx := context.Background()
+	d := newDevice(t)
+	c := newYGNMIClient(t, d)
+	one := exampleocpath.Root().Parent().Child().One()
+
+	if err := d.PushConfig("hostname dev1", false); err != nil {
+		t.Fatalf("PushConfig() got error: %v", err)
+	}
+	if _, err := ygnmi.Replace(ctx, c, one.Config(), "foo"); err != nil {
+		t.Fatalf("Replace() got error: %v", err)
+	}
+	if err := d.PushConfig("hostname dev2", true); err != nil {
+		t.Fatalf("PushConfig() got error: %v", err)
+	}
+	if got, want := d.RunningConfig(), "hostname dev2"; got != want {
+		t.Errorf("RunningConfig() got %q, want %q", got, want)
+	}
+	if v, err := ygnmi.Lookup(ctx, c, one.State()); err != nil || v.IsPresent() {
+		t.Errorf("Lookup() after reset got %v, %v, want not present", v, err)
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	d := newDevice(t)
+	d.PushConfig("hostname dev1", false)
+	d.HandleCommand("show version", func(string) (string, string) {
+		return "v1", ""
+	})
+	tests := []struct {
+		cmd, wantOut string
+		wantErr      bool
+	}{
+		{cmd: "show running-config", wantOut: "hostname dev1"},
+		{cmd: "show version", wantOut: "v1"},
+		{cmd: "show bogus", wantErr: true},
+	}
+	for _, tt := range tests {
+		out, errMsg := d.RunCommand(tt.cmd)
+		if out != tt.wantOut {
+			t.Errorf("RunCommand(%q) got output %q, want %q", tt.cmd, out, tt.wantOut)
+		}
+		if gotErr := errMsg != ""; gotErr != tt.wantErr {
+			t.Errorf("RunCommand(%q) got error %q, want error %t", tt.cmd, errMsg, tt.wantErr)
+		}
+	}
+}
+
+func TestGRIBI(t *testing.T) {
+	ctx := context.Background()
+	d := newDevice(t)
+	conn, err := d.Dial(ctx)
+	if err != nil {
+		t.Fatalf("Dial() got error: %v", err)
+	}
+	c := grpb.NewGRIBIClient(conn)
+	stream, err := c.Modify(ctx)
+	if err != nil {
+		t.Fatalf("Modify() got error: %v", err)
+	}
+	nh := &grpb.AFTOperation{
+		Id:              1,
+		NetworkInstance: "DEFAULT",
+		Op:              grpb.AFTOperation_ADD,
+		Entry:           &grpb.AFTOperation_NextHop{NextHop: &aftpb.Afts_NextHopKey{Index: 1}},
+	}
+	del := &grpb.AFTOperation{
+		Id:              2,
+		NetworkInstance: "DEFAULT",
+		Op:              grpb.AFTOperation_DELETE,
+		Entry:           &grpb.AFTOperation_NextHop{NextHop: &aftpb.Afts_NextHopKey{Index: 2}},
+	}
+	if err := stream.Send(&grpb.ModifyRequest{Operation: []*grpb.AFTOperation{nh, del}}); err != nil {
+		t.Fatalf("Send() got error: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() got error: %v", err)
+	}
+	var gotStatus []grpb.AFTResult_Status
+	for _, r := range resp.GetResult() {
+		gotStatus = append(gotStatus, r.GetStatus())
+	}
+	wantStatus := []grpb.AFTResult_Status{grpb.AFTResult_RIB_PROGRAMMED, grpb.AFTResult_FAILED}
+	if diff := cmp.Diff(wantStatus, gotStatus); diff != "" {
+		t.Errorf("Modify() got unexpected statuses (-want +got):\n%s", diff)
+	}
+
+	getEntries := func() []*grpb.AFTEntry {
+		t.Helper()
+		get, err := c.Get(ctx, &grpb.GetRequest{
+			NetworkInstance: &grpb.GetRequest_All{All: &grpb.Empty{}},
+			Aft:             grpb.AFTType_ALL,
+		})
+		if err != nil {
+			t.Fatalf("Get() got error: %v", err)
+		}
+		getResp, err := get.Recv()
+		if err != nil {
+			t.Fatalf("Recv() got error: %v", err)
+		}
+		return getResp.GetEntry()
+	}
+	wantEntries := []*grpb.AFTEntry{{
+		NetworkInstance: "DEFAULT",
+		Entry:           &grpb.AFTEntry_NextHop{NextHop: &aftpb.Afts_NextHopKey{Index: 1}},
+		RibStatus:       grpb.AFTEntry_PROGRAMMED,
+		FibStatus:       grpb.AFTEntry_PROGRAMMED,
+	}}
+	if diff := cmp.Diff(wantEntries, getEntries(), protocmp.Transform()); diff != "" {
+		t.Errorf("Get() got unexpected entries (-want +got):\n%s", diff)
+	}
+
+	// A reboot flushes all gRIBI entries.
+	sys := gnoigo.NewClients(conn).System()
+	if _, err := sys.Reboot(ctx, &spb.RebootRequest{Method: spb.RebootMethod_COLD}); err != nil {
+		t.Fatalf("Reboot() got error: %v", err)
+	}
+	if got := getEntries(); len(got) != 0 {
+		t.Errorf("Get() after reboot got entries %v, want none", got)
+	}
+	status, err := sys.RebootStatus(ctx, &spb.RebootStatusRequest{})
+	if err != nil {
+		t.Fatalf("RebootStatus() got error: %v", err)
+	}
+	if got, want := status.GetCount(), uint32(1); got != want {
+		t.Errorf("RebootStatus() got count %d, want %d", got, want)
+	}
+}
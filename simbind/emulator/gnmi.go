@@ -0,0 +1,455 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+const (
+	configElem = "config"
+	stateElem  = "state"
+
+	// Number of pending notifications buffered per streaming subscription.
+	subBufferSize = 1024
+)
+
+// datastore is a gNMI datastore backed by a ygot GoStruct.
+//
+// The datastore holds a single tree of generated structs. Every leaf that is
+// writable via gNMI Set is served under both its config and its state path, so
+// config written by a test is immediately reflected in the mirrored state, as
+// it would be on a device that has applied the config.
+type datastore struct {
+	newSchema func() (*ytypes.Schema, error)
+
+	mu     sync.Mutex
+	schema *ytypes.Schema
+	leaves map[string]*gpb.Update
+	subs   map[*subscriber]struct{}
+}
+
+func newDatastore(newSchema func() (*ytypes.Schema, error)) (*datastore, error) {
+	ds := &datastore{
+		newSchema: newSchema,
+		leaves:    make(map[string]*gpb.Update),
+		subs:      make(map[*subscriber]struct{}),
+	}
+	if err := ds.reset(); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// reset restores the datastore to an empty tree.
+func (ds *datastore) reset() error {
+	schema, err := ds.newSchema()
+	if err != nil {
+		return fmt.Errorf("error creating schema: %w", err)
+	}
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.commit(schema)
+}
+
+// set applies the SetRequest atomically to the datastore.
+func (ds *datastore) set(req *gpb.SetRequest) error {
+	return ds.apply(func(schema *ytypes.Schema) error {
+		return ytypes.UnmarshalSetRequest(schema, req, &ytypes.PreferShadowPath{})
+	})
+}
+
+// update applies the notifications atomically to the datastore.
+// Unlike set, the notifications may write state-only leaves.
+func (ds *datastore) update(ns []*gpb.Notification) error {
+	return ds.apply(func(schema *ytypes.Schema) error {
+		return ytypes.UnmarshalNotifications(schema, ns)
+	})
+}
+
+func (ds *datastore) apply(fn func(*ytypes.Schema) error) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	root, err := ygot.DeepCopy(ds.schema.Root)
+	if err != nil {
+		return status.Errorf(codes.Internal, "error copying datastore: %v", err)
+	}
+	schema := &ytypes.Schema{
+		Root:       root,
+		SchemaTree: ds.schema.SchemaTree,
+		Unmarshal:  ds.schema.Unmarshal,
+	}
+	if err := fn(schema); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return ds.commit(schema)
+}
+
+// commit replaces the datastore tree and notifies subscribers of the changes.
+// The caller must hold the lock.
+func (ds *datastore) commit(schema *ytypes.Schema) error {
+	leaves, err := render(schema)
+	if err != nil {
+		return status.Errorf(codes.Internal, "error rendering datastore: %v", err)
+	}
+	ts := time.Now().UnixNano()
+	n := &gpb.Notification{Timestamp: ts}
+	for k, u := range leaves {
+		if old, ok := ds.leaves[k]; !ok || !proto.Equal(old.GetVal(), u.GetVal()) {
+			n.Update = append(n.Update, u)
+		}
+	}
+	for k, u := range ds.leaves {
+		if _, ok := leaves[k]; !ok {
+			n.Delete = append(n.Delete, u.GetPath())
+		}
+	}
+	ds.schema = schema
+	ds.leaves = leaves
+	if len(n.GetUpdate())+len(n.GetDelete()) > 0 {
+		for sub := range ds.subs {
+			sub.notify(n)
+		}
+	}
+	return nil
+}
+
+// render flattens the tree into a map of leaf updates, keyed by path string.
+func render(schema *ytypes.Schema) (map[string]*gpb.Update, error) {
+	leaves := make(map[string]*gpb.Update)
+	if err := renderInto(leaves, schema.Root, false); err != nil {
+		return nil, err
+	}
+	// Mirror config-true leaves, so they are present under config and state.
+	cfg, err := ygot.DeepCopy(schema.Root)
+	if err != nil {
+		return nil, err
+	}
+	rootName := reflect.TypeOf(schema.Root).Elem().Name()
+	if err := ygot.PruneConfigFalse(schema.SchemaTree[rootName], cfg); err != nil {
+		return nil, err
+	}
+	if err := renderInto(leaves, cfg, true); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+func renderInto(leaves map[string]*gpb.Update, s ygot.GoStruct, mirror bool) error {
+	ns, err := ygot.TogNMINotifications(s, 0, ygot.GNMINotificationsConfig{UsePathElem: true})
+	if err != nil {
+		return err
+	}
+	for _, n := range ns {
+		for _, u := range n.GetUpdate() {
+			p, err := util.JoinPaths(n.GetPrefix(), u.GetPath())
+			if err != nil {
+				return err
+			}
+			if mirror {
+				if p = mirrorPath(p); p == nil {
+					continue
+				}
+			}
+			k, err := ygot.PathToString(p)
+			if err != nil {
+				return err
+			}
+			leaves[k] = &gpb.Update{Path: p, Val: u.GetVal()}
+		}
+	}
+	return nil
+}
+
+// mirrorPath returns the config path for a state leaf and vice versa, or nil
+// if the leaf is not in a config or state container.
+func mirrorPath(p *gpb.Path) *gpb.Path {
+	elems := p.GetElem()
+	if len(elems) < 2 {
+		return nil
+	}
+	var name string
+	switch elems[len(elems)-2].GetName() {
+	case configElem:
+		name = stateElem
+	case stateElem:
+		name = configElem
+	default:
+		return nil
+	}
+	m := proto.Clone(p).(*gpb.Path)
+	m.GetElem()[len(elems)-2].Name = name
+	return m
+}
+
+// query returns the leaves matching any of the specified paths.
+func (ds *datastore) query(paths []*gpb.Path) []*gpb.Update {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return matchingUpdates(ds.leaves, paths)
+}
+
+func matchingUpdates(leaves map[string]*gpb.Update, paths []*gpb.Path) []*gpb.Update {
+	var us []*gpb.Update
+	for _, u := range leaves {
+		if matchesAny(u.GetPath(), paths) {
+			us = append(us, u)
+		}
+	}
+	return us
+}
+
+func matchesAny(p *gpb.Path, queries []*gpb.Path) bool {
+	for _, q := range queries {
+		// The leaves are stored without an origin, so match on elements only.
+		if util.PathMatchesQuery(p, &gpb.Path{Elem: q.GetElem()}) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe registers a subscriber and returns the current matching leaves.
+func (ds *datastore) subscribe(paths []*gpb.Path) (*subscriber, []*gpb.Update) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	sub := &subscriber{
+		paths:    paths,
+		ch:       make(chan *gpb.Notification, subBufferSize),
+		overflow: make(chan struct{}),
+	}
+	ds.subs[sub] = struct{}{}
+	return sub, matchingUpdates(ds.leaves, paths)
+}
+
+func (ds *datastore) unsubscribe(sub *subscriber) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	delete(ds.subs, sub)
+}
+
+type subscriber struct {
+	paths []*gpb.Path
+	ch    chan *gpb.Notification
+	// overflow is closed when the subscriber falls too far behind, after which
+	// no more notifications are queued. Guarded by the datastore lock.
+	overflow   chan struct{}
+	overflowed bool
+}
+
+// notify filters the notification to the subscribed paths and queues it.
+// If the subscriber is not keeping up, the subscriber overflows rather than
+// silently missing the notification. The caller must hold the datastore lock.
+func (s *subscriber) notify(n *gpb.Notification) {
+	if s.overflowed {
+		return
+	}
+	filtered := &gpb.Notification{Timestamp: n.GetTimestamp()}
+	for _, u := range n.GetUpdate() {
+		if matchesAny(u.GetPath(), s.paths) {
+			filtered.Update = append(filtered.Update, u)
+		}
+	}
+	for _, d := range n.GetDelete() {
+		if matchesAny(d, s.paths) {
+			filtered.Delete = append(filtered.Delete, d)
+		}
+	}
+	if len(filtered.GetUpdate())+len(filtered.GetDelete()) == 0 {
+		return
+	}
+	select {
+	case s.ch <- filtered:
+	default:
+		s.overflowed = true
+		close(s.overflow)
+	}
+}
+
+// gnmiServer is a gNMI server backed by a datastore.
+type gnmiServer struct {
+	gpb.UnimplementedGNMIServer
+	ds *datastore
+}
+
+// Capabilities returns the supported encodings of the server.
+func (s *gnmiServer) Capabilities(context.Context, *gpb.CapabilityRequest) (*gpb.CapabilityResponse, error) {
+	return &gpb.CapabilityResponse{
+		SupportedEncodings: []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_PROTO},
+		GNMIVersion:        "0.10.0",
+	}, nil
+}
+
+// Get returns the leaves matching the requested paths.
+func (s *gnmiServer) Get(_ context.Context, req *gpb.GetRequest) (*gpb.GetResponse, error) {
+	paths, err := joinPrefix(req.GetPrefix(), req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	resp := &gpb.GetResponse{}
+	ts := time.Now().UnixNano()
+	for _, p := range paths {
+		var us []*gpb.Update
+		for _, u := range s.ds.query([]*gpb.Path{p}) {
+			if matchesType(u.GetPath(), req.GetType()) {
+				us = append(us, u)
+			}
+		}
+		if len(us) == 0 {
+			return nil, status.Errorf(codes.NotFound, "no data at path %v", p)
+		}
+		resp.Notification = append(resp.Notification, &gpb.Notification{
+			Timestamp: ts,
+			Prefix:    &gpb.Path{Origin: p.GetOrigin(), Target: req.GetPrefix().GetTarget()},
+			Update:    us,
+		})
+	}
+	return resp, nil
+}
+
+func matchesType(p *gpb.Path, typ gpb.GetRequest_DataType) bool {
+	var inConfig bool
+	for _, e := range p.GetElem() {
+		if e.GetName() == configElem {
+			inConfig = true
+		}
+	}
+	switch typ {
+	case gpb.GetRequest_CONFIG:
+		return inConfig
+	case gpb.GetRequest_STATE, gpb.GetRequest_OPERATIONAL:
+		return !inConfig
+	}
+	return true
+}
+
+// Set applies the SetRequest to the datastore.
+func (s *gnmiServer) Set(_ context.Context, req *gpb.SetRequest) (*gpb.SetResponse, error) {
+	if err := s.ds.set(req); err != nil {
+		return nil, err
+	}
+	resp := &gpb.SetResponse{Prefix: req.GetPrefix(), Timestamp: time.Now().UnixNano()}
+	for _, p := range req.GetDelete() {
+		resp.Response = append(resp.Response, &gpb.UpdateResult{Path: p, Op: gpb.UpdateResult_DELETE})
+	}
+	for _, u := range req.GetReplace() {
+		resp.Response = append(resp.Response, &gpb.UpdateResult{Path: u.GetPath(), Op: gpb.UpdateResult_REPLACE})
+	}
+	for _, u := range req.GetUpdate() {
+		resp.Response = append(resp.Response, &gpb.UpdateResult{Path: u.GetPath(), Op: gpb.UpdateResult_UPDATE})
+	}
+	return resp, nil
+}
+
+// Subscribe serves ONCE, POLL, and STREAM subscriptions.
+// All STREAM subscriptions are served as ON_CHANGE.
+func (s *gnmiServer) Subscribe(stream gpb.GNMI_SubscribeServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	sl := req.GetSubscribe()
+	if sl == nil {
+		return status.Errorf(codes.InvalidArgument, "first SubscribeRequest must contain a SubscriptionList: %v", req)
+	}
+	var subPaths []*gpb.Path
+	for _, sub := range sl.GetSubscription() {
+		subPaths = append(subPaths, sub.GetPath())
+	}
+	paths, err := joinPrefix(sl.GetPrefix(), subPaths)
+	if err != nil {
+		return err
+	}
+	prefix := &gpb.Path{Target: sl.GetPrefix().GetTarget(), Origin: sl.GetPrefix().GetOrigin()}
+	send := func(n *gpb.Notification) error {
+		n.Prefix = prefix
+		return stream.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: n}})
+	}
+	sendSnapshot := func(us []*gpb.Update) error {
+		ts := time.Now().UnixNano()
+		for _, u := range us {
+			if err := send(&gpb.Notification{Timestamp: ts, Update: []*gpb.Update{u}}); err != nil {
+				return err
+			}
+		}
+		return stream.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}})
+	}
+
+	switch sl.GetMode() {
+	case gpb.SubscriptionList_ONCE:
+		return sendSnapshot(s.ds.query(paths))
+	case gpb.SubscriptionList_POLL:
+		if err := sendSnapshot(s.ds.query(paths)); err != nil {
+			return err
+		}
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			if req.GetPoll() == nil {
+				return status.Errorf(codes.InvalidArgument, "expected a Poll request, got %v", req)
+			}
+			if err := sendSnapshot(s.ds.query(paths)); err != nil {
+				return err
+			}
+		}
+	case gpb.SubscriptionList_STREAM:
+		sub, us := s.ds.subscribe(paths)
+		defer s.ds.unsubscribe(sub)
+		if err := sendSnapshot(us); err != nil {
+			return err
+		}
+		for {
+			select {
+			case <-stream.Context().Done():
+				return stream.Context().Err()
+			case <-sub.overflow:
+				return status.Errorf(codes.ResourceExhausted, "subscription fell more than %d notifications behind", subBufferSize)
+			case n := <-sub.ch:
+				if err := send(n); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return status.Errorf(codes.InvalidArgument, "unsupported subscription mode %v", sl.GetMode())
+}
+
+func joinPrefix(prefix *gpb.Path, paths []*gpb.Path) ([]*gpb.Path, error) {
+	var joined []*gpb.Path
+	for _, p := range paths {
+		jp, err := util.JoinPaths(&gpb.Path{Origin: prefix.GetOrigin(), Elem: prefix.GetElem()}, p)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid path %v: %v", p, err)
+		}
+		joined = append(joined, jp)
+	}
+	return joined, nil
+}
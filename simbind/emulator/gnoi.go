@@ -0,0 +1,74 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	spb "github.com/openconfig/gnoi/system"
+)
+
+// systemServer is a gNOI System server.
+// Reboots complete immediately and clear any state that does not persist
+// across a reboot on a real device, i.e. the gRIBI entries.
+type systemServer struct {
+	spb.UnimplementedSystemServer
+	rebootFn func()
+
+	mu      sync.Mutex
+	reboots uint32
+	reason  string
+}
+
+// Time returns the current time.
+func (s *systemServer) Time(context.Context, *spb.TimeRequest) (*spb.TimeResponse, error) {
+	return &spb.TimeResponse{Time: uint64(time.Now().UnixNano())}, nil
+}
+
+// Reboot reboots the device.
+func (s *systemServer) Reboot(_ context.Context, req *spb.RebootRequest) (*spb.RebootResponse, error) {
+	switch req.GetMethod() {
+	case spb.RebootMethod_COLD, spb.RebootMethod_WARM, spb.RebootMethod_NSF:
+	default:
+		return nil, status.Errorf(codes.Unimplemented, "unsupported reboot method %v", req.GetMethod())
+	}
+	if len(req.GetSubcomponents()) > 0 {
+		return nil, status.Errorf(codes.Unimplemented, "rebooting subcomponents is not supported")
+	}
+	s.mu.Lock()
+	s.reboots++
+	s.reason = req.GetMessage()
+	s.mu.Unlock()
+	s.rebootFn()
+	return &spb.RebootResponse{}, nil
+}
+
+// RebootStatus returns the status of the last reboot.
+func (s *systemServer) RebootStatus(context.Context, *spb.RebootStatusRequest) (*spb.RebootStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &spb.RebootStatusResponse{Reason: s.reason, Count: s.reboots}, nil
+}
+
+// CancelReboot is a no-op, because reboots complete immediately.
+func (s *systemServer) CancelReboot(context.Context, *spb.CancelRebootRequest) (*spb.CancelRebootResponse, error) {
+	return &spb.CancelRebootResponse{}, nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpb "github.com/openconfig/gribi/v1/proto/service"
+)
+
+// gribiServer is a gRIBI server that programs every valid operation.
+//
+// Entries are stored per network instance and acknowledged as programmed.
+// The server does not resolve entry references or enforce election IDs.
+type gribiServer struct {
+	grpb.UnimplementedGRIBIServer
+
+	mu      sync.Mutex
+	entries map[string]*grpb.AFTEntry
+}
+
+func newGRIBIServer() *gribiServer {
+	return &gribiServer{entries: make(map[string]*grpb.AFTEntry)}
+}
+
+// Modify programs the operations in each request and acknowledges them.
+func (s *gribiServer) Modify(stream grpb.GRIBI_ModifyServer) error {
+	ackType := grpb.SessionParameters_RIB_ACK
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp := &grpb.ModifyResponse{ElectionId: req.GetElectionId()}
+		if params := req.GetParams(); params != nil {
+			ackType = params.GetAckType()
+			resp.SessionParamsResult = &grpb.SessionParametersResult{Status: grpb.SessionParametersResult_OK}
+		}
+		for _, op := range req.GetOperation() {
+			resp.Result = append(resp.Result, &grpb.AFTResult{
+				Id:        op.GetId(),
+				Status:    s.program(op, ackType),
+				Timestamp: time.Now().UnixNano(),
+			})
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *gribiServer) program(op *grpb.AFTOperation, ackType grpb.SessionParameters_AFTResultStatusType) grpb.AFTResult_Status {
+	entry, key, err := opToEntry(op)
+	if err != nil {
+		return grpb.AFTResult_FAILED
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, exists := s.entries[key]
+	switch op.GetOp() {
+	case grpb.AFTOperation_ADD:
+		s.entries[key] = entry
+	case grpb.AFTOperation_REPLACE:
+		if !exists {
+			return grpb.AFTResult_FAILED
+		}
+		s.entries[key] = entry
+	case grpb.AFTOperation_DELETE:
+		if !exists {
+			return grpb.AFTResult_FAILED
+		}
+		delete(s.entries, key)
+	default:
+		return grpb.AFTResult_FAILED
+	}
+	if ackType == grpb.SessionParameters_RIB_AND_FIB_ACK {
+		return grpb.AFTResult_FIB_PROGRAMMED
+	}
+	return grpb.AFTResult_RIB_PROGRAMMED
+}
+
+// opToEntry converts an operation to the entry it programs and a unique key.
+func opToEntry(op *grpb.AFTOperation) (*grpb.AFTEntry, string, error) {
+	e := &grpb.AFTEntry{
+		NetworkInstance: op.GetNetworkInstance(),
+		RibStatus:       grpb.AFTEntry_PROGRAMMED,
+		FibStatus:       grpb.AFTEntry_PROGRAMMED,
+	}
+	var key string
+	switch v := op.GetEntry().(type) {
+	case *grpb.AFTOperation_Ipv4:
+		e.Entry = &grpb.AFTEntry_Ipv4{Ipv4: v.Ipv4}
+		key = "ipv4:" + v.Ipv4.GetPrefix()
+	case *grpb.AFTOperation_Ipv6:
+		e.Entry = &grpb.AFTEntry_Ipv6{Ipv6: v.Ipv6}
+		key = "ipv6:" + v.Ipv6.GetPrefix()
+	case *grpb.AFTOperation_Mpls:
+		e.Entry = &grpb.AFTEntry_Mpls{Mpls: v.Mpls}
+		key = fmt.Sprintf("mpls:%v", v.Mpls.GetLabel())
+	case *grpb.AFTOperation_NextHopGroup:
+		e.Entry = &grpb.AFTEntry_NextHopGroup{NextHopGroup: v.NextHopGroup}
+		key = fmt.Sprintf("nhg:%d", v.NextHopGroup.GetId())
+	case *grpb.AFTOperation_NextHop:
+		e.Entry = &grpb.AFTEntry_NextHop{NextHop: v.NextHop}
+		key = fmt.Sprintf("nh:%d", v.NextHop.GetIndex())
+	case *grpb.AFTOperation_MacEntry:
+		e.Entry = &grpb.AFTEntry_MacEntry{MacEntry: v.MacEntry}
+		key = "mac:" + v.MacEntry.GetMacAddress()
+	case *grpb.AFTOperation_PolicyForwardingEntry:
+		e.Entry = &grpb.AFTEntry_PolicyForwardingEntry{PolicyForwardingEntry: v.PolicyForwardingEntry}
+		key = fmt.Sprintf("pf:%d", v.PolicyForwardingEntry.GetIndex())
+	default:
+		return nil, "", fmt.Errorf("unsupported entry type %T", v)
+	}
+	return e, op.GetNetworkInstance() + "/" + key, nil
+}
+
+// Get streams the installed entries matching the request.
+func (s *gribiServer) Get(req *grpb.GetRequest, stream grpb.GRIBI_GetServer) error {
+	if req.GetName() == "" && req.GetAll() == nil {
+		return status.Errorf(codes.InvalidArgument, "network instance must be specified: %v", req)
+	}
+	s.mu.Lock()
+	var keys []string
+	for k, e := range s.entries {
+		if (req.GetAll() != nil || e.GetNetworkInstance() == req.GetName()) && matchesAFT(e, req.GetAft()) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	resp := &grpb.GetResponse{}
+	for _, k := range keys {
+		resp.Entry = append(resp.Entry, s.entries[k])
+	}
+	s.mu.Unlock()
+	return stream.Send(resp)
+}
+
+func matchesAFT(e *grpb.AFTEntry, aft grpb.AFTType) bool {
+	switch aft {
+	case grpb.AFTType_ALL:
+		return true
+	case grpb.AFTType_IPV4:
+		return e.GetIpv4() != nil
+	case grpb.AFTType_IPV6:
+		return e.GetIpv6() != nil
+	case grpb.AFTType_MPLS:
+		return e.GetMpls() != nil
+	case grpb.AFTType_NEXTHOP:
+		return e.GetNextHop() != nil
+	case grpb.AFTType_NEXTHOP_GROUP:
+		return e.GetNextHopGroup() != nil
+	case grpb.AFTType_MAC:
+		return e.GetMacEntry() != nil
+	case grpb.AFTType_POLICY_FORWARDING:
+		return e.GetPolicyForwardingEntry() != nil
+	}
+	return false
+}
+
+// Flush removes the entries in the requested network instance.
+func (s *gribiServer) Flush(_ context.Context, req *grpb.FlushRequest) (*grpb.FlushResponse, error) {
+	if req.GetName() == "" && req.GetAll() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "network instance must be specified: %v", req)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.entries {
+		if req.GetAll() != nil || e.GetNetworkInstance() == req.GetName() {
+			delete(s.entries, k)
+		}
+	}
+	return &grpb.FlushResponse{Timestamp: time.Now().UnixNano(), Result: grpb.FlushResponse_OK}, nil
+}
+
+// flush removes all entries.
+func (s *gribiServer) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]*grpb.AFTEntry)
+}
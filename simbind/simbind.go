@@ -0,0 +1,260 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package simbind provides an Ondatra binding for fully in-memory emulated
+// devices, so that tests can run end to end without any real or virtual lab.
+//
+// The devices are declared in a KNE topology, against which testbeds are
+// solved the same way as in the KNE binding. Each reserved device is an
+// [emulator.Device] that serves gNMI, gNOI, gRIBI and CLI requests:
+//
+//	func TestMain(m *testing.M) {
+//		ondatra.RunTests(m, func() (binding.Binding, error) {
+//			topo, err := topo.Load("topology.textproto")
+//			if err != nil {
+//				return nil, err
+//			}
+//			return simbind.New(topo), nil
+//		})
+//	}
+package simbind
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/gnoigo"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ondatra/gnmi/otg"
+	"github.com/openconfig/ondatra/knebind/solver"
+	"github.com/openconfig/ondatra/simbind/emulator"
+	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/grpc"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	grpb "github.com/openconfig/gribi/v1/proto/service"
+	tpb "github.com/openconfig/kne/proto/topo"
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+// Option configures the emulated devices of a Binding.
+type Option func(b *Binding)
+
+// WithDUTSchema sets the function that creates the gNMI schema of every
+// emulated DUT. By default, the DUTs use the Ondatra OpenConfig schema.
+func WithDUTSchema(fn func() (*ytypes.Schema, error)) Option {
+	return func(b *Binding) {
+		b.dutSchema = fn
+	}
+}
+
+// WithATESchema sets the function that creates the gNMI schema of every
+// emulated ATE. By default, the ATEs use the Ondatra OTG schema.
+func WithATESchema(fn func() (*ytypes.Schema, error)) Option {
+	return func(b *Binding) {
+		b.ateSchema = fn
+	}
+}
+
+// New returns a new binding that emulates the devices in the topology.
+func New(topo *tpb.Topology, opts ...Option) *Binding {
+	b := &Binding{
+		topo:      topo,
+		dutSchema: oc.Schema,
+		ateSchema: otg.Schema,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+var _ binding.Binding = (*Binding)(nil)
+
+// Binding implements the Ondatra binding interface for emulated devices.
+type Binding struct {
+	topo      *tpb.Topology
+	dutSchema func() (*ytypes.Schema, error)
+	ateSchema func() (*ytypes.Schema, error)
+
+	mu   sync.Mutex
+	res  *binding.Reservation
+	devs map[string]*emulator.Device
+}
+
+// Reserve solves the testbed against the topology and starts an emulated
+// device for every reserved DUT and ATE. The devices of a reservation must be
+// released before another reservation is made.
+func (b *Binding) Reserve(ctx context.Context, tb *opb.Testbed, _, _ time.Duration, partial map[string]string) (_ *binding.Reservation, rerr error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.res != nil {
+		return nil, fmt.Errorf("reservation %q is already held; release it first", b.res.ID)
+	}
+	res, err := solver.Solve(ctx, tb, b.topo, partial)
+	if err != nil {
+		return nil, err
+	}
+	b.devs = make(map[string]*emulator.Device)
+	defer func() {
+		if rerr != nil {
+			b.closeDevices()
+		}
+	}()
+	for id, dut := range res.DUTs {
+		dev, err := b.startDevice(dut.Name(), b.dutSchema)
+		if err != nil {
+			return nil, err
+		}
+		res.DUTs[id] = &simDUT{ServiceDUT: dut.(*solver.ServiceDUT), dev: dev}
+	}
+	for id, ate := range res.ATEs {
+		dev, err := b.startDevice(ate.Name(), b.ateSchema)
+		if err != nil {
+			return nil, err
+		}
+		res.ATEs[id] = &simATE{ServiceATE: ate.(*solver.ServiceATE), dev: dev}
+	}
+	b.res = res
+	return res, nil
+}
+
+func (b *Binding) startDevice(name string, schema func() (*ytypes.Schema, error)) (*emulator.Device, error) {
+	dev, err := emulator.New(name, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error starting emulated device %q: %w", name, err)
+	}
+	b.devs[name] = dev
+	return dev, nil
+}
+
+// Release stops all the emulated devices.
+func (b *Binding) Release(context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.res = nil
+	return b.closeDevices()
+}
+
+func (b *Binding) closeDevices() error {
+	var errs []error
+	for _, dev := range b.devs {
+		if err := dev.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.devs = nil
+	return errors.Join(errs...)
+}
+
+// FetchReservation returns the current reservation of this binding.
+// Emulated devices only exist in memory, so no other reservation can be fetched.
+func (b *Binding) FetchReservation(_ context.Context, id string) (*binding.Reservation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.res == nil || b.res.ID != id {
+		return nil, fmt.Errorf("reservation %q not found", id)
+	}
+	return b.res, nil
+}
+
+// Emulator returns the emulated device with the specified name, so that tests
+// can stub CLI commands or write operational state.
+func (b *Binding) Emulator(name string) (*emulator.Device, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	dev, ok := b.devs[name]
+	if !ok {
+		return nil, fmt.Errorf("no emulated device named %q is reserved", name)
+	}
+	return dev, nil
+}
+
+type simDUT struct {
+	*solver.ServiceDUT
+	dev *emulator.Device
+}
+
+func (d *simDUT) PushConfig(_ context.Context, config string, reset bool) error {
+	return d.dev.PushConfig(config, reset)
+}
+
+func (d *simDUT) DialCLI(context.Context) (binding.CLIClient, error) {
+	return &simCLI{dev: d.dev}, nil
+}
+
+func (d *simDUT) DialGNMI(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+	conn, err := d.dev.Dial(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return gpb.NewGNMIClient(conn), nil
+}
+
+func (d *simDUT) DialGNOI(ctx context.Context, opts ...grpc.DialOption) (gnoigo.Clients, error) {
+	conn, err := d.dev.Dial(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return gnoigo.NewClients(conn), nil
+}
+
+func (d *simDUT) DialGRIBI(ctx context.Context, opts ...grpc.DialOption) (grpb.GRIBIClient, error) {
+	conn, err := d.dev.Dial(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return grpb.NewGRIBIClient(conn), nil
+}
+
+type simCLI struct {
+	*binding.AbstractCLIClient
+	dev *emulator.Device
+}
+
+func (c *simCLI) RunCommand(_ context.Context, cmd string) (binding.CommandResult, error) {
+	output, errMsg := c.dev.RunCommand(cmd)
+	return &cmdResult{output: output, error: errMsg}, nil
+}
+
+type cmdResult struct {
+	*binding.AbstractCommandResult
+	output, error string
+}
+
+func (r *cmdResult) Output() string {
+	return r.output
+}
+
+func (r *cmdResult) Error() string {
+	return r.error
+}
+
+type simATE struct {
+	*solver.ServiceATE
+	dev *emulator.Device
+}
+
+func (a *simATE) DialGNMI(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+	conn, err := a.dev.Dial(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return gpb.NewGNMIClient(conn), nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simbind
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/ygnmi"
+
+	tpb "github.com/openconfig/kne/proto/topo"
+	opb "github.com/openconfig/ondatra/proto"
+)
+
+var (
+	topo = &tpb.Topology{
+		Name: "sim",
+		Nodes: []*tpb.Node{{
+			Name:   "r1",
+			Vendor: tpb.Vendor_ARISTA,
+			Model:  "ceos",
+			Interfaces: map[string]*tpb.Interface{
+				"eth1": {Name: "Ethernet1"},
+			},
+		}, {
+			Name:   "otg",
+			Vendor: tpb.Vendor_KEYSIGHT,
+			Interfaces: map[string]*tpb.Interface{
+				"eth1": {},
+			},
+		}},
+		Links: []*tpb.Link{{ANode: "r1", AInt: "eth1", ZNode: "otg", ZInt: "eth1"}},
+	}
+	tb = &opb.Testbed{
+		Duts:  []*opb.Device{{Id: "dut", Ports: []*opb.Port{{Id: "port1"}}}},
+		Ates:  []*opb.Device{{Id: "ate", Ports: []*opb.Port{{Id: "port1"}}}},
+		Links: []*opb.Link{{A: "dut:port1", B: "ate:port1"}},
+	}
+)
+
+func TestReserve(t *testing.T) {
+	ctx := context.Background()
+	b := New(topo, WithDUTSchema(exampleoc.Schema), WithATESchema(exampleoc.Schema))
+	res, err := b.Reserve(ctx, tb, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("Reserve() got error: %v", err)
+	}
+	defer b.Release(ctx)
+
+	dut := res.DUTs["dut"]
+	if got, want := dut.Name(), "r1"; got != want {
+		t.Errorf("DUT name got %q, want %q", got, want)
+	}
+	if got, want := dut.Ports()["port1"].Name, "Ethernet1"; got != want {
+		t.Errorf("DUT port name got %q, want %q", got, want)
+	}
+	if got, want := res.ATEs["ate"].Name(), "otg"; got != want {
+		t.Errorf("ATE name got %q, want %q", got, want)
+	}
+	if fetched, err := b.FetchReservation(ctx, res.ID); err != nil || fetched != res {
+		t.Errorf("FetchReservation(%q) got %v, %v, want %v", res.ID, fetched, err, res)
+	}
+
+	gnmiC, err := dut.DialGNMI(ctx)
+	if err != nil {
+		t.Fatalf("DialGNMI() got error: %v", err)
+	}
+	c, err := ygnmi.NewClient(gnmiC)
+	if err != nil {
+		t.Fatalf("NewClient() got error: %v", err)
+	}
+	one := exampleocpath.Root().Parent().Child().One()
+	if _, err := ygnmi.Replace(ctx, c, one.Config(), "foo"); err != nil {
+		t.Fatalf("Replace() got error: %v", err)
+	}
+	if got, err := ygnmi.Get(ctx, c, one.State()); err != nil || got != "foo" {
+		t.Errorf("Get() got %q, %v, want %q", got, err, "foo")
+	}
+
+	if err := dut.PushConfig(ctx, "hostname r1", true); err != nil {
+		t.Fatalf("PushConfig() got error: %v", err)
+	}
+	cli, err := dut.DialCLI(ctx)
+	if err != nil {
+		t.Fatalf("DialCLI() got error: %v", err)
+	}
+	cmdRes, err := cli.RunCommand(ctx, "show running-config")
+	if err != nil {
+		t.Fatalf("RunCommand() got error: %v", err)
+	}
+	if got, want := cmdRes.Output(), "hostname r1"; got != want {
+		t.Errorf("RunCommand() got output %q, want %q", got, want)
+	}
+}
+
+func TestReserveUnsatisfiable(t *testing.T) {
+	tb := &opb.Testbed{Duts: []*opb.Device{{Id: "dut", Vendor: opb.Device_CISCO}}}
+	if _, err := New(topo).Reserve(context.Background(), tb, 0, 0, nil); err == nil {
+		t.Errorf("Reserve() got no error, want error")
+	}
+}
+
+func TestEmulator(t *testing.T) {
+	ctx := context.Background()
+	b := New(topo, WithDUTSchema(exampleoc.Schema), WithATESchema(exampleoc.Schema))
+	if _, err := b.Reserve(ctx, tb, 0, 0, nil); err != nil {
+		t.Fatalf("Reserve() got error: %v", err)
+	}
+	if _, err := b.Emulator("r1"); err != nil {
+		t.Errorf("Emulator(%q) got error: %v", "r1", err)
+	}
+	if _, err := b.Reserve(ctx, tb, 0, 0, nil); err == nil || !strings.Contains(err.Error(), "already held") {
+		t.Errorf("Reserve() while reserved got error %v, want already held", err)
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Fatalf("Release() got error: %v", err)
+	}
+	if _, err := b.Emulator("r1"); err == nil {
+		t.Errorf("Emulator(%q) after Release got no error, want error", "r1")
+	}
+}
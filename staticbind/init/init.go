@@ -0,0 +1,41 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package init installs the Ondatra binding for testing with a static lab.
+// It also installs an --inventory flag, which must specify an inventory file.
+// See the inventory proto for syntax details.
+package init
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/staticbind"
+)
+
+var inventory = flag.String("inventory", "", "Path to a YAML or textproto lab inventory file")
+
+// Init provides a generator for a static bind instance which uses the
+// inventory set by the flag --inventory. To be used with ondatra.RunTests.
+func Init() (binding.Binding, error) {
+	if *inventory == "" {
+		return nil, errors.New("--inventory flag must be provided")
+	}
+	inv, err := staticbind.LoadInventory(*inventory)
+	if err != nil {
+		return nil, err
+	}
+	return staticbind.New(inv)
+}
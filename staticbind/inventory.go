@@ -0,0 +1,112 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package staticbind
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	ipb "github.com/openconfig/ondatra/staticbind/proto"
+)
+
+// LoadInventory reads an inventory from a file. Files with a ".yaml" or
+// ".yml" extension are parsed as YAML, in which the field names are those of
+// the inventory proto or their JSON equivalents; any other file is parsed as
+// a textproto.
+func LoadInventory(path string) (*ipb.Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading inventory file: %w", err)
+	}
+	inv := new(ipb.Inventory)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		js, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing inventory YAML: %w", err)
+		}
+		if err := protojson.Unmarshal(js, inv); err != nil {
+			return nil, fmt.Errorf("error unmarshalling inventory YAML: %w", err)
+		}
+	default:
+		if err := prototext.Unmarshal(data, inv); err != nil {
+			return nil, fmt.Errorf("error unmarshalling inventory textproto: %w", err)
+		}
+	}
+	if err := validateInventory(inv); err != nil {
+		return nil, fmt.Errorf("invalid inventory %s: %w", path, err)
+	}
+	return inv, nil
+}
+
+// validateInventory checks that the device and port names of an inventory
+// are unique, that its links are between its ports, and that its services
+// have addresses.
+func validateInventory(inv *ipb.Inventory) error {
+	ports := make(map[string]bool)
+	devs := make(map[string]bool)
+	for _, dev := range append(append([]*ipb.Device{}, inv.GetDuts()...), inv.GetAtes()...) {
+		name := dev.GetName()
+		if name == "" {
+			return fmt.Errorf("device has no name: %v", dev)
+		}
+		if devs[name] {
+			return fmt.Errorf("duplicate device name %q", name)
+		}
+		devs[name] = true
+		for _, p := range dev.GetPorts() {
+			if p.GetName() == "" {
+				return fmt.Errorf("port of device %q has no name: %v", name, p)
+			}
+			pn := portName(dev, p)
+			if ports[pn] {
+				return fmt.Errorf("duplicate port %q", pn)
+			}
+			ports[pn] = true
+		}
+		for svcName, svc := range dev.GetServices() {
+			if svc.GetAddress() == "" {
+				return fmt.Errorf("service %q of device %q has no address", svcName, name)
+			}
+		}
+		if ssh := dev.GetSsh(); ssh != nil && ssh.GetAddress() == "" {
+			return fmt.Errorf("SSH endpoint of device %q has no address", name)
+		}
+	}
+	linked := make(map[string]bool)
+	for _, l := range inv.GetLinks() {
+		for _, pn := range []string{l.GetA(), l.GetB()} {
+			if !ports[pn] {
+				return fmt.Errorf("link %v has unknown port %q", l, pn)
+			}
+			if linked[pn] {
+				return fmt.Errorf("port %q is in multiple links", pn)
+			}
+			linked[pn] = true
+		}
+	}
+	return nil
+}
+
+// portName returns the name of a port by which links refer to it.
+func portName(dev *ipb.Device, p *ipb.Port) string {
+	return fmt.Sprintf("%s:%s", dev.GetName(), p.GetName())
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package staticbind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	opb "github.com/openconfig/ondatra/proto"
+	ipb "github.com/openconfig/ondatra/staticbind/proto"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile(%q) failed: %v", path, err)
+	}
+	return path
+}
+
+func TestLoadInventory(t *testing.T) {
+	want := &ipb.Inventory{
+		Duts: []*ipb.Device{{
+			Name:   "dut1",
+			Vendor: opb.Device_ARISTA,
+			Ports: []*ipb.Port{{
+				Name:  "Ethernet1/1",
+				Speed: opb.Port_S_400GB,
+				Pmd:   opb.Port_PMD_400GBASE_DR4,
+			}},
+			Services: map[string]*ipb.Service{
+				"gnmi": {Address: "dut1.lab:6030"},
+			},
+			Ssh: &ipb.Ssh{Address: "dut1.lab:22"},
+		}},
+		Ates: []*ipb.Device{{
+			Name:  "ate1",
+			Ports: []*ipb.Port{{Name: "1/1"}},
+		}},
+		Links:       []*ipb.Link{{A: "dut1:Ethernet1/1", B: "ate1:1/1"}},
+		Tls:         &ipb.Tls{SkipVerify: true},
+		Credentials: &ipb.Credentials{Username: "admin", Password: "pass"},
+	}
+	tests := []struct {
+		desc, name, content string
+	}{{
+		desc: "yaml",
+		name: "inventory.yaml",
+		content: `
+tls: {skip_verify: true}
+credentials: {username: admin, password: pass}
+duts:
+- name: dut1
+  vendor: ARISTA
+  ports:
+  - {name: Ethernet1/1, speed: S_400GB, pmd: PMD_400GBASE_DR4}
+  services:
+    gnmi: {address: "dut1.lab:6030"}
+  ssh: {address: "dut1.lab:22"}
+ates:
+- name: ate1
+  ports:
+  - {name: "1/1"}
+links:
+- {a: "dut1:Ethernet1/1", b: "ate1:1/1"}
+`,
+	}, {
+		desc: "textproto",
+		name: "inventory.textproto",
+		content: `
+tls { skip_verify: true }
+credentials { username: "admin" password: "pass" }
+duts {
+  name: "dut1"
+  vendor: ARISTA
+  ports { name: "Ethernet1/1" speed: S_400GB pmd: PMD_400GBASE_DR4 }
+  services { key: "gnmi" value { address: "dut1.lab:6030" } }
+  ssh { address: "dut1.lab:22" }
+}
+ates {
+  name: "ate1"
+  ports { name: "1/1" }
+}
+links { a: "dut1:Ethernet1/1" b: "ate1:1/1" }
+`,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := LoadInventory(writeFile(t, test.name, test.content))
+			if err != nil {
+				t.Fatalf("LoadInventory() got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("LoadInventory() got unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadInventoryErrors(t *testing.T) {
+	tests := []struct {
+		desc, content, wantErr string
+	}{{
+		desc:    "bad textproto",
+		content: `duts { bogus: 1 }`,
+		wantErr: "error unmarshalling",
+	}, {
+		desc:    "no device name",
+		content: `duts {}`,
+		wantErr: "no name",
+	}, {
+		desc:    "duplicate device",
+		content: `duts { name: "d" } ates { name: "d" }`,
+		wantErr: "duplicate device",
+	}, {
+		desc:    "duplicate port",
+		content: `duts { name: "d" ports { name: "p" } ports { name: "p" } }`,
+		wantErr: "duplicate port",
+	}, {
+		desc:    "unknown link port",
+		content: `duts { name: "d" ports { name: "p" } } links { a: "d:p" b: "d:q" }`,
+		wantErr: "unknown port",
+	}, {
+		desc:    "port in multiple links",
+		content: `duts { name: "d" ports { name: "p" } ports { name: "q" } ports { name: "r" } } links { a: "d:p" b: "d:q" } links { a: "d:p" b: "d:r" }`,
+		wantErr: "multiple links",
+	}, {
+		desc:    "service without address",
+		content: `duts { name: "d" services { key: "gnmi" value {} } }`,
+		wantErr: "no address",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := LoadInventory(writeFile(t, "inventory.textproto", test.content))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("LoadInventory() got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
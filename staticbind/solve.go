@@ -0,0 +1,193 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package staticbind
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/binding/portgraph"
+	"github.com/openconfig/ondatra/internal/tbgraph"
+
+	opb "github.com/openconfig/ondatra/proto"
+	ipb "github.com/openconfig/ondatra/staticbind/proto"
+)
+
+// Names of the well-known gRPC services of an inventory device.
+const (
+	gnmiService  = "gnmi"
+	gnoiService  = "gnoi"
+	gnsiService  = "gnsi"
+	gribiService = "gribi"
+	otgService   = "otg"
+	p4rtService  = "p4rt"
+)
+
+// assignment is a solution of a testbed on an inventory.
+type assignment struct {
+	dev2Dev   map[*opb.Device]*ipb.Device
+	port2Port map[*opb.Port]*ipb.Port
+}
+
+// solve assigns inventory devices and ports to the devices and ports of a
+// testbed, such that the testbed links are between linked inventory ports.
+func solve(ctx context.Context, tb *opb.Testbed, inv *ipb.Inventory, partial map[string]string) (*assignment, error) {
+	absGraph, absNode2Dev, absPort2Port, err := tbgraph.Abstract(tb, partial, "static testbed")
+	if err != nil {
+		return nil, fmt.Errorf("could not parse specified testbed: %w", err)
+	}
+	conGraph, conNode2Dev, conPort2Port := inventoryToConcreteGraph(inv)
+	a, err := portgraph.Solve(ctx, absGraph, conGraph)
+	if err != nil {
+		return nil, fmt.Errorf("could not solve for specified testbed: %w", err)
+	}
+	asgn := &assignment{
+		dev2Dev:   make(map[*opb.Device]*ipb.Device),
+		port2Port: make(map[*opb.Port]*ipb.Port),
+	}
+	for absNode, conNode := range a.Node2Node {
+		asgn.dev2Dev[absNode2Dev[absNode]] = conNode2Dev[conNode]
+	}
+	for absPort, conPort := range a.Port2Port {
+		// Ports added for required port counts are not in the testbed.
+		if port, ok := absPort2Port[absPort]; ok {
+			asgn.port2Port[port] = conPort2Port[conPort]
+		}
+	}
+	return asgn, nil
+}
+
+// inventoryToConcreteGraph translates an inventory to a ConcreteGraph.
+func inventoryToConcreteGraph(inv *ipb.Inventory) (*portgraph.ConcreteGraph, map[*portgraph.ConcreteNode]*ipb.Device, map[*portgraph.ConcretePort]*ipb.Port) {
+	var nodes []*portgraph.ConcreteNode
+	var edges []*portgraph.ConcreteEdge
+	name2Port := make(map[string]*portgraph.ConcretePort)
+	node2Dev := make(map[*portgraph.ConcreteNode]*ipb.Device)
+	port2Port := make(map[*portgraph.ConcretePort]*ipb.Port)
+
+	addDevice := func(dev *ipb.Device, role string) {
+		attrs := map[string]string{
+			tbgraph.RoleAttr: role,
+			tbgraph.NameAttr: dev.GetName(),
+		}
+		if v := dev.GetVendor(); v != opb.Device_VENDOR_UNSPECIFIED {
+			attrs[tbgraph.VendorAttr] = v.String()
+		}
+		if hw := dev.GetHardwareModel(); hw != "" {
+			attrs[tbgraph.HWAttr] = hw
+		}
+		if sw := dev.GetSoftwareVersion(); sw != "" {
+			attrs[tbgraph.SWAttr] = sw
+		}
+		for k, v := range dev.GetExtraDimensions() {
+			attrs[k] = v
+		}
+		for k, v := range tbgraph.CapsAttrs(deviceCaps(dev, role == tbgraph.RoleATE)) {
+			attrs[k] = v
+		}
+		var ports []*portgraph.ConcretePort
+		for _, p := range dev.GetPorts() {
+			portAttrs := map[string]string{tbgraph.NameAttr: p.GetName()}
+			if s := p.GetSpeed(); s != opb.Port_SPEED_UNSPECIFIED {
+				portAttrs[tbgraph.SpeedAttr] = s.String()
+			}
+			if pmd := p.GetPmd(); pmd != opb.Port_PMD_UNSPECIFIED {
+				portAttrs[tbgraph.PMDAttr] = pmd.String()
+			}
+			if cm := p.GetCardModel(); cm != "" {
+				portAttrs[tbgraph.CardAttr] = cm
+			}
+			if g := p.GetGroup(); g != "" {
+				portAttrs[tbgraph.GroupAttr] = g
+			}
+			if bg := p.GetBreakoutGroup(); bg != "" {
+				portAttrs[tbgraph.BreakoutGroupAttr] = bg
+			}
+			cp := &portgraph.ConcretePort{Desc: portName(dev, p), Attrs: portAttrs}
+			name2Port[cp.Desc] = cp
+			port2Port[cp] = p
+			ports = append(ports, cp)
+		}
+		n := &portgraph.ConcreteNode{Desc: dev.GetName(), Attrs: attrs, Ports: ports}
+		node2Dev[n] = dev
+		nodes = append(nodes, n)
+	}
+	for _, dev := range inv.GetDuts() {
+		addDevice(dev, tbgraph.RoleDUT)
+	}
+	for _, dev := range inv.GetAtes() {
+		addDevice(dev, tbgraph.RoleATE)
+	}
+	for _, l := range inv.GetLinks() {
+		edges = append(edges, &portgraph.ConcreteEdge{Src: name2Port[l.GetA()], Dst: name2Port[l.GetB()]})
+	}
+	return &portgraph.ConcreteGraph{Desc: "inventory", Nodes: nodes, Edges: edges}, node2Dev, port2Port
+}
+
+// deviceCaps returns the capabilities of an inventory device: the gRIBI,
+// P4RT and OTG services it exposes, whether it has breakout ports, and its
+// number of ports of each speed.
+func deviceCaps(dev *ipb.Device, isATE bool) *opb.Capabilities {
+	svcs := dev.GetServices()
+	caps := &opb.Capabilities{
+		Gribi: svcs[gribiService] != nil,
+		P4Rt:  svcs[p4rtService] != nil,
+	}
+	if isATE && svcs[otgService] != nil {
+		caps.AteApi = opb.Capabilities_OTG
+	}
+	counts := make(map[opb.Port_Speed]int32)
+	var speeds []opb.Port_Speed
+	for _, p := range dev.GetPorts() {
+		if p.GetBreakoutGroup() != "" {
+			caps.Breakout = true
+		}
+		if counts[p.GetSpeed()] == 0 {
+			speeds = append(speeds, p.GetSpeed())
+		}
+		counts[p.GetSpeed()]++
+	}
+	for _, s := range speeds {
+		caps.PortCounts = append(caps.PortCounts, &opb.Capabilities_PortCount{Speed: s, Count: counts[s]})
+	}
+	return caps
+}
+
+// dims returns the dimensions of the inventory device assigned to a testbed
+// device.
+func (a *assignment) dims(dev *opb.Device, isATE bool) *binding.Dims {
+	idev := a.dev2Dev[dev]
+	dims := &binding.Dims{
+		Name:            idev.GetName(),
+		Vendor:          idev.GetVendor(),
+		HardwareModel:   idev.GetHardwareModel(),
+		SoftwareVersion: idev.GetSoftwareVersion(),
+		Ports:           make(map[string]*binding.Port),
+		Capabilities:    deviceCaps(idev, isATE),
+	}
+	for _, p := range dev.GetPorts() {
+		ip := a.port2Port[p]
+		dims.Ports[p.GetId()] = &binding.Port{
+			Name:          ip.GetName(),
+			Speed:         ip.GetSpeed(),
+			CardModel:     ip.GetCardModel(),
+			PMD:           ip.GetPmd(),
+			BreakoutGroup: ip.GetBreakoutGroup(),
+		}
+	}
+	return dims
+}
@@ -0,0 +1,464 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package staticbind provides an Ondatra binding for a static lab of devices,
+// described by an inventory file.
+//
+// The binding reserves the devices and ports of a testbed by solving the
+// testbed against the devices, ports and links of the inventory. It dials the
+// gRPC services of the devices at the addresses in the inventory, with the
+// TLS config and credentials of the inventory, and runs CLI commands over SSH.
+// The binding does not reset or push config to the devices; it assumes that
+// the lab devices are already in a suitable base configuration.
+package staticbind
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	log "github.com/golang/glog"
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/gnoigo"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/binding/sshcli"
+	"github.com/openconfig/ondatra/secrets"
+	"github.com/pborman/uuid"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	acctzpb "github.com/openconfig/gnsi/acctz"
+	authzpb "github.com/openconfig/gnsi/authz"
+	certzpb "github.com/openconfig/gnsi/certz"
+	credzpb "github.com/openconfig/gnsi/credentialz"
+	pathzpb "github.com/openconfig/gnsi/pathz"
+	grpb "github.com/openconfig/gribi/v1/proto/service"
+	opb "github.com/openconfig/ondatra/proto"
+	ipb "github.com/openconfig/ondatra/staticbind/proto"
+	p4pb "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+const grpcDialTimeout = 30 * time.Second
+
+// New returns a new static bind instance for the devices of an inventory.
+func New(inv *ipb.Inventory) (*Bind, error) {
+	if inv == nil {
+		return nil, fmt.Errorf("inventory cannot be nil")
+	}
+	if err := validateInventory(inv); err != nil {
+		return nil, fmt.Errorf("invalid inventory: %w", err)
+	}
+	return &Bind{inv: inv}, nil
+}
+
+// Bind implements the ondatra Binding interface for a static lab.
+type Bind struct {
+	binding.Binding
+	inv *ipb.Inventory
+
+	mu  sync.Mutex // guards res
+	res *binding.Reservation
+}
+
+// Reserve implements the binding Reserve method by finding devices and links
+// in the inventory that match the requested testbed.
+func (b *Bind) Reserve(ctx context.Context, tb *opb.Testbed, _, _ time.Duration, partial map[string]string) (*binding.Reservation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.res != nil {
+		return nil, fmt.Errorf("reservation %q is already held; release it first", b.res.ID)
+	}
+	a, err := solve(ctx, tb, b.inv, partial)
+	if err != nil {
+		return nil, err
+	}
+	res := &binding.Reservation{
+		ID:   uuid.New(),
+		DUTs: make(map[string]binding.DUT),
+		ATEs: make(map[string]binding.ATE),
+	}
+	for _, dut := range tb.GetDuts() {
+		res.DUTs[dut.GetId()] = &staticDUT{
+			AbstractDUT: &binding.AbstractDUT{Dims: a.dims(dut, false)},
+			dev:         &device{inv: b.inv, dev: a.dev2Dev[dut]},
+		}
+	}
+	for _, ate := range tb.GetAtes() {
+		res.ATEs[ate.GetId()] = &staticATE{
+			AbstractATE: &binding.AbstractATE{Dims: a.dims(ate, true)},
+			dev:         &device{inv: b.inv, dev: a.dev2Dev[ate]},
+		}
+	}
+	b.res = res
+	return res, nil
+}
+
+// FetchReservation implements the binding FetchReservation method by returning
+// the reservation held by the binding, if it has the specified ID. The lab
+// devices are not held by the binding, so reservations only exist in the
+// process that made them.
+func (b *Bind) FetchReservation(_ context.Context, id string) (*binding.Reservation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.res == nil || b.res.ID != id {
+		return nil, fmt.Errorf("reservation %q not found", id)
+	}
+	return b.res, nil
+}
+
+// Release implements the binding Release method by closing the CLI sessions
+// of the reserved DUTs and dropping the reservation.
+func (b *Bind) Release(context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.res == nil {
+		return fmt.Errorf("no reservation to release")
+	}
+	for _, dut := range b.res.DUTs {
+		if err := dut.(*staticDUT).closeCLI(); err != nil {
+			log.Warningf("Error closing CLI session of DUT %q: %v", dut.Name(), err)
+		}
+	}
+	b.res = nil
+	return nil
+}
+
+// device is an inventory device and the inventory of which it is a part,
+// which holds its default TLS config and credentials.
+type device struct {
+	inv *ipb.Inventory
+	dev *ipb.Device
+}
+
+// tlsConfig returns the TLS config of a service of the device: that of the
+// service, else that of the device, else the default of the inventory.
+func (d *device) tlsConfig(svc *ipb.Service) *ipb.Tls {
+	switch {
+	case svc.GetTls() != nil:
+		return svc.GetTls()
+	case d.dev.GetTls() != nil:
+		return d.dev.GetTls()
+	default:
+		return d.inv.GetTls()
+	}
+}
+
+// credentials returns the credentials of an endpoint of the device: those of
+// the endpoint, else those of the device, else the default of the inventory.
+// A password of the form "secret:<key>" is replaced with the secret with
+// that key from the secrets provider.
+func (d *device) credentials(ctx context.Context, endpoint *ipb.Credentials) (*ipb.Credentials, error) {
+	var creds *ipb.Credentials
+	switch {
+	case endpoint != nil:
+		creds = endpoint
+	case d.dev.GetCredentials() != nil:
+		creds = d.dev.GetCredentials()
+	default:
+		creds = d.inv.GetCredentials()
+	}
+	if creds.GetPassword() == "" {
+		return creds, nil
+	}
+	password, err := secrets.Resolve(ctx, creds.GetPassword())
+	if err != nil {
+		return nil, fmt.Errorf("error resolving password of device %q: %w", d.dev.GetName(), err)
+	}
+	return &ipb.Credentials{Username: creds.GetUsername(), Password: password}, nil
+}
+
+// dialGRPC dials the service of the device with the specified name.
+// The options of the caller are appended to the default options.
+func (d *device) dialGRPC(ctx context.Context, svcName string, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	svc, ok := d.dev.GetServices()[svcName]
+	if !ok {
+		return nil, fmt.Errorf("service %q not found on device %q", svcName, d.dev.GetName())
+	}
+	defaults, err := d.dialOpts(ctx, svc)
+	if err != nil {
+		return nil, fmt.Errorf("service %q on device %q: %w", svcName, d.dev.GetName(), err)
+	}
+	log.Infof("Dialing service %q on device %s at %s with options %v", svcName, d.dev.GetName(), svc.GetAddress(), opts)
+	ctx, cancel := context.WithTimeout(ctx, grpcDialTimeout)
+	defer cancel()
+	return grpc.DialContext(ctx, svc.GetAddress(), append(defaults, opts...)...)
+}
+
+// dialOpts returns the default options with which to dial a service.
+func (d *device) dialOpts(ctx context.Context, svc *ipb.Service) ([]grpc.DialOption, error) {
+	tc := d.tlsConfig(svc)
+	var opts []grpc.DialOption
+	if tc.GetPlaintext() {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		cfg, err := newTLSConfig(tc)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
+	}
+	c, err := d.credentials(ctx, svc.GetCredentials())
+	if err != nil {
+		return nil, err
+	}
+	if c.GetUsername() != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(&rpcCredentials{
+			creds:  c,
+			secure: !tc.GetPlaintext(),
+		}))
+	}
+	return opts, nil
+}
+
+// newTLSConfig returns the TLS config of a client with the specified
+// inventory TLS config. A nil config verifies the server with the system
+// certificate pool.
+func newTLSConfig(tc *ipb.Tls) (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: tc.GetSkipVerify(), // NOLINT
+		ServerName:         tc.GetServerName(),
+	}
+	if f := tc.GetCaFile(); f != "" {
+		pem, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA file %s", f)
+		}
+		cfg.RootCAs = pool
+	}
+	if tc.GetCertFile() != "" || tc.GetKeyFile() != "" {
+		cert, err := tls.LoadX509KeyPair(tc.GetCertFile(), tc.GetKeyFile())
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// rpcCredentials sends a username and password with every RPC.
+type rpcCredentials struct {
+	creds  *ipb.Credentials
+	secure bool
+}
+
+func (r *rpcCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		"username": r.creds.GetUsername(),
+		"password": r.creds.GetPassword(),
+	}, nil
+}
+
+func (r *rpcCredentials) RequireTransportSecurity() bool {
+	return r.secure
+}
+
+type staticDUT struct {
+	*binding.AbstractDUT
+	dev *device
+
+	mu  sync.Mutex // guards cli
+	cli *sshcli.CLI
+}
+
+func (d *staticDUT) DialGNMI(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+	conn, err := d.dev.dialGRPC(ctx, gnmiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gpb.NewGNMIClient(conn), nil
+}
+
+func (d *staticDUT) DialGNOI(ctx context.Context, opts ...grpc.DialOption) (gnoigo.Clients, error) {
+	conn, err := d.dev.dialGRPC(ctx, gnoiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gnoigo.NewClients(conn), nil
+}
+
+func (d *staticDUT) DialGRIBI(ctx context.Context, opts ...grpc.DialOption) (grpb.GRIBIClient, error) {
+	conn, err := d.dev.dialGRPC(ctx, gribiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return grpb.NewGRIBIClient(conn), nil
+}
+
+func (d *staticDUT) DialP4RT(ctx context.Context, opts ...grpc.DialOption) (p4pb.P4RuntimeClient, error) {
+	conn, err := d.dev.dialGRPC(ctx, p4rtService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return p4pb.NewP4RuntimeClient(conn), nil
+}
+
+// gnsiConn implements the stub builder needed by the Ondatra
+// binding.Binding interface.
+type gnsiConn struct {
+	*binding.AbstractGNSIClients
+	conn *grpc.ClientConn
+}
+
+func (c *gnsiConn) Authz() authzpb.AuthzClient {
+	return authzpb.NewAuthzClient(c.conn)
+}
+
+func (c *gnsiConn) Pathz() pathzpb.PathzClient {
+	return pathzpb.NewPathzClient(c.conn)
+}
+
+func (c *gnsiConn) Certz() certzpb.CertzClient {
+	return certzpb.NewCertzClient(c.conn)
+}
+
+func (c *gnsiConn) Credentialz() credzpb.CredentialzClient {
+	return credzpb.NewCredentialzClient(c.conn)
+}
+
+func (c *gnsiConn) Acctz() acctzpb.AcctzClient {
+	return acctzpb.NewAcctzClient(c.conn)
+}
+
+func (d *staticDUT) DialGNSI(ctx context.Context, opts ...grpc.DialOption) (binding.GNSIClients, error) {
+	conn, err := d.dev.dialGRPC(ctx, gnsiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &gnsiConn{conn: conn}, nil
+}
+
+// DialGRPC dials the service with the specified name.
+// It is exported so that test may use a type assertion to dial custom services.
+func (d *staticDUT) DialGRPC(ctx context.Context, serviceName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return d.dev.dialGRPC(ctx, serviceName, opts)
+}
+
+// DialCLI returns the CLI session of the DUT, logging in on the first call.
+// The session is shared by all callers and closed when the reservation is
+// released.
+func (d *staticDUT) DialCLI(ctx context.Context) (binding.CLIClient, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cli != nil {
+		return d.cli, nil
+	}
+	sc := d.dev.dev.GetSsh()
+	if sc == nil {
+		return nil, fmt.Errorf("no SSH endpoint for DUT %q", d.Name())
+	}
+	cfg, err := d.dev.sshConfig(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := sshcli.Dial(ctx, &sshcli.Config{
+		Addr:   sc.GetAddress(),
+		SSH:    cfg,
+		Vendor: d.Vendor(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.cli = cli
+	return cli, nil
+}
+
+func (d *staticDUT) closeCLI() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cli == nil {
+		return nil
+	}
+	err := d.cli.Close()
+	d.cli = nil
+	return err
+}
+
+// sshConfig returns the config with which to dial the SSH endpoint of the
+// device. It authenticates with a password, either directly or in response
+// to keyboard-interactive questions.
+func (d *device) sshConfig(ctx context.Context, sc *ipb.Ssh) (*ssh.ClientConfig, error) {
+	creds, err := d.credentials(ctx, sc.GetCredentials())
+	if err != nil {
+		return nil, err
+	}
+	if creds.GetUsername() == "" {
+		return nil, fmt.Errorf("CLI requires credentials for device %q", d.dev.GetName())
+	}
+	var hostKeyCallback ssh.HostKeyCallback
+	switch f := sc.GetKnownHostsFile(); {
+	case f != "":
+		var err error
+		hostKeyCallback, err = knownhosts.New(f)
+		if err != nil {
+			return nil, fmt.Errorf("error reading known hosts file: %w", err)
+		}
+	case sc.GetSkipHostKeyVerify():
+		log.Warningf("Skipping SSH host key verification of device %q", d.dev.GetName())
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, fmt.Errorf("SSH of device %q requires a known_hosts_file or skip_host_key_verify", d.dev.GetName())
+	}
+	return &ssh.ClientConfig{
+		User: creds.GetUsername(),
+		Auth: []ssh.AuthMethod{
+			ssh.Password(creds.GetPassword()),
+			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = creds.GetPassword()
+				}
+				return answers, nil
+			}),
+		},
+		HostKeyCallback: hostKeyCallback,
+	}, nil
+}
+
+type staticATE struct {
+	*binding.AbstractATE
+	dev *device
+}
+
+func (a *staticATE) DialOTG(ctx context.Context, opts ...grpc.DialOption) (gosnappi.GosnappiApi, error) {
+	conn, err := a.dev.dialGRPC(ctx, otgService, opts)
+	if err != nil {
+		return nil, err
+	}
+	api := gosnappi.NewApi()
+	api.NewGrpcTransport().
+		SetClientConnection(conn).
+		SetRequestTimeout(30 * time.Second)
+	return api, nil
+}
+
+func (a *staticATE) DialGNMI(ctx context.Context, opts ...grpc.DialOption) (gpb.GNMIClient, error) {
+	conn, err := a.dev.dialGRPC(ctx, gnmiService, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gpb.NewGNMIClient(conn), nil
+}
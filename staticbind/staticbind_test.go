@@ -0,0 +1,380 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package staticbind
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/binding"
+	"github.com/openconfig/ondatra/internal/fakessh"
+	"github.com/openconfig/ondatra/secrets"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	opb "github.com/openconfig/ondatra/proto"
+	ipb "github.com/openconfig/ondatra/staticbind/proto"
+)
+
+func inventory() *ipb.Inventory {
+	return &ipb.Inventory{
+		Duts: []*ipb.Device{{
+			Name:            "dut1",
+			Vendor:          opb.Device_ARISTA,
+			HardwareModel:   "7280R3",
+			SoftwareVersion: "4.30.1F",
+			Ports: []*ipb.Port{
+				{Name: "Ethernet1/1", Speed: opb.Port_S_400GB, Pmd: opb.Port_PMD_400GBASE_DR4},
+				{Name: "Ethernet2/1", Speed: opb.Port_S_100GB, BreakoutGroup: "Ethernet2"},
+			},
+			Services: map[string]*ipb.Service{
+				"gnmi":  {Address: "dut1.lab:6030"},
+				"gribi": {Address: "dut1.lab:9340"},
+			},
+		}, {
+			Name:   "dut2",
+			Vendor: opb.Device_ARISTA,
+			Ports:  []*ipb.Port{{Name: "Ethernet1/1", Speed: opb.Port_S_400GB}},
+		}},
+		Ates: []*ipb.Device{{
+			Name:   "ate1",
+			Vendor: opb.Device_IXIA,
+			Ports: []*ipb.Port{
+				{Name: "1/1", Speed: opb.Port_S_400GB},
+				{Name: "1/2", Speed: opb.Port_S_400GB},
+			},
+			Services: map[string]*ipb.Service{
+				"otg": {Address: "otg.lab:40051"},
+			},
+		}},
+		Links: []*ipb.Link{
+			{A: "dut1:Ethernet1/1", B: "ate1:1/1"},
+			{A: "dut2:Ethernet1/1", B: "ate1:1/2"},
+		},
+	}
+}
+
+func testbed() *opb.Testbed {
+	return &opb.Testbed{
+		Duts:  []*opb.Device{{Id: "dut", Ports: []*opb.Port{{Id: "port1", Speed: opb.Port_S_400GB}}}},
+		Ates:  []*opb.Device{{Id: "ate", Ports: []*opb.Port{{Id: "port1"}}}},
+		Links: []*opb.Link{{A: "dut:port1", B: "ate:port1"}},
+	}
+}
+
+func TestReserve(t *testing.T) {
+	b, err := New(inventory())
+	if err != nil {
+		t.Fatalf("New() got unexpected error: %v", err)
+	}
+	gribiTB := testbed()
+	gribiTB.GetDuts()[0].Capabilities = &opb.Capabilities{Gribi: true}
+
+	tests := []struct {
+		desc        string
+		tb          *opb.Testbed
+		partial     map[string]string
+		wantDUTName string
+		wantATEPort string
+	}{{
+		desc:        "gRIBI required",
+		tb:          gribiTB,
+		wantDUTName: "dut1",
+		wantATEPort: "1/1",
+	}, {
+		desc:        "partial",
+		tb:          testbed(),
+		partial:     map[string]string{"dut": "dut2"},
+		wantDUTName: "dut2",
+		wantATEPort: "1/2",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			res, err := b.Reserve(context.Background(), test.tb, 0, 0, test.partial)
+			if err != nil {
+				t.Fatalf("Reserve() got unexpected error: %v", err)
+			}
+			defer b.Release(context.Background())
+			if got := res.DUTs["dut"].Name(); got != test.wantDUTName {
+				t.Errorf("Reserve() got DUT %q, want %q", got, test.wantDUTName)
+			}
+			if got := res.ATEs["ate"].Ports()["port1"].Name; got != test.wantATEPort {
+				t.Errorf("Reserve() got ATE port %q, want %q", got, test.wantATEPort)
+			}
+		})
+	}
+}
+
+func TestReserveDims(t *testing.T) {
+	b, err := New(inventory())
+	if err != nil {
+		t.Fatalf("New() got unexpected error: %v", err)
+	}
+	res, err := b.Reserve(context.Background(), testbed(), 0, 0, map[string]string{"dut": "dut1"})
+	if err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	dut := res.DUTs["dut"]
+	wantPort := &binding.Port{Name: "Ethernet1/1", Speed: opb.Port_S_400GB, PMD: opb.Port_PMD_400GBASE_DR4}
+	if diff := cmp.Diff(wantPort, dut.Ports()["port1"]); diff != "" {
+		t.Errorf("Reserve() got unexpected DUT port diff (-want +got):\n%s", diff)
+	}
+	if got, want := dut.HardwareModel(), "7280R3"; got != want {
+		t.Errorf("Reserve() got DUT hardware model %q, want %q", got, want)
+	}
+	wantCaps := &opb.Capabilities{
+		Gribi:    true,
+		Breakout: true,
+		PortCounts: []*opb.Capabilities_PortCount{
+			{Speed: opb.Port_S_400GB, Count: 1},
+			{Speed: opb.Port_S_100GB, Count: 1},
+		},
+	}
+	if diff := cmp.Diff(wantCaps, dut.Capabilities(), protocmp.Transform()); diff != "" {
+		t.Errorf("Reserve() got unexpected DUT capabilities diff (-want +got):\n%s", diff)
+	}
+	if got, want := res.ATEs["ate"].Capabilities().GetAteApi(), opb.Capabilities_OTG; got != want {
+		t.Errorf("Reserve() got ATE API %v, want %v", got, want)
+	}
+}
+
+func TestReserveErrors(t *testing.T) {
+	b, err := New(inventory())
+	if err != nil {
+		t.Fatalf("New() got unexpected error: %v", err)
+	}
+	tb := testbed()
+	tb.GetDuts()[0].Vendor = opb.Device_JUNIPER
+	_, err = b.Reserve(context.Background(), tb, 0, 0, nil)
+	if want := "could not solve"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Reserve() got error %v, want %q", err, want)
+	}
+}
+
+// gnmiServer records the metadata of the Capabilities RPC.
+type gnmiServer struct {
+	gpb.UnimplementedGNMIServer
+	md metadata.MD
+}
+
+func (s *gnmiServer) Capabilities(ctx context.Context, _ *gpb.CapabilityRequest) (*gpb.CapabilityResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	return &gpb.CapabilityResponse{}, nil
+}
+
+func TestDialGNMI(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	srv := grpc.NewServer()
+	gs := new(gnmiServer)
+	gpb.RegisterGNMIServer(srv, gs)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	inv := inventory()
+	inv.Tls = &ipb.Tls{SkipVerify: true}
+	inv.Credentials = &ipb.Credentials{Username: "default", Password: "default"}
+	dut1 := inv.GetDuts()[0]
+	dut1.Credentials = &ipb.Credentials{Username: "admin", Password: "hunter2"}
+	dut1.GetServices()["gnmi"] = &ipb.Service{
+		Address: lis.Addr().String(),
+		Tls:     &ipb.Tls{Plaintext: true},
+	}
+	b, err := New(inv)
+	if err != nil {
+		t.Fatalf("New() got unexpected error: %v", err)
+	}
+	res, err := b.Reserve(context.Background(), testbed(), 0, 0, map[string]string{"dut": "dut1"})
+	if err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	gnmiClient, err := res.DUTs["dut"].DialGNMI(context.Background())
+	if err != nil {
+		t.Fatalf("DialGNMI() got unexpected error: %v", err)
+	}
+	if _, err := gnmiClient.Capabilities(context.Background(), &gpb.CapabilityRequest{}); err != nil {
+		t.Fatalf("Capabilities() got unexpected error: %v", err)
+	}
+	if got, want := gs.md.Get("username"), []string{"admin"}; !cmp.Equal(got, want) {
+		t.Errorf("DialGNMI() sent username %v, want %v", got, want)
+	}
+	if got, want := gs.md.Get("password"), []string{"hunter2"}; !cmp.Equal(got, want) {
+		t.Errorf("DialGNMI() sent password %v, want %v", got, want)
+	}
+}
+
+func TestDialCLI(t *testing.T) {
+	srv, err := fakessh.New(&fakessh.Config{
+		User:     "admin",
+		Password: "hunter2",
+		Prompt:   "dut1#",
+		Handler: func(cmd string) string {
+			if cmd == "show hostname" {
+				return "Hostname: dut1"
+			}
+			return ""
+		},
+	})
+	if err != nil {
+		t.Fatalf("fakessh.New() failed: %v", err)
+	}
+	defer srv.Close()
+
+	secrets.SetProvider(secrets.EnvProvider("STATICBIND_TEST_"))
+	defer secrets.SetProvider(nil)
+	t.Setenv("STATICBIND_TEST_DUT1_PASSWORD", "hunter2")
+
+	inv := inventory()
+	dut1 := inv.GetDuts()[0]
+	dut1.Credentials = &ipb.Credentials{Username: "admin", Password: "secret:dut1/password"}
+	dut1.Ssh = &ipb.Ssh{Address: srv.Addr(), SkipHostKeyVerify: true}
+	b, err := New(inv)
+	if err != nil {
+		t.Fatalf("New() got unexpected error: %v", err)
+	}
+	res, err := b.Reserve(context.Background(), testbed(), 0, 0, map[string]string{"dut": "dut1"})
+	if err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		cli, err := res.DUTs["dut"].DialCLI(context.Background())
+		if err != nil {
+			t.Fatalf("DialCLI() got unexpected error: %v", err)
+		}
+		cmdRes, err := cli.RunCommand(context.Background(), "show hostname")
+		if err != nil {
+			t.Fatalf("RunCommand() got unexpected error: %v", err)
+		}
+		if got, want := cmdRes.Output(), "Hostname: dut1\n"; got != want {
+			t.Errorf("RunCommand() got output %q, want %q", got, want)
+		}
+	}
+	if err := b.Release(context.Background()); err != nil {
+		t.Fatalf("Release() got unexpected error: %v", err)
+	}
+	// The second dial reuses the session, so pagination is disabled once.
+	wantCmds := []string{"terminal length 0", "terminal width 32767", "show hostname", "show hostname"}
+	if diff := cmp.Diff(wantCmds, srv.Commands()); diff != "" {
+		t.Errorf("DialCLI() sent unexpected commands (-want +got):\n%s", diff)
+	}
+}
+
+func TestDialCLIRequiresHostKey(t *testing.T) {
+	inv := inventory()
+	dut1 := inv.GetDuts()[0]
+	dut1.Credentials = &ipb.Credentials{Username: "admin", Password: "hunter2"}
+	dut1.Ssh = &ipb.Ssh{Address: "dut1.lab:22"}
+	b, err := New(inv)
+	if err != nil {
+		t.Fatalf("New() got unexpected error: %v", err)
+	}
+	res, err := b.Reserve(context.Background(), testbed(), 0, 0, map[string]string{"dut": "dut1"})
+	if err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	wantErr := "requires a known_hosts_file or skip_host_key_verify"
+	if _, err := res.DUTs["dut"].DialCLI(context.Background()); err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Errorf("DialCLI() got error %v, want %q", err, wantErr)
+	}
+}
+
+func TestDialErrors(t *testing.T) {
+	inv := inventory()
+	inv.GetDuts()[0].GetServices()["gribi"].Tls = &ipb.Tls{CaFile: "/nonexistent/ca.pem"}
+	b, err := New(inv)
+	if err != nil {
+		t.Fatalf("New() got unexpected error: %v", err)
+	}
+	res, err := b.Reserve(context.Background(), testbed(), 0, 0, map[string]string{"dut": "dut1"})
+	if err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	dut := res.DUTs["dut"]
+	tests := []struct {
+		desc    string
+		dial    func() error
+		wantErr string
+	}{{
+		desc: "no service",
+		dial: func() error {
+			_, err := dut.DialP4RT(context.Background())
+			return err
+		},
+		wantErr: "not found",
+	}, {
+		desc: "bad CA file",
+		dial: func() error {
+			_, err := dut.DialGRIBI(context.Background())
+			return err
+		},
+		wantErr: "error reading CA file",
+	}, {
+		desc: "no SSH endpoint",
+		dial: func() error {
+			_, err := dut.DialCLI(context.Background())
+			return err
+		},
+		wantErr: "no SSH endpoint",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if err := test.dial(); err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("dial got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchReservation(t *testing.T) {
+	b, err := New(inventory())
+	if err != nil {
+		t.Fatalf("New() got unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	res, err := b.Reserve(ctx, testbed(), 0, 0, nil)
+	if err != nil {
+		t.Fatalf("Reserve() got unexpected error: %v", err)
+	}
+	if _, err := b.Reserve(ctx, testbed(), 0, 0, nil); err == nil || !strings.Contains(err.Error(), "already held") {
+		t.Errorf("Reserve() got error %v, want already held", err)
+	}
+	got, err := b.FetchReservation(ctx, res.ID)
+	if err != nil {
+		t.Fatalf("FetchReservation() got unexpected error: %v", err)
+	}
+	if got != res {
+		t.Errorf("FetchReservation() got %v, want %v", got, res)
+	}
+	if _, err := b.FetchReservation(ctx, "unknown"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("FetchReservation() got error %v, want not found", err)
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Fatalf("Release() got unexpected error: %v", err)
+	}
+	if _, err := b.FetchReservation(ctx, res.ID); err == nil {
+		t.Errorf("FetchReservation() after Release() got nil error, want not found")
+	}
+	if err := b.Release(ctx); err == nil {
+		t.Errorf("Release() with no reservation got nil error, want error")
+	}
+}